@@ -8,181 +8,2351 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/cmd/common/signer"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/metadata"
 	"github.com/hyperledger/fabric/internal/osnadmin"
+	"github.com/hyperledger/fabric/orderer/common/channelparticipation"
+	"github.com/hyperledger/fabric/orderer/common/types"
 	"github.com/hyperledger/fabric/protoutil"
 	"gopkg.in/alecthomas/kingpin.v2"
+	"gopkg.in/yaml.v2"
 )
 
-func main() {
-	kingpin.Version("0.0.1")
+// stderr is where warnings are written to.
+var stderr io.Writer = os.Stderr
+
+// isTerminal reports whether the command output is written to a terminal.
+var isTerminal = func() bool {
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// stderrIsTerminal reports whether warnings and progress are written to a
+// terminal.
+var stderrIsTerminal = func() bool {
+	f, ok := stderr.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
 
+func main() {
 	output, exit, err := executeForArgs(os.Args[1:])
 	if err != nil {
-		kingpin.Fatalf("parsing arguments: %s. Try --help", err)
+		kingpin.Errorf("parsing arguments: %s. Try --help", err)
+		os.Exit(exit)
 	}
 	fmt.Println(output)
 	os.Exit(exit)
 }
 
 func executeForArgs(args []string) (output string, exit int, err error) {
+	c := newCLI()
+	command, err := c.parse(args)
+	if err != nil {
+		return "", 1, err
+	}
+
+	if command == c.version.FullCommand() {
+		return versionInfo(c.versionFull), 0, nil
+	}
+
+	// channel diff exits with 1 when the OSNs differ so, as with diff(1), it
+	// exits with 2 when they cannot be compared
+	if command == c.diff.FullCommand() {
+		defer func() {
+			if err != nil {
+				exit = 2
+			}
+		}()
+	}
+
+	// the address is read from a file when given as @path, e.g. one written
+	// by a sidecar, before it is checked or used
+	ordererAddressErr := resolveOrdererAddress(&c.orderer)
+
+	if c.explain {
+		return c.explainOutput(command, ordererAddressErr)
+	}
+
+	if err := c.checkConnectionFlags(command, ordererAddressErr); err != nil {
+		return "", 1, err
+	}
+	conn, err := c.connection()
+	if err != nil {
+		return "", 1, err
+	}
+	// the certificate is pinned before any request is sent, so that a
+	// mismatch is reported once rather than as a failure of every request
+	if c.pinFile != "" && !c.printCert {
+		if err := c.pinCertificate(&conn); err != nil {
+			return errorOutput(err), 1, nil
+		}
+	}
+
+	switch {
+	case command == c.doctor.FullCommand():
+		return doctorOutput(osnadmin.Diagnose(c.orderer, c.pathPrefix, conn.caCertPool, conn.tlsClientCert, conn.clientOpts))
+	case command == c.probe.FullCommand():
+		if err := osnadmin.Probe(conn.osnURL, conn.caCertPool, conn.tlsClientCert, conn.clientOpts); err != nil {
+			return errorOutput(err), 1, nil
+		}
+		return "", 0, nil
+	case c.printCert:
+		certs, err := osnadmin.ServerCertificates(c.orderer, conn.tlsClientCert)
+		if err != nil {
+			return errorOutput(err), 1, nil
+		}
+		return certificatesOutput(certs, conn.caCertPool), 0, nil
+	}
+
+	r := &runner{cli: c, command: command, conn: conn}
+	if r.retryPolicy, err = c.retryPolicy(); err != nil {
+		return "", 1, err
+	}
+	if r.output, err = c.outputOptions(command); err != nil {
+		return "", 1, err
+	}
+	if r.expectation, err = c.checkCommandFlags(command); err != nil {
+		return "", 1, err
+	}
+	if r.configBlock, r.blockChannelID, err = readConfigBlock(c.configBlockPath, c.configBlockB64, c.joinChannelID); err != nil {
+		return "", 1, err
+	}
+
+	if c.logFile != "" {
+		f, err := os.OpenFile(c.logFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o640)
+		if err != nil {
+			return "", 1, fmt.Errorf("opening log file: %s", err)
+		}
+		defer f.Close()
+		r.logWriter = f
+		r.opLog = newOperationLog(f, c.orderer)
+	}
+
+	return r.run()
+}
+
+// cli holds the commands of osnadmin and the values of their flags.
+type cli struct {
+	app *kingpin.Application
+
+	join, list, remove, status, setMaintenance, setNormal, diff, doctor, probe, version *kingpin.CmdClause
+
+	orderer           string
+	pathPrefix        string
+	caFile            string
+	caCertDir         string
+	expectSAN         string
+	pinFile           string
+	clientCert        string
+	clientKey         string
+	secretDir         string
+	pkcs11Lib         string
+	pkcs11Pin         string
+	pkcs11Label       string
+	noStatus          bool
+	statusOnly        bool
+	printCert         bool
+	certExpiryWarning int
+	retries           int
+	retryInterval     time.Duration
+	userAgent         string
+	timeout           time.Duration
+	verbose           bool
+	retryOn           string
+	format            string
+	outputTemplate    string
+	tableColumns      string
+	noColor           bool
+	timing            bool
+	explain           bool
+	logFile           string
+
+	joinChannelID     string
+	configBlockPath   string
+	configBlockB64    string
+	fromOrderer       string
+	fromOrdererCAFile string
+	mspID             string
+	signingCert       string
+	signingKey        string
+	joinCompress      bool
+	joinDryRunServer  bool
+	joinFollow        bool
+	joinFollowTimeout time.Duration
+	joinBatchFile     string
+	joinFieldName     string
+
+	listChannelID   string
+	listSinceHeight uint64
+
+	removeChannelID     string
+	removeAll           bool
+	removeForce         bool
+	removeSystemChannel bool
+
+	statusChannelID       string
+	statusExpect          string
+	statusExpectRelation  string
+	statusExpectMinHeight uint64
+
+	setMaintenanceChannelID string
+	setNormalChannelID      string
+
+	diffOrderers      []string
+	diffCompareHeight bool
+
+	versionFull bool
+}
+
+// newCLI defines the commands and flags of osnadmin.
+func newCLI() *cli {
+	c := &cli{}
+
 	//
 	// command line flags
 	//
 	app := kingpin.New("osnadmin", "Orderer Service Node (OSN) administration")
-	orderer := app.Flag("orderer-address", "Admin endpoint of the OSN").Short('o').Required().String()
-	caFile := app.Flag("ca-file", "Path to file containing PEM-encoded TLS CA certificate(s) for the OSN").String()
-	clientCert := app.Flag("client-cert", "Path to file containing PEM-encoded X509 public key to use for mutual TLS communication with the OSN").String()
-	clientKey := app.Flag("client-key", "Path to file containing PEM-encoded private key to use for mutual TLS communication with the OSN").String()
-	noStatus := app.Flag("no-status", "Remove the HTTP status message from the command output").Default("false").Bool()
+	app.Version(metadata.Version)
+	// every flag that is not set falls back to the OSNADMIN_<FLAG> environment variable, e.g. OSNADMIN_ORDERER_ADDRESS
+	app.DefaultEnvars()
+	app.HelpFlag.NoEnvar()
+	app.VersionFlag.NoEnvar()
+	app.Flag("orderer-address", "Admin endpoint of the OSN (required by channel commands other than diff), or @path to read it from a file").Short('o').StringVar(&c.orderer)
+	app.Flag("path-prefix", "Path prefix that a gateway exposes the admin endpoint of the OSN under, e.g. /orderer1").StringVar(&c.pathPrefix)
+	app.Flag("ca-file", "Path to file containing PEM-encoded TLS CA certificate(s) for the OSN").StringVar(&c.caFile)
+	app.Flag("ca-cert-dir", "Path to a directory of PEM-encoded TLS CA certificates for the OSN, whose *.pem and *.crt files are trusted in addition to --ca-file").StringVar(&c.caCertDir)
+	app.Flag("expect-san", "Subject alternative name, a DNS name, IP address or URI, that the TLS certificate of the OSN must contain, e.g. orderer1.example.com").PlaceHolder("SAN").StringVar(&c.expectSAN)
+	app.Flag("trust-on-first-use", "Path to a file pinning the fingerprint of the OSN TLS certificate, trusted instead of --ca-file; a missing file records the certificate presented on first use").PlaceHolder("PIN-FILE").StringVar(&c.pinFile)
+	app.Flag("client-cert", "Path to file containing PEM-encoded X509 public key to use for mutual TLS communication with the OSN").StringVar(&c.clientCert)
+	app.Flag("client-key", "Path to file containing PEM-encoded private key to use for mutual TLS communication with the OSN").StringVar(&c.clientKey)
+	app.Flag("secret-dir", "Path to a mounted Kubernetes TLS secret, whose ca.crt, tls.crt and tls.key are used instead of --ca-file, --client-cert and --client-key").StringVar(&c.secretDir)
+	app.Flag("pkcs11-lib", "Path to the PKCS#11 library of the token holding the client private key, used instead of --client-key").StringVar(&c.pkcs11Lib)
+	app.Flag("pkcs11-pin", "User PIN of the PKCS#11 token").StringVar(&c.pkcs11Pin)
+	app.Flag("pkcs11-label", "Label of the PKCS#11 token").StringVar(&c.pkcs11Label)
+	app.Flag("no-status", "Remove the HTTP status message from the command output").Default("false").BoolVar(&c.noStatus)
+	app.Flag("output-status-only", "Print only the HTTP status code of the response, and exit with code 1 when it is not a success").Default("false").BoolVar(&c.statusOnly)
+	app.Flag("print-cert", "Print the TLS certificate chain presented by the OSN and exit").Default("false").BoolVar(&c.printCert)
+	app.Flag("output-cert-expiry-warning", "Print a warning when the client certificate expires within this number of days (0 disables the warning)").Default("0").IntVar(&c.certExpiryWarning)
+	app.Flag("retries", "Maximum number of times a failed request is retried").Default("0").IntVar(&c.retries)
+	app.Flag("retry-interval", "Time to wait between retries").Default("1s").DurationVar(&c.retryInterval)
+	app.Flag("user-agent", "User-Agent header sent with every request to the OSN, e.g. to tag osnadmin traffic for a web application firewall").Default("osnadmin/" + metadata.Version).StringVar(&c.userAgent)
+	app.Flag("timeout", "Time allowed for each request to the OSN, including reading the response, e.g. 30s; 0 means no timeout").Default("0").DurationVar(&c.timeout)
+	app.Flag("verbose", "Print the number of attempts of each request to the OSN, and why it was retried, to stderr").Default("false").BoolVar(&c.verbose)
+	app.Flag("retry-on", "Comma separated list of HTTP status codes and network errors (connrefused, connreset, timeout) that are retried; joins, which are not idempotent, are not retried on connreset or timeout").Default(osnadmin.DefaultRetryOn).StringVar(&c.retryOn)
+	app.Flag("format", "Output format of join and list responses: json, template, table or yaml").Default("json").EnumVar(&c.format, "json", "template", "table", "yaml")
+	app.Flag("template", "Go template applied to the channel information of join and list responses when using --format template, e.g. '{{.Height}}'").StringVar(&c.outputTemplate)
+	app.Flag("columns", "Comma separated columns of table output, in the order they appear, e.g. name,height,status").StringVar(&c.tableColumns)
+	app.Flag("no-color", "Do not color the channel status in table output, which is only colored when the output is a terminal").Default("false").BoolVar(&c.noColor)
+	app.Flag("timing", "Print the elapsed time of the operation to stderr").Default("false").BoolVar(&c.timing)
+	app.Flag("explain", "Run the local checks of the orderer address, TLS materials, channel IDs and config block, and print a JSON report of every problem found, without contacting the OSN").Default("false").BoolVar(&c.explain)
+	app.Flag("log-file", "Path to a file that a JSON record of every request sent to the OSN is appended to").StringVar(&c.logFile)
 
 	channel := app.Command("channel", "Channel actions")
 
-	join := channel.Command("join", "Join an Ordering Service Node (OSN) to a channel. If the channel does not yet exist, it will be created.")
-	joinChannelID := join.Flag("channelID", "Channel ID").Short('c').Required().String()
-	configBlockPath := join.Flag("config-block", "Path to the file containing an up-to-date config block for the channel").Short('b').Required().String()
+	c.join = channel.Command("join", "Join an Ordering Service Node (OSN) to a channel. If the channel does not yet exist, it will be created.")
+	c.join.Flag("channelID", "Channel ID (defaults to the channel ID in the config block)").Short('c').StringVar(&c.joinChannelID)
+	c.join.Flag("config-block", "Path to the file containing an up-to-date config block for the channel").Short('b').StringVar(&c.configBlockPath)
+	c.join.Flag("config-block-b64", "Base64 encoding of an up-to-date config block for the channel, instead of using --config-block").StringVar(&c.configBlockB64)
+	c.join.Flag("from-orderer", "Address of an orderer to fetch the latest config block of the channel from, instead of using --config-block").StringVar(&c.fromOrderer)
+	c.join.Flag("from-orderer-ca-file", "Path to file containing PEM-encoded TLS CA certificate(s) for the orderer set by --from-orderer (defaults to --ca-file)").StringVar(&c.fromOrdererCAFile)
+	c.join.Flag("mspID", "MSP ID of the identity that signs the requests to the orderer set by --from-orderer").StringVar(&c.mspID)
+	c.join.Flag("signing-cert", "Path to file containing the PEM-encoded certificate of the identity that signs the requests to the orderer set by --from-orderer").StringVar(&c.signingCert)
+	c.join.Flag("signing-key", "Path to file containing the PEM-encoded private key of the identity that signs the requests to the orderer set by --from-orderer").StringVar(&c.signingKey)
+	c.join.Flag("compress", "Compress the config block upload with gzip, for large blocks over slow links").Default("false").BoolVar(&c.joinCompress)
+	c.join.Flag("dry-run-server", "Send the join to the OSN to be validated only, reporting whether it would succeed without creating the channel; exits with code 1 when it would not").Default("false").BoolVar(&c.joinDryRunServer)
+	c.join.Flag("follow", "After joining, print the status and height of the channel to stderr as it onboards, until it is active; drawn as a progress bar on a terminal").Default("false").BoolVar(&c.joinFollow)
+	c.join.Flag("follow-timeout", "Time allowed for the channel to become active when using --follow").Default("10m").DurationVar(&c.joinFollowTimeout)
+	c.join.Flag("batch-file", "Path to a YAML manifest of the channels to join, each with a channelID and a configBlock path, instead of using --config-block").StringVar(&c.joinBatchFile)
+	c.join.Flag("config-block-field", "Name of the multipart form field used to send the config block").Default(osnadmin.DefaultJoinFieldName).Hidden().StringVar(&c.joinFieldName)
+
+	c.list = channel.Command("list", "List channel information for an Ordering Service Node (OSN). If the channelID flag is set, more detailed information will be provided for that channel.")
+	c.list.Flag("channelID", "Channel ID").Short('c').StringVar(&c.listChannelID)
+	c.list.Flag("since-height", "Only list the channels whose height is at least this number (0 lists every channel)").Default("0").Uint64Var(&c.listSinceHeight)
+
+	c.remove = channel.Command("remove", "Remove an Ordering Service Node (OSN) from a channel.")
+	c.remove.Flag("channelID", "Channel ID").Short('c').StringVar(&c.removeChannelID)
+	// the removal of every channel must be asked for explicitly, never through the environment
+	c.remove.Flag("all", "Remove the OSN from every application channel it has joined").Default("false").NoEnvar().BoolVar(&c.removeAll)
+	c.remove.Flag("force", "Confirm the removal of every channel when using --all").Default("false").NoEnvar().BoolVar(&c.removeForce)
+	c.remove.Flag("include-system-channel", "Remove the system channel first when using --all, which is otherwise refused on an OSN with a system channel").Default("false").NoEnvar().BoolVar(&c.removeSystemChannel)
+
+	c.status = channel.Command("status", "Check the status of a channel of an Ordering Service Node (OSN) once, printing nothing and exiting with code 0 when it matches the expectations, for CI gating.")
+	c.status.Flag("channelID", "Channel ID").Short('c').Required().StringVar(&c.statusChannelID)
+	c.status.Flag("expect", "Expected status of the channel: active, onboarding, inactive, failed or removing").Required().StringVar(&c.statusExpect)
+	c.status.Flag("expect-relation", "Expected consensus relation of the OSN to the channel: consenter, follower, config-tracker or other").StringVar(&c.statusExpectRelation)
+	c.status.Flag("expect-min-height", "Minimum expected height of the channel (0 does not check the height)").Default("0").Uint64Var(&c.statusExpectMinHeight)
+
+	c.setMaintenance = channel.Command("set-maintenance", "Put a channel of an Ordering Service Node (OSN) into maintenance mode, by submitting a config update that sets its consensus state to maintenance.")
+	c.setMaintenance.Flag("channelID", "Channel ID").Short('c').Required().StringVar(&c.setMaintenanceChannelID)
+	c.setMaintenance.Flag("mspID", "MSP ID of the identity that signs the config update").Required().StringVar(&c.mspID)
+	c.setMaintenance.Flag("signing-cert", "Path to file containing the PEM-encoded certificate of the identity that signs the config update, which must satisfy the modification policy of the consensus type").Required().StringVar(&c.signingCert)
+	c.setMaintenance.Flag("signing-key", "Path to file containing the PEM-encoded private key of the identity that signs the config update").Required().StringVar(&c.signingKey)
+
+	c.setNormal = channel.Command("set-normal", "Take a channel of an Ordering Service Node (OSN) out of maintenance mode, by submitting a config update that sets its consensus state to normal.")
+	c.setNormal.Flag("channelID", "Channel ID").Short('c').Required().StringVar(&c.setNormalChannelID)
+	c.setNormal.Flag("mspID", "MSP ID of the identity that signs the config update").Required().StringVar(&c.mspID)
+	c.setNormal.Flag("signing-cert", "Path to file containing the PEM-encoded certificate of the identity that signs the config update, which must satisfy the modification policy of the consensus type").Required().StringVar(&c.signingCert)
+	c.setNormal.Flag("signing-key", "Path to file containing the PEM-encoded private key of the identity that signs the config update").Required().StringVar(&c.signingKey)
+
+	c.diff = channel.Command("diff", "Compare the channels of two Ordering Service Nodes (OSNs), printing the channels only one of them is in, and those with a different consensus relation, or height with --compare-height. The exit code is 1 when they differ, and 2 when they cannot be compared.")
+	c.diff.Flag("orderer", "Admin endpoint of an OSN to compare, set twice").NoEnvar().StringsVar(&c.diffOrderers)
+	c.diff.Flag("compare-height", "Also report the channels with a different height, which differs between active OSNs while blocks are being committed").Default("false").BoolVar(&c.diffCompareHeight)
 
-	list := channel.Command("list", "List channel information for an Ordering Service Node (OSN). If the channelID flag is set, more detailed information will be provided for that channel.")
-	listChannelID := list.Flag("channelID", "Channel ID").Short('c').String()
+	c.doctor = app.Command("doctor", "Check the DNS resolution, TCP connectivity, TLS handshake, client certificate and channel list of an Ordering Service Node (OSN) admin endpoint, and print a report.")
 
-	remove := channel.Command("remove", "Remove an Ordering Service Node (OSN) from a channel.")
-	removeChannelID := remove.Flag("channelID", "Channel ID").Short('c').Required().String()
+	c.probe = app.Command("probe", "Check that the admin endpoint of an Ordering Service Node (OSN) is alive, printing nothing and exiting with code 0 when it is, for liveness probes.")
 
-	command, err := app.Parse(args)
+	c.version = app.Command("version", "Print the version of osnadmin.")
+	c.version.Flag("full", "Also print the commit SHA, build date, Go version and OS/Arch").Default("false").BoolVar(&c.versionFull)
+
+	c.app = app
+	return c
+}
+
+// parse parses the command line and returns the selected command.
+func (c *cli) parse(args []string) (string, error) {
+	args = joinOrdererAddressFile(args)
+	command, err := c.app.Parse(args)
 	if err != nil {
-		return "", 1, err
+		// an unknown command is reported after the usage, which lists the known ones
+		if name, ok := unknownCommand(c.app.Model(), args); ok {
+			c.app.UsageWriter(stderr)
+			c.app.Usage(nil)
+			return "", fmt.Errorf("unknown command %q", name)
+		}
+		return "", err
 	}
+	return command, nil
+}
 
-	//
-	// flag validation
-	//
-	var (
-		osnURL        string
-		caCertPool    *x509.CertPool
-		tlsClientCert tls.Certificate
-	)
-	// TLS enabled
-	if *caFile != "" {
-		osnURL = fmt.Sprintf("https://%s", *orderer)
-		var err error
-		caCertPool = x509.NewCertPool()
-		caFilePEM, err := ioutil.ReadFile(*caFile)
-		if err != nil {
-			return "", 1, fmt.Errorf("reading orderer CA certificate: %s", err)
+// unknownCommand returns the first argument, other than a flag or its value,
+// that is not a command of the application or a subcommand of the command
+// before it.
+func unknownCommand(model *kingpin.ApplicationModel, args []string) (string, bool) {
+	commands := model.Commands
+	flags := model.Flags
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return "", false
+		case strings.HasPrefix(arg, "-"):
+			if flagTakesValue(flags, arg) {
+				i++
+			}
+			continue
 		}
-		if !caCertPool.AppendCertsFromPEM(caFilePEM) {
-			return "", 1, fmt.Errorf("failed to add ca-file PEM to cert pool")
+
+		var command *kingpin.CmdModel
+		for _, cmd := range commands {
+			if cmd.Name == arg || contains(cmd.Aliases, arg) {
+				command = cmd
+				break
+			}
+		}
+		if command == nil {
+			return arg, true
 		}
+		// the arguments of a command without subcommands are not commands
+		if len(command.Commands) == 0 {
+			return "", false
+		}
+		commands = command.Commands
+		flags = append(flags, command.Flags...)
+	}
+	return "", false
+}
 
-		tlsClientCert, err = tls.LoadX509KeyPair(*clientCert, *clientKey)
-		if err != nil {
-			return "", 1, fmt.Errorf("loading client cert/key pair: %s", err)
+// flagTakesValue reports whether arg is a known flag whose value is the next
+// argument, rather than being set with = or being a boolean flag.
+func flagTakesValue(flags []*kingpin.FlagModel, arg string) bool {
+	if strings.Contains(arg, "=") {
+		return false
+	}
+	for _, flag := range flags {
+		switch {
+		case strings.HasPrefix(arg, "--") && arg[2:] == flag.Name:
+		case !strings.HasPrefix(arg, "--") && flag.Short != 0 && arg == "-"+string(flag.Short):
+		default:
+			continue
 		}
-	} else { // TLS disabled
-		osnURL = fmt.Sprintf("http://%s", *orderer)
+		return !flag.IsBoolFlag()
 	}
+	return false
+}
 
-	var marshaledConfigBlock []byte
-	if *configBlockPath != "" {
-		marshaledConfigBlock, err = ioutil.ReadFile(*configBlockPath)
-		if err != nil {
-			return "", 1, fmt.Errorf("reading config block: %s", err)
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
 		}
+	}
+	return false
+}
 
-		err = validateBlockChannelID(marshaledConfigBlock, *joinChannelID)
-		if err != nil {
-			return "", 1, err
+// explainOutput runs the local checks of --explain.
+func (c *cli) explainOutput(command string, ordererAddressErr error) (string, int, error) {
+	ordererFlag, ordererAddresses := "--orderer-address", []string{c.orderer}
+	if command == c.diff.FullCommand() {
+		ordererFlag, ordererAddresses, ordererAddressErr = "--orderer", c.diffOrderers, nil
+	}
+	return explainOutput(command, explainProblems(explainInput{
+		ordererFlag:       ordererFlag,
+		ordererAddresses:  ordererAddresses,
+		ordererAddressErr: ordererAddressErr,
+		caFile:            c.caFile,
+		caCertDir:         c.caCertDir,
+		pinFile:           c.pinFile,
+		clientCert:        c.clientCert,
+		clientKey:         c.clientKey,
+		secretDir:         c.secretDir,
+		pkcs11Lib:         c.pkcs11Lib,
+		expectSAN:         c.expectSAN,
+		channelIDs:        c.channelIDs(),
+		configBlockPath:   c.configBlockPath,
+		configBlockB64:    c.configBlockB64,
+		joinChannelID:     c.joinChannelID,
+	}, time.Now()))
+}
+
+// channelIDs returns the values of the --channelID flags of every command.
+func (c *cli) channelIDs() []string {
+	return []string{c.joinChannelID, c.listChannelID, c.removeChannelID, c.setMaintenanceChannelID, c.setNormalChannelID, c.statusChannelID}
+}
+
+// checkConnectionFlags checks the flags that select the OSN and how to
+// connect to it. A Kubernetes TLS secret set by --secret-dir is expanded into
+// the TLS flags.
+func (c *cli) checkConnectionFlags(command string, ordererAddressErr error) error {
+	// channel diff is sent to the two OSNs set by --orderer instead
+	if command == c.diff.FullCommand() {
+		switch {
+		case len(c.diffOrderers) != 2:
+			return fmt.Errorf("%s requires --orderer exactly twice", command)
+		case c.pinFile != "":
+			return fmt.Errorf("--trust-on-first-use is not supported by %s", command)
+		case c.printCert:
+			return fmt.Errorf("--print-cert is not supported by %s", command)
+		case c.format != "json":
+			return fmt.Errorf("--format %s is not supported by %s", c.format, command)
+		case c.statusOnly:
+			return fmt.Errorf("--output-status-only is not supported by %s", command)
 		}
+	} else if ordererAddressErr != nil {
+		return ordererAddressErr
+	} else if c.orderer == "" {
+		return fmt.Errorf("required flag --orderer-address not provided")
 	}
 
-	//
-	// call the underlying implementations
-	//
-	var resp *http.Response
+	if c.timeout < 0 {
+		return fmt.Errorf("--timeout must not be negative, use 0 for no timeout")
+	}
 
-	switch command {
-	case join.FullCommand():
-		resp, err = osnadmin.Join(osnURL, marshaledConfigBlock, caCertPool, tlsClientCert)
-	case list.FullCommand():
-		if *listChannelID != "" {
-			resp, err = osnadmin.ListSingleChannel(osnURL, *listChannelID, caCertPool, tlsClientCert)
-			break
+	// a Kubernetes TLS secret mounts its keys as files named after them
+	if c.secretDir != "" {
+		if c.caFile != "" || c.clientCert != "" || c.clientKey != "" {
+			return fmt.Errorf("--secret-dir cannot be combined with --ca-file, --client-cert or --client-key")
 		}
-		resp, err = osnadmin.ListAllChannels(osnURL, caCertPool, tlsClientCert)
-	case remove.FullCommand():
-		resp, err = osnadmin.Remove(osnURL, *removeChannelID, caCertPool, tlsClientCert)
+		c.caFile = filepath.Join(c.secretDir, "ca.crt")
+		c.clientCert = filepath.Join(c.secretDir, "tls.crt")
+		c.clientKey = filepath.Join(c.secretDir, "tls.key")
+	}
+
+	if c.caFile != "" && c.pinFile != "" {
+		return fmt.Errorf("--ca-file and --trust-on-first-use are mutually exclusive")
+	}
+	if c.caCertDir != "" && c.pinFile != "" {
+		return fmt.Errorf("--ca-cert-dir and --trust-on-first-use are mutually exclusive")
+	}
+	if !c.tlsEnabled() && c.expectSAN != "" {
+		return fmt.Errorf("--expect-san requires TLS, use --ca-file, --ca-cert-dir or --trust-on-first-use")
+	}
+	return nil
+}
+
+// tlsEnabled reports whether the OSN is connected to with TLS.
+func (c *cli) tlsEnabled() bool {
+	return c.caFile != "" || c.caCertDir != "" || c.pinFile != ""
+}
+
+// connection carries what every request to the OSN is sent with.
+type connection struct {
+	osnURL        string
+	caCertPool    *x509.CertPool
+	tlsClientCert tls.Certificate
+	clientOpts    osnadmin.ClientOptions
+}
+
+// connection loads the TLS materials of the OSN, if any.
+func (c *cli) connection() (connection, error) {
+	// a zero timeout is a deliberate opt out of the client timeout, e.g. for
+	// joins with huge config blocks, rather than a timeout of zero length
+	conn := connection{
+		clientOpts: osnadmin.ClientOptions{
+			Timeout:     c.timeout,
+			ExpectedSAN: c.expectSAN,
+			UserAgent:   c.userAgent,
+		},
 	}
+
+	// TLS disabled
+	if !c.tlsEnabled() {
+		conn.osnURL = osnadmin.OSNURL("http", c.orderer, c.pathPrefix)
+		return conn, nil
+	}
+
+	conn.osnURL = osnadmin.OSNURL("https", c.orderer, c.pathPrefix)
+	var err error
+	if conn.caCertPool, err = loadCACertPool(c.caFile, c.caCertDir); err != nil {
+		return connection{}, err
+	}
+	conn.tlsClientCert, err = loadClientCertificate(c.clientCert, c.clientKey, osnadmin.PKCS11Opts{
+		Library: c.pkcs11Lib,
+		Pin:     c.pkcs11Pin,
+		Label:   c.pkcs11Label,
+	})
 	if err != nil {
-		return errorOutput(err), 1, nil
+		return connection{}, err
+	}
+	if c.certExpiryWarning > 0 {
+		if err := warnCertExpiry(conn.tlsClientCert, c.certExpiryWarning, time.Now()); err != nil {
+			return connection{}, err
+		}
 	}
+	return conn, nil
+}
 
-	bodyBytes, err := readBodyBytes(resp.Body)
+// pinCertificate trusts the TLS certificate of the OSN pinned in the file of
+// --trust-on-first-use, pinning the one presented when the file is missing.
+func (c *cli) pinCertificate(conn *connection) error {
+	caCertPool, pinned, err := osnadmin.PinnedCertPool(c.orderer, c.pinFile, conn.tlsClientCert)
 	if err != nil {
-		return errorOutput(err), 1, nil
+		return err
+	}
+	if pinned {
+		fmt.Fprintf(stderr, "Note: trusting the TLS certificate of the OSN on first use, its fingerprint is pinned in %s\n", c.pinFile)
+	}
+	conn.caCertPool = caCertPool
+	return nil
+}
+
+// loadCACertPool returns the pool of the CA certificates of --ca-file and
+// --ca-cert-dir, or nil when neither is set.
+func loadCACertPool(caFile, caCertDir string) (*x509.CertPool, error) {
+	if caFile == "" && caCertDir == "" {
+		return nil, nil
 	}
+	caCertPool := x509.NewCertPool()
+	if caFile != "" {
+		caFilePEM, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading orderer CA certificate: %s", err)
+		}
+		if !caCertPool.AppendCertsFromPEM(caFilePEM) {
+			return nil, fmt.Errorf("failed to add ca-file PEM to cert pool")
+		}
+	}
+	if caCertDir != "" {
+		if err := osnadmin.AppendCertsFromDir(caCertPool, caCertDir); err != nil {
+			return nil, fmt.Errorf("loading --ca-cert-dir: %s", err)
+		}
+	}
+	return caCertPool, nil
+}
 
-	output, err = responseOutput(!*noStatus, resp.StatusCode, bodyBytes)
+// loadClientCertificate loads the client certificate of mutual TLS, with the
+// private key of --client-key, or of the PKCS#11 token when --pkcs11-lib is
+// set.
+func loadClientCertificate(certFile, keyFile string, pkcs11Opts osnadmin.PKCS11Opts) (tls.Certificate, error) {
+	if pkcs11Opts.Library != "" {
+		cert, err := osnadmin.PKCS11ClientCertificate(certFile, pkcs11Opts)
+		if err != nil {
+			return tls.Certificate{}, fmt.Errorf("loading client cert/PKCS#11 key: %s", err)
+		}
+		return cert, nil
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
 	if err != nil {
-		return errorOutput(err), 1, nil
+		return tls.Certificate{}, fmt.Errorf("loading client cert/key pair: %s", err)
 	}
+	return cert, nil
+}
 
-	return output, 0, nil
+// retryPolicy returns the policy that the requests to the OSN are retried
+// with.
+func (c *cli) retryPolicy() (osnadmin.RetryPolicy, error) {
+	if c.retries < 0 {
+		return osnadmin.RetryPolicy{}, fmt.Errorf("--retries must not be negative")
+	}
+	retryPolicy, err := osnadmin.NewRetryPolicy(c.retries, c.retryInterval, c.retryOn)
+	if err != nil {
+		return osnadmin.RetryPolicy{}, fmt.Errorf("parsing --retry-on: %s", err)
+	}
+	if c.verbose {
+		retryPolicy.Report = printAttempts
+	}
+	return retryPolicy, nil
 }
 
-func responseOutput(showStatus bool, statusCode int, responseBody []byte) (string, error) {
-	var buffer bytes.Buffer
-	if showStatus {
-		fmt.Fprintf(&buffer, "Status: %d\n", statusCode)
+// outputOptions carries how the response of a request is printed.
+type outputOptions struct {
+	format     string
+	tmpl       *template.Template
+	columns    []tableColumn
+	showStatus bool
+	statusOnly bool
+	color      bool
+}
+
+// outputOptions checks that the output format is supported by the command,
+// and parses the template and the table columns, if any.
+func (c *cli) outputOptions(command string) (outputOptions, error) {
+	// the commands that respond with no channel information, which cannot
+	// be rendered with a template, as a table or as YAML
+	noChannelInfo := command == c.remove.FullCommand() || command == c.setMaintenance.FullCommand() || command == c.setNormal.FullCommand() || command == c.status.FullCommand()
+	joinBatch := command == c.join.FullCommand() && c.joinBatchFile != ""
+
+	out := outputOptions{
+		format:     c.format,
+		showStatus: !c.noStatus,
+		statusOnly: c.statusOnly,
+		color:      !c.noColor && isTerminal(),
 	}
-	if len(responseBody) != 0 {
-		if err := json.Indent(&buffer, responseBody, "", "\t"); err != nil {
-			return "", err
+	switch {
+	case c.format == "template" && c.outputTemplate == "":
+		return outputOptions{}, fmt.Errorf("--format template requires --template")
+	case c.format != "template" && c.outputTemplate != "":
+		return outputOptions{}, fmt.Errorf("--template requires --format template")
+	case c.format != "json" && noChannelInfo:
+		return outputOptions{}, fmt.Errorf("--format %s is not supported by %s", c.format, command)
+	case c.format != "json" && joinBatch:
+		return outputOptions{}, fmt.Errorf("--format %s is not supported by --batch-file", c.format)
+	case c.format != "json" && c.statusOnly:
+		return outputOptions{}, fmt.Errorf("--format %s and --output-status-only are mutually exclusive", c.format)
+	case c.format == "template":
+		var err error
+		if out.tmpl, err = template.New("output").Parse(c.outputTemplate); err != nil {
+			return outputOptions{}, fmt.Errorf("parsing --template: %s", err)
 		}
 	}
-	return buffer.String(), nil
+
+	if c.tableColumns == "" {
+		return out, nil
+	}
+	if c.format != "table" {
+		return outputOptions{}, fmt.Errorf("--columns requires --format table")
+	}
+	// a channel list is only rendered when listing every channel, the
+	// other commands render the information of a single channel
+	available := channelInfoColumns
+	if command == c.list.FullCommand() && c.listChannelID == "" {
+		available = channelListColumns
+	}
+	var err error
+	if out.columns, err = selectColumns(available, c.tableColumns); err != nil {
+		return outputOptions{}, fmt.Errorf("parsing --columns: %s", err)
+	}
+	return out, nil
 }
 
-func readBodyBytes(body io.ReadCloser) ([]byte, error) {
-	bodyBytes, err := ioutil.ReadAll(body)
+// checkCommandFlags checks the flags of the command, and returns the parsed
+// expectations of channel status.
+func (c *cli) checkCommandFlags(command string) (channelExpectation, error) {
+	if command == c.join.FullCommand() {
+		if err := c.checkJoinFlags(); err != nil {
+			return channelExpectation{}, err
+		}
+	}
+
+	if c.statusOnly && (c.joinBatchFile != "" || c.listSinceHeight > 0 || c.removeAll) {
+		return channelExpectation{}, fmt.Errorf("--output-status-only cannot be combined with --batch-file, --since-height or --all")
+	}
+
+	var expectation channelExpectation
+	switch command {
+	case c.list.FullCommand():
+		if c.listSinceHeight > 0 && c.listChannelID != "" {
+			return channelExpectation{}, fmt.Errorf("--channelID and --since-height are mutually exclusive")
+		}
+	case c.remove.FullCommand():
+		switch {
+		case c.removeAll && c.removeChannelID != "":
+			return channelExpectation{}, fmt.Errorf("--channelID and --all are mutually exclusive")
+		case c.removeAll && !c.removeForce:
+			return channelExpectation{}, fmt.Errorf("--all requires --force")
+		case !c.removeAll && c.removeChannelID == "":
+			return channelExpectation{}, fmt.Errorf("required flag --channelID not provided")
+		}
+	case c.status.FullCommand():
+		if c.statusOnly {
+			return channelExpectation{}, fmt.Errorf("--output-status-only is not supported by %s", command)
+		}
+		var err error
+		if expectation, err = parseChannelExpectation(c.statusExpect, c.statusExpectRelation, c.statusExpectMinHeight); err != nil {
+			return channelExpectation{}, err
+		}
+	}
+
+	// catch a mistyped channel ID before it is sent to the OSN
+	for _, channelID := range c.channelIDs() {
+		if channelID == "" {
+			continue
+		}
+		if err := configtx.ValidateChannelID(channelID); err != nil {
+			return channelExpectation{}, fmt.Errorf("invalid --channelID: %s", err)
+		}
+	}
+	return expectation, nil
+}
+
+// checkJoinFlags checks that the flags of channel join set exactly one source
+// of the config block, and are supported by that source.
+func (c *cli) checkJoinFlags() error {
+	if c.joinFollow {
+		switch {
+		case c.joinBatchFile != "":
+			return fmt.Errorf("--follow is not supported by --batch-file")
+		case c.statusOnly:
+			return fmt.Errorf("--follow and --output-status-only are mutually exclusive")
+		case c.joinFollowTimeout <= 0:
+			return fmt.Errorf("--follow-timeout must be positive")
+		}
+	}
+
+	switch {
+	case c.joinBatchFile != "" && (c.configBlockPath != "" || c.configBlockB64 != "" || c.fromOrderer != "" || c.joinChannelID != ""):
+		return fmt.Errorf("--batch-file cannot be combined with --config-block, --config-block-b64, --from-orderer or --channelID")
+	case c.joinBatchFile != "" && c.joinDryRunServer:
+		return fmt.Errorf("--dry-run-server is not supported by --batch-file")
+	case c.joinBatchFile != "":
+	case c.configBlockPath != "" && c.configBlockB64 != "":
+		return fmt.Errorf("--config-block and --config-block-b64 are mutually exclusive")
+	case c.configBlockPath != "" && c.fromOrderer != "":
+		return fmt.Errorf("--config-block and --from-orderer are mutually exclusive")
+	case c.configBlockB64 != "" && c.fromOrderer != "":
+		return fmt.Errorf("--config-block-b64 and --from-orderer are mutually exclusive")
+	case c.configBlockPath == "" && c.configBlockB64 == "" && c.fromOrderer == "":
+		return fmt.Errorf("required flag --config-block, --config-block-b64, --from-orderer or --batch-file not provided")
+	case c.fromOrderer != "" && (c.mspID == "" || c.signingCert == "" || c.signingKey == ""):
+		return fmt.Errorf("--from-orderer requires --mspID, --signing-cert and --signing-key")
+	case c.fromOrderer != "" && c.joinChannelID == "":
+		return fmt.Errorf("--from-orderer requires --channelID")
+	case c.joinDryRunServer && c.joinFollow:
+		return fmt.Errorf("--dry-run-server and --follow are mutually exclusive")
+	}
+	return nil
+}
+
+// parseChannelExpectation parses the expectations of channel status.
+func parseChannelExpectation(status, relation string, minHeight uint64) (channelExpectation, error) {
+	expectation := channelExpectation{minHeight: minHeight}
+	var err error
+	if expectation.status, err = types.ParseStatus(status); err != nil {
+		return channelExpectation{}, fmt.Errorf("parsing --expect: %s", err)
+	}
+	if relation != "" {
+		if expectation.relation, err = types.ParseConsensusRelation(relation); err != nil {
+			return channelExpectation{}, fmt.Errorf("parsing --expect-relation: %s", err)
+		}
+	}
+	return expectation, nil
+}
+
+// readConfigBlock reads the config block of channel join set by
+// --config-block or --config-block-b64, if any, and returns it with its
+// channel ID.
+func readConfigBlock(path, b64, channelID string) ([]byte, string, error) {
+	var (
+		blockBytes []byte
+		err        error
+	)
+	switch {
+	case path != "":
+		blockBytes, err = ioutil.ReadFile(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("reading config block: %s", err)
+		}
+	case b64 != "":
+		// a block pasted on the command line may carry stray whitespace
+		blockBytes, err = base64.StdEncoding.DecodeString(strings.TrimSpace(b64))
+		if err != nil {
+			return nil, "", fmt.Errorf("decoding --config-block-b64: %s", err)
+		}
+	default:
+		return nil, "", nil
+	}
+
+	blockChannelID, err := channelIDFromBlock(blockBytes)
 	if err != nil {
-		return nil, fmt.Errorf("reading http response body: %s", err)
+		return nil, "", err
 	}
-	body.Close()
+	// quick sanity check that the orderer admin is joining
+	// the channel they think they're joining. When --channelID
+	// is omitted, the channel ID in the block is used as is.
+	if channelID != "" && channelID != blockChannelID {
+		return nil, "", fmt.Errorf("specified --channelID %s does not match channel ID %s in config block", channelID, blockChannelID)
+	}
+	return blockBytes, blockChannelID, nil
+}
 
-	return bodyBytes, nil
+// runner runs a channel command once its flags are checked.
+type runner struct {
+	*cli
+	command     string
+	conn        connection
+	retryPolicy osnadmin.RetryPolicy
+	output      outputOptions
+	expectation channelExpectation
+	// the config block of channel join, and the channel ID in it
+	configBlock    []byte
+	blockChannelID string
+	opLog          *operationLog
+	logWriter      io.Writer
 }
 
-func errorOutput(err error) string {
-	return fmt.Sprintf("Error: %s\n", err)
+// run calls the underlying implementation of the command.
+func (r *runner) run() (string, int, error) {
+	switch r.command {
+	case r.join.FullCommand():
+		return r.runJoin()
+	case r.list.FullCommand():
+		return r.runList()
+	case r.remove.FullCommand():
+		return r.runRemove()
+	case r.status.FullCommand():
+		return r.runStatus()
+	case r.setMaintenance.FullCommand():
+		return r.runSetConsensusState(r.setMaintenanceChannelID, types.ConsensusStateMaintenance)
+	case r.setNormal.FullCommand():
+		return r.runSetConsensusState(r.setNormalChannelID, types.ConsensusStateNormal)
+	case r.diff.FullCommand():
+		return r.runDiff()
+	}
+	return "", 1, fmt.Errorf("unsupported command %s", r.command)
 }
 
-func validateBlockChannelID(blockBytes []byte, channelID string) error {
-	block := &common.Block{}
-	err := proto.Unmarshal(blockBytes, block)
+func (r *runner) runJoin() (string, int, error) {
+	if r.joinBatchFile != "" {
+		manifest, err := readJoinManifest(r.joinBatchFile)
+		if err != nil {
+			return "", 1, err
+		}
+		output, exit := r.joinBatch(manifest)
+		return output, exit, nil
+	}
+
+	configBlock := r.configBlock
+	if r.fromOrderer != "" {
+		var err error
+		configBlock, err = r.fetchConfigBlock()
+		if err != nil {
+			return errorOutput(err), 1, nil
+		}
+	}
+	channelID := r.joinChannelID
+	if channelID == "" {
+		channelID = r.blockChannelID
+	}
+
+	// a join is not idempotent, so it is only retried when it did not
+	// reach the OSN
+	statusCode, bodyBytes, err := r.send(channelID, r.retryPolicy.NonIdempotent(), func() (*http.Response, error) {
+		return osnadmin.JoinWithOptions(r.conn.osnURL, configBlock, osnadmin.JoinOptions{
+			FieldName: r.joinFieldName,
+			Compress:  r.joinCompress,
+			DryRun:    r.joinDryRunServer,
+		}, r.conn.caCertPool, r.conn.tlsClientCert, r.conn.clientOpts)
+	})
 	if err != nil {
-		return fmt.Errorf("unmarshalling block: %s", err)
+		return errorOutput(err), 1, nil
+	}
+
+	if statusCode == http.StatusCreated {
+		printRestartNote(bodyBytes)
+	}
+
+	// an OSN that does not support dry runs ignores the dryRun query, and
+	// joins the channel
+	dryRunIgnored := r.joinDryRunServer && statusCode == http.StatusCreated
+
+	if r.output.statusOnly {
+		return statusOnlyOutput(statusCode, dryRunIgnored)
 	}
 
-	blockChannelID, err := protoutil.GetChannelIDFromBlock(block)
+	output, err := r.output.render(statusCode, bodyBytes, &types.ChannelInfo{})
 	if err != nil {
-		return err
+		return errorOutput(err), 1, nil
 	}
 
-	// quick sanity check that the orderer admin is joining
-	// the channel they think they're joining.
-	if channelID != blockChannelID {
-		return fmt.Errorf("specified --channelID %s does not match channel ID %s in config block", channelID, blockChannelID)
+	// a dry run reports whether the join would succeed with the exit code
+	success := isSuccess(statusCode)
+	switch {
+	case dryRunIgnored:
+		return output + errorOutput(fmt.Errorf("the OSN does not support --dry-run-server and joined channel %s", channelID)), 1, nil
+	case r.joinDryRunServer && !success:
+		return output, 1, nil
 	}
 
-	return nil
+	// the channel is followed once it is joined, so that the join response
+	// is printed even when the channel fails to become active
+	if r.joinFollow && success {
+		if err := r.followChannel(channelID); err != nil {
+			return output + errorOutput(err), 1, nil
+		}
+	}
+
+	return output, 0, nil
+}
+
+func (r *runner) runList() (string, int, error) {
+	if r.listSinceHeight > 0 {
+		start := time.Now()
+		bodyBytes, err := r.listChannelsSinceHeight()
+		r.printElapsed(start)
+		if err != nil {
+			return errorOutput(err), 1, nil
+		}
+		output, err := r.output.render(http.StatusOK, bodyBytes, &types.ChannelList{})
+		if err != nil {
+			return errorOutput(err), 1, nil
+		}
+		return output, 0, nil
+	}
+
+	if r.listChannelID != "" {
+		return r.runRequest(r.listChannelID, &types.ChannelInfo{}, func() (*http.Response, error) {
+			return osnadmin.ListSingleChannel(r.conn.osnURL, r.listChannelID, r.conn.caCertPool, r.conn.tlsClientCert, r.conn.clientOpts)
+		})
+	}
+	return r.runRequest("", &types.ChannelList{}, func() (*http.Response, error) {
+		return osnadmin.ListAllChannels(r.conn.osnURL, r.conn.caCertPool, r.conn.tlsClientCert, r.conn.clientOpts)
+	})
+}
+
+func (r *runner) runRemove() (string, int, error) {
+	if r.removeAll {
+		start := time.Now()
+		output, exit, err := r.removeAllChannels()
+		r.printElapsed(start)
+		if err != nil {
+			return errorOutput(err), 1, nil
+		}
+		return output, exit, nil
+	}
+	return r.runRequest(r.removeChannelID, nil, func() (*http.Response, error) {
+		return osnadmin.Remove(r.conn.osnURL, r.removeChannelID, r.conn.caCertPool, r.conn.tlsClientCert, r.conn.clientOpts)
+	})
+}
+
+func (r *runner) runStatus() (string, int, error) {
+	start := time.Now()
+	info, err := r.listChannel(r.statusChannelID)
+	r.printElapsed(start)
+	if err != nil {
+		return errorOutput(err), 1, nil
+	}
+	if err := r.expectation.check(r.statusChannelID, info); err != nil {
+		return errorOutput(err), 1, nil
+	}
+	return "", 0, nil
+}
+
+func (r *runner) runDiff() (string, int, error) {
+	start := time.Now()
+	output, exit, err := r.diffChannels()
+	r.printElapsed(start)
+	if err != nil {
+		return errorOutput(err), 2, nil
+	}
+	return output, exit, nil
+}
+
+// runRequest sends a single request about a channel, if any, and prints its
+// response. The body of a successful response is decoded into responseModel
+// for template, table and YAML output.
+func (r *runner) runRequest(channelID string, responseModel interface{}, request func() (*http.Response, error)) (string, int, error) {
+	statusCode, bodyBytes, err := r.send(channelID, r.retryPolicy, request)
+	if err != nil {
+		return errorOutput(err), 1, nil
+	}
+	if r.output.statusOnly {
+		return statusOnlyOutput(statusCode, false)
+	}
+	output, err := r.output.render(statusCode, bodyBytes, responseModel)
+	if err != nil {
+		return errorOutput(err), 1, nil
+	}
+	return output, 0, nil
+}
+
+// send sends a request with the retry policy, records it in the operation
+// log, and returns the status code and body of the response.
+func (r *runner) send(channelID string, retryPolicy osnadmin.RetryPolicy, request func() (*http.Response, error)) (int, []byte, error) {
+	start := time.Now()
+	resp, err := osnadmin.Retry(retryPolicy, request)
+	r.printElapsed(start)
+	if err != nil {
+		r.opLog.record(r.command, channelID, start, 0, err)
+		return 0, nil, err
+	}
+
+	bodyBytes, err := readBodyBytes(resp.Body)
+	if err != nil {
+		r.opLog.record(r.command, channelID, start, resp.StatusCode, err)
+		return 0, nil, err
+	}
+	r.opLog.record(r.command, channelID, start, resp.StatusCode, responseError(resp.StatusCode, bodyBytes))
+	return resp.StatusCode, bodyBytes, nil
+}
+
+// printElapsed prints the time elapsed since start when --timing is set.
+func (r *runner) printElapsed(start time.Time) {
+	if r.timing {
+		printElapsed(start)
+	}
+}
+
+// render prints a response. Error responses are not rendered with the
+// template, as a table or as YAML, so that the error is not lost.
+func (o outputOptions) render(statusCode int, responseBody []byte, responseModel interface{}) (string, error) {
+	success := isSuccess(statusCode)
+	switch {
+	case o.tmpl != nil && success:
+		return templateOutput(o.tmpl, responseBody, responseModel)
+	case o.format == "table" && success:
+		return tableOutput(responseBody, responseModel, o.columns, o.color)
+	case o.format == "yaml" && success:
+		return yamlOutput(responseBody, responseModel)
+	default:
+		return responseOutput(o.showStatus, statusCode, responseBody)
+	}
+}
+
+// statusOnlyOutput prints just the status code of --output-status-only, and
+// exits with 1 when it is not a success or fail is set.
+func statusOnlyOutput(statusCode int, fail bool) (string, int, error) {
+	if !isSuccess(statusCode) || fail {
+		return fmt.Sprintf("%d\n", statusCode), 1, nil
+	}
+	return fmt.Sprintf("%d\n", statusCode), 0, nil
+}
+
+func isSuccess(statusCode int) bool {
+	return statusCode >= 200 && statusCode < 300
+}
+
+// followReconnectDelay is the time waited before reconnecting to an event
+// stream that ended before the channel became active.
+var followReconnectDelay = time.Second
+
+// errEventStreamEnded is returned by followEvents when the event stream ends
+// before the channel is active, e.g. when the OSN closes the connection.
+var errEventStreamEnded = errors.New("event stream ended")
+
+// followChannel prints the status and height of a channel to stderr, as
+// reported by the event stream of the OSN, until the channel is active. It
+// reconnects when the stream ends early, and fails when the channel ends up
+// in another status, or --follow-timeout elapses.
+func (r *runner) followChannel(channelID string) error {
+	timeout := r.joinFollowTimeout
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	progress := &progressPrinter{w: stderr, terminal: stderrIsTerminal()}
+	defer progress.end()
+
+	for {
+		err := r.followEvents(ctx, channelID, progress)
+		switch {
+		case ctx.Err() != nil:
+			return fmt.Errorf("channel %s is not active after %s", channelID, timeout)
+		case err != errEventStreamEnded:
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("channel %s is not active after %s", channelID, timeout)
+		case <-time.After(followReconnectDelay):
+		}
+	}
+}
+
+// followEvents prints the progress events of a single event stream of the
+// channel, until the channel is active, in another status, or the stream ends.
+func (r *runner) followEvents(ctx context.Context, channelID string, progress *progressPrinter) error {
+	resp, err := osnadmin.ChannelEvents(ctx, r.conn.osnURL, channelID, r.conn.caCertPool, r.conn.tlsClientCert, r.conn.clientOpts)
+	if err != nil {
+		return fmt.Errorf("following channel %s: %s", channelID, err)
+	}
+	defer resp.Body.Close()
+	if err := osnadmin.CheckResponse(resp); err != nil {
+		return fmt.Errorf("following channel %s: %s", channelID, err)
+	}
+
+	reader := osnadmin.NewEventReader(resp.Body)
+	for {
+		event, err := reader.Next()
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case err == io.EOF, err == io.ErrUnexpectedEOF:
+			// e.g. the write timeout of the OSN closed the stream
+			return errEventStreamEnded
+		case err != nil:
+			return fmt.Errorf("reading the event stream of channel %s: %s", channelID, err)
+		}
+
+		switch event.Name {
+		case "progress":
+			info := &types.ChannelInfo{}
+			if err := json.Unmarshal([]byte(event.Data), info); err != nil {
+				return fmt.Errorf("decoding the event stream of channel %s: %s", channelID, err)
+			}
+			progress.print(channelID, info)
+			switch info.Status {
+			case types.StatusActive:
+				return nil
+			case types.StatusOnBoarding:
+			default:
+				return fmt.Errorf("channel %s is %s", channelID, info.Status)
+			}
+		case "error":
+			return fmt.Errorf("following channel %s: %s", channelID, responseError(http.StatusInternalServerError, []byte(event.Data)))
+		}
+	}
+}
+
+// progressBarWidth is the number of cells of the progress bar of --follow.
+const progressBarWidth = 30
+
+// progressPrinter prints the progress of a followed channel. On a terminal,
+// an onboarding channel whose target height is known is drawn as a progress
+// bar, redrawn in place as the height grows. Otherwise every update is a line
+// of plain text, so that logs and pipes are free of escape codes.
+type progressPrinter struct {
+	w        io.Writer
+	terminal bool
+	// whether the progress bar is drawn on the current line
+	drawn bool
+}
+
+func (p *progressPrinter) print(channelID string, info *types.ChannelInfo) {
+	target := uint64(0)
+	if info.TargetHeight != nil {
+		target = *info.TargetHeight
+	}
+
+	if p.terminal && info.Status == types.StatusOnBoarding && target > 0 {
+		// return to the start of the line and clear it before redrawing
+		fmt.Fprintf(p.w, "\r\x1b[KChannel %s: %s %s %d/%d", channelID, info.Status, progressBar(info.Height, target, progressBarWidth), info.Height, target)
+		p.drawn = true
+		return
+	}
+
+	p.end()
+	if target > 0 {
+		fmt.Fprintf(p.w, "Channel %s: %s, height %d of %d\n", channelID, info.Status, info.Height, target)
+		return
+	}
+	fmt.Fprintf(p.w, "Channel %s: %s, height %d\n", channelID, info.Status, info.Height)
+}
+
+// end terminates the line of the progress bar, if one is drawn, so that what
+// follows is not written over it.
+func (p *progressPrinter) end() {
+	if p.drawn {
+		fmt.Fprintln(p.w)
+		p.drawn = false
+	}
+}
+
+// progressBar renders height out of target as a bar of width cells, followed
+// by the percentage done.
+func progressBar(height, target uint64, width int) string {
+	if height > target {
+		height = target
+	}
+	filled := int(height * uint64(width) / target)
+	return fmt.Sprintf("[%s%s] %3d%%", strings.Repeat("#", filled), strings.Repeat("-", width-filled), height*100/target)
+}
+
+// channelExpectation is what channel status expects of a channel. The zero
+// value of a field is not checked, except for the status.
+type channelExpectation struct {
+	status    types.Status
+	relation  types.ConsensusRelation
+	minHeight uint64
+}
+
+// check returns the first mismatch between the channel and the expectation,
+// or nil when it matches. A channel that does not exist never matches.
+func (e channelExpectation) check(channelID string, info *types.ChannelInfo) error {
+	switch {
+	case info == nil:
+		return fmt.Errorf("channel %s does not exist", channelID)
+	case info.Status != e.status:
+		return fmt.Errorf("channel %s status is %s, expected %s", channelID, info.Status, e.status)
+	case e.relation != "" && info.ConsensusRelation != e.relation:
+		return fmt.Errorf("channel %s consensus relation is %s, expected %s", channelID, info.ConsensusRelation, e.relation)
+	case info.Height < e.minHeight:
+		return fmt.Errorf("channel %s height is %d, expected at least %d", channelID, info.Height, e.minHeight)
+	}
+	return nil
+}
+
+// runSetConsensusState switches the channel to the consensus state with a
+// config update signed by the identity set by --signing-cert and --signing-key.
+func (r *runner) runSetConsensusState(channelID, state string) (string, int, error) {
+	updateSigner, err := signer.NewSigner(signer.Config{
+		MSPID:        r.mspID,
+		IdentityPath: r.signingCert,
+		KeyPath:      r.signingKey,
+	})
+	if err != nil {
+		return errorOutput(fmt.Errorf("loading signing identity: %s", err)), 1, nil
+	}
+	return r.runRequest(channelID, nil, func() (*http.Response, error) {
+		return osnadmin.SetConsensusState(r.conn.osnURL, channelID, state, updateSigner, r.conn.caCertPool, r.conn.tlsClientCert, r.conn.clientOpts)
+	})
+}
+
+// fetchConfigBlock fetches the latest config block of the channel to join
+// from the deliver service of the source orderer of --from-orderer. The TLS CA
+// of the source orderer defaults to the one of the target orderer.
+func (r *runner) fetchConfigBlock() ([]byte, error) {
+	caCertPool := r.conn.caCertPool
+	if r.fromOrdererCAFile != "" {
+		caFilePEM, err := ioutil.ReadFile(r.fromOrdererCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading source orderer CA certificate: %s", err)
+		}
+		caCertPool = x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(caFilePEM) {
+			return nil, fmt.Errorf("failed to add from-orderer-ca-file PEM to cert pool")
+		}
+	}
+
+	deliverSigner, err := signer.NewSigner(signer.Config{
+		MSPID:        r.mspID,
+		IdentityPath: r.signingCert,
+		KeyPath:      r.signingKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("loading signing identity: %s", err)
+	}
+
+	block, err := osnadmin.FetchConfigBlock(r.fromOrderer, r.joinChannelID, deliverSigner, caCertPool, r.conn.tlsClientCert)
+	if err != nil {
+		return nil, fmt.Errorf("fetching config block from %s: %s", r.fromOrderer, err)
+	}
+
+	return proto.Marshal(block)
+}
+
+// removeAllChannels lists the channels of the OSN and removes each one. As an OSN with a system channel cannot be
+// removed from its application channels, the system channel is removed first with --include-system-channel, and
+// the removal is refused otherwise. The result of every removal is reported, and the exit code is 1 when any of them
+// fails, as the OSN is then still in that channel.
+func (r *runner) removeAllChannels() (string, int, error) {
+	channelList, err := r.listChannels()
+	if err != nil {
+		return "", 1, err
+	}
+
+	var buffer bytes.Buffer
+	removed, attempted := 0, 0
+	remove := func(channelID string) bool {
+		if attempted > 0 {
+			buffer.WriteString("\n")
+		}
+		attempted++
+		output, ok := r.removeChannel(channelID)
+		fmt.Fprintf(&buffer, "Channel: %s\n%s", channelID, output)
+		if ok {
+			removed++
+		}
+		return ok
+	}
+
+	if channelList.SystemChannel != nil {
+		if !r.removeSystemChannel {
+			return "", 1, fmt.Errorf("the OSN has system channel %s, which must be removed before its application channels, use --include-system-channel to remove it first", channelList.SystemChannel.Name)
+		}
+		if !remove(channelList.SystemChannel.Name) {
+			fmt.Fprintf(&buffer, "\nRemoved 0 of %d channels\n", len(channelList.Channels)+1)
+			return buffer.String(), 1, nil
+		}
+		// the OSN restarts the application channels it is a member of once
+		// the system channel is removed, and removes the others
+		channelList, err = r.listChannels()
+		if err != nil {
+			return buffer.String() + errorOutput(err), 1, nil
+		}
+	}
+
+	for _, info := range channelList.Channels {
+		remove(info.Name)
+	}
+
+	fmt.Fprintf(&buffer, "\nRemoved %d of %d channels\n", removed, attempted)
+	if removed < attempted {
+		return buffer.String(), 1, nil
+	}
+	return buffer.String(), 0, nil
+}
+
+// removeChannel removes a single channel of channel remove --all and returns
+// the output, and whether the channel was removed.
+func (r *runner) removeChannel(channelID string) (string, bool) {
+	start := time.Now()
+	resp, err := osnadmin.Retry(r.retryPolicy, func() (*http.Response, error) {
+		return osnadmin.Remove(r.conn.osnURL, channelID, r.conn.caCertPool, r.conn.tlsClientCert, r.conn.clientOpts)
+	})
+	if err != nil {
+		r.opLog.record("channel remove", channelID, start, 0, err)
+		return errorOutput(err), false
+	}
+	bodyBytes, err := readBodyBytes(resp.Body)
+	if err != nil {
+		r.opLog.record("channel remove", channelID, start, resp.StatusCode, err)
+		return errorOutput(err), false
+	}
+	r.opLog.record("channel remove", channelID, start, resp.StatusCode, responseError(resp.StatusCode, bodyBytes))
+
+	output, err := responseOutput(r.output.showStatus, resp.StatusCode, bodyBytes)
+	if err != nil {
+		return errorOutput(err), false
+	}
+	return output, resp.StatusCode >= 200 && resp.StatusCode < 300
+}
+
+// joinManifest lists the channels to join with channel join --batch-file.
+type joinManifest struct {
+	Channels []joinManifestEntry `yaml:"channels"`
+}
+
+type joinManifestEntry struct {
+	// The channel ID, checked against the config block when set.
+	ChannelID string `yaml:"channelID"`
+	// The path of the config block file, relative to the manifest unless absolute.
+	ConfigBlock string `yaml:"configBlock"`
+}
+
+// readJoinManifest reads and validates the manifest of a batch join. The
+// config block paths are resolved against the directory of the manifest.
+func readJoinManifest(manifestPath string) (*joinManifest, error) {
+	manifestBytes, err := ioutil.ReadFile(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading batch file: %s", err)
+	}
+	manifest := &joinManifest{}
+	if err := yaml.UnmarshalStrict(manifestBytes, manifest); err != nil {
+		return nil, fmt.Errorf("parsing batch file: %s", err)
+	}
+	if len(manifest.Channels) == 0 {
+		return nil, fmt.Errorf("batch file %s lists no channels", manifestPath)
+	}
+	for i, entry := range manifest.Channels {
+		if entry.ConfigBlock == "" {
+			return nil, fmt.Errorf("batch file entry %d has no configBlock", i)
+		}
+		if !filepath.IsAbs(entry.ConfigBlock) {
+			manifest.Channels[i].ConfigBlock = filepath.Join(filepath.Dir(manifestPath), entry.ConfigBlock)
+		}
+	}
+	return manifest, nil
+}
+
+// joinBatch joins the channels of the manifest one after the other and
+// reports the result of each. A failed join does not stop the batch; the exit
+// code is 1 when any of the joins failed.
+func (r *runner) joinBatch(manifest *joinManifest) (string, int) {
+	var buffer bytes.Buffer
+	joined := 0
+	for i, entry := range manifest.Channels {
+		if i > 0 {
+			buffer.WriteString("\n")
+		}
+		channelID, output, ok := r.joinBatchEntry(entry)
+		if channelID == "" {
+			channelID = entry.ConfigBlock
+		}
+		fmt.Fprintf(&buffer, "Channel: %s\n%s", channelID, output)
+		if ok {
+			joined++
+		}
+	}
+
+	fmt.Fprintf(&buffer, "\nJoined %d of %d channels\n", joined, len(manifest.Channels))
+	if joined < len(manifest.Channels) {
+		return buffer.String(), 1
+	}
+	return buffer.String(), 0
+}
+
+// joinBatchEntry joins a single channel of a batch join and returns the
+// channel ID, the output, and whether the channel was joined.
+func (r *runner) joinBatchEntry(entry joinManifestEntry) (string, string, bool) {
+	blockBytes, err := ioutil.ReadFile(entry.ConfigBlock)
+	if err != nil {
+		return entry.ChannelID, errorOutput(fmt.Errorf("reading config block: %s", err)), false
+	}
+	blockChannelID, err := channelIDFromBlock(blockBytes)
+	if err != nil {
+		return entry.ChannelID, errorOutput(err), false
+	}
+	if entry.ChannelID != "" && entry.ChannelID != blockChannelID {
+		return entry.ChannelID, errorOutput(fmt.Errorf("specified channelID %s does not match channel ID %s in config block", entry.ChannelID, blockChannelID)), false
+	}
+
+	start := time.Now()
+	resp, err := osnadmin.Retry(r.retryPolicy.NonIdempotent(), func() (*http.Response, error) {
+		return osnadmin.JoinWithOptions(r.conn.osnURL, blockBytes, osnadmin.JoinOptions{
+			FieldName: r.joinFieldName,
+			Compress:  r.joinCompress,
+		}, r.conn.caCertPool, r.conn.tlsClientCert, r.conn.clientOpts)
+	})
+	if err != nil {
+		r.opLog.record("channel join", blockChannelID, start, 0, err)
+		return blockChannelID, errorOutput(err), false
+	}
+	bodyBytes, err := readBodyBytes(resp.Body)
+	if err != nil {
+		r.opLog.record("channel join", blockChannelID, start, resp.StatusCode, err)
+		return blockChannelID, errorOutput(err), false
+	}
+	r.opLog.record("channel join", blockChannelID, start, resp.StatusCode, responseError(resp.StatusCode, bodyBytes))
+	if resp.StatusCode == http.StatusCreated {
+		printRestartNote(bodyBytes)
+	}
+
+	output, err := responseOutput(r.output.showStatus, resp.StatusCode, bodyBytes)
+	if err != nil {
+		return blockChannelID, errorOutput(err), false
+	}
+	return blockChannelID, output, resp.StatusCode >= 200 && resp.StatusCode < 300
+}
+
+// listChannelsSinceHeight lists the channels, and then the details of each
+// channel, and returns the marshaled types.ChannelList of the channels whose
+// height is at least the one of --since-height. A channel that is removed in
+// between is left out.
+func (r *runner) listChannelsSinceHeight() ([]byte, error) {
+	channelList, err := r.listChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	sinceHeight := func(channel types.ChannelInfoShort) (bool, error) {
+		info, err := r.listChannel(channel.Name)
+		if err != nil || info == nil {
+			return false, err
+		}
+		return info.Height >= r.listSinceHeight, nil
+	}
+
+	filtered := types.ChannelList{Channels: []types.ChannelInfoShort{}, Count: channelList.Count, Revision: channelList.Revision}
+	for _, channel := range channelList.Channels {
+		ok, err := sinceHeight(channel)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			filtered.Channels = append(filtered.Channels, channel)
+		}
+	}
+	if channelList.SystemChannel != nil {
+		ok, err := sinceHeight(*channelList.SystemChannel)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			filtered.SystemChannel = channelList.SystemChannel
+		}
+	}
+
+	// encoded like the response of the OSN
+	var buffer bytes.Buffer
+	if err := json.NewEncoder(&buffer).Encode(filtered); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// listChannels lists the channels of the OSN.
+func (r *runner) listChannels() (*types.ChannelList, error) {
+	start := time.Now()
+	resp, err := osnadmin.Retry(r.retryPolicy, func() (*http.Response, error) {
+		return osnadmin.ListAllChannels(r.conn.osnURL, r.conn.caCertPool, r.conn.tlsClientCert, r.conn.clientOpts)
+	})
+	if err != nil {
+		r.opLog.record("channel list", "", start, 0, err)
+		return nil, err
+	}
+	err = osnadmin.CheckResponse(resp)
+	r.opLog.record("channel list", "", start, resp.StatusCode, err)
+	if err != nil {
+		return nil, fmt.Errorf("listing channels: %s", err)
+	}
+	bodyBytes, err := readBodyBytes(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	channelList := &types.ChannelList{}
+	if err := json.Unmarshal(bodyBytes, channelList); err != nil {
+		return nil, fmt.Errorf("unmarshalling channel list: %s", err)
+	}
+	return channelList, nil
+}
+
+// listChannel returns the information of a channel of the OSN, or nil when
+// the channel does not exist, e.g. when it was removed since it was listed.
+func (r *runner) listChannel(channelID string) (*types.ChannelInfo, error) {
+	start := time.Now()
+	resp, err := osnadmin.Retry(r.retryPolicy, func() (*http.Response, error) {
+		return osnadmin.ListSingleChannel(r.conn.osnURL, channelID, r.conn.caCertPool, r.conn.tlsClientCert, r.conn.clientOpts)
+	})
+	if err != nil {
+		r.opLog.record("channel list", channelID, start, 0, err)
+		return nil, err
+	}
+	err = osnadmin.CheckResponse(resp)
+	r.opLog.record("channel list", channelID, start, resp.StatusCode, err)
+	if osnErr, ok := err.(*osnadmin.OSNError); ok && osnErr.Code == osnadmin.CodeChannelNotExist {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("listing channel %s: %s", channelID, err)
+	}
+	bodyBytes, err := readBodyBytes(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	info := &types.ChannelInfo{}
+	if err := json.Unmarshal(bodyBytes, info); err != nil {
+		return nil, fmt.Errorf("unmarshalling channel %s: %s", channelID, err)
+	}
+	return info, nil
+}
+
+// channelsDiff is the output of channel diff.
+type channelsDiff struct {
+	// The admin endpoints of the two OSNs compared.
+	Orderers []string `json:"orderers"`
+	// The channels only the first OSN is in.
+	OnlyFirst []string `json:"onlyFirst"`
+	// The channels only the second OSN is in.
+	OnlySecond []string `json:"onlySecond"`
+	// The channels both OSNs are in, with a different consensus relation, or
+	// height when compared.
+	Differing []channelDifference `json:"differing"`
+}
+
+type channelDifference struct {
+	Name   string       `json:"name"`
+	First  channelState `json:"first"`
+	Second channelState `json:"second"`
+}
+
+// channelState is the part of the channel information compared by channel
+// diff. The height is only set when it is compared.
+type channelState struct {
+	ConsensusRelation types.ConsensusRelation `json:"consensusRelation"`
+	Height            uint64                  `json:"height,omitempty"`
+}
+
+// channelStates lists the channels of the OSN, the system channel included,
+// with the part of their information compared by channel diff.
+func (r *runner) channelStates() (map[string]channelState, error) {
+	channelList, err := r.listChannels()
+	if err != nil {
+		return nil, err
+	}
+	channels := channelList.Channels
+	if channelList.SystemChannel != nil {
+		channels = append(channels, *channelList.SystemChannel)
+	}
+
+	states := map[string]channelState{}
+	for _, channel := range channels {
+		info, err := r.listChannel(channel.Name)
+		if err != nil {
+			return nil, err
+		}
+		if info == nil {
+			continue
+		}
+		state := channelState{ConsensusRelation: info.ConsensusRelation}
+		if r.diffCompareHeight {
+			state.Height = info.Height
+		}
+		states[channel.Name] = state
+	}
+	return states, nil
+}
+
+// diffChannels compares the channels of the two OSNs of --orderer-address.
+// The exit code is 1 when they differ, as with diff(1). The heights of the
+// channels are only compared with --compare-height.
+func (r *runner) diffChannels() (string, int, error) {
+	scheme := "http"
+	if r.caFile != "" || r.caCertDir != "" {
+		scheme = "https"
+	}
+	var states []map[string]channelState
+	for _, address := range r.diffOrderers {
+		// the requests to each OSN are sent and logged by a copy of the
+		// runner connected to it
+		o := *r
+		o.conn.osnURL = osnadmin.OSNURL(scheme, address, r.pathPrefix)
+		o.opLog = nil
+		if r.logWriter != nil {
+			o.opLog = newOperationLog(r.logWriter, address)
+		}
+		s, err := o.channelStates()
+		if err != nil {
+			return "", 0, fmt.Errorf("%s: %s", address, err)
+		}
+		states = append(states, s)
+	}
+
+	diff := channelsDiff{
+		Orderers:   r.diffOrderers,
+		OnlyFirst:  []string{},
+		OnlySecond: []string{},
+		Differing:  []channelDifference{},
+	}
+	for name, first := range states[0] {
+		second, ok := states[1][name]
+		switch {
+		case !ok:
+			diff.OnlyFirst = append(diff.OnlyFirst, name)
+		case first != second:
+			diff.Differing = append(diff.Differing, channelDifference{Name: name, First: first, Second: second})
+		}
+	}
+	for name := range states[1] {
+		if _, ok := states[0][name]; !ok {
+			diff.OnlySecond = append(diff.OnlySecond, name)
+		}
+	}
+	sort.Strings(diff.OnlyFirst)
+	sort.Strings(diff.OnlySecond)
+	sort.Slice(diff.Differing, func(i, j int) bool { return diff.Differing[i].Name < diff.Differing[j].Name })
+
+	output, err := json.MarshalIndent(diff, "", "\t")
+	if err != nil {
+		return "", 0, err
+	}
+	exit := 0
+	if len(diff.OnlyFirst) > 0 || len(diff.OnlySecond) > 0 || len(diff.Differing) > 0 {
+		exit = 1
+	}
+	return string(output) + "\n", exit, nil
+}
+
+// operationRecord is the JSON record of a request sent to the OSN, written to the --log-file.
+type operationRecord struct {
+	Time      time.Time `json:"time"`
+	Operation string    `json:"operation"`
+	Orderer   string    `json:"orderer"`
+	Channel   string    `json:"channel,omitempty"`
+	Status    int       `json:"status,omitempty"`
+	Duration  string    `json:"duration"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// operationLog writes a JSON line per request sent to the OSN. A nil
+// operationLog discards the records.
+type operationLog struct {
+	orderer string
+	encoder *json.Encoder
+}
+
+func newOperationLog(w io.Writer, orderer string) *operationLog {
+	return &operationLog{
+		orderer: orderer,
+		encoder: json.NewEncoder(w),
+	}
+}
+
+func (l *operationLog) record(operation, channelID string, start time.Time, statusCode int, err error) {
+	if l == nil {
+		return
+	}
+	now := time.Now()
+	rec := operationRecord{
+		Time:      now.UTC(),
+		Operation: operation,
+		Orderer:   l.orderer,
+		Channel:   channelID,
+		Status:    statusCode,
+		Duration:  now.Sub(start).String(),
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	if err := l.encoder.Encode(rec); err != nil {
+		fmt.Fprintf(stderr, "Warning: writing log file: %s\n", err)
+	}
+}
+
+// responseError returns the error reported in the body of a non-2xx response.
+func responseError(statusCode int, responseBody []byte) error {
+	if statusCode >= 200 && statusCode < 300 {
+		return nil
+	}
+	errResp := &types.ErrorResponse{}
+	if err := json.Unmarshal(responseBody, errResp); err == nil && errResp.Error != "" {
+		return errors.New(errResp.Error)
+	}
+	return errors.New(http.StatusText(statusCode))
+}
+
+func responseOutput(showStatus bool, statusCode int, responseBody []byte) (string, error) {
+	var buffer bytes.Buffer
+	if showStatus {
+		fmt.Fprintf(&buffer, "Status: %d\n", statusCode)
+	}
+	if len(responseBody) == 0 {
+		return buffer.String(), nil
+	}
+	// a proxy in front of the OSN may respond with e.g. an HTML error page,
+	// which is printed as is rather than lost to an indentation error
+	if !json.Valid(responseBody) {
+		fmt.Fprintf(stderr, "Note: the response body is not JSON, printing it as is\n")
+		buffer.Write(responseBody)
+		if !bytes.HasSuffix(responseBody, []byte("\n")) {
+			buffer.WriteString("\n")
+		}
+		return buffer.String(), nil
+	}
+	// json.Indent works on the raw body, so any JSON value is pretty-printed
+	// and the keys of objects keep their order, but the trailing whitespace of
+	// the body is copied as is, e.g. none after a scalar, so it is normalized
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, responseBody, "", "\t"); err != nil {
+		return "", err
+	}
+	buffer.Write(bytes.TrimRight(indented.Bytes(), " \t\r\n"))
+	buffer.WriteString("\n")
+	return buffer.String(), nil
+}
+
+func templateOutput(tmpl *template.Template, responseBody []byte, responseModel interface{}) (string, error) {
+	if err := json.Unmarshal(responseBody, responseModel); err != nil {
+		return "", fmt.Errorf("unmarshalling response: %s", err)
+	}
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, responseModel); err != nil {
+		return "", fmt.Errorf("executing template: %s", err)
+	}
+	return buffer.String(), nil
+}
+
+// yamlOutput renders the response as YAML. The response is decoded into its
+// typed model, so that a malformed response is an error as with the other
+// formats, and encoded again as JSON, so that the YAML has the keys of the
+// JSON, in the same order, and leaves out the same empty fields.
+func yamlOutput(responseBody []byte, responseModel interface{}) (string, error) {
+	if err := json.Unmarshal(responseBody, responseModel); err != nil {
+		return "", fmt.Errorf("unmarshalling response: %s", err)
+	}
+	jsonBytes, err := json.Marshal(responseModel)
+	if err != nil {
+		return "", err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(jsonBytes))
+	decoder.UseNumber()
+	value, err := orderedJSONValue(decoder)
+	if err != nil {
+		return "", err
+	}
+	yamlBytes, err := yaml.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("marshalling YAML: %s", err)
+	}
+	return string(yamlBytes), nil
+}
+
+// orderedJSONValue decodes the next JSON value of the decoder, with its
+// objects as yaml.MapSlice, as a map would lose the order of the keys.
+func orderedJSONValue(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case json.Delim('{'):
+		object := yaml.MapSlice{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := orderedJSONValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			object = append(object, yaml.MapItem{Key: key, Value: value})
+		}
+		_, err := decoder.Token() // the closing delimiter
+		return object, err
+	case json.Delim('['):
+		array := []interface{}{}
+		for decoder.More() {
+			value, err := orderedJSONValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		_, err := decoder.Token()
+		return array, err
+	default:
+		return token, nil
+	}
+}
+
+// statusColors are the ANSI escape codes of the colors of the channel statuses in table output.
+var statusColors = map[types.Status]string{
+	types.StatusActive:     "\x1b[32m",
+	types.StatusOnBoarding: "\x1b[33m",
+	types.StatusFailed:     "\x1b[31m",
+}
+
+// tableColumn is a column of table output, named as the JSON key of the
+// value it shows.
+type tableColumn struct {
+	name   string
+	header string
+}
+
+// channelListColumns and channelInfoColumns are the columns of the table
+// output of a channel list and of the information of a channel, in their
+// default order.
+var (
+	channelListColumns = []tableColumn{
+		{name: "name", header: "NAME"},
+		{name: "type", header: "TYPE"},
+		{name: "url", header: "URL"},
+	}
+	channelInfoColumns = []tableColumn{
+		{name: "name", header: "NAME"},
+		{name: "consensusRelation", header: "CONSENSUS RELATION"},
+		{name: "height", header: "HEIGHT"},
+		{name: "status", header: "STATUS"},
+	}
+)
+
+// selectColumns returns the available columns named by a comma separated
+// list, in the order of the list.
+func selectColumns(available []tableColumn, names string) ([]tableColumn, error) {
+	var columns []tableColumn
+	selected := map[string]bool{}
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if selected[name] {
+			return nil, fmt.Errorf("duplicate column %q", name)
+		}
+		found := false
+		for _, column := range available {
+			if column.name == name {
+				columns = append(columns, column)
+				found = true
+				break
+			}
+		}
+		if !found {
+			var availableNames []string
+			for _, column := range available {
+				availableNames = append(availableNames, column.name)
+			}
+			return nil, fmt.Errorf("unknown column %q, the columns are: %s", name, strings.Join(availableNames, ", "))
+		}
+		selected[name] = true
+	}
+	return columns, nil
+}
+
+// tableOutput renders the channel list, or the channel information, of a
+// response as a table of the columns, or of the default columns when none
+// are set, coloring the channel status when color is set.
+func tableOutput(responseBody []byte, responseModel interface{}, columns []tableColumn, color bool) (string, error) {
+	if err := json.Unmarshal(responseBody, responseModel); err != nil {
+		return "", fmt.Errorf("unmarshalling response: %s", err)
+	}
+	var rows []map[string]string
+	switch model := responseModel.(type) {
+	case *types.ChannelList:
+		if columns == nil {
+			columns = channelListColumns
+		}
+		if model.SystemChannel != nil {
+			rows = append(rows, map[string]string{"name": model.SystemChannel.Name, "type": "system", "url": model.SystemChannel.URL})
+		}
+		for _, info := range model.Channels {
+			rows = append(rows, map[string]string{"name": info.Name, "type": "application", "url": info.URL})
+		}
+	case *types.ChannelInfo:
+		if columns == nil {
+			columns = channelInfoColumns
+		}
+		// the status is only colored when it is the last column, so that its
+		// escape codes do not upset the alignment of the others
+		status := string(model.Status)
+		if code, ok := statusColors[model.Status]; ok && color && columns[len(columns)-1].name == "status" {
+			status = code + status + "\x1b[0m"
+		}
+		rows = append(rows, map[string]string{
+			"name":              model.Name,
+			"consensusRelation": string(model.ConsensusRelation),
+			"height":            fmt.Sprint(model.Height),
+			"status":            status,
+		})
+	}
+
+	var buffer bytes.Buffer
+	w := tabwriter.NewWriter(&buffer, 0, 0, 2, ' ', 0)
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = column.header
+	}
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		values := make([]string, len(columns))
+		for i, column := range columns {
+			values[i] = row[column.name]
+		}
+		fmt.Fprintln(w, strings.Join(values, "\t"))
+	}
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+func certificatesOutput(certs []*x509.Certificate, caCertPool *x509.CertPool) string {
+	var buffer bytes.Buffer
+	for i, cert := range certs {
+		fmt.Fprintf(&buffer, "Certificate %d:\n", i)
+		fmt.Fprintf(&buffer, "\tSubject: %s\n", cert.Subject)
+		fmt.Fprintf(&buffer, "\tIssuer: %s\n", cert.Issuer)
+		fmt.Fprintf(&buffer, "\tSANs: %s\n", strings.Join(subjectAlternativeNames(cert), ", "))
+		fmt.Fprintf(&buffer, "\tNot Before: %s\n", cert.NotBefore.UTC().Format(time.RFC3339))
+		fmt.Fprintf(&buffer, "\tNot After: %s\n", cert.NotAfter.UTC().Format(time.RFC3339))
+	}
+
+	// when a CA was provided, report whether the presented chain verifies
+	// against it to help diagnose CA mismatches.
+	if caCertPool != nil && len(certs) != 0 {
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err := certs[0].Verify(x509.VerifyOptions{
+			Roots:         caCertPool,
+			Intermediates: intermediates,
+		})
+		if err != nil {
+			fmt.Fprintf(&buffer, "Verification: failed: %s\n", err)
+		} else {
+			fmt.Fprintf(&buffer, "Verification: OK\n")
+		}
+	}
+
+	return buffer.String()
+}
+
+func subjectAlternativeNames(cert *x509.Certificate) []string {
+	var sans []string
+	sans = append(sans, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	sans = append(sans, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	return sans
+}
+
+// warnCertExpiry writes a warning to stderr when the certificate expires
+// within the given number of days.
+func warnCertExpiry(cert tls.Certificate, days int, now time.Time) error {
+	if len(cert.Certificate) == 0 {
+		return nil
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("parsing client certificate: %s", err)
+	}
+
+	notAfter := leaf.NotAfter.UTC().Format(time.RFC3339)
+	switch {
+	case now.After(leaf.NotAfter):
+		fmt.Fprintf(stderr, "Warning: client certificate expired on %s\n", notAfter)
+	case now.Add(time.Duration(days) * 24 * time.Hour).After(leaf.NotAfter):
+		fmt.Fprintf(stderr, "Warning: client certificate expires on %s, within %d days\n", notAfter, days)
+	}
+
+	return nil
+}
+
+// printRestartNote writes a note to stderr when the join response reports
+// that the orderer must be restarted to activate the channel, as is the case
+// for the system channel.
+func printRestartNote(responseBody []byte) {
+	info := &types.ChannelInfo{}
+	if err := json.Unmarshal(responseBody, info); err != nil || !info.RequiresRestart {
+		return
+	}
+	fmt.Fprintf(stderr, "NOTE: channel %s is inactive until the orderer is restarted\n", info.Name)
+}
+
+// printAttempts writes the number of attempts of a request, and the retry
+// conditions that caused the retries, to stderr, so that --retries can be
+// tuned without touching the command output.
+func printAttempts(attempts int, reasons []string) {
+	if len(reasons) == 0 {
+		fmt.Fprintf(stderr, "attempts: %d\n", attempts)
+		return
+	}
+	fmt.Fprintf(stderr, "attempts: %d, retried on: %s\n", attempts, strings.Join(reasons, ", "))
+}
+
+// printElapsed writes the time elapsed since start to stderr, so that the
+// command output is left untouched.
+func printElapsed(start time.Time) {
+	fmt.Fprintf(stderr, "took %s\n", time.Since(start))
+}
+
+func readBodyBytes(body io.ReadCloser) ([]byte, error) {
+	bodyBytes, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("reading http response body: %s", err)
+	}
+	body.Close()
+
+	return bodyBytes, nil
+}
+
+// versionInfo returns the version of osnadmin, followed by the build
+// metadata when full is set.
+func versionInfo(full bool) string {
+	if !full {
+		return metadata.Version
+	}
+	return fmt.Sprintf("osnadmin:\n Version: %s\n Commit SHA: %s\n Build date: %s\n Go version: %s\n OS/Arch: %s",
+		metadata.Version, metadata.CommitSHA, metadata.BuildDate, runtime.Version(),
+		fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH))
+}
+
+// joinOrdererAddressFile joins an @path value of --orderer-address to its flag,
+// as kingpin would otherwise replace a separate @path argument with the lines
+// of the file, untrimmed, instead of leaving it to be read as the address.
+func joinOrdererAddressFile(args []string) []string {
+	joined := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		if (args[i] == "--orderer-address" || args[i] == "-o") && i+1 < len(args) && strings.HasPrefix(args[i+1], "@") {
+			joined = append(joined, "--orderer-address="+args[i+1])
+			i++
+			continue
+		}
+		joined = append(joined, args[i])
+	}
+	return joined
+}
+
+// resolveOrdererAddress replaces an @path value of --orderer-address with the
+// address read from the file, trimmed.
+func resolveOrdererAddress(address *string) error {
+	if !strings.HasPrefix(*address, "@") {
+		return nil
+	}
+	addressFile := strings.TrimPrefix(*address, "@")
+	addressBytes, err := ioutil.ReadFile(addressFile)
+	if err != nil {
+		return fmt.Errorf("reading --orderer-address file: %s", err)
+	}
+	*address = strings.TrimSpace(string(addressBytes))
+	if *address == "" {
+		return fmt.Errorf("--orderer-address file %s is empty", addressFile)
+	}
+	return nil
+}
+
+// explainInput carries the flags checked by --explain.
+type explainInput struct {
+	ordererFlag       string
+	ordererAddresses  []string
+	ordererAddressErr error
+	caFile            string
+	caCertDir         string
+	pinFile           string
+	clientCert        string
+	clientKey         string
+	secretDir         string
+	pkcs11Lib         string
+	expectSAN         string
+	channelIDs        []string
+	configBlockPath   string
+	configBlockB64    string
+	joinChannelID     string
+}
+
+// explainProblem is a problem found by --explain, attributed to the flag
+// whose value causes it.
+type explainProblem struct {
+	Flag  string `json:"flag"`
+	Error string `json:"error"`
+}
+
+// explainReport is the output of --explain.
+type explainReport struct {
+	Command  string           `json:"command"`
+	Problems []explainProblem `json:"problems"`
+}
+
+// explainProblems runs the checks of the flags that can be done without
+// contacting the OSN, and returns every problem found rather than stopping at
+// the first, so that all of them can be fixed at once, e.g. in CI.
+func explainProblems(in explainInput, now time.Time) []explainProblem {
+	problems := []explainProblem{}
+	report := func(flag string, err error) {
+		problems = append(problems, explainProblem{Flag: flag, Error: err.Error()})
+	}
+
+	if in.ordererAddressErr != nil {
+		report(in.ordererFlag, in.ordererAddressErr)
+	} else {
+		for _, address := range in.ordererAddresses {
+			if err := checkOrdererAddress(address); err != nil {
+				report(in.ordererFlag, err)
+			}
+		}
+	}
+
+	if in.secretDir != "" {
+		if in.caFile != "" || in.clientCert != "" || in.clientKey != "" {
+			report("--secret-dir", fmt.Errorf("--secret-dir cannot be combined with --ca-file, --client-cert or --client-key"))
+		} else {
+			in.caFile = filepath.Join(in.secretDir, "ca.crt")
+			in.clientCert = filepath.Join(in.secretDir, "tls.crt")
+			in.clientKey = filepath.Join(in.secretDir, "tls.key")
+		}
+	}
+	if in.pinFile != "" && in.caFile != "" {
+		report("--trust-on-first-use", fmt.Errorf("--ca-file and --trust-on-first-use are mutually exclusive"))
+	}
+	if in.pinFile != "" && in.caCertDir != "" {
+		report("--trust-on-first-use", fmt.Errorf("--ca-cert-dir and --trust-on-first-use are mutually exclusive"))
+	}
+	if in.caFile != "" {
+		caFilePEM, err := ioutil.ReadFile(in.caFile)
+		switch {
+		case err != nil:
+			report("--ca-file", fmt.Errorf("reading orderer CA certificate: %s", err))
+		case !x509.NewCertPool().AppendCertsFromPEM(caFilePEM):
+			report("--ca-file", fmt.Errorf("no PEM-encoded certificate in %s", in.caFile))
+		}
+	}
+	if in.caCertDir != "" {
+		if err := osnadmin.AppendCertsFromDir(x509.NewCertPool(), in.caCertDir); err != nil {
+			report("--ca-cert-dir", err)
+		}
+	}
+	tlsEnabled := in.caFile != "" || in.caCertDir != "" || in.pinFile != ""
+	switch {
+	case !tlsEnabled && in.expectSAN != "":
+		report("--expect-san", fmt.Errorf("--expect-san requires TLS, use --ca-file, --ca-cert-dir or --trust-on-first-use"))
+	case tlsEnabled && in.pkcs11Lib == "":
+		// the key of a PKCS#11 token is only checked when it is used
+		if err := checkClientCert(in.clientCert, in.clientKey, now); err != nil {
+			report("--client-cert", err)
+		}
+	}
+
+	for _, channelID := range in.channelIDs {
+		if channelID == "" {
+			continue
+		}
+		if err := configtx.ValidateChannelID(channelID); err != nil {
+			report("--channelID", fmt.Errorf("invalid --channelID: %s", err))
+		}
+	}
+
+	var (
+		blockFlag  string
+		blockBytes []byte
+		err        error
+	)
+	switch {
+	case in.configBlockPath != "" && in.configBlockB64 != "":
+		report("--config-block-b64", fmt.Errorf("--config-block and --config-block-b64 are mutually exclusive"))
+	case in.configBlockPath != "":
+		blockFlag = "--config-block"
+		if blockBytes, err = ioutil.ReadFile(in.configBlockPath); err != nil {
+			report(blockFlag, fmt.Errorf("reading config block: %s", err))
+		}
+	case in.configBlockB64 != "":
+		blockFlag = "--config-block-b64"
+		if blockBytes, err = base64.StdEncoding.DecodeString(strings.TrimSpace(in.configBlockB64)); err != nil {
+			report(blockFlag, fmt.Errorf("decoding --config-block-b64: %s", err))
+		}
+	}
+	if blockBytes != nil {
+		for _, err := range joinBlockProblems(blockBytes, in.joinChannelID) {
+			report(blockFlag, err)
+		}
+	}
+
+	return problems
+}
+
+// checkOrdererAddress checks that an orderer address is a host and a port.
+func checkOrdererAddress(address string) error {
+	if address == "" {
+		return fmt.Errorf("required flag --orderer-address not provided")
+	}
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid orderer address %s: %s", address, err)
+	}
+	if host == "" {
+		return fmt.Errorf("invalid orderer address %s: missing host", address)
+	}
+	if n, err := strconv.ParseUint(port, 10, 16); err != nil || n == 0 {
+		return fmt.Errorf("invalid orderer address %s: invalid port %s", address, port)
+	}
+	return nil
+}
+
+// checkClientCert checks that the client certificate and key are a valid key
+// pair, and that the certificate is valid at the time.
+func checkClientCert(certFile, keyFile string, now time.Time) error {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return fmt.Errorf("loading client cert/key pair: %s", err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("parsing client certificate: %s", err)
+	}
+	switch {
+	case now.After(leaf.NotAfter):
+		return fmt.Errorf("client certificate expired on %s", leaf.NotAfter.UTC().Format(time.RFC3339))
+	case now.Before(leaf.NotBefore):
+		return fmt.Errorf("client certificate is not valid before %s", leaf.NotBefore.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// joinBlockProblems runs the checks of the join block that the OSN runs, and
+// returns every reason it would be rejected.
+func joinBlockProblems(blockBytes []byte, channelID string) []error {
+	block := &common.Block{}
+	if err := proto.Unmarshal(blockBytes, block); err != nil {
+		return []error{fmt.Errorf("unmarshalling block: %s", err)}
+	}
+	if err := channelparticipation.ValidateBlockDataHash(block); err != nil {
+		return []error{fmt.Errorf("invalid join block: %s", err)}
+	}
+
+	var problems []error
+	if blockChannelID, err := protoutil.GetChannelIDFromBlock(block); err == nil && channelID != "" && channelID != blockChannelID {
+		problems = append(problems, fmt.Errorf("specified --channelID %s does not match channel ID %s in config block", channelID, blockChannelID))
+	}
+	invalid, unsupported := channelparticipation.JoinBlockErrors(block)
+	for _, err := range invalid {
+		problems = append(problems, fmt.Errorf("invalid join block: %s", err))
+	}
+	for _, err := range unsupported {
+		problems = append(problems, fmt.Errorf("unsupported join block: %s", err))
+	}
+	if len(invalid) == 0 {
+		if err := channelparticipation.ValidateJoinBlockConsenters(block); err != nil {
+			problems = append(problems, fmt.Errorf("invalid join block: %s", err))
+		}
+	}
+	return problems
+}
+
+// explainOutput prints the report of --explain as JSON, and exits with 1 when
+// a problem was found.
+func explainOutput(command string, problems []explainProblem) (string, int, error) {
+	reportBytes, err := json.Marshal(explainReport{Command: command, Problems: problems})
+	if err != nil {
+		return "", 1, err
+	}
+	output, err := responseOutput(false, 0, reportBytes)
+	if err != nil {
+		return "", 1, err
+	}
+	if len(problems) > 0 {
+		return output, 1, nil
+	}
+	return output, 0, nil
+}
+
+// doctorOutput prints a line per check and exits with 1 if any check failed.
+func doctorOutput(diagnoses []osnadmin.Diagnosis) (string, int, error) {
+	var buf strings.Builder
+	exit := 0
+	for _, d := range diagnoses {
+		switch {
+		case d.Skipped:
+			fmt.Fprintf(&buf, "[SKIP] %s\n", d.Check)
+		case d.Err != nil:
+			fmt.Fprintf(&buf, "[FAIL] %s: %s\n", d.Check, d.Err)
+			exit = 1
+		default:
+			fmt.Fprintf(&buf, "[PASS] %s\n", d.Check)
+		}
+	}
+	return buf.String(), exit, nil
+}
+
+func errorOutput(err error) string {
+	return fmt.Sprintf("Error: %s\n", err)
+}
+
+func channelIDFromBlock(blockBytes []byte) (string, error) {
+	block := &common.Block{}
+	err := proto.Unmarshal(blockBytes, block)
+	if err != nil {
+		return "", fmt.Errorf("unmarshalling block: %s", err)
+	}
+
+	return protoutil.GetChannelIDFromBlock(block)
 }
@@ -7,29 +7,53 @@ SPDX-License-Identifier: Apache-2.0
 package main
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"math"
+	"math/big"
+	"net"
+	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric-protos-go/common"
+	ab "github.com/hyperledger/fabric-protos-go/orderer"
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/cmd/osnadmin/mocks"
 	"github.com/hyperledger/fabric/common/crypto/tlsgen"
+	"github.com/hyperledger/fabric/common/metadata"
 	"github.com/hyperledger/fabric/orderer/common/channelparticipation"
 	"github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/common/types"
 	"github.com/hyperledger/fabric/protoutil"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	. "github.com/onsi/gomega/gstruct"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"gopkg.in/yaml.v2"
 )
 
 var _ = Describe("osnadmin", func() {
@@ -43,6 +67,7 @@ var _ = Describe("osnadmin", func() {
 		tlsConfig             *tls.Config
 		ordererURL            string
 		channelID             string
+		clientSubject         string
 	)
 
 	BeforeEach(func() {
@@ -55,8 +80,11 @@ var _ = Describe("osnadmin", func() {
 		ordererCACert = filepath.Join(tempDir, "server-ca.pem")
 		clientCert = filepath.Join(tempDir, "client-cert.pem")
 		clientKey = filepath.Join(tempDir, "client-key.pem")
+		// the OSN reports the client that joined a channel by the subject of its certificate
+		clientSubject = certSubject(clientCert)
 
 		channelID = "testing123"
+		stderr = gbytes.NewBuffer()
 
 		config := localconfig.ChannelParticipation{
 			Enabled:            true,
@@ -153,6 +181,7 @@ var _ = Describe("osnadmin", func() {
 					Name: "fight-the-system",
 					URL:  "/participation/v1/channels/fight-the-system",
 				},
+				Count: 3,
 			}
 			checkStatusOutput(output, exit, err, 200, expectedOutput)
 		})
@@ -231,6 +260,7 @@ var _ = Describe("osnadmin", func() {
 						Name: "fight-the-system",
 						URL:  "/participation/v1/channels/fight-the-system",
 					},
+					Count: 3,
 				}
 				checkStatusOutput(output, exit, err, 200, expectedOutput)
 			})
@@ -335,200 +365,3981 @@ var _ = Describe("osnadmin", func() {
 		})
 	})
 
+	Describe("Remove all", func() {
+		BeforeEach(func() {
+			mockChannelManagement.ChannelListReturns(types.ChannelList{
+				Channels: []types.ChannelInfoShort{
+					{Name: "participation-trophy"},
+					{Name: "another-participation-trophy"},
+				},
+			})
+			mockChannelManagement.RemoveChannelStub = func(channelID string) error {
+				if channelID == "another-participation-trophy" {
+					return types.ErrChannelPendingRemoval
+				}
+				return nil
+			}
+		})
+
+		It("removes every application channel, and exits with code 1 when a removal fails", func() {
+			args := []string{
+				"channel",
+				"remove",
+				"--orderer-address", ordererURL,
+				"--all",
+				"--force",
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+			Expect(output).To(Equal(
+				"Channel: participation-trophy\n" +
+					"Status: 204\n" +
+					"\n" +
+					"Channel: another-participation-trophy\n" +
+					"Status: 409\n" +
+					"{\n\t\"error\": \"cannot remove: channel pending removal\"\n}\n" +
+					"\n" +
+					"Removed 1 of 2 channels\n",
+			))
+
+			Expect(mockChannelManagement.RemoveChannelCallCount()).To(Equal(2))
+			Expect(mockChannelManagement.RemoveChannelArgsForCall(0)).To(Equal("participation-trophy"))
+			Expect(mockChannelManagement.RemoveChannelArgsForCall(1)).To(Equal("another-participation-trophy"))
+		})
+
+		Context("when the OSN has a system channel", func() {
+			var systemChannelRemoved bool
+
+			BeforeEach(func() {
+				systemChannelRemoved = false
+				appChannels := []types.ChannelInfoShort{
+					{Name: "participation-trophy"},
+					{Name: "another-participation-trophy"},
+				}
+				mockChannelManagement.ChannelListStub = func() types.ChannelList {
+					if systemChannelRemoved {
+						return types.ChannelList{Channels: appChannels}
+					}
+					return types.ChannelList{
+						Channels:      appChannels,
+						SystemChannel: &types.ChannelInfoShort{Name: "fight-the-system"},
+					}
+				}
+				// like the registrar, application channels cannot be removed
+				// while the system channel exists
+				mockChannelManagement.RemoveChannelStub = func(channelID string) error {
+					switch {
+					case channelID == "fight-the-system":
+						systemChannelRemoved = true
+						return nil
+					case !systemChannelRemoved:
+						return types.ErrSystemChannelExists
+					default:
+						return nil
+					}
+				}
+			})
+
+			It("refuses to remove any channel without --include-system-channel", func() {
+				args := []string{
+					"channel",
+					"remove",
+					"--orderer-address", ordererURL,
+					"--all",
+					"--force",
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				checkCLIError(output, exit, err, "the OSN has system channel fight-the-system, which must be removed before its application channels, use --include-system-channel to remove it first")
+				Expect(mockChannelManagement.RemoveChannelCallCount()).To(Equal(0))
+			})
+
+			It("removes the system channel first when it is explicitly included", func() {
+				args := []string{
+					"channel",
+					"remove",
+					"--orderer-address", ordererURL,
+					"--all",
+					"--force",
+					"--include-system-channel",
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exit).To(Equal(0))
+				Expect(output).To(Equal(
+					"Channel: fight-the-system\n" +
+						"Status: 204\n" +
+						"\n" +
+						"Channel: participation-trophy\n" +
+						"Status: 204\n" +
+						"\n" +
+						"Channel: another-participation-trophy\n" +
+						"Status: 204\n" +
+						"\n" +
+						"Removed 3 of 3 channels\n",
+				))
+
+				Expect(mockChannelManagement.RemoveChannelCallCount()).To(Equal(3))
+				Expect(mockChannelManagement.RemoveChannelArgsForCall(0)).To(Equal("fight-the-system"))
+			})
+
+			It("stops when the system channel cannot be removed", func() {
+				mockChannelManagement.RemoveChannelStub = func(channelID string) error {
+					return errors.New("etcdraft only")
+				}
+				args := []string{
+					"channel",
+					"remove",
+					"--orderer-address", ordererURL,
+					"--all",
+					"--force",
+					"--include-system-channel",
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+					"--no-status",
+				}
+				output, exit, err := executeForArgs(args)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exit).To(Equal(1))
+				Expect(output).To(HavePrefix("Channel: fight-the-system\n"))
+				Expect(output).To(HaveSuffix("Removed 0 of 3 channels\n"))
+				Expect(mockChannelManagement.RemoveChannelCallCount()).To(Equal(1))
+			})
+		})
+
+		It("exits with code 0 when every channel is removed", func() {
+			mockChannelManagement.RemoveChannelStub = nil
+			args := []string{
+				"channel",
+				"remove",
+				"--orderer-address", ordererURL,
+				"--all",
+				"--force",
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(HaveSuffix("Removed 2 of 2 channels\n"))
+		})
+
+		Context("when the connection fails while removing a channel", func() {
+			BeforeEach(func() {
+				mockChannelManagement.RemoveChannelStub = nil
+				handler := testServer.Config.Handler
+				testServer.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Method == http.MethodDelete && strings.HasSuffix(r.URL.Path, "/participation-trophy") {
+						conn, _, err := w.(http.Hijacker).Hijack()
+						Expect(err).NotTo(HaveOccurred())
+						conn.Close()
+						return
+					}
+					handler.ServeHTTP(w, r)
+				})
+			})
+
+			It("reports the error and goes on with the other channels", func() {
+				args := []string{
+					"channel",
+					"remove",
+					"--orderer-address", ordererURL,
+					"--all",
+					"--force",
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exit).To(Equal(1))
+				Expect(output).To(HavePrefix("Channel: participation-trophy\nError: "))
+				Expect(output).To(HaveSuffix(
+					"Channel: another-participation-trophy\n" +
+						"Status: 204\n" +
+						"\n" +
+						"Removed 1 of 2 channels\n",
+				))
+				Expect(mockChannelManagement.RemoveChannelCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when --force is not set", func() {
+			It("returns with exit code 1 and prints the error", func() {
+				args := []string{
+					"channel",
+					"remove",
+					"--orderer-address", ordererURL,
+					"--all",
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				checkFlagError(output, exit, err, "--all requires --force")
+				Expect(mockChannelManagement.RemoveChannelCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when --channelID is also set", func() {
+			It("returns with exit code 1 and prints the error", func() {
+				args := []string{
+					"channel",
+					"remove",
+					"--orderer-address", ordererURL,
+					"--channelID", channelID,
+					"--all",
+					"--force",
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				checkFlagError(output, exit, err, "--channelID and --all are mutually exclusive")
+			})
+		})
+
+		Context("when neither --channelID nor --all is set", func() {
+			It("returns with exit code 1 and prints the error", func() {
+				args := []string{
+					"channel",
+					"remove",
+					"--orderer-address", ordererURL,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				checkFlagError(output, exit, err, "required flag --channelID not provided")
+			})
+		})
+	})
+
 	Describe("Join", func() {
 		var blockPath string
 
 		BeforeEach(func() {
-			configBlock := blockWithGroups(
-				map[string]*cb.ConfigGroup{
-					"Application": {},
-				},
-				"testing123",
-			)
-			blockPath = createBlockFile(tempDir, configBlock)
+			configBlock := blockWithGroups(
+				map[string]*cb.ConfigGroup{
+					"Application": {},
+				},
+				"testing123",
+			)
+			blockPath = createBlockFile(tempDir, configBlock)
+
+			mockChannelManagement.JoinChannelReturns(types.ChannelInfo{
+				Name:              "apple",
+				ConsensusRelation: "banana",
+				Status:            "orange",
+				Height:            123,
+			}, nil)
+		})
+
+		It("uses the channel participation API to join a channel", func() {
+			args := []string{
+				"channel",
+				"join",
+				"--orderer-address", ordererURL,
+				"--channelID", channelID,
+				"--config-block", blockPath,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			expectedOutput := types.ChannelInfo{
+				Name:              "apple",
+				URL:               "/participation/v1/channels/apple",
+				ConsensusRelation: "banana",
+				Status:            "orange",
+				Height:            123,
+				LastModifiedBy:    clientSubject,
+			}
+			checkStatusOutput(output, exit, err, 201, expectedOutput)
+		})
+
+		Context("when --channelID is omitted", func() {
+			It("joins the channel in the config block", func() {
+				args := []string{
+					"channel",
+					"join",
+					"--orderer-address", ordererURL,
+					"--config-block", blockPath,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				expectedOutput := types.ChannelInfo{
+					Name:              "apple",
+					URL:               "/participation/v1/channels/apple",
+					ConsensusRelation: "banana",
+					Status:            "orange",
+					Height:            123,
+					LastModifiedBy:    clientSubject,
+				}
+				checkStatusOutput(output, exit, err, 201, expectedOutput)
+
+				Expect(mockChannelManagement.JoinChannelCallCount()).To(Equal(1))
+				joinedChannelID, _, _ := mockChannelManagement.JoinChannelArgsForCall(0)
+				Expect(joinedChannelID).To(Equal("testing123"))
+			})
+		})
+
+		It("does not print a restart note for an application channel", func() {
+			args := []string{
+				"channel",
+				"join",
+				"--orderer-address", ordererURL,
+				"--config-block", blockPath,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			_, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(string(stderr.(*gbytes.Buffer).Contents())).NotTo(ContainSubstring("NOTE"))
+		})
+
+		Context("when the orderer must be restarted to activate the channel", func() {
+			BeforeEach(func() {
+				configBlock := blockWithGroups(
+					map[string]*cb.ConfigGroup{
+						"Consortiums": {},
+					},
+					"system-channel",
+				)
+				blockPath = createBlockFile(tempDir, configBlock)
+
+				mockChannelManagement.JoinChannelReturns(types.ChannelInfo{
+					Name:              "system-channel",
+					ConsensusRelation: "consenter",
+					Status:            "inactive",
+					Height:            1,
+					RequiresRestart:   true,
+				}, nil)
+			})
+
+			It("prints a restart note to stderr", func() {
+				args := []string{
+					"channel",
+					"join",
+					"--orderer-address", ordererURL,
+					"--config-block", blockPath,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				expectedOutput := types.ChannelInfo{
+					Name:              "system-channel",
+					URL:               "/participation/v1/channels/system-channel",
+					ConsensusRelation: "consenter",
+					Status:            "inactive",
+					Height:            1,
+					RequiresRestart:   true,
+					LastModifiedBy:    clientSubject,
+				}
+				checkStatusOutput(output, exit, err, 201, expectedOutput)
+				Expect(stderr).To(gbytes.Say(`NOTE: channel system-channel is inactive until the orderer is restarted\n`))
+			})
+		})
+
+		Context("when a custom config block field name is used", func() {
+			var fieldNames []string
+
+			BeforeEach(func() {
+				fieldNames = nil
+				testServer.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					err := r.ParseMultipartForm(1024 * 1024)
+					Expect(err).NotTo(HaveOccurred())
+					for name := range r.MultipartForm.File {
+						fieldNames = append(fieldNames, name)
+					}
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusCreated)
+					json.NewEncoder(w).Encode(types.ChannelInfo{Name: "apple"})
+				})
+			})
+
+			It("sends the config block in the multipart form field with that name", func() {
+				args := []string{
+					"channel",
+					"join",
+					"--orderer-address", ordererURL,
+					"--channelID", channelID,
+					"--config-block", blockPath,
+					"--config-block-field", "join-block",
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				checkStatusOutput(output, exit, err, 201, types.ChannelInfo{Name: "apple"})
+				Expect(fieldNames).To(Equal([]string{"join-block"}))
+			})
+		})
+
+		Context("when --compress is set", func() {
+			var contentEncodings []string
+
+			BeforeEach(func() {
+				contentEncodings = nil
+				handler := testServer.Config.Handler
+				testServer.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					contentEncodings = append(contentEncodings, r.Header.Get("Content-Encoding"))
+					handler.ServeHTTP(w, r)
+				})
+			})
+
+			It("gzips the upload, which the OSN decodes", func() {
+				args := []string{
+					"channel",
+					"join",
+					"--orderer-address", ordererURL,
+					"--channelID", channelID,
+					"--config-block", blockPath,
+					"--compress",
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				expectedOutput := types.ChannelInfo{
+					Name:              "apple",
+					URL:               "/participation/v1/channels/apple",
+					ConsensusRelation: "banana",
+					Status:            "orange",
+					Height:            123,
+					LastModifiedBy:    clientSubject,
+				}
+				checkStatusOutput(output, exit, err, 201, expectedOutput)
+				Expect(contentEncodings).To(Equal([]string{"gzip"}))
+
+				blockBytes, err := ioutil.ReadFile(blockPath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(mockChannelManagement.JoinChannelCallCount()).To(Equal(1))
+				_, joinedBlock, _ := mockChannelManagement.JoinChannelArgsForCall(0)
+				Expect(protoutil.MarshalOrPanic(joinedBlock)).To(Equal(blockBytes))
+			})
+		})
+
+		Context("when the config block is passed in base64 with --config-block-b64", func() {
+			var blockB64 string
+
+			BeforeEach(func() {
+				blockBytes, err := ioutil.ReadFile(blockPath)
+				Expect(err).NotTo(HaveOccurred())
+				blockB64 = base64.StdEncoding.EncodeToString(blockBytes)
+			})
+
+			It("decodes the block and uses the channel participation API to join the channel", func() {
+				args := []string{
+					"channel",
+					"join",
+					"--orderer-address", ordererURL,
+					"--channelID", channelID,
+					"--config-block-b64", blockB64,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				expectedOutput := types.ChannelInfo{
+					Name:              "apple",
+					URL:               "/participation/v1/channels/apple",
+					ConsensusRelation: "banana",
+					Status:            "orange",
+					Height:            123,
+					LastModifiedBy:    clientSubject,
+				}
+				checkStatusOutput(output, exit, err, 201, expectedOutput)
+
+				blockBytes, err := ioutil.ReadFile(blockPath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(mockChannelManagement.JoinChannelCallCount()).To(Equal(1))
+				_, joinedBlock, _ := mockChannelManagement.JoinChannelArgsForCall(0)
+				Expect(protoutil.MarshalOrPanic(joinedBlock)).To(Equal(blockBytes))
+			})
+
+			It("returns with exit code 1 when the value is not valid base64", func() {
+				args := []string{
+					"channel",
+					"join",
+					"--orderer-address", ordererURL,
+					"--channelID", channelID,
+					"--config-block-b64", "not*base64",
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				checkFlagError(output, exit, err, "decoding --config-block-b64: illegal base64 data at input byte 3")
+			})
+
+			It("returns with exit code 1 when the channel ID does not match the block", func() {
+				args := []string{
+					"channel",
+					"join",
+					"--orderer-address", ordererURL,
+					"--channelID", "not-the-channel-youre-looking-for",
+					"--config-block-b64", blockB64,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				checkFlagError(output, exit, err, "specified --channelID not-the-channel-youre-looking-for does not match channel ID testing123 in config block")
+			})
+
+			It("returns with exit code 1 when --config-block is also set", func() {
+				args := []string{
+					"channel",
+					"join",
+					"--orderer-address", ordererURL,
+					"--channelID", channelID,
+					"--config-block", blockPath,
+					"--config-block-b64", blockB64,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				checkFlagError(output, exit, err, "--config-block and --config-block-b64 are mutually exclusive")
+			})
+		})
+
+		Context("when the config block is fetched from another orderer", func() {
+			var (
+				configBlock   *cb.Block
+				sourceOrderer *deliverServer
+				grpcServer    *grpc.Server
+				sourceAddress string
+			)
+
+			BeforeEach(func() {
+				configBlock = blockWithGroups(map[string]*cb.ConfigGroup{"Application": {}}, "testing123")
+				configBlock.Header.Number = 3
+				setLastConfigIndex(configBlock, 3)
+				newestBlock := &cb.Block{Header: &cb.BlockHeader{Number: 5}}
+				setLastConfigIndex(newestBlock, 3)
+
+				sourceOrderer = &deliverServer{
+					blocks: map[uint64]*cb.Block{3: configBlock, 5: newestBlock},
+					newest: 5,
+				}
+				grpcServer = grpc.NewServer(grpc.Creds(credentials.NewTLS(tlsConfig)))
+				ab.RegisterAtomicBroadcastServer(grpcServer, sourceOrderer)
+				lis, err := net.Listen("tcp", "127.0.0.1:0")
+				Expect(err).NotTo(HaveOccurred())
+				sourceAddress = lis.Addr().String()
+				go grpcServer.Serve(lis)
+			})
+
+			AfterEach(func() {
+				grpcServer.Stop()
+			})
+
+			It("fetches the latest config block from the source orderer and joins the target orderer with it", func() {
+				args := []string{
+					"channel",
+					"join",
+					"--orderer-address", ordererURL,
+					"--channelID", channelID,
+					"--from-orderer", sourceAddress,
+					"--mspID", "SampleOrg",
+					"--signing-cert", clientCert,
+					"--signing-key", clientKey,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				expectedOutput := types.ChannelInfo{
+					Name:              "apple",
+					URL:               "/participation/v1/channels/apple",
+					ConsensusRelation: "banana",
+					Status:            "orange",
+					Height:            123,
+					LastModifiedBy:    clientSubject,
+				}
+				checkStatusOutput(output, exit, err, 201, expectedOutput)
+
+				Expect(sourceOrderer.ChannelIDs()).To(Equal([]string{"testing123", "testing123"}))
+				Expect(mockChannelManagement.JoinChannelCallCount()).To(Equal(1))
+				joinedChannelID, joinedBlock, _ := mockChannelManagement.JoinChannelArgsForCall(0)
+				Expect(joinedChannelID).To(Equal("testing123"))
+				Expect(proto.Equal(joinedBlock, configBlock)).To(BeTrue())
+			})
+
+			It("returns with exit code 1 and prints the error when the block cannot be fetched", func() {
+				args := []string{
+					"channel",
+					"join",
+					"--orderer-address", ordererURL,
+					"--channelID", "not-a-channel",
+					"--from-orderer", sourceAddress,
+					"--mspID", "SampleOrg",
+					"--signing-cert", clientCert,
+					"--signing-key", clientKey,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				checkCLIError(output, exit, err, fmt.Sprintf("fetching config block from %s: fetching newest block: expected a block, got status: NOT_FOUND", sourceAddress))
+				Expect(mockChannelManagement.JoinChannelCallCount()).To(Equal(0))
+			})
+
+			It("returns with exit code 1 when --config-block is also set", func() {
+				args := []string{
+					"channel",
+					"join",
+					"--orderer-address", ordererURL,
+					"--channelID", channelID,
+					"--config-block", blockPath,
+					"--from-orderer", sourceAddress,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				checkFlagError(output, exit, err, "--config-block and --from-orderer are mutually exclusive")
+			})
+
+			It("returns with exit code 1 when the signing identity is not set", func() {
+				args := []string{
+					"channel",
+					"join",
+					"--orderer-address", ordererURL,
+					"--channelID", channelID,
+					"--from-orderer", sourceAddress,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				checkFlagError(output, exit, err, "--from-orderer requires --mspID, --signing-cert and --signing-key")
+			})
+
+			It("returns with exit code 1 when --channelID is not set", func() {
+				args := []string{
+					"channel",
+					"join",
+					"--orderer-address", ordererURL,
+					"--from-orderer", sourceAddress,
+					"--mspID", "SampleOrg",
+					"--signing-cert", clientCert,
+					"--signing-key", clientKey,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				checkFlagError(output, exit, err, "--from-orderer requires --channelID")
+			})
+		})
+
+		Context("when neither --config-block nor --from-orderer is set", func() {
+			It("returns with exit code 1 and prints the error", func() {
+				args := []string{
+					"channel",
+					"join",
+					"--orderer-address", ordererURL,
+					"--channelID", channelID,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				checkFlagError(output, exit, err, "required flag --config-block, --config-block-b64, --from-orderer or --batch-file not provided")
+			})
+		})
+
+		Context("when the block is empty", func() {
+			BeforeEach(func() {
+				blockPath = createBlockFile(tempDir, &cb.Block{})
+			})
+
+			It("returns with exit code 1 and prints the error", func() {
+				args := []string{
+					"channel",
+					"join",
+					"--orderer-address", ordererURL,
+					"--channelID", channelID,
+					"--config-block", blockPath,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+
+				checkFlagError(output, exit, err, "failed to retrieve channel id - block is empty")
+			})
+		})
+
+		Context("when the --channelID does not match the channel ID in the block", func() {
+			BeforeEach(func() {
+				channelID = "not-the-channel-youre-looking-for"
+			})
+
+			It("returns with exit code 1 and prints the error", func() {
+				args := []string{
+					"channel",
+					"join",
+					"--orderer-address", ordererURL,
+					"--channelID", channelID,
+					"--config-block", blockPath,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+
+				checkFlagError(output, exit, err, "specified --channelID not-the-channel-youre-looking-for does not match channel ID testing123 in config block")
+			})
+		})
+
+		Context("when the block isn't a valid config block", func() {
+			BeforeEach(func() {
+				block := &cb.Block{
+					Data: &cb.BlockData{
+						Data: [][]byte{
+							protoutil.MarshalOrPanic(&cb.Envelope{
+								Payload: protoutil.MarshalOrPanic(&cb.Payload{
+									Header: &cb.Header{
+										ChannelHeader: protoutil.MarshalOrPanic(&cb.ChannelHeader{
+											Type:      int32(cb.HeaderType_ENDORSER_TRANSACTION),
+											ChannelId: channelID,
+										}),
+									},
+								}),
+							}),
+						},
+					},
+				}
+				block.Header = &cb.BlockHeader{DataHash: protoutil.BlockDataHash(block.Data)}
+				blockPath = createBlockFile(tempDir, block)
+			})
+
+			It("returns 422 unprocessable entity", func() {
+				args := []string{
+					"channel",
+					"join",
+					"--orderer-address", ordererURL,
+					"--channelID", channelID,
+					"--config-block", blockPath,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exit).To(Equal(0))
+
+				expectedOutput := types.ErrorResponse{
+					Error:  "invalid join block: block is not a config block",
+					Errors: []string{"invalid join block: block is not a config block"},
+				}
+				checkStatusOutput(output, exit, err, 422, expectedOutput)
+			})
+		})
+
+		Context("when joining the channel fails", func() {
+			BeforeEach(func() {
+				mockChannelManagement.JoinChannelReturns(types.ChannelInfo{}, types.ErrChannelAlreadyExists)
+			})
+
+			It("returns 405 not allowed", func() {
+				args := []string{
+					"channel",
+					"join",
+					"--orderer-address", ordererURL,
+					"--channelID", channelID,
+					"--config-block", blockPath,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				expectedOutput := types.ErrorResponse{
+					Error: "cannot join: channel already exists",
+				}
+				checkStatusOutput(output, exit, err, 405, expectedOutput)
+			})
+
+			It("returns 405 not allowed (without status)", func() {
+				args := []string{
+					"channel",
+					"join",
+					"--orderer-address", ordererURL,
+					"--channelID", channelID,
+					"--config-block", blockPath,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+					"--no-status",
+				}
+				output, exit, err := executeForArgs(args)
+				expectedOutput := types.ErrorResponse{
+					Error: "cannot join: channel already exists",
+				}
+				checkOutput(output, exit, err, expectedOutput)
+			})
+		})
+
+		Context("when TLS is disabled", func() {
+			BeforeEach(func() {
+				tlsConfig = nil
+			})
+
+			It("uses the channel participation API to join a channel", func() {
+				args := []string{
+					"channel",
+					"join",
+					"--orderer-address", ordererURL,
+					"--channelID", channelID,
+					"--config-block", blockPath,
+				}
+				output, exit, err := executeForArgs(args)
+				expectedOutput := types.ChannelInfo{
+					Name:              "apple",
+					URL:               "/participation/v1/channels/apple",
+					ConsensusRelation: "banana",
+					Status:            "orange",
+					Height:            123,
+				}
+				checkStatusOutput(output, exit, err, 201, expectedOutput)
+			})
+		})
+	})
+
+	Describe("PrintCert", func() {
+		It("prints the certificate chain presented by the OSN", func() {
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--print-cert",
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(HavePrefix("Certificate 0:\n"))
+			Expect(output).To(ContainSubstring("\tSANs: 127.0.0.1\n"))
+			Expect(output).To(ContainSubstring("\tNot After: "))
+			Expect(output).To(HaveSuffix("Verification: OK\n"))
+			Expect(mockChannelManagement.ChannelListCallCount()).To(Equal(0))
+		})
+
+		Context("when the ca-file does not match the server certificate", func() {
+			BeforeEach(func() {
+				ordererCACert = filepath.Join(tempDir, "client-ca.pem")
+			})
+
+			It("prints the certificate chain and the verification failure", func() {
+				args := []string{
+					"channel",
+					"list",
+					"--orderer-address", ordererURL,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+					"--print-cert",
+				}
+				output, exit, err := executeForArgs(args)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exit).To(Equal(0))
+				Expect(output).To(ContainSubstring("\tSANs: 127.0.0.1\n"))
+				Expect(output).To(ContainSubstring("Verification: failed: x509: certificate signed by unknown authority"))
+			})
+		})
+	})
+
+	Describe("Client certificate expiry warning", func() {
+		// the client certificate generated by tlsgen expires within a day
+		It("prints a warning to stderr when the client certificate expires within the threshold", func() {
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--output-cert-expiry-warning", "2",
+			}
+			output, exit, err := executeForArgs(args)
+			checkStatusOutput(output, exit, err, 200, types.ChannelList{})
+			Expect(stderr).To(gbytes.Say(`Warning: client certificate expires on \S+, within 2 days\n`))
+		})
+
+		It("does not print a warning by default", func() {
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			checkStatusOutput(output, exit, err, 200, types.ChannelList{})
+			Expect(stderr.(*gbytes.Buffer).Contents()).To(BeEmpty())
+		})
+
+		It("does not print a warning when disabled", func() {
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--output-cert-expiry-warning", "0",
+			}
+			output, exit, err := executeForArgs(args)
+			checkStatusOutput(output, exit, err, 200, types.ChannelList{})
+			Expect(stderr.(*gbytes.Buffer).Contents()).To(BeEmpty())
+		})
+	})
+
+	Describe("Template output", func() {
+		BeforeEach(func() {
+			mockChannelManagement.ChannelListReturns(types.ChannelList{
+				Channels: []types.ChannelInfoShort{
+					{Name: "participation-trophy"},
+					{Name: "another-participation-trophy"},
+				},
+			})
+			mockChannelManagement.JoinChannelReturns(types.ChannelInfo{
+				Name:              "apple",
+				ConsensusRelation: "banana",
+				Status:            "orange",
+				Height:            123,
+			}, nil)
+		})
+
+		It("renders a field of the join response", func() {
+			blockPath := createBlockFile(tempDir, blockWithGroups(map[string]*cb.ConfigGroup{"Application": {}}, "testing123"))
+			args := []string{
+				"channel",
+				"join",
+				"--orderer-address", ordererURL,
+				"--channelID", channelID,
+				"--config-block", blockPath,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--format", "template",
+				"--template", "{{.Height}}",
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(Equal("123"))
+		})
+
+		It("renders the fields of the list response", func() {
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--format", "template",
+				"--template", "{{range .Channels}}{{.Name}} {{.URL}}\n{{end}}",
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(Equal(
+				"participation-trophy /participation/v1/channels/participation-trophy\n" +
+					"another-participation-trophy /participation/v1/channels/another-participation-trophy\n",
+			))
+		})
+
+		It("prints error responses as JSON", func() {
+			mockChannelManagement.ChannelInfoReturns(types.ChannelInfo{}, errors.New("eat-your-peas"))
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--channelID", "tell-me-your-secrets",
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--format", "template",
+				"--template", "{{.Height}}",
+			}
+			output, exit, err := executeForArgs(args)
+			checkStatusOutput(output, exit, err, 404, types.ErrorResponse{Error: "eat-your-peas"})
+		})
+
+		It("returns with exit code 1 when the template is missing", func() {
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--format", "template",
+			}
+			output, exit, err := executeForArgs(args)
+			checkFlagError(output, exit, err, "--format template requires --template")
+		})
+
+		It("returns with exit code 1 when the template cannot be parsed", func() {
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--format", "template",
+				"--template", "{{.Height",
+			}
+			output, exit, err := executeForArgs(args)
+			checkFlagError(output, exit, err, "parsing --template: ")
+		})
+
+		It("returns with exit code 1 when used with remove", func() {
+			args := []string{
+				"channel",
+				"remove",
+				"--orderer-address", ordererURL,
+				"--channelID", channelID,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--format", "template",
+				"--template", "{{.}}",
+			}
+			output, exit, err := executeForArgs(args)
+			checkFlagError(output, exit, err, "--format template is not supported by channel remove")
+		})
+	})
+
+	Describe("Table output", func() {
+		var originalIsTerminal func() bool
+
+		BeforeEach(func() {
+			originalIsTerminal = isTerminal
+			isTerminal = func() bool { return false }
+
+			mockChannelManagement.ChannelListReturns(types.ChannelList{
+				Channels: []types.ChannelInfoShort{
+					{Name: "participation-trophy"},
+				},
+				SystemChannel: &types.ChannelInfoShort{Name: "fight-the-system"},
+			})
+			mockChannelManagement.ChannelInfoReturns(types.ChannelInfo{
+				Name:              "participation-trophy",
+				ConsensusRelation: "consenter",
+				Status:            "active",
+				Height:            123,
+			}, nil)
+		})
+
+		AfterEach(func() {
+			isTerminal = originalIsTerminal
+		})
+
+		It("renders the list response as a table", func() {
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--format", "table",
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(Equal(
+				"NAME                  TYPE         URL\n" +
+					"fight-the-system      system       /participation/v1/channels/fight-the-system\n" +
+					"participation-trophy  application  /participation/v1/channels/participation-trophy\n",
+			))
+		})
+
+		It("does not color the status when the output is not a terminal", func() {
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--channelID", "participation-trophy",
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--format", "table",
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(Equal(
+				"NAME                  CONSENSUS RELATION  HEIGHT  STATUS\n" +
+					"participation-trophy  consenter           123     active\n",
+			))
+			Expect(output).NotTo(ContainSubstring("\x1b["))
+		})
+
+		Context("when the output is a terminal", func() {
+			BeforeEach(func() {
+				isTerminal = func() bool { return true }
+			})
+
+			It("colors the status", func() {
+				args := []string{
+					"channel",
+					"list",
+					"--orderer-address", ordererURL,
+					"--channelID", "participation-trophy",
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+					"--format", "table",
+				}
+				output, exit, err := executeForArgs(args)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exit).To(Equal(0))
+				Expect(output).To(HaveSuffix("  \x1b[32mactive\x1b[0m\n"))
+			})
+
+			It("does not color the status with --no-color", func() {
+				args := []string{
+					"channel",
+					"list",
+					"--orderer-address", ordererURL,
+					"--channelID", "participation-trophy",
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+					"--format", "table",
+					"--no-color",
+				}
+				output, exit, err := executeForArgs(args)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exit).To(Equal(0))
+				Expect(output).NotTo(ContainSubstring("\x1b["))
+			})
+		})
+
+		Context("when the columns are set", func() {
+			tableArgs := func(columns string, extraArgs ...string) []string {
+				return append([]string{
+					"channel",
+					"list",
+					"--orderer-address", ordererURL,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+					"--format", "table",
+					"--columns", columns,
+				}, extraArgs...)
+			}
+
+			It("renders the columns of the channel in the order they are set", func() {
+				output, exit, err := executeForArgs(tableArgs("height,name,status", "--channelID", "participation-trophy"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exit).To(Equal(0))
+				Expect(output).To(Equal(
+					"HEIGHT  NAME                  STATUS\n" +
+						"123     participation-trophy  active\n",
+				))
+			})
+
+			It("renders the columns of the list in the order they are set", func() {
+				output, exit, err := executeForArgs(tableArgs("url,name"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exit).To(Equal(0))
+				Expect(output).To(Equal(
+					"URL                                              NAME\n" +
+						"/participation/v1/channels/fight-the-system      fight-the-system\n" +
+						"/participation/v1/channels/participation-trophy  participation-trophy\n",
+				))
+			})
+
+			It("returns with exit code 1 for an unknown column", func() {
+				output, exit, err := executeForArgs(tableArgs("name,type", "--channelID", "participation-trophy"))
+				checkFlagError(output, exit, err, `parsing --columns: unknown column "type", the columns are: name, consensusRelation, height, status`)
+			})
+
+			It("returns with exit code 1 for a duplicate column", func() {
+				output, exit, err := executeForArgs(tableArgs("name,url,name"))
+				checkFlagError(output, exit, err, `parsing --columns: duplicate column "name"`)
+			})
+
+			It("returns with exit code 1 without --format table", func() {
+				args := []string{
+					"channel",
+					"list",
+					"--orderer-address", ordererURL,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+					"--columns", "name",
+				}
+				output, exit, err := executeForArgs(args)
+				checkFlagError(output, exit, err, "--columns requires --format table")
+			})
+		})
+
+		It("prints error responses as JSON", func() {
+			mockChannelManagement.ChannelInfoReturns(types.ChannelInfo{}, errors.New("eat-your-peas"))
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--channelID", "tell-me-your-secrets",
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--format", "table",
+			}
+			output, exit, err := executeForArgs(args)
+			checkStatusOutput(output, exit, err, 404, types.ErrorResponse{Error: "eat-your-peas"})
+		})
+
+		It("returns with exit code 1 when used with remove", func() {
+			args := []string{
+				"channel",
+				"remove",
+				"--orderer-address", ordererURL,
+				"--channelID", channelID,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--format", "table",
+			}
+			output, exit, err := executeForArgs(args)
+			checkFlagError(output, exit, err, "--format table is not supported by channel remove")
+		})
+	})
+
+	Describe("YAML output", func() {
+		BeforeEach(func() {
+			mockChannelManagement.ChannelListReturns(types.ChannelList{
+				Channels: []types.ChannelInfoShort{
+					{Name: "participation-trophy"},
+				},
+				SystemChannel: &types.ChannelInfoShort{Name: "fight-the-system"},
+			})
+			mockChannelManagement.ChannelInfoReturns(types.ChannelInfo{
+				Name:              "participation-trophy",
+				ConsensusRelation: "consenter",
+				Status:            "active",
+				Height:            123,
+			}, nil)
+		})
+
+		yamlArgs := func(command string, extraArgs ...string) []string {
+			return append([]string{
+				"channel",
+				command,
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--format", "yaml",
+			}, extraArgs...)
+		}
+
+		It("renders the list response as YAML", func() {
+			output, exit, err := executeForArgs(yamlArgs("list"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(Equal(
+				"systemChannel:\n" +
+					"  name: fight-the-system\n" +
+					"  url: /participation/v1/channels/fight-the-system\n" +
+					"channels:\n" +
+					"- name: participation-trophy\n" +
+					"  url: /participation/v1/channels/participation-trophy\n" +
+					"count: 2\n" +
+					"revision: 0\n",
+			))
+
+			list := map[string]interface{}{}
+			Expect(yaml.UnmarshalStrict([]byte(output), &list)).To(Succeed())
+			Expect(list).To(HaveKey("systemChannel"))
+			Expect(list["channels"]).To(HaveLen(1))
+		})
+
+		It("renders the info response as YAML", func() {
+			output, exit, err := executeForArgs(yamlArgs("list", "--channelID", "participation-trophy"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(Equal(
+				"name: participation-trophy\n" +
+					"url: /participation/v1/channels/participation-trophy\n" +
+					"consensusRelation: consenter\n" +
+					"status: active\n" +
+					"height: 123\n",
+			))
+
+			info := map[string]interface{}{}
+			Expect(yaml.UnmarshalStrict([]byte(output), &info)).To(Succeed())
+			Expect(info).To(HaveKeyWithValue("height", 123))
+		})
+
+		It("prints error responses as JSON", func() {
+			mockChannelManagement.ChannelInfoReturns(types.ChannelInfo{}, errors.New("eat-your-peas"))
+			output, exit, err := executeForArgs(yamlArgs("list", "--channelID", "tell-me-your-secrets"))
+			checkStatusOutput(output, exit, err, 404, types.ErrorResponse{Error: "eat-your-peas"})
+		})
+
+		It("returns with exit code 1 when used with remove", func() {
+			output, exit, err := executeForArgs(yamlArgs("remove", "--channelID", channelID))
+			checkFlagError(output, exit, err, "--format yaml is not supported by channel remove")
+		})
+
+		It("returns with exit code 1 with --output-status-only", func() {
+			output, exit, err := executeForArgs(yamlArgs("list", "--output-status-only"))
+			checkFlagError(output, exit, err, "--format yaml and --output-status-only are mutually exclusive")
+		})
+	})
+
+	Describe("Trust on first use", func() {
+		var (
+			pinFile     string
+			fingerprint string
+		)
+
+		BeforeEach(func() {
+			pinFile = filepath.Join(tempDir, "orderer.pin")
+			serverCert, err := x509.ParseCertificate(tlsConfig.Certificates[0].Certificate[0])
+			Expect(err).NotTo(HaveOccurred())
+			digest := sha256.Sum256(serverCert.Raw)
+			fingerprint = hex.EncodeToString(digest[:])
+
+			mockChannelManagement.ChannelListReturns(types.ChannelList{
+				Channels: []types.ChannelInfoShort{{Name: "participation-trophy"}},
+			})
+		})
+
+		listArgs := func() []string {
+			return []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--trust-on-first-use", pinFile,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+		}
+
+		expectedOutput := types.ChannelList{
+			Channels: []types.ChannelInfoShort{
+				{Name: "participation-trophy", URL: "/participation/v1/channels/participation-trophy"},
+			},
+			Count: 1,
+		}
+
+		It("records the fingerprint of the server certificate on first use", func() {
+			output, exit, err := executeForArgs(listArgs())
+			checkStatusOutput(output, exit, err, 200, expectedOutput)
+			Expect(stderr).To(gbytes.Say(`Note: trusting the TLS certificate of the OSN on first use, its fingerprint is pinned in ` + regexp.QuoteMeta(pinFile)))
+
+			pin, err := ioutil.ReadFile(pinFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(pin)).To(Equal(fingerprint + "\n"))
+		})
+
+		It("connects when the server certificate matches the pin", func() {
+			Expect(ioutil.WriteFile(pinFile, []byte(strings.ToUpper(fingerprint)+"\n"), 0o600)).To(Succeed())
+
+			output, exit, err := executeForArgs(listArgs())
+			checkStatusOutput(output, exit, err, 200, expectedOutput)
+			Expect(stderr).NotTo(gbytes.Say(`Note: trusting`))
+		})
+
+		It("rejects a server certificate that does not match the pin", func() {
+			otherFingerprint := strings.Repeat("ab", 32)
+			Expect(ioutil.WriteFile(pinFile, []byte(otherFingerprint+"\n"), 0o600)).To(Succeed())
+
+			output, exit, err := executeForArgs(listArgs())
+			checkCLIError(output, exit, err, fmt.Sprintf("the TLS certificate of the OSN does not match the pin in %s: expected SHA-256 fingerprint %s, got %s", pinFile, otherFingerprint, fingerprint))
+			Expect(mockChannelManagement.ChannelListCallCount()).To(Equal(0))
+
+			pin, err := ioutil.ReadFile(pinFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(pin)).To(Equal(otherFingerprint + "\n"))
+		})
+
+		It("returns with exit code 1 when used with --ca-file", func() {
+			args := append(listArgs(), "--ca-file", ordererCACert)
+			output, exit, err := executeForArgs(args)
+			checkFlagError(output, exit, err, "--ca-file and --trust-on-first-use are mutually exclusive")
+		})
+	})
+
+	Describe("Consensus state", func() {
+		consensusStateArgs := func(command string) []string {
+			return []string{
+				"channel",
+				command,
+				"--orderer-address", ordererURL,
+				"--channelID", channelID,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--mspID", "SampleOrg",
+				"--signing-cert", clientCert,
+				"--signing-key", clientKey,
+			}
+		}
+
+		consensusStateUpdate := func(channelID string, state ab.ConsensusType_State, extraValues ...string) *cb.ConfigUpdate {
+			values := map[string]*cb.ConfigValue{
+				"ConsensusType": {
+					Version: 1,
+					Value:   protoutil.MarshalOrPanic(&ab.ConsensusType{Type: "etcdraft", State: state}),
+				},
+			}
+			for _, key := range extraValues {
+				values[key] = &cb.ConfigValue{Version: 1}
+			}
+			return &cb.ConfigUpdate{
+				ChannelId: channelID,
+				WriteSet: &cb.ConfigGroup{
+					Groups: map[string]*cb.ConfigGroup{
+						"Orderer": {Values: values},
+					},
+				},
+			}
+		}
+
+		checkSignedUpdate := func(configUpdate *cb.ConfigUpdate) {
+			Expect(mockChannelManagement.UpdateChannelConfigCallCount()).To(Equal(1))
+			updatedChannelID, configUpdateEnv := mockChannelManagement.UpdateChannelConfigArgsForCall(0)
+			Expect(updatedChannelID).To(Equal(channelID))
+			Expect(configUpdateEnv.ConfigUpdate).To(Equal(protoutil.MarshalOrPanic(configUpdate)))
+			Expect(configUpdateEnv.Signatures).To(HaveLen(1))
+
+			sigHeader, err := protoutil.UnmarshalSignatureHeader(configUpdateEnv.Signatures[0].SignatureHeader)
+			Expect(err).NotTo(HaveOccurred())
+			identity, err := protoutil.UnmarshalSerializedIdentity(sigHeader.Creator)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(identity.Mspid).To(Equal("SampleOrg"))
+			Expect(configUpdateEnv.Signatures[0].Signature).NotTo(BeEmpty())
+		}
+
+		It("uses the channel participation API to put a channel into maintenance mode", func() {
+			configUpdate := consensusStateUpdate(channelID, ab.ConsensusType_STATE_MAINTENANCE)
+			mockChannelManagement.ComputeConfigUpdateReturns(configUpdate, nil)
+
+			output, exit, err := executeForArgs(consensusStateArgs("set-maintenance"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(Equal("Status: 202\n"))
+
+			Expect(mockChannelManagement.ComputeConfigUpdateCallCount()).To(Equal(1))
+			patchedChannelID, patch := mockChannelManagement.ComputeConfigUpdateArgsForCall(0)
+			Expect(patchedChannelID).To(Equal(channelID))
+			Expect(patch.ConsensusState).To(PointTo(Equal("maintenance")))
+			checkSignedUpdate(configUpdate)
+		})
+
+		It("uses the channel participation API to take a channel out of maintenance mode", func() {
+			configUpdate := consensusStateUpdate(channelID, ab.ConsensusType_STATE_NORMAL)
+			mockChannelManagement.ComputeConfigUpdateReturns(configUpdate, nil)
+
+			output, exit, err := executeForArgs(consensusStateArgs("set-normal"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(Equal("Status: 202\n"))
+
+			Expect(mockChannelManagement.ComputeConfigUpdateCallCount()).To(Equal(1))
+			patchedChannelID, patch := mockChannelManagement.ComputeConfigUpdateArgsForCall(0)
+			Expect(patchedChannelID).To(Equal(channelID))
+			Expect(patch.ConsensusState).To(PointTo(Equal("normal")))
+			checkSignedUpdate(configUpdate)
+		})
+
+		Context("when the channel is already in the consensus state", func() {
+			BeforeEach(func() {
+				mockChannelManagement.ComputeConfigUpdateReturns(nil, types.ErrConsensusStateUnchanged)
+			})
+
+			It("returns 409 conflict without submitting a config update", func() {
+				output, exit, err := executeForArgs(consensusStateArgs("set-maintenance"))
+				expectedOutput := types.ErrorResponse{
+					Error: "cannot update: channel is already in the consensus state",
+				}
+				checkStatusOutput(output, exit, err, 409, expectedOutput)
+				Expect(mockChannelManagement.UpdateChannelConfigCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the submitted config update is rejected", func() {
+			BeforeEach(func() {
+				mockChannelManagement.ComputeConfigUpdateReturns(consensusStateUpdate(channelID, ab.ConsensusType_STATE_MAINTENANCE), nil)
+				mockChannelManagement.UpdateChannelConfigReturns(errors.New("config update rejected: policy not satisfied"))
+			})
+
+			It("returns 400 bad request", func() {
+				output, exit, err := executeForArgs(consensusStateArgs("set-maintenance"))
+				expectedOutput := types.ErrorResponse{
+					Error: "cannot update: config update rejected: policy not satisfied",
+				}
+				checkStatusOutput(output, exit, err, 400, expectedOutput)
+			})
+		})
+
+		Context("when the computed config update does not only switch the consensus state", func() {
+			It("refuses to sign a config update of another channel", func() {
+				mockChannelManagement.ComputeConfigUpdateReturns(consensusStateUpdate("other-channel", ab.ConsensusType_STATE_MAINTENANCE), nil)
+				output, exit, err := executeForArgs(consensusStateArgs("set-maintenance"))
+				checkCLIError(output, exit, err, fmt.Sprintf("config update is for channel other-channel, not %s", channelID))
+				Expect(mockChannelManagement.UpdateChannelConfigCallCount()).To(Equal(0))
+			})
+
+			It("refuses to sign a config update of other values", func() {
+				mockChannelManagement.ComputeConfigUpdateReturns(consensusStateUpdate(channelID, ab.ConsensusType_STATE_MAINTENANCE, "BatchSize"), nil)
+				output, exit, err := executeForArgs(consensusStateArgs("set-maintenance"))
+				checkCLIError(output, exit, err, "config update changes more than the consensus type")
+				Expect(mockChannelManagement.UpdateChannelConfigCallCount()).To(Equal(0))
+			})
+
+			It("refuses to sign a config update to another consensus state", func() {
+				mockChannelManagement.ComputeConfigUpdateReturns(consensusStateUpdate(channelID, ab.ConsensusType_STATE_NORMAL), nil)
+				output, exit, err := executeForArgs(consensusStateArgs("set-maintenance"))
+				checkCLIError(output, exit, err, "config update sets the consensus state to STATE_NORMAL, not STATE_MAINTENANCE")
+				Expect(mockChannelManagement.UpdateChannelConfigCallCount()).To(Equal(0))
+			})
+		})
+
+		It("returns with exit code 1 when the signing identity cannot be loaded", func() {
+			args := consensusStateArgs("set-normal")
+			args[len(args)-1] = filepath.Join(tempDir, "missing-key.pem")
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+			Expect(output).To(HavePrefix("Error: loading signing identity: "))
+			Expect(mockChannelManagement.ComputeConfigUpdateCallCount()).To(Equal(0))
+		})
+
+		It("requires --signing-key", func() {
+			args := consensusStateArgs("set-maintenance")
+			output, exit, err := executeForArgs(args[:len(args)-2])
+			checkFlagError(output, exit, err, "required flag --signing-key not provided")
+		})
+
+		It("requires --channelID", func() {
+			args := []string{
+				"channel",
+				"set-normal",
+				"--orderer-address", ordererURL,
+				"--mspID", "SampleOrg",
+				"--signing-cert", clientCert,
+				"--signing-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			checkFlagError(output, exit, err, "required flag --channelID not provided")
+		})
+
+		It("does not support --format table", func() {
+			args := append(consensusStateArgs("set-maintenance"), "--format", "table")
+			output, exit, err := executeForArgs(args)
+			checkFlagError(output, exit, err, "--format table is not supported by channel set-maintenance")
+		})
+	})
+
+	Describe("Diff", func() {
+		var (
+			otherChannelManagement *mocks.ChannelManagement
+			otherServer            *httptest.Server
+			otherOrdererURL        string
+		)
+
+		channelInfos := func(infos ...types.ChannelInfo) func(string) (types.ChannelInfo, error) {
+			return func(channelID string) (types.ChannelInfo, error) {
+				for _, info := range infos {
+					if info.Name == channelID {
+						return info, nil
+					}
+				}
+				return types.ChannelInfo{}, types.ErrChannelNotExist
+			}
+		}
+
+		BeforeEach(func() {
+			mockChannelManagement.ChannelListReturns(types.ChannelList{
+				Channels: []types.ChannelInfoShort{{Name: "shared"}, {Name: "lagging"}, {Name: "first-only"}},
+			})
+			mockChannelManagement.ChannelInfoCalls(channelInfos(
+				types.ChannelInfo{Name: "shared", ConsensusRelation: "consenter", Status: "active", Height: 10},
+				types.ChannelInfo{Name: "lagging", ConsensusRelation: "consenter", Status: "active", Height: 7},
+				types.ChannelInfo{Name: "first-only", ConsensusRelation: "follower", Status: "active", Height: 3},
+			))
+
+			otherChannelManagement = &mocks.ChannelManagement{}
+			otherChannelManagement.ChannelListReturns(types.ChannelList{
+				Channels: []types.ChannelInfoShort{{Name: "second-only"}, {Name: "lagging"}, {Name: "shared"}},
+			})
+			otherChannelManagement.ChannelInfoCalls(channelInfos(
+				types.ChannelInfo{Name: "shared", ConsensusRelation: "consenter", Status: "active", Height: 12},
+				types.ChannelInfo{Name: "lagging", ConsensusRelation: "follower", Status: "onboarding", Height: 5},
+				types.ChannelInfo{Name: "second-only", ConsensusRelation: "consenter", Status: "active", Height: 1},
+			))
+			config := localconfig.ChannelParticipation{Enabled: true}
+			otherServer = httptest.NewUnstartedServer(channelparticipation.NewHTTPHandler(config, otherChannelManagement))
+		})
+
+		JustBeforeEach(func() {
+			otherServer.TLS = tlsConfig
+			otherServer.StartTLS()
+			u, err := url.Parse(otherServer.URL)
+			Expect(err).NotTo(HaveOccurred())
+			otherOrdererURL = u.Host
+		})
+
+		AfterEach(func() {
+			otherServer.Close()
+		})
+
+		diffArgs := func(orderers ...string) []string {
+			args := []string{"channel", "diff"}
+			for _, orderer := range orderers {
+				args = append(args, "--orderer", orderer)
+			}
+			return append(args,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			)
+		}
+
+		It("prints the channels only one of the OSNs is in, and those with a different relation", func() {
+			output, exit, err := executeForArgs(diffArgs(ordererURL, otherOrdererURL))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+
+			expectedOutput := channelsDiff{
+				Orderers:   []string{ordererURL, otherOrdererURL},
+				OnlyFirst:  []string{"first-only"},
+				OnlySecond: []string{"second-only"},
+				Differing: []channelDifference{
+					{
+						Name:   "lagging",
+						First:  channelState{ConsensusRelation: "consenter"},
+						Second: channelState{ConsensusRelation: "follower"},
+					},
+				},
+			}
+			expected, err := json.MarshalIndent(expectedOutput, "", "\t")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(Equal(string(expected) + "\n"))
+		})
+
+		It("also prints the channels with a different height with --compare-height", func() {
+			output, exit, err := executeForArgs(append(diffArgs(ordererURL, otherOrdererURL), "--compare-height"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+
+			expectedOutput := channelsDiff{
+				Orderers:   []string{ordererURL, otherOrdererURL},
+				OnlyFirst:  []string{"first-only"},
+				OnlySecond: []string{"second-only"},
+				Differing: []channelDifference{
+					{
+						Name:   "lagging",
+						First:  channelState{ConsensusRelation: "consenter", Height: 7},
+						Second: channelState{ConsensusRelation: "follower", Height: 5},
+					},
+					{
+						Name:   "shared",
+						First:  channelState{ConsensusRelation: "consenter", Height: 10},
+						Second: channelState{ConsensusRelation: "consenter", Height: 12},
+					},
+				},
+			}
+			expected, err := json.MarshalIndent(expectedOutput, "", "\t")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(Equal(string(expected) + "\n"))
+		})
+
+		It("exits with code 0 when the OSNs are in the same channels", func() {
+			output, exit, err := executeForArgs(diffArgs(ordererURL, ordererURL))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(ContainSubstring(`"onlyFirst": []`))
+			Expect(output).To(ContainSubstring(`"onlySecond": []`))
+			Expect(output).To(ContainSubstring(`"differing": []`))
+		})
+
+		It("reports a failure to list the channels of an OSN with exit code 2", func() {
+			otherServer.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"error":"oops"}`))
+			})
+			output, exit, err := executeForArgs(diffArgs(ordererURL, otherOrdererURL))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(2))
+			Expect(output).To(Equal(fmt.Sprintf("Error: %s: listing channels: OSN responded with status 500: oops\n", otherOrdererURL)))
+		})
+
+		It("requires --orderer exactly twice, exiting with code 2", func() {
+			output, exit, err := executeForArgs(diffArgs(ordererURL))
+			Expect(err).To(MatchError("channel diff requires --orderer exactly twice"))
+			Expect(exit).To(Equal(2))
+			Expect(output).To(BeEmpty())
+		})
+
+		It("does not support --format table", func() {
+			args := append(diffArgs(ordererURL, otherOrdererURL), "--format", "table")
+			output, exit, err := executeForArgs(args)
+			Expect(err).To(MatchError("--format table is not supported by channel diff"))
+			Expect(exit).To(Equal(2))
+			Expect(output).To(BeEmpty())
+		})
+	})
+
+	Describe("Follow", func() {
+		var (
+			blockPath string
+			// the Server-Sent Events of the channel, sent in order
+			events []string
+			// keeps the event stream open after the events are sent
+			hold bool
+			// the events of the streams after the first one, which ended early
+			reconnectEvents []string
+			connections     int32
+
+			originalReconnectDelay time.Duration
+		)
+
+		progressEvent := func(status string, height int) string {
+			return fmt.Sprintf("event: progress\ndata: {\"name\":\"testing123\",\"status\":%q,\"height\":%d}\n\n", status, height)
+		}
+		onboardingEvent := func(height, targetHeight int) string {
+			return fmt.Sprintf("event: progress\ndata: {\"name\":\"testing123\",\"status\":\"onboarding\",\"height\":%d,\"targetHeight\":%d}\n\n", height, targetHeight)
+		}
+
+		BeforeEach(func() {
+			configBlock := blockWithGroups(
+				map[string]*cb.ConfigGroup{
+					"Application": {},
+				},
+				"testing123",
+			)
+			blockPath = createBlockFile(tempDir, configBlock)
+
+			mockChannelManagement.JoinChannelReturns(types.ChannelInfo{
+				Name:   "testing123",
+				Status: "onboarding",
+				Height: 1,
+			}, nil)
+
+			events = []string{
+				progressEvent("onboarding", 1),
+				progressEvent("onboarding", 50),
+				progressEvent("active", 100),
+			}
+			hold = false
+			reconnectEvents = nil
+			atomic.StoreInt32(&connections, 0)
+
+			originalReconnectDelay = followReconnectDelay
+			followReconnectDelay = 10 * time.Millisecond
+
+			participation := testServer.Config.Handler
+			testServer.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/participation/v1/channels/testing123/events" {
+					participation.ServeHTTP(w, r)
+					return
+				}
+				Expect(r.Header.Get("Accept")).To(Equal("text/event-stream"))
+				w.Header().Set("Content-Type", "text/event-stream")
+				events := events
+				if atomic.AddInt32(&connections, 1) > 1 && reconnectEvents != nil {
+					events = reconnectEvents
+				}
+				for _, event := range events {
+					fmt.Fprint(w, event)
+					w.(http.Flusher).Flush()
+				}
+				if hold {
+					<-r.Context().Done()
+				}
+			})
+		})
+
+		AfterEach(func() {
+			followReconnectDelay = originalReconnectDelay
+		})
+
+		joinArgs := func(extraArgs ...string) []string {
+			return append([]string{
+				"channel",
+				"join",
+				"--orderer-address", ordererURL,
+				"--config-block", blockPath,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--follow",
+			}, extraArgs...)
+		}
+
+		It("prints the progress of the channel until it is active", func() {
+			output, exit, err := executeForArgs(joinArgs())
+			expectedOutput := types.ChannelInfo{
+				Name:           "testing123",
+				URL:            "/participation/v1/channels/testing123",
+				Status:         "onboarding",
+				Height:         1,
+				LastModifiedBy: clientSubject,
+			}
+			checkStatusOutput(output, exit, err, 201, expectedOutput)
+			Expect(stderr).To(gbytes.Say("Channel testing123: onboarding, height 1\n"))
+			Expect(stderr).To(gbytes.Say("Channel testing123: onboarding, height 50\n"))
+			Expect(stderr).To(gbytes.Say("Channel testing123: active, height 100\n"))
+		})
+
+		Context("when the target height is known", func() {
+			var originalStderrIsTerminal func() bool
+
+			BeforeEach(func() {
+				originalStderrIsTerminal = stderrIsTerminal
+				events = []string{
+					onboardingEvent(1, 100),
+					onboardingEvent(50, 100),
+					progressEvent("active", 100),
+				}
+			})
+
+			AfterEach(func() {
+				stderrIsTerminal = originalStderrIsTerminal
+			})
+
+			It("prints plain-text progress lines when stderr is not a terminal", func() {
+				output, exit, err := executeForArgs(joinArgs())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exit).To(Equal(0))
+				Expect(output).To(HavePrefix("Status: 201\n"))
+				Expect(stderr).To(gbytes.Say("Channel testing123: onboarding, height 1 of 100\n"))
+				Expect(stderr).To(gbytes.Say("Channel testing123: onboarding, height 50 of 100\n"))
+				Expect(stderr).To(gbytes.Say("Channel testing123: active, height 100\n"))
+				Expect(string(stderr.(*gbytes.Buffer).Contents())).NotTo(ContainSubstring("\x1b"))
+				Expect(string(stderr.(*gbytes.Buffer).Contents())).NotTo(ContainSubstring("\r"))
+			})
+
+			It("draws a progress bar when stderr is a terminal", func() {
+				stderrIsTerminal = func() bool { return true }
+				output, exit, err := executeForArgs(joinArgs())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exit).To(Equal(0))
+				Expect(output).To(HavePrefix("Status: 201\n"))
+				Expect(string(stderr.(*gbytes.Buffer).Contents())).To(HaveSuffix(
+					"\r\x1b[KChannel testing123: onboarding [------------------------------]   1% 1/100" +
+						"\r\x1b[KChannel testing123: onboarding [###############---------------]  50% 50/100" +
+						"\nChannel testing123: active, height 100\n",
+				))
+			})
+
+			It("ends the line of the progress bar when the channel fails", func() {
+				stderrIsTerminal = func() bool { return true }
+				events = []string{onboardingEvent(1, 100)}
+				hold = true
+				output, exit, err := executeForArgs(joinArgs("--follow-timeout", "100ms"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exit).To(Equal(1))
+				Expect(output).To(HaveSuffix("Error: channel testing123 is not active after 100ms\n"))
+				Expect(string(stderr.(*gbytes.Buffer).Contents())).To(HaveSuffix("1/100\n"))
+			})
+		})
+
+		It("returns with exit code 1 when the channel fails", func() {
+			events = []string{
+				progressEvent("onboarding", 1),
+				progressEvent("failed", 7),
+			}
+			output, exit, err := executeForArgs(joinArgs())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+			Expect(output).To(HavePrefix("Status: 201\n"))
+			Expect(output).To(HaveSuffix("Error: channel testing123 is failed\n"))
+			Expect(stderr).To(gbytes.Say("Channel testing123: failed, height 7\n"))
+		})
+
+		It("returns with exit code 1 for an error event", func() {
+			events = []string{
+				progressEvent("onboarding", 1),
+				"event: error\ndata: {\"error\":\"channel does not exist\"}\n\n",
+			}
+			output, exit, err := executeForArgs(joinArgs())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+			Expect(output).To(HaveSuffix("Error: following channel testing123: channel does not exist\n"))
+		})
+
+		It("reconnects when the stream ends before the channel is active", func() {
+			events = []string{progressEvent("onboarding", 1)}
+			reconnectEvents = []string{
+				progressEvent("onboarding", 50),
+				progressEvent("active", 100),
+			}
+			output, exit, err := executeForArgs(joinArgs())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(HavePrefix("Status: 201\n"))
+			Expect(stderr).To(gbytes.Say("Channel testing123: onboarding, height 1\n"))
+			Expect(stderr).To(gbytes.Say("Channel testing123: onboarding, height 50\n"))
+			Expect(stderr).To(gbytes.Say("Channel testing123: active, height 100\n"))
+			Expect(atomic.LoadInt32(&connections)).To(Equal(int32(2)))
+		})
+
+		It("keeps reconnecting until the timeout when the stream keeps ending", func() {
+			events = []string{progressEvent("onboarding", 1)}
+			output, exit, err := executeForArgs(joinArgs("--follow-timeout", "200ms"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+			Expect(output).To(HaveSuffix("Error: channel testing123 is not active after 200ms\n"))
+			Expect(atomic.LoadInt32(&connections)).To(BeNumerically(">", 1))
+		})
+
+		It("returns with exit code 1 when the channel is not active before the timeout", func() {
+			events = []string{progressEvent("onboarding", 1)}
+			hold = true
+			output, exit, err := executeForArgs(joinArgs("--follow-timeout", "100ms"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+			Expect(output).To(HaveSuffix("Error: channel testing123 is not active after 100ms\n"))
+			Expect(stderr).To(gbytes.Say("Channel testing123: onboarding, height 1\n"))
+		})
+
+		It("does not follow a channel that failed to join", func() {
+			mockChannelManagement.JoinChannelReturns(types.ChannelInfo{}, types.ErrChannelAlreadyExists)
+			output, exit, err := executeForArgs(joinArgs())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(HavePrefix("Status: 405\n"))
+			Expect(string(stderr.(*gbytes.Buffer).Contents())).NotTo(ContainSubstring("Channel testing123"))
+		})
+
+		It("returns with exit code 1 for a non-positive --follow-timeout", func() {
+			output, exit, err := executeForArgs(joinArgs("--follow-timeout", "0"))
+			checkFlagError(output, exit, err, "--follow-timeout must be positive")
+		})
+
+		It("returns with exit code 1 with --output-status-only", func() {
+			output, exit, err := executeForArgs(joinArgs("--output-status-only"))
+			checkFlagError(output, exit, err, "--follow and --output-status-only are mutually exclusive")
+		})
+	})
+
+	Describe("Dry run on the server", func() {
+		var (
+			blockPath string
+			// the query of the last join request received by the OSN
+			joinQuery string
+		)
+
+		BeforeEach(func() {
+			configBlock := blockWithGroups(
+				map[string]*cb.ConfigGroup{
+					"Application": {},
+				},
+				"testing123",
+			)
+			blockPath = createBlockFile(tempDir, configBlock)
+
+			mockChannelManagement.ValidateJoinReturns(types.ChannelInfo{
+				Name:              "testing123",
+				ConsensusRelation: "consenter",
+				Status:            "active",
+				Height:            1,
+			}, nil)
+
+			joinQuery = ""
+			participation := testServer.Config.Handler
+			testServer.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					joinQuery = r.URL.RawQuery
+				}
+				participation.ServeHTTP(w, r)
+			})
+		})
+
+		dryRunArgs := func(extraArgs ...string) []string {
+			return append([]string{
+				"channel",
+				"join",
+				"--orderer-address", ordererURL,
+				"--config-block", blockPath,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--dry-run-server",
+			}, extraArgs...)
+		}
+
+		It("sends the join with the dryRun query and does not create the channel", func() {
+			output, exit, err := executeForArgs(dryRunArgs())
+			expectedOutput := types.ChannelInfo{
+				Name:              "testing123",
+				URL:               "/participation/v1/channels/testing123",
+				ConsensusRelation: "consenter",
+				Status:            "active",
+				Height:            1,
+			}
+			checkStatusOutput(output, exit, err, 200, expectedOutput)
+			Expect(joinQuery).To(Equal("dryRun=true"))
+			Expect(mockChannelManagement.ValidateJoinCallCount()).To(Equal(1))
+			Expect(mockChannelManagement.JoinChannelCallCount()).To(Equal(0))
+		})
+
+		It("returns with exit code 1 when the join would fail", func() {
+			mockChannelManagement.ValidateJoinReturns(types.ChannelInfo{}, types.ErrChannelAlreadyExists)
+			output, exit, err := executeForArgs(dryRunArgs())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+			Expect(output).To(HavePrefix("Status: 405\n"))
+			Expect(output).To(ContainSubstring("cannot join: channel already exists"))
+			Expect(mockChannelManagement.JoinChannelCallCount()).To(Equal(0))
+		})
+
+		It("returns with exit code 1 when the OSN ignores the dry run and joins", func() {
+			participation := testServer.Config.Handler
+			testServer.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				r.URL.RawQuery = ""
+				participation.ServeHTTP(w, r)
+			})
+			mockChannelManagement.JoinChannelReturns(types.ChannelInfo{Name: "testing123"}, nil)
+			output, exit, err := executeForArgs(dryRunArgs())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+			Expect(output).To(HavePrefix("Status: 201\n"))
+			Expect(output).To(HaveSuffix("Error: the OSN does not support --dry-run-server and joined channel testing123\n"))
+		})
+
+		It("returns with exit code 1 with --follow", func() {
+			output, exit, err := executeForArgs(dryRunArgs("--follow"))
+			checkFlagError(output, exit, err, "--dry-run-server and --follow are mutually exclusive")
+		})
+
+		It("returns with exit code 1 with --batch-file", func() {
+			args := []string{
+				"channel",
+				"join",
+				"--orderer-address", ordererURL,
+				"--batch-file", filepath.Join(tempDir, "channels.yaml"),
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--dry-run-server",
+			}
+			output, exit, err := executeForArgs(args)
+			checkFlagError(output, exit, err, "--dry-run-server is not supported by --batch-file")
+		})
+	})
+
+	Describe("Request timeout", func() {
+		BeforeEach(func() {
+			testServer.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(500 * time.Millisecond)
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"systemChannel":null,"channels":null}`))
+			})
+		})
+
+		listArgs := func(timeout string) []string {
+			return []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--timeout=" + timeout,
+			}
+		}
+
+		It("does not time out a long request with --timeout 0", func() {
+			output, exit, err := executeForArgs(listArgs("0"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(HavePrefix("Status: 200\n"))
+		})
+
+		It("times out a request that takes longer than --timeout", func() {
+			output, exit, err := executeForArgs(listArgs("50ms"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+			Expect(output).To(ContainSubstring("Client.Timeout exceeded"))
+		})
+
+		It("rejects a negative timeout", func() {
+			output, exit, err := executeForArgs(listArgs("-1s"))
+			checkFlagError(output, exit, err, "--timeout must not be negative, use 0 for no timeout")
+		})
+	})
+
+	Describe("User agent", func() {
+		var userAgents chan string
+
+		BeforeEach(func() {
+			userAgents = make(chan string, 1)
+			handler := testServer.Config.Handler
+			testServer.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				userAgents <- r.Header.Get("User-Agent")
+				handler.ServeHTTP(w, r)
+			})
+
+			mockChannelManagement.ChannelListReturns(types.ChannelList{
+				Channels: []types.ChannelInfoShort{{Name: "participation-trophy"}},
+			})
+		})
+
+		listArgs := func(extra ...string) []string {
+			return append([]string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}, extra...)
+		}
+
+		It("sends the osnadmin version by default", func() {
+			output, exit, err := executeForArgs(listArgs())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(HavePrefix("Status: 200\n"))
+			Expect(userAgents).To(Receive(Equal("osnadmin/" + metadata.Version)))
+		})
+
+		It("sends the User-Agent set by --user-agent", func() {
+			output, exit, err := executeForArgs(listArgs("--user-agent", "ops-team/1.0"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(HavePrefix("Status: 200\n"))
+			Expect(userAgents).To(Receive(Equal("ops-team/1.0")))
+		})
+	})
+
+	Describe("Path prefix", func() {
+		BeforeEach(func() {
+			testServer.Config.Handler = http.StripPrefix("/orderer1", testServer.Config.Handler)
+
+			mockChannelManagement.ChannelListReturns(types.ChannelList{
+				Channels: []types.ChannelInfoShort{{Name: "participation-trophy"}},
+			})
+		})
+
+		It("sends the requests under the path prefix", func() {
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--path-prefix", "/orderer1",
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			expectedOutput := types.ChannelList{
+				Channels: []types.ChannelInfoShort{
+					{
+						Name: "participation-trophy",
+						URL:  "/participation/v1/channels/participation-trophy",
+					},
+				},
+				Count: 1,
+			}
+			checkStatusOutput(output, exit, err, 200, expectedOutput)
+		})
+
+		It("removes a channel under the path prefix", func() {
+			args := []string{
+				"channel",
+				"remove",
+				"--orderer-address", ordererURL,
+				"--path-prefix", "orderer1/",
+				"--channelID", "participation-trophy",
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(Equal("Status: 204\n"))
+			Expect(mockChannelManagement.RemoveChannelCallCount()).To(Equal(1))
+		})
+
+		It("is not found without the path prefix", func() {
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(HavePrefix("Status: 404\n"))
+		})
+	})
+
+	Describe("CA certificate directory", func() {
+		var (
+			caCertDir        string
+			otherServer      *httptest.Server
+			otherOrdererURL  string
+			otherOrdererCert string
+		)
+
+		BeforeEach(func() {
+			// a second OSN, whose TLS certificate is issued by another CA
+			otherDir := filepath.Join(tempDir, "other")
+			Expect(os.Mkdir(otherDir, 0o755)).To(Succeed())
+			generateCertificates(otherDir)
+			otherOrdererCert = filepath.Join(otherDir, "server-ca.pem")
+
+			cert, err := tls.LoadX509KeyPair(
+				filepath.Join(otherDir, "server-cert.pem"),
+				filepath.Join(otherDir, "server-key.pem"),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			otherServer = httptest.NewUnstartedServer(testServer.Config.Handler)
+			otherServer.TLS = &tls.Config{
+				Certificates: []tls.Certificate{cert},
+				ClientCAs:    tlsConfig.ClientCAs,
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+			}
+			otherServer.StartTLS()
+			u, err := url.Parse(otherServer.URL)
+			Expect(err).NotTo(HaveOccurred())
+			otherOrdererURL = u.Host
+
+			caCertDir = filepath.Join(tempDir, "cas")
+			Expect(os.Mkdir(caCertDir, 0o755)).To(Succeed())
+			for source, name := range map[string]string{
+				ordererCACert:    "orderer1.pem",
+				otherOrdererCert: "orderer2.crt",
+			} {
+				pem, err := ioutil.ReadFile(source)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ioutil.WriteFile(filepath.Join(caCertDir, name), pem, 0o644)).To(Succeed())
+			}
+			Expect(ioutil.WriteFile(filepath.Join(caCertDir, "README"), []byte("not a certificate"), 0o644)).To(Succeed())
+
+			mockChannelManagement.ChannelListReturns(types.ChannelList{
+				Channels: []types.ChannelInfoShort{{Name: "participation-trophy"}},
+			})
+		})
+
+		AfterEach(func() {
+			otherServer.Close()
+		})
+
+		listArgs := func(ordererAddress string, extraArgs ...string) []string {
+			return append([]string{
+				"channel",
+				"list",
+				"--orderer-address", ordererAddress,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}, extraArgs...)
+		}
+
+		It("trusts every CA of the directory", func() {
+			expectedOutput := types.ChannelList{
+				Channels: []types.ChannelInfoShort{
+					{
+						Name: "participation-trophy",
+						URL:  "/participation/v1/channels/participation-trophy",
+					},
+				},
+				Count: 1,
+			}
+			for _, ordererAddress := range []string{ordererURL, otherOrdererURL} {
+				output, exit, err := executeForArgs(listArgs(ordererAddress, "--ca-cert-dir", caCertDir))
+				checkStatusOutput(output, exit, err, 200, expectedOutput)
+			}
+		})
+
+		It("trusts the CAs of the directory in addition to --ca-file", func() {
+			Expect(os.Remove(filepath.Join(caCertDir, "orderer1.pem"))).To(Succeed())
+			for _, ordererAddress := range []string{ordererURL, otherOrdererURL} {
+				output, exit, err := executeForArgs(listArgs(ordererAddress, "--ca-file", ordererCACert, "--ca-cert-dir", caCertDir))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exit).To(Equal(0))
+				Expect(output).To(HavePrefix("Status: 200\n"))
+			}
+		})
+
+		It("does not trust a CA missing from the directory", func() {
+			Expect(os.Remove(filepath.Join(caCertDir, "orderer2.crt"))).To(Succeed())
+			output, exit, err := executeForArgs(listArgs(otherOrdererURL, "--ca-cert-dir", caCertDir))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+			Expect(output).To(ContainSubstring("certificate signed by unknown authority"))
+		})
+
+		It("returns an error for a directory without certificates", func() {
+			emptyDir := filepath.Join(tempDir, "empty")
+			Expect(os.Mkdir(emptyDir, 0o755)).To(Succeed())
+			output, exit, err := executeForArgs(listArgs(ordererURL, "--ca-cert-dir", emptyDir))
+			checkFlagError(output, exit, err, "loading --ca-cert-dir: no *.pem or *.crt file in "+emptyDir)
+		})
+
+		It("returns an error for a certificate file without a certificate", func() {
+			Expect(ioutil.WriteFile(filepath.Join(caCertDir, "bad.pem"), []byte("not a certificate"), 0o644)).To(Succeed())
+			output, exit, err := executeForArgs(listArgs(ordererURL, "--ca-cert-dir", caCertDir))
+			checkFlagError(output, exit, err, "loading --ca-cert-dir: no PEM-encoded certificate in "+filepath.Join(caCertDir, "bad.pem"))
+		})
+
+		It("cannot be combined with --trust-on-first-use", func() {
+			output, exit, err := executeForArgs(listArgs(ordererURL, "--ca-cert-dir", caCertDir, "--trust-on-first-use", filepath.Join(tempDir, "pin")))
+			checkFlagError(output, exit, err, "--ca-cert-dir and --trust-on-first-use are mutually exclusive")
+		})
+	})
+
+	Describe("Expected SAN", func() {
+		var (
+			sanServer      *httptest.Server
+			sanOrdererURL  string
+			sanOrdererCert string
+		)
+
+		BeforeEach(func() {
+			// a second OSN, whose TLS certificate also names orderer1.example.com
+			ca, err := tlsgen.NewCA()
+			Expect(err).NotTo(HaveOccurred())
+			sanOrdererCert = filepath.Join(tempDir, "san-ca.pem")
+			Expect(ioutil.WriteFile(sanOrdererCert, ca.CertBytes(), 0o640)).To(Succeed())
+			keyPair, err := ca.NewServerCertKeyPair("127.0.0.1", "orderer1.example.com")
+			Expect(err).NotTo(HaveOccurred())
+			cert, err := tls.X509KeyPair(keyPair.Cert, keyPair.Key)
+			Expect(err).NotTo(HaveOccurred())
+
+			sanServer = httptest.NewUnstartedServer(testServer.Config.Handler)
+			sanServer.TLS = &tls.Config{
+				Certificates: []tls.Certificate{cert},
+				ClientCAs:    tlsConfig.ClientCAs,
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+			}
+			sanServer.StartTLS()
+			u, err := url.Parse(sanServer.URL)
+			Expect(err).NotTo(HaveOccurred())
+			sanOrdererURL = u.Host
+
+			mockChannelManagement.ChannelListReturns(types.ChannelList{
+				Channels: []types.ChannelInfoShort{{Name: "participation-trophy"}},
+			})
+		})
+
+		AfterEach(func() {
+			sanServer.Close()
+		})
+
+		listArgs := func(ordererAddress, caFile string, extraArgs ...string) []string {
+			return append([]string{
+				"channel",
+				"list",
+				"--orderer-address", ordererAddress,
+				"--ca-file", caFile,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}, extraArgs...)
+		}
+
+		It("accepts a certificate containing the SAN", func() {
+			output, exit, err := executeForArgs(listArgs(sanOrdererURL, sanOrdererCert, "--expect-san", "orderer1.example.com"))
+			expectedOutput := types.ChannelList{
+				Channels: []types.ChannelInfoShort{
+					{
+						Name: "participation-trophy",
+						URL:  "/participation/v1/channels/participation-trophy",
+					},
+				},
+				Count: 1,
+			}
+			checkStatusOutput(output, exit, err, 200, expectedOutput)
+		})
+
+		It("accepts an IP address SAN", func() {
+			output, exit, err := executeForArgs(listArgs(ordererURL, ordererCACert, "--expect-san", "127.0.0.1"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(ContainSubstring("Status: 200"))
+		})
+
+		It("rejects a trusted certificate lacking the SAN", func() {
+			output, exit, err := executeForArgs(listArgs(ordererURL, ordererCACert, "--expect-san", "orderer1.example.com"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+			Expect(output).To(ContainSubstring("the TLS certificate of the OSN does not contain the subject alternative name orderer1.example.com"))
+			Expect(mockChannelManagement.ChannelListCallCount()).To(Equal(0))
+		})
+
+		It("requires TLS", func() {
+			output, exit, err := executeForArgs([]string{"channel", "list", "--orderer-address", ordererURL, "--expect-san", "orderer1.example.com"})
+			checkFlagError(output, exit, err, "--expect-san requires TLS, use --ca-file, --ca-cert-dir or --trust-on-first-use")
+		})
+	})
+
+	Describe("Explain", func() {
+		explainReport := func(output string) map[string]interface{} {
+			report := map[string]interface{}{}
+			Expect(json.Unmarshal([]byte(output), &report)).To(Succeed())
+			return report
+		}
+
+		It("reports no problems for valid flags and config block", func() {
+			blockPath := createBlockFile(tempDir, blockWithGroups(map[string]*cb.ConfigGroup{"Application": {}}, "testing123"))
+			args := []string{
+				"channel",
+				"join",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--channelID", "testing123",
+				"--config-block", blockPath,
+				"--explain",
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(explainReport(output)).To(Equal(map[string]interface{}{
+				"command":  "channel join",
+				"problems": []interface{}{},
+			}))
+			Expect(mockChannelManagement.JoinChannelCallCount()).To(Equal(0))
+		})
+
+		It("reports every local problem without contacting the OSN", func() {
+			blockPath := createBlockFile(tempDir, blockWithGroups(map[string]*cb.ConfigGroup{}, "testing123"))
+			args := []string{
+				"channel",
+				"join",
+				"--orderer-address", "orderer.example.com",
+				"--ca-file", filepath.Join(tempDir, "missing-ca.pem"),
+				"--client-cert", clientCert,
+				"--client-key", filepath.Join(tempDir, "server-key.pem"),
+				"--channelID", "Bad_Channel",
+				"--config-block", blockPath,
+				"--explain",
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+			report := explainReport(output)
+			Expect(report["command"]).To(Equal("channel join"))
+			Expect(report["problems"]).To(ConsistOf(
+				map[string]interface{}{
+					"flag":  "--orderer-address",
+					"error": "invalid orderer address orderer.example.com: address orderer.example.com: missing port in address",
+				},
+				map[string]interface{}{
+					"flag":  "--ca-file",
+					"error": fmt.Sprintf("reading orderer CA certificate: open %s: no such file or directory", filepath.Join(tempDir, "missing-ca.pem")),
+				},
+				map[string]interface{}{
+					"flag":  "--client-cert",
+					"error": "loading client cert/key pair: tls: private key does not match public key",
+				},
+				map[string]interface{}{
+					"flag":  "--channelID",
+					"error": "invalid --channelID: 'Bad_Channel' contains illegal characters",
+				},
+				map[string]interface{}{
+					"flag":  "--config-block",
+					"error": "specified --channelID Bad_Channel does not match channel ID testing123 in config block",
+				},
+				map[string]interface{}{
+					"flag":  "--config-block",
+					"error": "invalid join block: invalid config: must have at least one of application or consortiums",
+				},
+			))
+			Expect(mockChannelManagement.JoinChannelCallCount()).To(Equal(0))
+		})
+
+		It("reports the problems of each address of channel diff", func() {
+			args := []string{
+				"channel",
+				"diff",
+				"--orderer", ordererURL,
+				"--orderer", "orderer2.example.com:port",
+				"--explain",
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+			Expect(explainReport(output)["problems"]).To(ConsistOf(
+				map[string]interface{}{
+					"flag":  "--orderer",
+					"error": "invalid orderer address orderer2.example.com:port: invalid port port",
+				},
+			))
+		})
+	})
+
+	Describe("Status", func() {
+		BeforeEach(func() {
+			mockChannelManagement.ChannelInfoReturns(types.ChannelInfo{
+				Name:              "testing123",
+				ConsensusRelation: types.ConsensusRelationConsenter,
+				Status:            types.StatusActive,
+				Height:            987,
+			}, nil)
+		})
+
+		statusArgs := func(extraArgs ...string) []string {
+			return append([]string{
+				"channel",
+				"status",
+				"--orderer-address", ordererURL,
+				"--channelID", "testing123",
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}, extraArgs...)
+		}
+
+		It("prints nothing and exits with 0 when the status matches", func() {
+			output, exit, err := executeForArgs(statusArgs("--expect", "active"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(BeEmpty())
+			Expect(mockChannelManagement.ChannelInfoArgsForCall(0)).To(Equal("testing123"))
+		})
+
+		It("matches the consensus relation and minimum height when expected", func() {
+			output, exit, err := executeForArgs(statusArgs("--expect", "active", "--expect-relation", "consenter", "--expect-min-height", "987"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(BeEmpty())
+		})
+
+		It("exits with 1 when the status does not match", func() {
+			output, exit, err := executeForArgs(statusArgs("--expect", "onboarding"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+			Expect(output).To(Equal("Error: channel testing123 status is active, expected onboarding\n"))
+		})
+
+		It("exits with 1 when the consensus relation does not match", func() {
+			output, exit, err := executeForArgs(statusArgs("--expect", "active", "--expect-relation", "follower"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+			Expect(output).To(Equal("Error: channel testing123 consensus relation is consenter, expected follower\n"))
+		})
+
+		It("exits with 1 when the height is lower than expected", func() {
+			output, exit, err := executeForArgs(statusArgs("--expect", "active", "--expect-min-height", "1000"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+			Expect(output).To(Equal("Error: channel testing123 height is 987, expected at least 1000\n"))
+		})
+
+		It("exits with 1 when the channel does not exist", func() {
+			mockChannelManagement.ChannelInfoReturns(types.ChannelInfo{}, types.ErrChannelNotExist)
+			output, exit, err := executeForArgs(statusArgs("--expect", "active"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+			Expect(output).To(Equal("Error: channel testing123 does not exist\n"))
+		})
+
+		It("returns an error for an unknown status", func() {
+			output, exit, err := executeForArgs(statusArgs("--expect", "running"))
+			checkFlagError(output, exit, err, "parsing --expect: unknown status: running")
+		})
+
+		It("returns an error for an unknown consensus relation", func() {
+			output, exit, err := executeForArgs(statusArgs("--expect", "active", "--expect-relation", "leader"))
+			checkFlagError(output, exit, err, "parsing --expect-relation: unknown consensus relation: leader")
+		})
+	})
+
+	Describe("Kubernetes secret", func() {
+		var secretDir string
+
+		BeforeEach(func() {
+			secretDir = filepath.Join(tempDir, "secret")
+			Expect(os.Mkdir(secretDir, 0o755)).To(Succeed())
+			for source, key := range map[string]string{
+				ordererCACert: "ca.crt",
+				clientCert:    "tls.crt",
+				clientKey:     "tls.key",
+			} {
+				pem, err := ioutil.ReadFile(source)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ioutil.WriteFile(filepath.Join(secretDir, key), pem, 0o600)).To(Succeed())
+			}
+
+			mockChannelManagement.ChannelListReturns(types.ChannelList{
+				Channels: []types.ChannelInfoShort{{Name: "participation-trophy"}},
+			})
+		})
+
+		It("loads the TLS materials from the ca.crt, tls.crt and tls.key of the secret", func() {
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--secret-dir", secretDir,
+			}
+			output, exit, err := executeForArgs(args)
+			expectedOutput := types.ChannelList{
+				Channels: []types.ChannelInfoShort{
+					{
+						Name: "participation-trophy",
+						URL:  "/participation/v1/channels/participation-trophy",
+					},
+				},
+				Count: 1,
+			}
+			checkStatusOutput(output, exit, err, 200, expectedOutput)
+		})
+
+		It("returns an error when the secret does not contain a key", func() {
+			Expect(os.Remove(filepath.Join(secretDir, "tls.key"))).To(Succeed())
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--secret-dir", secretDir,
+			}
+			output, exit, err := executeForArgs(args)
+			checkFlagError(output, exit, err, "loading client cert/key pair: open "+filepath.Join(secretDir, "tls.key")+": no such file or directory")
+		})
+
+		It("cannot be combined with the TLS file flags", func() {
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--secret-dir", secretDir,
+				"--ca-file", ordererCACert,
+			}
+			output, exit, err := executeForArgs(args)
+			checkFlagError(output, exit, err, "--secret-dir cannot be combined with --ca-file, --client-cert or --client-key")
+		})
+	})
+
+	Describe("Timing", func() {
+		It("prints the elapsed time to stderr when enabled", func() {
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--output-cert-expiry-warning", "0",
+				"--timing",
+			}
+			output, exit, err := executeForArgs(args)
+			checkStatusOutput(output, exit, err, 200, types.ChannelList{})
+			Expect(stderr).To(gbytes.Say(`took \S+\n`))
+		})
+
+		It("does not print the elapsed time by default", func() {
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--output-cert-expiry-warning", "0",
+			}
+			output, exit, err := executeForArgs(args)
+			checkStatusOutput(output, exit, err, 200, types.ChannelList{})
+			Expect(stderr.(*gbytes.Buffer).Contents()).To(BeEmpty())
+		})
+	})
+
+	Describe("Version", func() {
+		var savedVersion, savedCommitSHA, savedBuildDate string
+
+		BeforeEach(func() {
+			savedVersion, savedCommitSHA, savedBuildDate = metadata.Version, metadata.CommitSHA, metadata.BuildDate
+			// the values injected with ldflags at build time
+			metadata.Version = "2.3.0"
+			metadata.CommitSHA = "abcdefg"
+			metadata.BuildDate = "2021-02-03T04:05:06Z"
+		})
+
+		AfterEach(func() {
+			metadata.Version, metadata.CommitSHA, metadata.BuildDate = savedVersion, savedCommitSHA, savedBuildDate
+		})
+
+		It("prints the version without requiring an orderer address", func() {
+			output, exit, err := executeForArgs([]string{"version"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(Equal("2.3.0"))
+		})
+
+		It("prints the build metadata with --full", func() {
+			output, exit, err := executeForArgs([]string{"version", "--full"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(Equal(fmt.Sprintf("osnadmin:\n Version: 2.3.0\n Commit SHA: abcdefg\n Build date: 2021-02-03T04:05:06Z\n Go version: %s\n OS/Arch: %s/%s",
+				runtime.Version(), runtime.GOOS, runtime.GOARCH)))
+		})
+
+		It("still requires an orderer address for channel commands", func() {
+			output, exit, err := executeForArgs([]string{"channel", "list"})
+			checkFlagError(output, exit, err, "required flag --orderer-address not provided")
+		})
+	})
+
+	Describe("Doctor", func() {
+		It("passes every check against a healthy OSN", func() {
+			args := []string{
+				"doctor",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(Equal(fmt.Sprintf(
+				"[PASS] DNS resolve 127.0.0.1\n"+
+					"[PASS] TCP connect to %s\n"+
+					"[PASS] TLS handshake and server certificate\n"+
+					"[PASS] TLS client certificate accepted\n"+
+					"[PASS] List channels\n",
+				ordererURL)))
+			Expect(mockChannelManagement.ChannelListCallCount()).To(Equal(2))
+		})
+
+		It("fails the connect check and skips the rest when the OSN is unreachable", func() {
+			testServer.Close()
+
+			args := []string{
+				"doctor",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+			Expect(output).To(MatchRegexp(
+				`^\[PASS\] DNS resolve 127\.0\.0\.1\n` +
+					`\[FAIL\] TCP connect to ` + ordererURL + `: dial tcp ` + ordererURL + `: [^\n]+\n` +
+					`\[SKIP\] TLS handshake and server certificate\n` +
+					`\[SKIP\] TLS client certificate accepted\n` +
+					`\[SKIP\] List channels\n$`))
+		})
+
+		It("fails the TLS handshake check when the ca-file does not match the server certificate", func() {
+			args := []string{
+				"doctor",
+				"--orderer-address", ordererURL,
+				"--ca-file", filepath.Join(tempDir, "client-ca.pem"),
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+			Expect(output).To(ContainSubstring("[PASS] TCP connect to"))
+			Expect(output).To(ContainSubstring("[FAIL] TLS handshake and server certificate: "))
+			Expect(output).To(ContainSubstring("[SKIP] TLS client certificate accepted\n"))
+		})
+
+		It("fails the client certificate check when the OSN does not trust the client certificate", func() {
+			args := []string{
+				"doctor",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", filepath.Join(tempDir, "server-cert.pem"),
+				"--client-key", filepath.Join(tempDir, "server-key.pem"),
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+			Expect(output).To(ContainSubstring("[PASS] TLS handshake and server certificate\n"))
+			Expect(output).To(ContainSubstring("[FAIL] TLS client certificate accepted: "))
+			Expect(output).To(HaveSuffix("[SKIP] List channels\n"))
+			Expect(mockChannelManagement.ChannelListCallCount()).To(Equal(0))
+		})
+
+		Context("when TLS is disabled", func() {
+			BeforeEach(func() {
+				tlsConfig = nil
+			})
+
+			It("skips the TLS checks", func() {
+				args := []string{
+					"doctor",
+					"--orderer-address", ordererURL,
+				}
+				output, exit, err := executeForArgs(args)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exit).To(Equal(0))
+				Expect(output).To(ContainSubstring("[SKIP] TLS handshake and server certificate\n[SKIP] TLS client certificate accepted\n[PASS] List channels\n"))
+			})
+		})
+	})
+
+	Describe("List since height", func() {
+		BeforeEach(func() {
+			mockChannelManagement.ChannelListReturns(types.ChannelList{
+				Channels: []types.ChannelInfoShort{
+					{Name: "caught-up"},
+					{Name: "catching-up"},
+					{Name: "just-there"},
+					{Name: "just-removed"},
+				},
+				SystemChannel: &types.ChannelInfoShort{Name: "fight-the-system"},
+			})
+			heights := map[string]uint64{
+				"caught-up":        150,
+				"catching-up":      12,
+				"just-there":       100,
+				"fight-the-system": 7,
+			}
+			mockChannelManagement.ChannelInfoStub = func(channelID string) (types.ChannelInfo, error) {
+				height, ok := heights[channelID]
+				if !ok {
+					return types.ChannelInfo{}, types.ErrChannelNotExist
+				}
+				return types.ChannelInfo{Name: channelID, Height: height}, nil
+			}
+		})
+
+		It("lists only the channels whose height is at least the threshold", func() {
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--since-height", "100",
+			}
+			output, exit, err := executeForArgs(args)
+			expectedOutput := types.ChannelList{
+				Channels: []types.ChannelInfoShort{
+					{
+						Name: "caught-up",
+						URL:  "/participation/v1/channels/caught-up",
+					},
+					{
+						Name: "just-there",
+						URL:  "/participation/v1/channels/just-there",
+					},
+				},
+				Count: 5,
+			}
+			checkStatusOutput(output, exit, err, 200, expectedOutput)
+			Expect(mockChannelManagement.ChannelInfoCallCount()).To(Equal(5))
+		})
+
+		It("includes the system channel when its height is at least the threshold", func() {
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--since-height", "7",
+				"--format", "template",
+				"--template", "{{range .Channels}}{{.Name}} {{end}}{{.SystemChannel.Name}}",
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(Equal("caught-up catching-up just-there fight-the-system"))
+		})
+
+		It("cannot be used with --channelID", func() {
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--channelID", "caught-up",
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--since-height", "100",
+			}
+			output, exit, err := executeForArgs(args)
+			checkFlagError(output, exit, err, "--channelID and --since-height are mutually exclusive")
+		})
+	})
+
+	Describe("Join batch", func() {
+		var manifestPath string
+
+		BeforeEach(func() {
+			for _, channelID := range []string{"apple", "banana"} {
+				blockBytes, err := proto.Marshal(blockWithGroups(
+					map[string]*cb.ConfigGroup{
+						"Application": {},
+					},
+					channelID,
+				))
+				Expect(err).NotTo(HaveOccurred())
+				err = ioutil.WriteFile(filepath.Join(tempDir, channelID+".pb"), blockBytes, 0o644)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			manifestPath = filepath.Join(tempDir, "manifest.yaml")
+			manifest := "channels:\n" +
+				"  - channelID: apple\n" +
+				"    configBlock: apple.pb\n" +
+				"  - configBlock: " + filepath.Join(tempDir, "banana.pb") + "\n"
+			err := ioutil.WriteFile(manifestPath, []byte(manifest), 0o644)
+			Expect(err).NotTo(HaveOccurred())
+
+			mockChannelManagement.JoinChannelStub = func(channelID string, _ *cb.Block, _ bool) (types.ChannelInfo, error) {
+				if channelID == "banana" {
+					return types.ChannelInfo{}, types.ErrChannelAlreadyExists
+				}
+				return types.ChannelInfo{
+					Name:              channelID,
+					ConsensusRelation: "consenter",
+					Status:            "active",
+					Height:            1,
+				}, nil
+			}
+		})
+
+		It("joins every channel of the manifest and reports the result of each", func() {
+			args := []string{
+				"channel",
+				"join",
+				"--orderer-address", ordererURL,
+				"--batch-file", manifestPath,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+			Expect(output).To(Equal(
+				"Channel: apple\n" +
+					"Status: 201\n" +
+					"{\n\t\"name\": \"apple\",\n\t\"url\": \"/participation/v1/channels/apple\",\n\t\"consensusRelation\": \"consenter\",\n\t\"status\": \"active\",\n\t\"height\": 1,\n\t\"lastModifiedBy\": \"" + clientSubject + "\"\n}\n" +
+					"\n" +
+					"Channel: banana\n" +
+					"Status: 405\n" +
+					"{\n\t\"error\": \"cannot join: channel already exists\"\n}\n" +
+					"\n" +
+					"Joined 1 of 2 channels\n",
+			))
+
+			Expect(mockChannelManagement.JoinChannelCallCount()).To(Equal(2))
+			joinedChannelID, _, _ := mockChannelManagement.JoinChannelArgsForCall(0)
+			Expect(joinedChannelID).To(Equal("apple"))
+			joinedChannelID, _, _ = mockChannelManagement.JoinChannelArgsForCall(1)
+			Expect(joinedChannelID).To(Equal("banana"))
+		})
+
+		Context("when the channel ID of an entry does not match its config block", func() {
+			BeforeEach(func() {
+				manifest := "channels:\n" +
+					"  - channelID: cherry\n" +
+					"    configBlock: apple.pb\n"
+				err := ioutil.WriteFile(manifestPath, []byte(manifest), 0o644)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("reports the entry as failed without joining it", func() {
+				args := []string{
+					"channel",
+					"join",
+					"--orderer-address", ordererURL,
+					"--batch-file", manifestPath,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exit).To(Equal(1))
+				Expect(output).To(Equal(
+					"Channel: cherry\n" +
+						"Error: specified channelID cherry does not match channel ID apple in config block\n" +
+						"\n" +
+						"Joined 0 of 1 channels\n",
+				))
+				Expect(mockChannelManagement.JoinChannelCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the manifest has an unknown field", func() {
+			BeforeEach(func() {
+				err := ioutil.WriteFile(manifestPath, []byte("channels:\n  - block: apple.pb\n"), 0o644)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns with exit code 1 and prints the error", func() {
+				args := []string{
+					"channel",
+					"join",
+					"--orderer-address", ordererURL,
+					"--batch-file", manifestPath,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				Expect(output).To(BeEmpty())
+				Expect(exit).To(Equal(1))
+				Expect(err).To(MatchError(ContainSubstring("parsing batch file: yaml: unmarshal errors:")))
+				Expect(mockChannelManagement.JoinChannelCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when --config-block is also set", func() {
+			It("returns with exit code 1 and prints the error", func() {
+				args := []string{
+					"channel",
+					"join",
+					"--orderer-address", ordererURL,
+					"--batch-file", manifestPath,
+					"--config-block", filepath.Join(tempDir, "apple.pb"),
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				checkFlagError(output, exit, err, "--batch-file cannot be combined with --config-block, --config-block-b64, --from-orderer or --channelID")
+			})
+		})
+	})
+
+	Describe("Status only output", func() {
+		var blockPath string
+
+		BeforeEach(func() {
+			configBlock := blockWithGroups(
+				map[string]*cb.ConfigGroup{
+					"Application": {},
+				},
+				"testing123",
+			)
+			blockPath = createBlockFile(tempDir, configBlock)
+
+			mockChannelManagement.JoinChannelReturns(types.ChannelInfo{
+				Name:              "apple",
+				ConsensusRelation: "banana",
+				Status:            "orange",
+				Height:            123,
+			}, nil)
+		})
+
+		It("prints only the status code", func() {
+			args := []string{
+				"channel",
+				"join",
+				"--orderer-address", ordererURL,
+				"--config-block", blockPath,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--output-status-only",
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(Equal("201\n"))
+		})
+
+		Context("when the request fails", func() {
+			BeforeEach(func() {
+				mockChannelManagement.JoinChannelReturns(types.ChannelInfo{}, types.ErrChannelAlreadyExists)
+			})
+
+			It("prints only the status code and exits with code 1", func() {
+				args := []string{
+					"channel",
+					"join",
+					"--orderer-address", ordererURL,
+					"--config-block", blockPath,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+					"--output-status-only",
+				}
+				output, exit, err := executeForArgs(args)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exit).To(Equal(1))
+				Expect(output).To(Equal("405\n"))
+			})
+		})
+
+		Context("when --format template is also set", func() {
+			It("returns with exit code 1 and prints the error", func() {
+				args := []string{
+					"channel",
+					"list",
+					"--orderer-address", ordererURL,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+					"--output-status-only",
+					"--format", "template",
+					"--template", "{{.Channels}}",
+				}
+				output, exit, err := executeForArgs(args)
+				checkFlagError(output, exit, err, "--format template and --output-status-only are mutually exclusive")
+			})
+		})
+
+		Context("when --all is also set", func() {
+			It("returns with exit code 1 and prints the error", func() {
+				args := []string{
+					"channel",
+					"remove",
+					"--orderer-address", ordererURL,
+					"--all",
+					"--force",
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+					"--output-status-only",
+				}
+				output, exit, err := executeForArgs(args)
+				checkFlagError(output, exit, err, "--output-status-only cannot be combined with --batch-file, --since-height or --all")
+			})
+		})
+	})
+
+	Describe("JSON response values", func() {
+		var responseBody string
+
+		BeforeEach(func() {
+			testServer.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(responseBody))
+			})
+		})
+
+		It("pretty-prints an array response", func() {
+			responseBody = `[{"name":"channel1","url":"/participation/v1/channels/channel1"},{"name":"channel2","url":"/participation/v1/channels/channel2"}]`
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			checkStatusOutput(output, exit, err, 200, []types.ChannelInfoShort{
+				{Name: "channel1", URL: "/participation/v1/channels/channel1"},
+				{Name: "channel2", URL: "/participation/v1/channels/channel2"},
+			})
+		})
+
+		It("pretty-prints an object response keeping the order of its keys", func() {
+			responseBody = "{\"status\":\"active\",\"name\":\"testing123\",\"height\":3}\n"
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--channelID", "testing123",
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(Equal("Status: 200\n{\n\t\"status\": \"active\",\n\t\"name\": \"testing123\",\n\t\"height\": 3\n}\n"))
+		})
+
+		It("prints a scalar response on a line of its own", func() {
+			responseBody = `42`
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(Equal("Status: 200\n42\n"))
+		})
+	})
+
+	Describe("Non-JSON response", func() {
+		BeforeEach(func() {
+			testServer.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html")
+				w.WriteHeader(http.StatusBadGateway)
+				w.Write([]byte("<html><body><h1>502 Bad Gateway</h1></body></html>"))
+			})
+		})
+
+		It("prints the response body as is with a note", func() {
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(Equal("Status: 502\n<html><body><h1>502 Bad Gateway</h1></body></html>\n"))
+			Expect(stderr).To(gbytes.Say(`Note: the response body is not JSON, printing it as is\n`))
+		})
+	})
+
+	Describe("Channel ID validation", func() {
+		var requestCount int
+
+		BeforeEach(func() {
+			requestCount = 0
+			h := testServer.Config.Handler
+			testServer.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requestCount++
+				h.ServeHTTP(w, r)
+			})
+			mockChannelManagement.ChannelInfoReturns(types.ChannelInfo{Name: "my-channel.v2"}, nil)
+		})
+
+		listChannel := func(channelID string) (string, int, error) {
+			return executeForArgs([]string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--channelID", channelID,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			})
+		}
+
+		It("accepts a valid channel ID", func() {
+			output, exit, err := listChannel("my-channel.v2")
+			checkStatusOutput(output, exit, err, 200, types.ChannelInfo{
+				Name: "my-channel.v2",
+				URL:  "/participation/v1/channels/my-channel.v2",
+			})
+			Expect(requestCount).To(Equal(1))
+		})
+
+		It("rejects an invalid channel ID without sending a request", func() {
+			for channelID, expectedError := range map[string]string{
+				"MyChannel":              "invalid --channelID: 'MyChannel' contains illegal characters",
+				"my_channel":             "invalid --channelID: 'my_channel' contains illegal characters",
+				"1channel":               "invalid --channelID: '1channel' contains illegal characters",
+				".channel":               "invalid --channelID: '.channel' contains illegal characters",
+				strings.Repeat("a", 250): "invalid --channelID: channel ID illegal, cannot be longer than 249",
+			} {
+				output, exit, err := listChannel(channelID)
+				checkFlagError(output, exit, err, expectedError)
+			}
+			Expect(requestCount).To(Equal(0))
+		})
+
+		It("validates the channel ID of join and remove", func() {
+			output, exit, err := executeForArgs([]string{
+				"channel",
+				"remove",
+				"--orderer-address", ordererURL,
+				"--channelID", "Bad Channel",
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			})
+			checkFlagError(output, exit, err, "invalid --channelID: 'Bad Channel' contains illegal characters")
+
+			output, exit, err = executeForArgs([]string{
+				"channel",
+				"join",
+				"--orderer-address", ordererURL,
+				"--channelID", "Bad Channel",
+				"--config-block", "block.pb",
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			})
+			checkFlagError(output, exit, err, "invalid --channelID: 'Bad Channel' contains illegal characters")
+			Expect(requestCount).To(Equal(0))
+		})
+	})
+
+	Describe("Probe", func() {
+		var args []string
+
+		JustBeforeEach(func() {
+			args = []string{
+				"probe",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+		})
+
+		It("prints nothing and exits with 0 when the OSN is alive", func() {
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(BeEmpty())
+			Expect(mockChannelManagement.ChannelListCallCount()).To(Equal(1))
+		})
+
+		It("exits with 0 when the OSN responds with 401", func() {
+			testServer.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			})
+
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(BeEmpty())
+		})
+
+		It("exits with 1 when the OSN responds with an error", func() {
+			testServer.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			})
+
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+			Expect(output).To(Equal("Error: unexpected status: 503 Service Unavailable\n"))
+		})
+
+		It("exits with 1 when the OSN is unreachable", func() {
+			testServer.Close()
+
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+			Expect(output).To(ContainSubstring("connect: connection refused"))
+		})
+
+		It("exits with 1 when the TLS handshake fails", func() {
+			args[4] = filepath.Join(tempDir, "client-ca.pem")
+
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+			Expect(output).To(ContainSubstring("certificate signed by unknown authority"))
+		})
+	})
+
+	Describe("Orderer address file", func() {
+		var addressFile string
+
+		BeforeEach(func() {
+			addressFile = filepath.Join(tempDir, "orderer-address")
+			mockChannelManagement.ChannelInfoReturns(types.ChannelInfo{
+				Name:              "testing123",
+				ConsensusRelation: "consenter",
+				Status:            "active",
+				Height:            5,
+			}, nil)
+		})
+
+		It("reads the address from the file, trimming whitespace", func() {
+			err := ioutil.WriteFile(addressFile, []byte(" "+ordererURL+"\n"), 0o640)
+			Expect(err).NotTo(HaveOccurred())
+
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", "@" + addressFile,
+				"--channelID", channelID,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			expectedOutput := types.ChannelInfo{
+				Name:              "testing123",
+				URL:               "/participation/v1/channels/testing123",
+				ConsensusRelation: "consenter",
+				Status:            "active",
+				Height:            5,
+			}
+			checkStatusOutput(output, exit, err, 200, expectedOutput)
+		})
+
+		It("reads the address from the file with the short flag and from the environment", func() {
+			err := ioutil.WriteFile(addressFile, []byte(ordererURL+"\n"), 0o640)
+			Expect(err).NotTo(HaveOccurred())
+
+			args := []string{
+				"channel",
+				"list",
+				"-o", "@" + addressFile,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(HavePrefix("Status: 200\n"))
+
+			os.Setenv("OSNADMIN_ORDERER_ADDRESS", "@"+addressFile)
+			defer os.Unsetenv("OSNADMIN_ORDERER_ADDRESS")
+			output, exit, err = executeForArgs(append(args[:2], args[4:]...))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(HavePrefix("Status: 200\n"))
+		})
+
+		It("returns with exit code 1 when the file does not exist", func() {
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", "@" + addressFile,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			checkFlagError(output, exit, err, "reading --orderer-address file: open "+addressFile+": no such file or directory")
+		})
+
+		It("returns with exit code 1 when the file is empty", func() {
+			err := ioutil.WriteFile(addressFile, []byte("\n"), 0o640)
+			Expect(err).NotTo(HaveOccurred())
+
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", "@" + addressFile,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			checkFlagError(output, exit, err, "--orderer-address file "+addressFile+" is empty")
+		})
+
+		It("checks the address read from the file with --explain", func() {
+			err := ioutil.WriteFile(addressFile, []byte("orderer.example.com\n"), 0o640)
+			Expect(err).NotTo(HaveOccurred())
+
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", "@" + addressFile,
+				"--explain",
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+			Expect(output).To(MatchJSON(`{
+				"command": "channel list",
+				"problems": [{
+					"flag": "--orderer-address",
+					"error": "invalid orderer address orderer.example.com: address orderer.example.com: missing port in address"
+				}]
+			}`))
+
+			Expect(os.Remove(addressFile)).To(Succeed())
+			output, exit, err = executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+			Expect(output).To(MatchJSON(`{
+				"command": "channel list",
+				"problems": [{
+					"flag": "--orderer-address",
+					"error": "reading --orderer-address file: open ` + addressFile + `: no such file or directory"
+				}]
+			}`))
+		})
+	})
+
+	Describe("Unknown command", func() {
+		It("prints the usage and returns with exit code 1", func() {
+			output, exit, err := executeForArgs([]string{"frobnicate"})
+			checkFlagError(output, exit, err, `unknown command "frobnicate"`)
+			Expect(stderr).To(gbytes.Say(`usage: osnadmin`))
+			Expect(stderr).To(gbytes.Say(`channel join`))
+		})
+
+		It("reports an unknown subcommand", func() {
+			output, exit, err := executeForArgs([]string{"channel", "frobnicate"})
+			checkFlagError(output, exit, err, `unknown command "frobnicate"`)
+			Expect(stderr).To(gbytes.Say(`usage: osnadmin`))
+		})
+
+		It("skips the flags and their values before the unknown command", func() {
+			output, exit, err := executeForArgs([]string{"--orderer-address", "channel", "--no-status", "channel", "frobnicate"})
+			checkFlagError(output, exit, err, `unknown command "frobnicate"`)
+			Expect(stderr).To(gbytes.Say(`usage: osnadmin`))
+		})
+
+		It("does not print the usage for other parse errors", func() {
+			output, exit, err := executeForArgs([]string{"channel", "list", "--frobnicate"})
+			checkFlagError(output, exit, err, "unknown long flag '--frobnicate'")
+			Expect(stderr).NotTo(gbytes.Say(`usage: osnadmin`))
+		})
+	})
+
+	Describe("Environment variables", func() {
+		var envars map[string]string
+
+		JustBeforeEach(func() {
+			envars = map[string]string{
+				"OSNADMIN_ORDERER_ADDRESS": ordererURL,
+				"OSNADMIN_CA_FILE":         ordererCACert,
+				"OSNADMIN_CLIENT_CERT":     clientCert,
+				"OSNADMIN_CLIENT_KEY":      clientKey,
+				"OSNADMIN_CHANNELID":       "tell-me-your-secrets",
+			}
+			for name, value := range envars {
+				Expect(os.Setenv(name, value)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			for name := range envars {
+				os.Unsetenv(name)
+			}
+			os.Unsetenv("OSNADMIN_ALL")
+			os.Unsetenv("OSNADMIN_FORCE")
+		})
+
+		It("falls back to the environment for the flags that are not set", func() {
+			mockChannelManagement.ChannelInfoReturns(types.ChannelInfo{
+				Name:   "tell-me-your-secrets",
+				Height: 987,
+			}, nil)
+
+			output, exit, err := executeForArgs([]string{"channel", "list"})
+			checkStatusOutput(output, exit, err, 200, types.ChannelInfo{
+				Name:   "tell-me-your-secrets",
+				URL:    "/participation/v1/channels/tell-me-your-secrets",
+				Height: 987,
+			})
+			Expect(mockChannelManagement.ChannelInfoCallCount()).To(Equal(1))
+			Expect(mockChannelManagement.ChannelInfoArgsForCall(0)).To(Equal("tell-me-your-secrets"))
+		})
+
+		It("prefers the flags over the environment", func() {
+			mockChannelManagement.ChannelInfoReturns(types.ChannelInfo{Name: "flag-wins"}, nil)
+
+			output, exit, err := executeForArgs([]string{"channel", "list", "--channelID", "flag-wins"})
+			checkStatusOutput(output, exit, err, 200, types.ChannelInfo{
+				Name: "flag-wins",
+				URL:  "/participation/v1/channels/flag-wins",
+			})
+			Expect(mockChannelManagement.ChannelInfoArgsForCall(0)).To(Equal("flag-wins"))
+		})
+
+		It("never removes every channel through the environment", func() {
+			Expect(os.Unsetenv("OSNADMIN_CHANNELID")).To(Succeed())
+			Expect(os.Setenv("OSNADMIN_ALL", "true")).To(Succeed())
+			Expect(os.Setenv("OSNADMIN_FORCE", "true")).To(Succeed())
+
+			output, exit, err := executeForArgs([]string{"channel", "remove"})
+			checkFlagError(output, exit, err, "required flag --channelID not provided")
+			Expect(mockChannelManagement.RemoveChannelCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("Log file", func() {
+		var logFile string
+
+		BeforeEach(func() {
+			logFile = filepath.Join(tempDir, "osnadmin.log")
+		})
+
+		readRecords := func() []map[string]interface{} {
+			logBytes, err := ioutil.ReadFile(logFile)
+			Expect(err).NotTo(HaveOccurred())
+			var records []map[string]interface{}
+			for _, line := range strings.Split(strings.TrimSuffix(string(logBytes), "\n"), "\n") {
+				record := map[string]interface{}{}
+				Expect(json.Unmarshal([]byte(line), &record)).To(Succeed())
+				Expect(record).To(HaveKey("time"))
+				_, err := time.ParseDuration(record["duration"].(string))
+				Expect(err).NotTo(HaveOccurred())
+				records = append(records, record)
+			}
+			return records
+		}
+
+		It("appends a record per operation to the log file", func() {
+			mockChannelManagement.ChannelInfoReturns(types.ChannelInfo{}, errors.New("eat-me"))
+
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--log-file", logFile,
+			}
+			output, exit, err := executeForArgs(args)
+			checkStatusOutput(output, exit, err, 200, types.ChannelList{})
+
+			args = append(args, "--channelID", "tofu")
+			output, exit, err = executeForArgs(args)
+			checkStatusOutput(output, exit, err, 404, types.ErrorResponse{Error: "eat-me"})
+
+			records := readRecords()
+			Expect(records).To(HaveLen(2))
+			Expect(records[0]).To(MatchKeys(IgnoreExtras, Keys{
+				"operation": Equal("channel list"),
+				"orderer":   Equal(ordererURL),
+				"status":    BeNumerically("==", 200),
+			}))
+			Expect(records[0]).NotTo(HaveKey("channel"))
+			Expect(records[0]).NotTo(HaveKey("error"))
+			Expect(records[1]).To(MatchKeys(IgnoreExtras, Keys{
+				"operation": Equal("channel list"),
+				"orderer":   Equal(ordererURL),
+				"channel":   Equal("tofu"),
+				"status":    BeNumerically("==", 404),
+				"error":     Equal("eat-me"),
+			}))
+		})
+
+		It("records each channel removed with --all", func() {
+			mockChannelManagement.ChannelListReturns(types.ChannelList{
+				Channels: []types.ChannelInfoShort{{Name: "fruit"}, {Name: "veg"}},
+			})
+
+			args := []string{
+				"channel",
+				"remove",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--all",
+				"--force",
+				"--log-file", logFile,
+			}
+			_, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+
+			records := readRecords()
+			Expect(records).To(HaveLen(3))
+			Expect(records[0]).To(MatchKeys(IgnoreExtras, Keys{"operation": Equal("channel list")}))
+			Expect(records[1]).To(MatchKeys(IgnoreExtras, Keys{"operation": Equal("channel remove"), "channel": Equal("fruit"), "status": BeNumerically("==", 204)}))
+			Expect(records[2]).To(MatchKeys(IgnoreExtras, Keys{"operation": Equal("channel remove"), "channel": Equal("veg"), "status": BeNumerically("==", 204)}))
+		})
+
+		It("records the error when the OSN cannot be reached", func() {
+			testServer.Close()
+
+			args := []string{
+				"channel",
+				"remove",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--channelID", "fruit",
+				"--log-file", logFile,
+			}
+			_, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+
+			records := readRecords()
+			Expect(records).To(HaveLen(1))
+			Expect(records[0]).To(MatchKeys(IgnoreExtras, Keys{
+				"operation": Equal("channel remove"),
+				"channel":   Equal("fruit"),
+				"error":     ContainSubstring("connection refused"),
+			}))
+			Expect(records[0]).NotTo(HaveKey("status"))
+		})
+
+		It("returns with exit code 1 when the log file cannot be opened", func() {
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--log-file", filepath.Join(tempDir, "missing-dir", "osnadmin.log"),
+			}
+			output, exit, err := executeForArgs(args)
+			checkFlagError(output, exit, err, "opening log file: open "+filepath.Join(tempDir, "missing-dir", "osnadmin.log")+": no such file or directory")
+		})
+	})
+
+	Describe("Retry", func() {
+		var (
+			failures     int
+			failureCode  int
+			requestCount int
+		)
+
+		BeforeEach(func() {
+			failures = 1
+			failureCode = http.StatusServiceUnavailable
+			requestCount = 0
 
-			mockChannelManagement.JoinChannelReturns(types.ChannelInfo{
-				Name:              "apple",
-				ConsensusRelation: "banana",
-				Status:            "orange",
-				Height:            123,
-			}, nil)
+			h := testServer.Config.Handler
+			testServer.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requestCount++
+				if requestCount <= failures {
+					w.WriteHeader(failureCode)
+					return
+				}
+				h.ServeHTTP(w, r)
+			})
 		})
 
-		It("uses the channel participation API to join a channel", func() {
+		It("retries a request that returned a status code listed in --retry-on", func() {
 			args := []string{
 				"channel",
-				"join",
+				"list",
 				"--orderer-address", ordererURL,
-				"--channelID", channelID,
-				"--config-block", blockPath,
 				"--ca-file", ordererCACert,
 				"--client-cert", clientCert,
 				"--client-key", clientKey,
+				"--retries", "2",
+				"--retry-interval", "1ms",
+				"--retry-on", "503,connrefused",
 			}
 			output, exit, err := executeForArgs(args)
-			expectedOutput := types.ChannelInfo{
-				Name:              "apple",
-				URL:               "/participation/v1/channels/apple",
-				ConsensusRelation: "banana",
-				Status:            "orange",
-				Height:            123,
+			expectedOutput := types.ChannelList{}
+			checkStatusOutput(output, exit, err, 200, expectedOutput)
+			Expect(requestCount).To(Equal(2))
+		})
+
+		It("reports the attempts of a retried request with --verbose", func() {
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--retries", "2",
+				"--retry-interval", "1ms",
+				"--retry-on", "503,connrefused",
+				"--verbose",
 			}
-			checkStatusOutput(output, exit, err, 201, expectedOutput)
+			output, exit, err := executeForArgs(args)
+			checkStatusOutput(output, exit, err, 200, types.ChannelList{})
+			Expect(stderr).To(gbytes.Say("attempts: 2, retried on: 503\n"))
 		})
 
-		Context("when the block is empty", func() {
-			BeforeEach(func() {
-				blockPath = createBlockFile(tempDir, &cb.Block{})
-			})
+		It("reports a single attempt with --verbose", func() {
+			failures = 0
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--retries", "2",
+				"--verbose",
+			}
+			output, exit, err := executeForArgs(args)
+			checkStatusOutput(output, exit, err, 200, types.ChannelList{})
+			Expect(stderr).To(gbytes.Say("attempts: 1\n"))
+		})
 
-			It("returns with exit code 1 and prints the error", func() {
-				args := []string{
-					"channel",
-					"join",
-					"--orderer-address", ordererURL,
-					"--channelID", channelID,
-					"--config-block", blockPath,
-					"--ca-file", ordererCACert,
-					"--client-cert", clientCert,
-					"--client-key", clientKey,
-				}
-				output, exit, err := executeForArgs(args)
+		It("does not report the attempts without --verbose", func() {
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--retries", "2",
+				"--retry-interval", "1ms",
+				"--retry-on", "503",
+			}
+			output, exit, err := executeForArgs(args)
+			checkStatusOutput(output, exit, err, 200, types.ChannelList{})
+			Expect(string(stderr.(*gbytes.Buffer).Contents())).NotTo(ContainSubstring("attempts:"))
+		})
 
-				checkFlagError(output, exit, err, "failed to retrieve channel id - block is empty")
-			})
+		It("returns the last response when the retries are exhausted", func() {
+			failures = 3
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--retries", "2",
+				"--retry-interval", "1ms",
+				"--retry-on", "503",
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(Equal("Status: 503\n"))
+			Expect(requestCount).To(Equal(3))
 		})
 
-		Context("when the --channelID does not match the channel ID in the block", func() {
+		Context("when the OSN resets the connection", func() {
+			var (
+				blockPath string
+				attempts  int
+			)
+
 			BeforeEach(func() {
-				channelID = "not-the-channel-youre-looking-for"
+				failures = 0
+				attempts = 0
+				blockPath = createBlockFile(tempDir, blockWithGroups(map[string]*cb.ConfigGroup{"Application": {}}, "testing123"))
+				h := testServer.Config.Handler
+				testServer.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					attempts++
+					if attempts > 1 {
+						h.ServeHTTP(w, r)
+						return
+					}
+					// closing the TCP connection with no linger, and no TLS
+					// close_notify alert, sends a reset rather than a FIN
+					conn, _, err := w.(http.Hijacker).Hijack()
+					Expect(err).NotTo(HaveOccurred())
+					tcpConn := conn.(*tls.Conn).NetConn().(*net.TCPConn)
+					Expect(tcpConn.SetLinger(0)).To(Succeed())
+					tcpConn.Close()
+				})
 			})
 
-			It("returns with exit code 1 and prints the error", func() {
+			It("retries a list", func() {
 				args := []string{
 					"channel",
-					"join",
+					"list",
 					"--orderer-address", ordererURL,
-					"--channelID", channelID,
-					"--config-block", blockPath,
 					"--ca-file", ordererCACert,
 					"--client-cert", clientCert,
 					"--client-key", clientKey,
+					"--retries", "2",
+					"--retry-interval", "1ms",
+					"--retry-on", "connreset",
 				}
 				output, exit, err := executeForArgs(args)
-
-				checkFlagError(output, exit, err, "specified --channelID not-the-channel-youre-looking-for does not match channel ID testing123 in config block")
-			})
-		})
-
-		Context("when the block isn't a valid config block", func() {
-			BeforeEach(func() {
-				block := &cb.Block{
-					Data: &cb.BlockData{
-						Data: [][]byte{
-							protoutil.MarshalOrPanic(&cb.Envelope{
-								Payload: protoutil.MarshalOrPanic(&cb.Payload{
-									Header: &cb.Header{
-										ChannelHeader: protoutil.MarshalOrPanic(&cb.ChannelHeader{
-											Type:      int32(cb.HeaderType_ENDORSER_TRANSACTION),
-											ChannelId: channelID,
-										}),
-									},
-								}),
-							}),
-						},
-					},
-				}
-				blockPath = createBlockFile(tempDir, block)
+				checkStatusOutput(output, exit, err, 200, types.ChannelList{})
+				Expect(attempts).To(Equal(2))
 			})
 
-			It("returns 405 bad request", func() {
+			It("does not retry a join, which the OSN may have processed", func() {
 				args := []string{
 					"channel",
 					"join",
 					"--orderer-address", ordererURL,
-					"--channelID", channelID,
 					"--config-block", blockPath,
 					"--ca-file", ordererCACert,
 					"--client-cert", clientCert,
 					"--client-key", clientKey,
+					"--retries", "2",
+					"--retry-interval", "1ms",
+					"--retry-on", "connreset",
 				}
 				output, exit, err := executeForArgs(args)
 				Expect(err).NotTo(HaveOccurred())
-				Expect(exit).To(Equal(0))
-
-				expectedOutput := types.ErrorResponse{
-					Error: "invalid join block: block is not a config block",
-				}
-				checkStatusOutput(output, exit, err, 400, expectedOutput)
+				Expect(exit).To(Equal(1))
+				Expect(output).To(ContainSubstring("connection reset by peer"))
+				Expect(attempts).To(Equal(1))
+				Expect(mockChannelManagement.JoinChannelCallCount()).To(Equal(0))
 			})
 		})
 
-		Context("when joining the channel fails", func() {
+		Context("when the status code is not listed in --retry-on", func() {
 			BeforeEach(func() {
-				mockChannelManagement.JoinChannelReturns(types.ChannelInfo{}, types.ErrChannelAlreadyExists)
+				failureCode = http.StatusBadRequest
 			})
 
-			It("returns 405 not allowed", func() {
+			It("does not retry the request", func() {
 				args := []string{
 					"channel",
-					"join",
+					"list",
 					"--orderer-address", ordererURL,
-					"--channelID", channelID,
-					"--config-block", blockPath,
 					"--ca-file", ordererCACert,
 					"--client-cert", clientCert,
 					"--client-key", clientKey,
+					"--retries", "2",
+					"--retry-interval", "1ms",
+					"--retry-on", "503,connrefused",
 				}
 				output, exit, err := executeForArgs(args)
-				expectedOutput := types.ErrorResponse{
-					Error: "cannot join: channel already exists",
-				}
-				checkStatusOutput(output, exit, err, 405, expectedOutput)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exit).To(Equal(0))
+				Expect(output).To(Equal("Status: 400\n"))
+				Expect(requestCount).To(Equal(1))
 			})
+		})
 
-			It("returns 405 not allowed (without status)", func() {
+		Context("when the default --retry-on is used", func() {
+			It("does not retry status codes", func() {
 				args := []string{
 					"channel",
-					"join",
+					"list",
 					"--orderer-address", ordererURL,
-					"--channelID", channelID,
-					"--config-block", blockPath,
 					"--ca-file", ordererCACert,
 					"--client-cert", clientCert,
 					"--client-key", clientKey,
-					"--no-status",
+					"--retries", "2",
+					"--retry-interval", "1ms",
 				}
 				output, exit, err := executeForArgs(args)
-				expectedOutput := types.ErrorResponse{
-					Error: "cannot join: channel already exists",
-				}
-				checkOutput(output, exit, err, expectedOutput)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exit).To(Equal(0))
+				Expect(output).To(Equal("Status: 503\n"))
+				Expect(requestCount).To(Equal(1))
 			})
 		})
 
-		Context("when TLS is disabled", func() {
-			BeforeEach(func() {
-				tlsConfig = nil
-			})
-
-			It("uses the channel participation API to join a channel", func() {
+		Context("when --retry-on contains an unknown condition", func() {
+			It("returns with exit code 1 and prints the error", func() {
 				args := []string{
 					"channel",
-					"join",
+					"list",
 					"--orderer-address", ordererURL,
-					"--channelID", channelID,
-					"--config-block", blockPath,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+					"--retry-on", "503,sometimes",
 				}
 				output, exit, err := executeForArgs(args)
-				expectedOutput := types.ChannelInfo{
-					Name:              "apple",
-					URL:               "/participation/v1/channels/apple",
-					ConsensusRelation: "banana",
-					Status:            "orange",
-					Height:            123,
-				}
-				checkStatusOutput(output, exit, err, 201, expectedOutput)
+				checkFlagError(output, exit, err, "parsing --retry-on: unknown retry condition: sometimes")
 			})
 		})
 	})
@@ -566,6 +4377,7 @@ var _ = Describe("osnadmin", func() {
 				ConsensusRelation: "banana",
 				Status:            "orange",
 				Height:            123,
+				LastModifiedBy:    clientSubject,
 			}
 			checkStatusOutput(output, exit, err, 201, expectedOutput)
 		})
@@ -641,6 +4453,23 @@ var _ = Describe("osnadmin", func() {
 			})
 		})
 
+		Context("when the client key cannot be loaded from the PKCS#11 token", func() {
+			It("returns with exit code 1 and prints the error", func() {
+				args := []string{
+					"channel",
+					"list",
+					"--orderer-address", ordererURL,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--pkcs11-lib", "brussel-sprouts.so",
+					"--pkcs11-pin", "1234",
+					"--pkcs11-label", "ForFabric",
+				}
+				output, exit, err := executeForArgs(args)
+				checkFlagError(output, exit, err, "loading client cert/PKCS#11 key: ")
+			})
+		})
+
 		Context("when the config block cannot be read", func() {
 			var configBlockPath string
 
@@ -665,6 +4494,33 @@ var _ = Describe("osnadmin", func() {
 		})
 	})
 
+	Describe("Server certificate not yet valid", func() {
+		BeforeEach(func() {
+			certFile, keyFile := generateNotYetValidCertificate(tempDir, time.Now().Add(2*time.Hour))
+			cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+			Expect(err).NotTo(HaveOccurred())
+			tlsConfig.Certificates = []tls.Certificate{cert}
+
+			ordererCACert = certFile
+		})
+
+		It("returns with exit code 1 and prints a hint about clock skew", func() {
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(1))
+			Expect(output).To(ContainSubstring("x509: certificate has expired or is not yet valid"))
+			Expect(output).To(MatchRegexp(`\nHint: the local time \S+ is (1h59m\d+s|2h0m0s) before the certificate of "not-yet-valid" becomes valid at \S+, check whether the clock of this host is behind\n$`))
+		})
+	})
+
 	Describe("Server using intermediate CA", func() {
 		BeforeEach(func() {
 			cert, err := tls.LoadX509KeyPair(
@@ -781,8 +4637,50 @@ func generateCertificates(tempDir string) {
 	Expect(err).NotTo(HaveOccurred())
 }
 
+// certSubject returns the subject of the PEM-encoded certificate in the file.
+func certSubject(certPath string) string {
+	certPEM, err := ioutil.ReadFile(certPath)
+	Expect(err).NotTo(HaveOccurred())
+	block, _ := pem.Decode(certPEM)
+	Expect(block).NotTo(BeNil())
+	cert, err := x509.ParseCertificate(block.Bytes)
+	Expect(err).NotTo(HaveOccurred())
+	return cert.Subject.String()
+}
+
+// generateNotYetValidCertificate writes a self-signed server certificate,
+// and its key, that only becomes valid at notBefore.
+func generateNotYetValidCertificate(tempDir string, notBefore time.Time) (certFile, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "not-yet-valid"},
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	Expect(err).NotTo(HaveOccurred())
+
+	certFile = filepath.Join(tempDir, "server-not-yet-valid-cert.pem")
+	err = ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certBytes}), 0o640)
+	Expect(err).NotTo(HaveOccurred())
+	keyFile = filepath.Join(tempDir, "server-not-yet-valid-key.pem")
+	err = ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}), 0o640)
+	Expect(err).NotTo(HaveOccurred())
+
+	return certFile, keyFile
+}
+
 func blockWithGroups(groups map[string]*cb.ConfigGroup, channelID string) *cb.Block {
-	return &cb.Block{
+	block := &cb.Block{
 		Data: &cb.BlockData{
 			Data: [][]byte{
 				protoutil.MarshalOrPanic(&cb.Envelope{
@@ -822,6 +4720,8 @@ func blockWithGroups(groups map[string]*cb.ConfigGroup, channelID string) *cb.Bl
 			},
 		},
 	}
+	block.Header = &cb.BlockHeader{DataHash: protoutil.BlockDataHash(block.Data)}
+	return block
 }
 
 func createBlockFile(tempDir string, configBlock *cb.Block) string {
@@ -832,3 +4732,72 @@ func createBlockFile(tempDir string, configBlock *cb.Block) string {
 	Expect(err).NotTo(HaveOccurred())
 	return blockPath
 }
+
+func setLastConfigIndex(block *cb.Block, index uint64) {
+	block.Metadata = &cb.BlockMetadata{Metadata: make([][]byte, len(cb.BlockMetadataIndex_name))}
+	block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = protoutil.MarshalOrPanic(&cb.Metadata{
+		Value: protoutil.MarshalOrPanic(&cb.OrdererBlockMetadata{
+			LastConfig: &cb.LastConfig{Index: index},
+		}),
+	})
+}
+
+// deliverServer is a source orderer that delivers the blocks of the testing123 channel.
+type deliverServer struct {
+	blocks     map[uint64]*cb.Block
+	newest     uint64
+	mutex      sync.Mutex
+	channelIDs []string
+}
+
+func (d *deliverServer) ChannelIDs() []string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.channelIDs
+}
+
+func (d *deliverServer) Broadcast(ab.AtomicBroadcast_BroadcastServer) error {
+	return errors.New("not implemented")
+}
+
+func (d *deliverServer) Deliver(stream ab.AtomicBroadcast_DeliverServer) error {
+	for {
+		env, err := stream.Recv()
+		if err != nil {
+			return nil
+		}
+		payload, err := protoutil.UnmarshalPayload(env.Payload)
+		if err != nil {
+			return err
+		}
+		chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil {
+			return err
+		}
+		d.mutex.Lock()
+		d.channelIDs = append(d.channelIDs, chdr.ChannelId)
+		d.mutex.Unlock()
+		seekInfo := &ab.SeekInfo{}
+		if err := proto.Unmarshal(payload.Data, seekInfo); err != nil {
+			return err
+		}
+
+		number := d.newest
+		if specified := seekInfo.Start.GetSpecified(); specified != nil {
+			number = specified.Number
+		}
+		block, ok := d.blocks[number]
+		if chdr.ChannelId != "testing123" || !ok {
+			if err := stream.Send(&ab.DeliverResponse{Type: &ab.DeliverResponse_Status{Status: cb.Status_NOT_FOUND}}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := stream.Send(&ab.DeliverResponse{Type: &ab.DeliverResponse_Block{Block: block}}); err != nil {
+			return err
+		}
+		if err := stream.Send(&ab.DeliverResponse{Type: &ab.DeliverResponse_Status{Status: cb.Status_SUCCESS}}); err != nil {
+			return err
+		}
+	}
+}
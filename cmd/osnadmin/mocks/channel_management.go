@@ -9,6 +9,19 @@ import (
 )
 
 type ChannelManagement struct {
+	ChannelCapabilitiesStub        func(string) (types.ChannelCapabilities, error)
+	channelCapabilitiesMutex       sync.RWMutex
+	channelCapabilitiesArgsForCall []struct {
+		arg1 string
+	}
+	channelCapabilitiesReturns struct {
+		result1 types.ChannelCapabilities
+		result2 error
+	}
+	channelCapabilitiesReturnsOnCall map[int]struct {
+		result1 types.ChannelCapabilities
+		result2 error
+	}
 	ChannelInfoStub        func(string) (types.ChannelInfo, error)
 	channelInfoMutex       sync.RWMutex
 	channelInfoArgsForCall []struct {
@@ -32,6 +45,58 @@ type ChannelManagement struct {
 	channelListReturnsOnCall map[int]struct {
 		result1 types.ChannelList
 	}
+	ComputeConfigUpdateStub        func(string, types.ChannelConfigPatch) (*common.ConfigUpdate, error)
+	computeConfigUpdateMutex       sync.RWMutex
+	computeConfigUpdateArgsForCall []struct {
+		arg1 string
+		arg2 types.ChannelConfigPatch
+	}
+	computeConfigUpdateReturns struct {
+		result1 *common.ConfigUpdate
+		result2 error
+	}
+	computeConfigUpdateReturnsOnCall map[int]struct {
+		result1 *common.ConfigUpdate
+		result2 error
+	}
+	ConsenterCountStub        func(string) (int, error)
+	consenterCountMutex       sync.RWMutex
+	consenterCountArgsForCall []struct {
+		arg1 string
+	}
+	consenterCountReturns struct {
+		result1 int
+		result2 error
+	}
+	consenterCountReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	FreeDiskSpaceStub        func() (uint64, error)
+	freeDiskSpaceMutex       sync.RWMutex
+	freeDiskSpaceArgsForCall []struct {
+	}
+	freeDiskSpaceReturns struct {
+		result1 uint64
+		result2 error
+	}
+	freeDiskSpaceReturnsOnCall map[int]struct {
+		result1 uint64
+		result2 error
+	}
+	JoinBlockStub        func(string) ([]byte, error)
+	joinBlockMutex       sync.RWMutex
+	joinBlockArgsForCall []struct {
+		arg1 string
+	}
+	joinBlockReturns struct {
+		result1 []byte
+		result2 error
+	}
+	joinBlockReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
 	JoinChannelStub        func(string, *common.Block, bool) (types.ChannelInfo, error)
 	joinChannelMutex       sync.RWMutex
 	joinChannelArgsForCall []struct {
@@ -47,6 +112,29 @@ type ChannelManagement struct {
 		result1 types.ChannelInfo
 		result2 error
 	}
+	LedgerBytesStub        func(string) (uint64, error)
+	ledgerBytesMutex       sync.RWMutex
+	ledgerBytesArgsForCall []struct {
+		arg1 string
+	}
+	ledgerBytesReturns struct {
+		result1 uint64
+		result2 error
+	}
+	ledgerBytesReturnsOnCall map[int]struct {
+		result1 uint64
+		result2 error
+	}
+	LoadingStub        func() bool
+	loadingMutex       sync.RWMutex
+	loadingArgsForCall []struct {
+	}
+	loadingReturns struct {
+		result1 bool
+	}
+	loadingReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	RemoveChannelStub        func(string) error
 	removeChannelMutex       sync.RWMutex
 	removeChannelArgsForCall []struct {
@@ -58,10 +146,100 @@ type ChannelManagement struct {
 	removeChannelReturnsOnCall map[int]struct {
 		result1 error
 	}
+	UpdateChannelConfigStub        func(string, *common.ConfigUpdateEnvelope) error
+	updateChannelConfigMutex       sync.RWMutex
+	updateChannelConfigArgsForCall []struct {
+		arg1 string
+		arg2 *common.ConfigUpdateEnvelope
+	}
+	updateChannelConfigReturns struct {
+		result1 error
+	}
+	updateChannelConfigReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ValidateJoinStub        func(string, *common.Block, bool) (types.ChannelInfo, error)
+	validateJoinMutex       sync.RWMutex
+	validateJoinArgsForCall []struct {
+		arg1 string
+		arg2 *common.Block
+		arg3 bool
+	}
+	validateJoinReturns struct {
+		result1 types.ChannelInfo
+		result2 error
+	}
+	validateJoinReturnsOnCall map[int]struct {
+		result1 types.ChannelInfo
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *ChannelManagement) ChannelCapabilities(arg1 string) (types.ChannelCapabilities, error) {
+	fake.channelCapabilitiesMutex.Lock()
+	ret, specificReturn := fake.channelCapabilitiesReturnsOnCall[len(fake.channelCapabilitiesArgsForCall)]
+	fake.channelCapabilitiesArgsForCall = append(fake.channelCapabilitiesArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("ChannelCapabilities", []interface{}{arg1})
+	fake.channelCapabilitiesMutex.Unlock()
+	if fake.ChannelCapabilitiesStub != nil {
+		return fake.ChannelCapabilitiesStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.channelCapabilitiesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ChannelManagement) ChannelCapabilitiesCallCount() int {
+	fake.channelCapabilitiesMutex.RLock()
+	defer fake.channelCapabilitiesMutex.RUnlock()
+	return len(fake.channelCapabilitiesArgsForCall)
+}
+
+func (fake *ChannelManagement) ChannelCapabilitiesCalls(stub func(string) (types.ChannelCapabilities, error)) {
+	fake.channelCapabilitiesMutex.Lock()
+	defer fake.channelCapabilitiesMutex.Unlock()
+	fake.ChannelCapabilitiesStub = stub
+}
+
+func (fake *ChannelManagement) ChannelCapabilitiesArgsForCall(i int) string {
+	fake.channelCapabilitiesMutex.RLock()
+	defer fake.channelCapabilitiesMutex.RUnlock()
+	argsForCall := fake.channelCapabilitiesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *ChannelManagement) ChannelCapabilitiesReturns(result1 types.ChannelCapabilities, result2 error) {
+	fake.channelCapabilitiesMutex.Lock()
+	defer fake.channelCapabilitiesMutex.Unlock()
+	fake.ChannelCapabilitiesStub = nil
+	fake.channelCapabilitiesReturns = struct {
+		result1 types.ChannelCapabilities
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChannelManagement) ChannelCapabilitiesReturnsOnCall(i int, result1 types.ChannelCapabilities, result2 error) {
+	fake.channelCapabilitiesMutex.Lock()
+	defer fake.channelCapabilitiesMutex.Unlock()
+	fake.ChannelCapabilitiesStub = nil
+	if fake.channelCapabilitiesReturnsOnCall == nil {
+		fake.channelCapabilitiesReturnsOnCall = make(map[int]struct {
+			result1 types.ChannelCapabilities
+			result2 error
+		})
+	}
+	fake.channelCapabilitiesReturnsOnCall[i] = struct {
+		result1 types.ChannelCapabilities
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *ChannelManagement) ChannelInfo(arg1 string) (types.ChannelInfo, error) {
 	fake.channelInfoMutex.Lock()
 	ret, specificReturn := fake.channelInfoReturnsOnCall[len(fake.channelInfoArgsForCall)]
@@ -177,6 +355,253 @@ func (fake *ChannelManagement) ChannelListReturnsOnCall(i int, result1 types.Cha
 	}{result1}
 }
 
+func (fake *ChannelManagement) ComputeConfigUpdate(arg1 string, arg2 types.ChannelConfigPatch) (*common.ConfigUpdate, error) {
+	fake.computeConfigUpdateMutex.Lock()
+	ret, specificReturn := fake.computeConfigUpdateReturnsOnCall[len(fake.computeConfigUpdateArgsForCall)]
+	fake.computeConfigUpdateArgsForCall = append(fake.computeConfigUpdateArgsForCall, struct {
+		arg1 string
+		arg2 types.ChannelConfigPatch
+	}{arg1, arg2})
+	fake.recordInvocation("ComputeConfigUpdate", []interface{}{arg1, arg2})
+	fake.computeConfigUpdateMutex.Unlock()
+	if fake.ComputeConfigUpdateStub != nil {
+		return fake.ComputeConfigUpdateStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.computeConfigUpdateReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ChannelManagement) ComputeConfigUpdateCallCount() int {
+	fake.computeConfigUpdateMutex.RLock()
+	defer fake.computeConfigUpdateMutex.RUnlock()
+	return len(fake.computeConfigUpdateArgsForCall)
+}
+
+func (fake *ChannelManagement) ComputeConfigUpdateCalls(stub func(string, types.ChannelConfigPatch) (*common.ConfigUpdate, error)) {
+	fake.computeConfigUpdateMutex.Lock()
+	defer fake.computeConfigUpdateMutex.Unlock()
+	fake.ComputeConfigUpdateStub = stub
+}
+
+func (fake *ChannelManagement) ComputeConfigUpdateArgsForCall(i int) (string, types.ChannelConfigPatch) {
+	fake.computeConfigUpdateMutex.RLock()
+	defer fake.computeConfigUpdateMutex.RUnlock()
+	argsForCall := fake.computeConfigUpdateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *ChannelManagement) ComputeConfigUpdateReturns(result1 *common.ConfigUpdate, result2 error) {
+	fake.computeConfigUpdateMutex.Lock()
+	defer fake.computeConfigUpdateMutex.Unlock()
+	fake.ComputeConfigUpdateStub = nil
+	fake.computeConfigUpdateReturns = struct {
+		result1 *common.ConfigUpdate
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChannelManagement) ComputeConfigUpdateReturnsOnCall(i int, result1 *common.ConfigUpdate, result2 error) {
+	fake.computeConfigUpdateMutex.Lock()
+	defer fake.computeConfigUpdateMutex.Unlock()
+	fake.ComputeConfigUpdateStub = nil
+	if fake.computeConfigUpdateReturnsOnCall == nil {
+		fake.computeConfigUpdateReturnsOnCall = make(map[int]struct {
+			result1 *common.ConfigUpdate
+			result2 error
+		})
+	}
+	fake.computeConfigUpdateReturnsOnCall[i] = struct {
+		result1 *common.ConfigUpdate
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChannelManagement) ConsenterCount(arg1 string) (int, error) {
+	fake.consenterCountMutex.Lock()
+	ret, specificReturn := fake.consenterCountReturnsOnCall[len(fake.consenterCountArgsForCall)]
+	fake.consenterCountArgsForCall = append(fake.consenterCountArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("ConsenterCount", []interface{}{arg1})
+	fake.consenterCountMutex.Unlock()
+	if fake.ConsenterCountStub != nil {
+		return fake.ConsenterCountStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.consenterCountReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ChannelManagement) ConsenterCountCallCount() int {
+	fake.computeConfigUpdateMutex.RLock()
+	defer fake.computeConfigUpdateMutex.RUnlock()
+	fake.consenterCountMutex.RLock()
+	defer fake.consenterCountMutex.RUnlock()
+	return len(fake.consenterCountArgsForCall)
+}
+
+func (fake *ChannelManagement) ConsenterCountCalls(stub func(string) (int, error)) {
+	fake.consenterCountMutex.Lock()
+	defer fake.consenterCountMutex.Unlock()
+	fake.ConsenterCountStub = stub
+}
+
+func (fake *ChannelManagement) ConsenterCountArgsForCall(i int) string {
+	fake.consenterCountMutex.RLock()
+	defer fake.consenterCountMutex.RUnlock()
+	argsForCall := fake.consenterCountArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *ChannelManagement) ConsenterCountReturns(result1 int, result2 error) {
+	fake.consenterCountMutex.Lock()
+	defer fake.consenterCountMutex.Unlock()
+	fake.ConsenterCountStub = nil
+	fake.consenterCountReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChannelManagement) ConsenterCountReturnsOnCall(i int, result1 int, result2 error) {
+	fake.consenterCountMutex.Lock()
+	defer fake.consenterCountMutex.Unlock()
+	fake.ConsenterCountStub = nil
+	if fake.consenterCountReturnsOnCall == nil {
+		fake.consenterCountReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.consenterCountReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChannelManagement) FreeDiskSpace() (uint64, error) {
+	fake.freeDiskSpaceMutex.Lock()
+	ret, specificReturn := fake.freeDiskSpaceReturnsOnCall[len(fake.freeDiskSpaceArgsForCall)]
+	fake.freeDiskSpaceArgsForCall = append(fake.freeDiskSpaceArgsForCall, struct {
+	}{})
+	fake.recordInvocation("FreeDiskSpace", []interface{}{})
+	fake.freeDiskSpaceMutex.Unlock()
+	if fake.FreeDiskSpaceStub != nil {
+		return fake.FreeDiskSpaceStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.freeDiskSpaceReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ChannelManagement) FreeDiskSpaceCallCount() int {
+	fake.freeDiskSpaceMutex.RLock()
+	defer fake.freeDiskSpaceMutex.RUnlock()
+	return len(fake.freeDiskSpaceArgsForCall)
+}
+
+func (fake *ChannelManagement) FreeDiskSpaceCalls(stub func() (uint64, error)) {
+	fake.freeDiskSpaceMutex.Lock()
+	defer fake.freeDiskSpaceMutex.Unlock()
+	fake.FreeDiskSpaceStub = stub
+}
+
+func (fake *ChannelManagement) FreeDiskSpaceReturns(result1 uint64, result2 error) {
+	fake.freeDiskSpaceMutex.Lock()
+	defer fake.freeDiskSpaceMutex.Unlock()
+	fake.FreeDiskSpaceStub = nil
+	fake.freeDiskSpaceReturns = struct {
+		result1 uint64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChannelManagement) FreeDiskSpaceReturnsOnCall(i int, result1 uint64, result2 error) {
+	fake.freeDiskSpaceMutex.Lock()
+	defer fake.freeDiskSpaceMutex.Unlock()
+	fake.FreeDiskSpaceStub = nil
+	if fake.freeDiskSpaceReturnsOnCall == nil {
+		fake.freeDiskSpaceReturnsOnCall = make(map[int]struct {
+			result1 uint64
+			result2 error
+		})
+	}
+	fake.freeDiskSpaceReturnsOnCall[i] = struct {
+		result1 uint64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChannelManagement) JoinBlock(arg1 string) ([]byte, error) {
+	fake.joinBlockMutex.Lock()
+	ret, specificReturn := fake.joinBlockReturnsOnCall[len(fake.joinBlockArgsForCall)]
+	fake.joinBlockArgsForCall = append(fake.joinBlockArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("JoinBlock", []interface{}{arg1})
+	fake.joinBlockMutex.Unlock()
+	if fake.JoinBlockStub != nil {
+		return fake.JoinBlockStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.joinBlockReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ChannelManagement) JoinBlockCallCount() int {
+	fake.joinBlockMutex.RLock()
+	defer fake.joinBlockMutex.RUnlock()
+	return len(fake.joinBlockArgsForCall)
+}
+
+func (fake *ChannelManagement) JoinBlockCalls(stub func(string) ([]byte, error)) {
+	fake.joinBlockMutex.Lock()
+	defer fake.joinBlockMutex.Unlock()
+	fake.JoinBlockStub = stub
+}
+
+func (fake *ChannelManagement) JoinBlockArgsForCall(i int) string {
+	fake.joinBlockMutex.RLock()
+	defer fake.joinBlockMutex.RUnlock()
+	argsForCall := fake.joinBlockArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *ChannelManagement) JoinBlockReturns(result1 []byte, result2 error) {
+	fake.joinBlockMutex.Lock()
+	defer fake.joinBlockMutex.Unlock()
+	fake.JoinBlockStub = nil
+	fake.joinBlockReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChannelManagement) JoinBlockReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.joinBlockMutex.Lock()
+	defer fake.joinBlockMutex.Unlock()
+	fake.JoinBlockStub = nil
+	if fake.joinBlockReturnsOnCall == nil {
+		fake.joinBlockReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.joinBlockReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *ChannelManagement) JoinChannel(arg1 string, arg2 *common.Block, arg3 bool) (types.ChannelInfo, error) {
 	fake.joinChannelMutex.Lock()
 	ret, specificReturn := fake.joinChannelReturnsOnCall[len(fake.joinChannelArgsForCall)]
@@ -242,6 +667,121 @@ func (fake *ChannelManagement) JoinChannelReturnsOnCall(i int, result1 types.Cha
 	}{result1, result2}
 }
 
+func (fake *ChannelManagement) LedgerBytes(arg1 string) (uint64, error) {
+	fake.ledgerBytesMutex.Lock()
+	ret, specificReturn := fake.ledgerBytesReturnsOnCall[len(fake.ledgerBytesArgsForCall)]
+	fake.ledgerBytesArgsForCall = append(fake.ledgerBytesArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("LedgerBytes", []interface{}{arg1})
+	fake.ledgerBytesMutex.Unlock()
+	if fake.LedgerBytesStub != nil {
+		return fake.LedgerBytesStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.ledgerBytesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ChannelManagement) LedgerBytesCallCount() int {
+	fake.ledgerBytesMutex.RLock()
+	defer fake.ledgerBytesMutex.RUnlock()
+	return len(fake.ledgerBytesArgsForCall)
+}
+
+func (fake *ChannelManagement) LedgerBytesCalls(stub func(string) (uint64, error)) {
+	fake.ledgerBytesMutex.Lock()
+	defer fake.ledgerBytesMutex.Unlock()
+	fake.LedgerBytesStub = stub
+}
+
+func (fake *ChannelManagement) LedgerBytesArgsForCall(i int) string {
+	fake.ledgerBytesMutex.RLock()
+	defer fake.ledgerBytesMutex.RUnlock()
+	argsForCall := fake.ledgerBytesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *ChannelManagement) LedgerBytesReturns(result1 uint64, result2 error) {
+	fake.ledgerBytesMutex.Lock()
+	defer fake.ledgerBytesMutex.Unlock()
+	fake.LedgerBytesStub = nil
+	fake.ledgerBytesReturns = struct {
+		result1 uint64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChannelManagement) LedgerBytesReturnsOnCall(i int, result1 uint64, result2 error) {
+	fake.ledgerBytesMutex.Lock()
+	defer fake.ledgerBytesMutex.Unlock()
+	fake.LedgerBytesStub = nil
+	if fake.ledgerBytesReturnsOnCall == nil {
+		fake.ledgerBytesReturnsOnCall = make(map[int]struct {
+			result1 uint64
+			result2 error
+		})
+	}
+	fake.ledgerBytesReturnsOnCall[i] = struct {
+		result1 uint64
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChannelManagement) Loading() bool {
+	fake.loadingMutex.Lock()
+	ret, specificReturn := fake.loadingReturnsOnCall[len(fake.loadingArgsForCall)]
+	fake.loadingArgsForCall = append(fake.loadingArgsForCall, struct {
+	}{})
+	fake.recordInvocation("Loading", []interface{}{})
+	fake.loadingMutex.Unlock()
+	if fake.LoadingStub != nil {
+		return fake.LoadingStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.loadingReturns
+	return fakeReturns.result1
+}
+
+func (fake *ChannelManagement) LoadingCallCount() int {
+	fake.loadingMutex.RLock()
+	defer fake.loadingMutex.RUnlock()
+	return len(fake.loadingArgsForCall)
+}
+
+func (fake *ChannelManagement) LoadingCalls(stub func() bool) {
+	fake.loadingMutex.Lock()
+	defer fake.loadingMutex.Unlock()
+	fake.LoadingStub = stub
+}
+
+func (fake *ChannelManagement) LoadingReturns(result1 bool) {
+	fake.loadingMutex.Lock()
+	defer fake.loadingMutex.Unlock()
+	fake.LoadingStub = nil
+	fake.loadingReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *ChannelManagement) LoadingReturnsOnCall(i int, result1 bool) {
+	fake.loadingMutex.Lock()
+	defer fake.loadingMutex.Unlock()
+	fake.LoadingStub = nil
+	if fake.loadingReturnsOnCall == nil {
+		fake.loadingReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.loadingReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *ChannelManagement) RemoveChannel(arg1 string) error {
 	fake.removeChannelMutex.Lock()
 	ret, specificReturn := fake.removeChannelReturnsOnCall[len(fake.removeChannelArgsForCall)]
@@ -302,17 +842,159 @@ func (fake *ChannelManagement) RemoveChannelReturnsOnCall(i int, result1 error)
 	}{result1}
 }
 
+func (fake *ChannelManagement) UpdateChannelConfig(arg1 string, arg2 *common.ConfigUpdateEnvelope) error {
+	fake.updateChannelConfigMutex.Lock()
+	ret, specificReturn := fake.updateChannelConfigReturnsOnCall[len(fake.updateChannelConfigArgsForCall)]
+	fake.updateChannelConfigArgsForCall = append(fake.updateChannelConfigArgsForCall, struct {
+		arg1 string
+		arg2 *common.ConfigUpdateEnvelope
+	}{arg1, arg2})
+	fake.recordInvocation("UpdateChannelConfig", []interface{}{arg1, arg2})
+	fake.updateChannelConfigMutex.Unlock()
+	if fake.UpdateChannelConfigStub != nil {
+		return fake.UpdateChannelConfigStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.updateChannelConfigReturns
+	return fakeReturns.result1
+}
+
+func (fake *ChannelManagement) UpdateChannelConfigCallCount() int {
+	fake.updateChannelConfigMutex.RLock()
+	defer fake.updateChannelConfigMutex.RUnlock()
+	return len(fake.updateChannelConfigArgsForCall)
+}
+
+func (fake *ChannelManagement) UpdateChannelConfigCalls(stub func(string, *common.ConfigUpdateEnvelope) error) {
+	fake.updateChannelConfigMutex.Lock()
+	defer fake.updateChannelConfigMutex.Unlock()
+	fake.UpdateChannelConfigStub = stub
+}
+
+func (fake *ChannelManagement) UpdateChannelConfigArgsForCall(i int) (string, *common.ConfigUpdateEnvelope) {
+	fake.updateChannelConfigMutex.RLock()
+	defer fake.updateChannelConfigMutex.RUnlock()
+	argsForCall := fake.updateChannelConfigArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *ChannelManagement) UpdateChannelConfigReturns(result1 error) {
+	fake.updateChannelConfigMutex.Lock()
+	defer fake.updateChannelConfigMutex.Unlock()
+	fake.UpdateChannelConfigStub = nil
+	fake.updateChannelConfigReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ChannelManagement) UpdateChannelConfigReturnsOnCall(i int, result1 error) {
+	fake.updateChannelConfigMutex.Lock()
+	defer fake.updateChannelConfigMutex.Unlock()
+	fake.UpdateChannelConfigStub = nil
+	if fake.updateChannelConfigReturnsOnCall == nil {
+		fake.updateChannelConfigReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateChannelConfigReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ChannelManagement) ValidateJoin(arg1 string, arg2 *common.Block, arg3 bool) (types.ChannelInfo, error) {
+	fake.validateJoinMutex.Lock()
+	ret, specificReturn := fake.validateJoinReturnsOnCall[len(fake.validateJoinArgsForCall)]
+	fake.validateJoinArgsForCall = append(fake.validateJoinArgsForCall, struct {
+		arg1 string
+		arg2 *common.Block
+		arg3 bool
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("ValidateJoin", []interface{}{arg1, arg2, arg3})
+	fake.validateJoinMutex.Unlock()
+	if fake.ValidateJoinStub != nil {
+		return fake.ValidateJoinStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.validateJoinReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ChannelManagement) ValidateJoinCallCount() int {
+	fake.validateJoinMutex.RLock()
+	defer fake.validateJoinMutex.RUnlock()
+	return len(fake.validateJoinArgsForCall)
+}
+
+func (fake *ChannelManagement) ValidateJoinCalls(stub func(string, *common.Block, bool) (types.ChannelInfo, error)) {
+	fake.validateJoinMutex.Lock()
+	defer fake.validateJoinMutex.Unlock()
+	fake.ValidateJoinStub = stub
+}
+
+func (fake *ChannelManagement) ValidateJoinArgsForCall(i int) (string, *common.Block, bool) {
+	fake.validateJoinMutex.RLock()
+	defer fake.validateJoinMutex.RUnlock()
+	argsForCall := fake.validateJoinArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *ChannelManagement) ValidateJoinReturns(result1 types.ChannelInfo, result2 error) {
+	fake.validateJoinMutex.Lock()
+	defer fake.validateJoinMutex.Unlock()
+	fake.ValidateJoinStub = nil
+	fake.validateJoinReturns = struct {
+		result1 types.ChannelInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChannelManagement) ValidateJoinReturnsOnCall(i int, result1 types.ChannelInfo, result2 error) {
+	fake.validateJoinMutex.Lock()
+	defer fake.validateJoinMutex.Unlock()
+	fake.ValidateJoinStub = nil
+	if fake.validateJoinReturnsOnCall == nil {
+		fake.validateJoinReturnsOnCall = make(map[int]struct {
+			result1 types.ChannelInfo
+			result2 error
+		})
+	}
+	fake.validateJoinReturnsOnCall[i] = struct {
+		result1 types.ChannelInfo
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *ChannelManagement) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.channelCapabilitiesMutex.RLock()
+	defer fake.channelCapabilitiesMutex.RUnlock()
 	fake.channelInfoMutex.RLock()
 	defer fake.channelInfoMutex.RUnlock()
 	fake.channelListMutex.RLock()
 	defer fake.channelListMutex.RUnlock()
+	fake.consenterCountMutex.RLock()
+	defer fake.consenterCountMutex.RUnlock()
+	fake.freeDiskSpaceMutex.RLock()
+	defer fake.freeDiskSpaceMutex.RUnlock()
+	fake.joinBlockMutex.RLock()
+	defer fake.joinBlockMutex.RUnlock()
 	fake.joinChannelMutex.RLock()
 	defer fake.joinChannelMutex.RUnlock()
+	fake.ledgerBytesMutex.RLock()
+	defer fake.ledgerBytesMutex.RUnlock()
+	fake.loadingMutex.RLock()
+	defer fake.loadingMutex.RUnlock()
 	fake.removeChannelMutex.RLock()
 	defer fake.removeChannelMutex.RUnlock()
+	fake.updateChannelConfigMutex.RLock()
+	defer fake.updateChannelConfigMutex.RUnlock()
+	fake.validateJoinMutex.RLock()
+	defer fake.validateJoinMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
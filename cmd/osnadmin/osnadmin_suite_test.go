@@ -18,10 +18,18 @@ import (
 //go:generate counterfeiter -o mocks/channel_management.go -fake-name ChannelManagement . channelManagement
 
 type channelManagement interface {
+	Loading() bool
 	ChannelList() types.ChannelList
 	ChannelInfo(channelID string) (types.ChannelInfo, error)
+	ChannelCapabilities(channelID string) (types.ChannelCapabilities, error)
+	ConsenterCount(channelID string) (int, error)
+	LedgerBytes(channelID string) (uint64, error)
+	FreeDiskSpace() (uint64, error)
+	JoinBlock(channelID string) ([]byte, error)
 	JoinChannel(channelID string, configBlock *cb.Block, isAppChannel bool) (types.ChannelInfo, error)
+	ValidateJoin(channelID string, configBlock *cb.Block, isAppChannel bool) (types.ChannelInfo, error)
 	RemoveChannel(channelID string) error
+	UpdateChannelConfig(channelID string, patch types.ChannelConfigPatch) error
 }
 
 func TestOsnadmin(t *testing.T) {
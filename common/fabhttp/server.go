@@ -9,6 +9,7 @@ package fabhttp
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"net"
 	"net/http"
 	"os"
@@ -30,6 +31,10 @@ type Options struct {
 	Logger        Logger
 	ListenAddress string
 	TLS           TLS
+	// UnauthorizedLimit is the number of unauthorized requests a client host
+	// may make within the UnauthorizedWindow before it is throttled; 0 means unlimited.
+	UnauthorizedLimit  int
+	UnauthorizedWindow time.Duration
 }
 
 type Server struct {
@@ -38,6 +43,9 @@ type Server struct {
 	httpServer *http.Server
 	mux        *http.ServeMux
 	addr       string
+	// requireCert is shared by all the secure handlers, so that unauthorized
+	// requests are counted across them.
+	requireCert middleware.Middleware
 }
 
 func NewServer(o Options) *Server {
@@ -94,12 +102,37 @@ func (s *Server) initializeServer() {
 		Handler:      s.mux,
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 2 * time.Minute,
+		ConnContext:  ConnContext,
 	}
+	s.requireCert = middleware.RequireCert()
+	if s.options.UnauthorizedLimit > 0 {
+		s.requireCert = middleware.RequireCertWithLimit(s.options.UnauthorizedLimit, s.options.UnauthorizedWindow)
+	}
+}
+
+type connContextKey struct{}
+
+// ConnContext keeps the connection of the requests in their context, so that a
+// handler streaming a response can move the write deadline of the connection
+// with SetWriteDeadline. It is meant to be the ConnContext of an http.Server.
+func ConnContext(ctx context.Context, c net.Conn) context.Context {
+	return context.WithValue(ctx, connContextKey{}, c)
+}
+
+// SetWriteDeadline sets the write deadline of the connection serving the
+// request, e.g. for a response streamed for longer than the WriteTimeout of
+// the server. It fails when the server does not use ConnContext.
+func SetWriteDeadline(req *http.Request, deadline time.Time) error {
+	conn, ok := req.Context().Value(connContextKey{}).(net.Conn)
+	if !ok {
+		return errors.New("connection of the request is unknown")
+	}
+	return conn.SetWriteDeadline(deadline)
 }
 
 func (s *Server) HandlerChain(h http.Handler, secure bool) http.Handler {
 	if secure {
-		return middleware.NewChain(middleware.RequireCert(), middleware.WithRequestID(util.GenerateUUID)).Handler(h)
+		return middleware.NewChain(s.requireCert, middleware.WithRequestID(util.GenerateUUID)).Handler(h)
 	}
 	return middleware.NewChain(middleware.WithRequestID(util.GenerateUUID)).Handler(h)
 }
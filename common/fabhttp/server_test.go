@@ -15,6 +15,7 @@ import (
 	"os"
 	"path/filepath"
 	"syscall"
+	"time"
 
 	"github.com/hyperledger/fabric/common/fabhttp"
 	"github.com/hyperledger/fabric/core/operations/fakes"
@@ -124,6 +125,59 @@ var _ = Describe("Server", func() {
 		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
 	})
 
+	It("lets the handlers set the write deadline of their connection", func() {
+		var deadlineErr error
+		server.RegisterHandler(AdditionalTestApiPath, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			deadlineErr = fabhttp.SetWriteDeadline(req, time.Now().Add(time.Minute))
+			w.WriteHeader(http.StatusOK)
+		}), options.TLS.Enabled)
+		err := server.Start()
+		Expect(err).NotTo(HaveOccurred())
+
+		resp, err := client.Get(fmt.Sprintf("https://%s%s", server.Addr(), AdditionalTestApiPath))
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(deadlineErr).NotTo(HaveOccurred())
+	})
+
+	It("fails to set the write deadline of a request served elsewhere", func() {
+		req, err := http.NewRequest(http.MethodGet, "/", nil)
+		Expect(err).NotTo(HaveOccurred())
+		err = fabhttp.SetWriteDeadline(req, time.Now())
+		Expect(err).To(MatchError("connection of the request is unknown"))
+	})
+
+	Context("when UnauthorizedLimit is set", func() {
+		BeforeEach(func() {
+			options.UnauthorizedLimit = 1
+			options.UnauthorizedWindow = time.Minute
+			server = fabhttp.NewServer(options)
+		})
+
+		It("throttles unauthorized requests across the secure endpoints", func() {
+			server.RegisterHandler(AdditionalTestApiPath, &fakes.Handler{Code: http.StatusOK, Text: "secure"}, options.TLS.Enabled)
+			server.RegisterHandler(AdditionalTestApiPath+"2", &fakes.Handler{Code: http.StatusOK, Text: "secure"}, options.TLS.Enabled)
+			err := server.Start()
+			Expect(err).NotTo(HaveOccurred())
+
+			resp, err := unauthClient.Get(fmt.Sprintf("https://%s%s", server.Addr(), AdditionalTestApiPath))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+			resp.Body.Close()
+
+			resp, err = unauthClient.Get(fmt.Sprintf("https://%s%s2", server.Addr(), AdditionalTestApiPath))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusTooManyRequests))
+			resp.Body.Close()
+
+			resp, err = client.Get(fmt.Sprintf("https://%s%s", server.Addr(), AdditionalTestApiPath))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			resp.Body.Close()
+		})
+	})
+
 	Context("when TLS is disabled", func() {
 		BeforeEach(func() {
 			options.TLS.Enabled = false
@@ -10,6 +10,7 @@ package metadata
 var (
 	Version         = "latest"
 	CommitSHA       = "development build"
+	BuildDate       = "unknown"
 	BaseDockerLabel = "org.hyperledger.fabric"
 	DockerNamespace = "hyperledger"
 )
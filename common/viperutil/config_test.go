@@ -161,6 +161,23 @@ func TestByteSize(t *testing.T) {
 	}
 }
 
+func TestByteSizeUint64(t *testing.T) {
+	data := "---\nInner:\n    ByteSize: 8GB"
+
+	config := New()
+	err := config.ReadConfig(strings.NewReader(data))
+	require.NoError(t, err, "error reading config")
+
+	var uconf struct {
+		Inner struct {
+			ByteSize uint64
+		}
+	}
+	err = config.EnhancedExactUnmarshal(&uconf)
+	require.NoError(t, err, "failed to unmarshal")
+	require.Exactly(t, uint64(8*1024*1024*1024), uconf.Inner.ByteSize, "incorrect byte size")
+}
+
 func TestByteSizeOverflow(t *testing.T) {
 	data := "---\nInner:\n    ByteSize: 4GB"
 
@@ -7,11 +7,17 @@ SPDX-License-Identifier: Apache-2.0
 package middleware
 
 import (
+	"math"
+	"net"
 	"net/http"
+	"strconv"
+	"sync"
+	"time"
 )
 
 type requireCert struct {
-	next http.Handler
+	next     http.Handler
+	attempts *unauthorizedAttempts
 }
 
 // RequireCert is used to ensure that a verified TLS client certificate was
@@ -22,6 +28,23 @@ func RequireCert() Middleware {
 	}
 }
 
+// RequireCertWithLimit is like RequireCert, but once a client host has made
+// more than limit unauthorized requests within the window, its further
+// unauthorized requests are rejected with http.StatusTooManyRequests, and only
+// logged at debug level, until the window expires. The number of requests that
+// were throttled is logged once the window of the host expires. The attempts
+// are counted across all the handlers wrapped by the returned Middleware.
+func RequireCertWithLimit(limit int, window time.Duration) Middleware {
+	attempts := &unauthorizedAttempts{
+		limit:  limit,
+		window: window,
+		hosts:  map[string]*attemptWindow{},
+	}
+	return func(next http.Handler) http.Handler {
+		return &requireCert{next: next, attempts: attempts}
+	}
+}
+
 func (r *requireCert) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	switch {
 	case req.TLS == nil:
@@ -29,9 +52,91 @@ func (r *requireCert) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	case len(req.TLS.VerifiedChains) == 0:
 		fallthrough
 	case len(req.TLS.VerifiedChains[0]) == 0:
+		r.unauthorized(w, req)
+	default:
+		r.next.ServeHTTP(w, req)
+	}
+}
+
+func (r *requireCert) unauthorized(w http.ResponseWriter, req *http.Request) {
+	if r.attempts == nil {
 		logger.Warnw("Client request not authorized, client must pass a valid client certificate for this operation", "URL", req.URL, "Method", req.Method, "RemoteAddr", req.RemoteAddr)
 		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	count, retryAfter := r.attempts.record(req.RemoteAddr)
+	switch {
+	case count <= r.attempts.limit:
+		logger.Warnw("Client request not authorized, client must pass a valid client certificate for this operation", "URL", req.URL, "Method", req.Method, "RemoteAddr", req.RemoteAddr, "Attempts", count)
+		w.WriteHeader(http.StatusUnauthorized)
+	case count == r.attempts.limit+1:
+		logger.Warnw("Too many unauthorized client requests, throttling client", "RemoteAddr", req.RemoteAddr, "RetryAfter", retryAfter)
+		fallthrough
 	default:
-		r.next.ServeHTTP(w, req)
+		logger.Debugw("Client request not authorized, client is throttled", "URL", req.URL, "Method", req.Method, "RemoteAddr", req.RemoteAddr, "Attempts", count)
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		w.WriteHeader(http.StatusTooManyRequests)
+	}
+}
+
+// unauthorizedAttempts counts the unauthorized requests of each client host
+// within a fixed window that starts with its first unauthorized request.
+type unauthorizedAttempts struct {
+	limit  int
+	window time.Duration
+
+	mutex sync.Mutex
+	hosts map[string]*attemptWindow
+}
+
+type attemptWindow struct {
+	start time.Time
+	count int
+}
+
+// record counts an unauthorized request from the remote address, returning the
+// number of requests from its host in the current window and the time left in it.
+func (u *unauthorizedAttempts) record(remoteAddr string) (int, time.Duration) {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	now := time.Now()
+	w, ok := u.hosts[host]
+	if !ok || now.Sub(w.start) >= u.window {
+		if ok {
+			u.expired(host, w)
+		} else {
+			u.removeExpired(now)
+		}
+		w = &attemptWindow{start: now}
+		u.hosts[host] = w
+	}
+	w.count++
+
+	return w.count, w.start.Add(u.window).Sub(now)
+}
+
+// removeExpired forgets the hosts whose window has expired, so that the
+// number of hosts tracked is bounded by those seen within a window.
+func (u *unauthorizedAttempts) removeExpired(now time.Time) {
+	for host, w := range u.hosts {
+		if now.Sub(w.start) >= u.window {
+			u.expired(host, w)
+			delete(u.hosts, host)
+		}
+	}
+}
+
+// expired logs the number of requests of the host that were throttled within
+// its expired window, as they were not logged one by one.
+func (u *unauthorizedAttempts) expired(host string, w *attemptWindow) {
+	if w.count > u.limit {
+		logger.Warnw("Unauthorized client requests were throttled", "Host", host, "Throttled", w.count-u.limit, "Window", u.window)
 	}
 }
@@ -10,11 +10,15 @@ import (
 	"crypto/x509"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"time"
 
+	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/middleware"
 	"github.com/hyperledger/fabric/core/middleware/fakes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
 )
 
 var _ = Describe("RequireCert", func() {
@@ -108,4 +112,121 @@ var _ = Describe("RequireCert", func() {
 			Expect(handler.ServeHTTPCallCount()).To(Equal(0))
 		})
 	})
+
+	Context("when the unauthorized requests are limited", func() {
+		var logs *gbytes.Buffer
+
+		BeforeEach(func() {
+			logs = gbytes.NewBuffer()
+			flogging.Global.SetWriter(logs)
+
+			chain = middleware.RequireCertWithLimit(2, time.Minute)(handler)
+			req.TLS = nil
+			req.RemoteAddr = "192.0.2.1:1234"
+		})
+
+		AfterEach(func() {
+			flogging.Global.SetWriter(os.Stderr)
+		})
+
+		It("throttles a client host after the limit is reached", func() {
+			for i := 0; i < 2; i++ {
+				resp := httptest.NewRecorder()
+				chain.ServeHTTP(resp, req)
+				Expect(resp.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+			}
+			Eventually(logs).Should(gbytes.Say(`Client request not authorized.*RemoteAddr=192\.0\.2\.1:1234 Attempts=1\n`))
+			Eventually(logs).Should(gbytes.Say(`Client request not authorized.*RemoteAddr=192\.0\.2\.1:1234 Attempts=2\n`))
+
+			// from another port of the same host
+			req.RemoteAddr = "192.0.2.1:5678"
+			for i := 0; i < 3; i++ {
+				resp := httptest.NewRecorder()
+				chain.ServeHTTP(resp, req)
+				Expect(resp.Result().StatusCode).To(Equal(http.StatusTooManyRequests))
+				Expect(resp.Result().Header.Get("Retry-After")).To(Equal("60"))
+			}
+			Eventually(logs).Should(gbytes.Say(`Too many unauthorized client requests, throttling client RemoteAddr=192\.0\.2\.1:5678`))
+			Consistently(logs).ShouldNot(gbytes.Say(`192\.0\.2\.1`))
+			Expect(handler.ServeHTTPCallCount()).To(Equal(0))
+		})
+
+		It("counts each client host separately", func() {
+			for i := 0; i < 3; i++ {
+				chain.ServeHTTP(httptest.NewRecorder(), req)
+			}
+
+			req.RemoteAddr = "192.0.2.2:1234"
+			chain.ServeHTTP(resp, req)
+			Expect(resp.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("counts the requests of handlers wrapped by the same middleware together", func() {
+			requireCert = middleware.RequireCertWithLimit(1, time.Minute)
+			requireCert(handler).ServeHTTP(httptest.NewRecorder(), req)
+
+			requireCert(handler).ServeHTTP(resp, req)
+			Expect(resp.Result().StatusCode).To(Equal(http.StatusTooManyRequests))
+		})
+
+		It("does not throttle authorized requests", func() {
+			for i := 0; i < 3; i++ {
+				chain.ServeHTTP(httptest.NewRecorder(), req)
+			}
+
+			req.TLS = httptest.NewRequest("GET", "https:///", nil).TLS
+			req.TLS.VerifiedChains = [][]*x509.Certificate{{
+				&x509.Certificate{},
+			}}
+			chain.ServeHTTP(resp, req)
+			Expect(resp.Result().StatusCode).To(Equal(http.StatusOK))
+			Expect(handler.ServeHTTPCallCount()).To(Equal(1))
+		})
+
+		It("resets the count when the window expires", func() {
+			chain = middleware.RequireCertWithLimit(1, 100*time.Millisecond)(handler)
+			chain.ServeHTTP(httptest.NewRecorder(), req)
+
+			Eventually(func() int {
+				resp := httptest.NewRecorder()
+				chain.ServeHTTP(resp, req)
+				return resp.Result().StatusCode
+			}).Should(Equal(http.StatusUnauthorized))
+		})
+
+		It("logs the throttled requests at debug level", func() {
+			flogging.ActivateSpec("middleware=debug")
+			defer flogging.ActivateSpec("info")
+
+			for i := 0; i < 4; i++ {
+				chain.ServeHTTP(httptest.NewRecorder(), req)
+			}
+			Eventually(logs).Should(gbytes.Say(`Too many unauthorized client requests, throttling client RemoteAddr=192\.0\.2\.1:1234`))
+			Eventually(logs).Should(gbytes.Say(`Client request not authorized, client is throttled.*RemoteAddr=192\.0\.2\.1:1234 Attempts=3\n`))
+			Eventually(logs).Should(gbytes.Say(`Client request not authorized, client is throttled.*RemoteAddr=192\.0\.2\.1:1234 Attempts=4\n`))
+		})
+
+		It("logs the number of throttled requests when the window expires", func() {
+			chain = middleware.RequireCertWithLimit(1, 100*time.Millisecond)(handler)
+			for i := 0; i < 4; i++ {
+				chain.ServeHTTP(httptest.NewRecorder(), req)
+			}
+
+			time.Sleep(100 * time.Millisecond)
+			chain.ServeHTTP(httptest.NewRecorder(), req)
+			Eventually(logs).Should(gbytes.Say(`Unauthorized client requests were throttled Host=192\.0\.2\.1 Throttled=3 Window=100ms`))
+		})
+
+		It("logs the number of throttled requests of a host whose expired window is forgotten", func() {
+			chain = middleware.RequireCertWithLimit(1, 100*time.Millisecond)(handler)
+			for i := 0; i < 2; i++ {
+				chain.ServeHTTP(httptest.NewRecorder(), req)
+			}
+
+			time.Sleep(100 * time.Millisecond)
+			req.RemoteAddr = "192.0.2.2:1234"
+			chain.ServeHTTP(httptest.NewRecorder(), req)
+			Eventually(logs).Should(gbytes.Say(`Unauthorized client requests were throttled Host=192\.0\.2\.1 Throttled=1 Window=100ms`))
+		})
+	})
 })
@@ -17,6 +17,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric/integration/nwo"
+	ordererTypes "github.com/hyperledger/fabric/orderer/common/types"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gstruct"
 	"github.com/onsi/gomega/types"
@@ -25,9 +26,9 @@ import (
 func Join(n *nwo.Network, o *nwo.Orderer, channel string, block *common.Block, expectedChannelInfo ChannelInfo) {
 	blockBytes, err := proto.Marshal(block)
 	Expect(err).NotTo(HaveOccurred())
-	url := fmt.Sprintf("https://127.0.0.1:%d/participation/v1/channels", n.OrdererPort(o, nwo.AdminPort))
+	url := fmt.Sprintf("https://%s/participation/v1/channels", n.OrdererAdminAddress(o))
 	req := GenerateJoinRequest(url, channel, blockBytes)
-	authClient, _ := nwo.OrdererOperationalClients(n, o)
+	authClient, _ := nwo.OrdererAdminClients(n, o)
 
 	body := doBody(authClient, req)
 	c := &ChannelInfo{}
@@ -74,8 +75,8 @@ type ChannelInfoShort struct {
 }
 
 func List(n *nwo.Network, o *nwo.Orderer) ChannelList {
-	authClient, _ := nwo.OrdererOperationalClients(n, o)
-	listChannelsURL := fmt.Sprintf("https://127.0.0.1:%d/participation/v1/channels", n.OrdererPort(o, nwo.AdminPort))
+	authClient, _ := nwo.OrdererAdminClients(n, o)
+	listChannelsURL := fmt.Sprintf("https://%s/participation/v1/channels", n.OrdererAdminAddress(o))
 
 	body := getBody(authClient, listChannelsURL)()
 	list := &ChannelList{}
@@ -97,16 +98,16 @@ func getBody(client *http.Client, url string) func() string {
 }
 
 type ChannelInfo struct {
-	Name              string `json:"name"`
-	URL               string `json:"url"`
-	Status            string `json:"status"`
-	ConsensusRelation string `json:"consensusRelation"`
-	Height            uint64 `json:"height"`
+	Name              string                         `json:"name"`
+	URL               string                         `json:"url"`
+	Status            ordererTypes.Status            `json:"status"`
+	ConsensusRelation ordererTypes.ConsensusRelation `json:"consensusRelation"`
+	Height            uint64                         `json:"height"`
 }
 
 func ListOne(n *nwo.Network, o *nwo.Orderer, channel string) ChannelInfo {
-	authClient, _ := nwo.OrdererOperationalClients(n, o)
-	listChannelURL := fmt.Sprintf("https://127.0.0.1:%d/participation/v1/channels/%s", n.OrdererPort(o, nwo.AdminPort), channel)
+	authClient, _ := nwo.OrdererAdminClients(n, o)
+	listChannelURL := fmt.Sprintf("https://%s/participation/v1/channels/%s", n.OrdererAdminAddress(o), channel)
 
 	body := getBody(authClient, listChannelURL)()
 	c := &ChannelInfo{}
@@ -116,8 +117,8 @@ func ListOne(n *nwo.Network, o *nwo.Orderer, channel string) ChannelInfo {
 }
 
 func Remove(n *nwo.Network, o *nwo.Orderer, channel string) {
-	authClient, _ := nwo.OrdererOperationalClients(n, o)
-	url := fmt.Sprintf("https://127.0.0.1:%d/participation/v1/channels/%s", n.OrdererPort(o, nwo.AdminPort), channel)
+	authClient, _ := nwo.OrdererAdminClients(n, o)
+	url := fmt.Sprintf("https://%s/participation/v1/channels/%s", n.OrdererAdminAddress(o), channel)
 
 	req, err := http.NewRequest(http.MethodDelete, url, nil)
 	Expect(err).NotTo(HaveOccurred())
@@ -127,6 +128,35 @@ func Remove(n *nwo.Network, o *nwo.Orderer, channel string) {
 	Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
 }
 
+// ExpectAbsent waits until the channel no longer appears in the channel list
+// of the orderer, and then expects listing the channel to fail with 404 Not
+// Found, as it does once the channel is fully removed.
+func ExpectAbsent(n *nwo.Network, o *nwo.Orderer, channel string) {
+	Eventually(func() []string {
+		return channelNames(List(n, o))
+	}, n.EventuallyTimeout).ShouldNot(ContainElement(channel))
+
+	authClient, _ := nwo.OrdererAdminClients(n, o)
+	listChannelURL := fmt.Sprintf("https://%s/participation/v1/channels/%s", n.OrdererAdminAddress(o), channel)
+	resp, err := authClient.Get(listChannelURL)
+	Expect(err).NotTo(HaveOccurred())
+	resp.Body.Close()
+	Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+}
+
+// channelNames returns the names of the channels in the list, the system
+// channel included.
+func channelNames(list ChannelList) []string {
+	var names []string
+	if list.SystemChannel != nil {
+		names = append(names, list.SystemChannel.Name)
+	}
+	for _, c := range list.Channels {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
 func ChannelListMatcher(list ChannelList, expectedChannels []string, systemChannel ...string) {
 	Expect(list).To(gstruct.MatchFields(gstruct.IgnoreExtras, gstruct.Fields{
 		"Channels":      channelsMatcher(expectedChannels),
@@ -158,3 +188,17 @@ func channelInfoShortMatcher(channel string) types.GomegaMatcher {
 		"URL":  Equal(fmt.Sprintf("/participation/v1/channels/%s", channel)),
 	})
 }
+
+// ChannelInfoMatcher matches a ChannelInfo against the expected one, except
+// for the height, which only needs to be at least the expected height, as it
+// may grow with blocks committed in the background, e.g. config updates.
+// An expected height of 0 matches any height.
+func ChannelInfoMatcher(expected ChannelInfo) types.GomegaMatcher {
+	return gstruct.MatchAllFields(gstruct.Fields{
+		"Name":              Equal(expected.Name),
+		"URL":               Equal(expected.URL),
+		"Status":            Equal(expected.Status),
+		"ConsensusRelation": Equal(expected.ConsensusRelation),
+		"Height":            BeNumerically(">=", expected.Height),
+	})
+}
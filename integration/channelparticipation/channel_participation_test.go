@@ -0,0 +1,189 @@
+/*
+Copyright IBM Corp All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channelparticipation_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/crypto/tlsgen"
+	"github.com/hyperledger/fabric/integration/channelparticipation"
+	"github.com/hyperledger/fabric/integration/nwo"
+	"github.com/hyperledger/fabric/integration/nwo/fabricconfig"
+	ordererTypes "github.com/hyperledger/fabric/orderer/common/types"
+	. "github.com/onsi/gomega"
+)
+
+func TestChannelInfoMatcher(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	expected := channelparticipation.ChannelInfo{
+		Name:              "testchannel",
+		URL:               "/participation/v1/channels/testchannel",
+		Status:            ordererTypes.StatusActive,
+		ConsensusRelation: ordererTypes.ConsensusRelationConsenter,
+		Height:            3,
+	}
+
+	actual := expected
+	gt.Expect(actual).To(channelparticipation.ChannelInfoMatcher(expected))
+
+	actual.Height = 5
+	gt.Expect(actual).To(channelparticipation.ChannelInfoMatcher(expected), "a height that overshoots matches")
+
+	actual.Height = 2
+	gt.Expect(actual).NotTo(channelparticipation.ChannelInfoMatcher(expected), "a lower height does not match")
+
+	anyHeight := expected
+	anyHeight.Height = 0
+	gt.Expect(actual).To(channelparticipation.ChannelInfoMatcher(anyHeight), "an expected height of 0 matches any height")
+
+	actual = expected
+	actual.Status = ordererTypes.StatusOnBoarding
+	gt.Expect(actual).NotTo(channelparticipation.ChannelInfoMatcher(expected), "other fields must be equal")
+}
+
+func TestListNonLoopbackAdminAddress(t *testing.T) {
+	RegisterTestingT(t)
+
+	host := nonLoopbackIPv4()
+	if host == "" {
+		t.Skip("no non-loopback IPv4 address")
+	}
+
+	tempDir, err := ioutil.TempDir("", "channelparticipation")
+	Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(tempDir)
+
+	var requestedHosts []string
+	n, o, server := startAdminServer(tempDir, host, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedHosts = append(requestedHosts, r.Host)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ordererTypes.ChannelList{
+			Channels: []ordererTypes.ChannelInfoShort{{Name: "testchannel", URL: "/participation/v1/channels/testchannel"}},
+		})
+	}))
+	defer server.Close()
+
+	Expect(n.OrdererAdminAddress(o)).To(Equal(server.Listener.Addr().String()))
+	channelparticipation.ChannelListMatcher(channelparticipation.List(n, o), []string{"testchannel"})
+	Expect(requestedHosts).To(Equal([]string{server.Listener.Addr().String()}))
+}
+
+func TestExpectAbsent(t *testing.T) {
+	RegisterTestingT(t)
+
+	tempDir, err := ioutil.TempDir("", "channelparticipation")
+	Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(tempDir)
+
+	// the channel is dropped from the list after a few polls, as while it is being removed
+	var listCount int
+	var listedOne []string
+	n, o, server := startAdminServer(tempDir, "127.0.0.1", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/participation/v1/channels" {
+			listedOne = append(listedOne, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(ordererTypes.ErrorResponse{Error: "channel does not exist"})
+			return
+		}
+		listCount++
+		list := ordererTypes.ChannelList{
+			Channels: []ordererTypes.ChannelInfoShort{{Name: "otherchannel", URL: "/participation/v1/channels/otherchannel"}},
+		}
+		if listCount <= 3 {
+			list.Channels = append(list.Channels, ordererTypes.ChannelInfoShort{Name: "testchannel", URL: "/participation/v1/channels/testchannel"})
+		}
+		json.NewEncoder(w).Encode(list)
+	}))
+	defer server.Close()
+
+	channelparticipation.ExpectAbsent(n, o, "testchannel")
+	Expect(listCount).To(Equal(4))
+	Expect(listedOne).To(Equal([]string{"/participation/v1/channels/testchannel"}))
+}
+
+// startAdminServer starts a TLS server with the handler on an ephemeral port
+// of the host, and returns a network with an orderer whose admin endpoint is
+// bound to it.
+func startAdminServer(tempDir, host string, handler http.Handler) (*nwo.Network, *nwo.Orderer, *httptest.Server) {
+	ca, err := tlsgen.NewCA()
+	Expect(err).NotTo(HaveOccurred())
+	serverKeyPair, err := ca.NewServerCertKeyPair(host)
+	Expect(err).NotTo(HaveOccurred())
+	serverCert, err := tls.X509KeyPair(serverKeyPair.Cert, serverKeyPair.Key)
+	Expect(err).NotTo(HaveOccurred())
+	caCertPool := x509.NewCertPool()
+	caCertPool.AppendCertsFromPEM(ca.CertBytes())
+
+	server := httptest.NewUnstartedServer(handler)
+	server.Listener.Close()
+	server.Listener, err = net.Listen("tcp", net.JoinHostPort(host, "0"))
+	Expect(err).NotTo(HaveOccurred())
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    caCertPool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	server.StartTLS()
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	Expect(err).NotTo(HaveOccurred())
+
+	o := &nwo.Orderer{Name: "orderer", Organization: "OrdererOrg"}
+	n := nwo.New(&nwo.Config{Orderers: []*nwo.Orderer{o}, Consensus: &nwo.Consensus{}}, tempDir, nil, 20000, nil)
+	adminPort, err := net.LookupPort("tcp", port)
+	Expect(err).NotTo(HaveOccurred())
+	n.PortsByOrdererID[o.ID()][nwo.AdminPort] = uint16(adminPort)
+
+	certFile := filepath.Join(tempDir, "server.crt")
+	keyFile := filepath.Join(tempDir, "server.key")
+	caFile := filepath.Join(tempDir, "ca.crt")
+	Expect(ioutil.WriteFile(certFile, serverKeyPair.Cert, 0o644)).To(Succeed())
+	Expect(ioutil.WriteFile(keyFile, serverKeyPair.Key, 0o600)).To(Succeed())
+	Expect(ioutil.WriteFile(caFile, ca.CertBytes(), 0o644)).To(Succeed())
+	Expect(os.MkdirAll(n.OrdererDir(o), 0o755)).To(Succeed())
+	n.WriteOrdererConfig(o, &fabricconfig.Orderer{
+		Admin: &fabricconfig.OrdererAdmin{
+			ListenAddress: net.JoinHostPort(host, port),
+			TLS: &fabricconfig.OrdererTLS{
+				Enabled:            true,
+				Certificate:        certFile,
+				PrivateKey:         keyFile,
+				RootCAs:            []string{caFile},
+				ClientAuthRequired: true,
+				ClientRootCAs:      []string{caFile},
+			},
+		},
+	})
+
+	return n, o, server
+}
+
+// nonLoopbackIPv4 returns an IPv4 address of this host that is not a loopback
+// address, or an empty string if there is none.
+func nonLoopbackIPv4() string {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return ""
+	}
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+			return ipNet.IP.String()
+		}
+	}
+	return ""
+}
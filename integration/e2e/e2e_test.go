@@ -163,7 +163,7 @@ var _ = Describe("EndToEnd", func() {
 			By("listing channels with osnadmin")
 			tlsdir := network.OrdererLocalTLSDir(orderer)
 			sess, err := network.Osnadmin(commands.ChannelList{
-				OrdererAddress: network.OrdererAddress(orderer, nwo.AdminPort),
+				OrdererAddress: network.OrdererAdminAddress(orderer),
 				CAFile:         filepath.Join(tlsdir, "ca.crt"),
 				ClientCert:     filepath.Join(tlsdir, "server.crt"),
 				ClientKey:      filepath.Join(tlsdir, "server.key"),
@@ -13,6 +13,7 @@ type Orderer struct {
 	FileLedger           *FileLedger           `yaml:"FileLedger,omitempty"`
 	Kafka                *Kafka                `yaml:"Kafka,omitempty"`
 	Operations           *OrdererOperations    `yaml:"Operations,omitempty"`
+	Admin                *OrdererAdmin         `yaml:"Admin,omitempty"`
 	ChannelParticipation *ChannelParticipation `yaml:"ChannelParticipation,omitempty"`
 	Consensus            map[string]string     `yaml:"Consensus,omitempty"`
 
@@ -133,6 +134,13 @@ type OrdererOperations struct {
 	TLS           *OrdererTLS     `yaml:"TLS"`
 }
 
+type OrdererAdmin struct {
+	ListenAddress      string        `yaml:"ListenAddress,omitempty"`
+	TLS                *OrdererTLS   `yaml:"TLS"`
+	UnauthorizedLimit  int           `yaml:"UnauthorizedLimit,omitempty"`
+	UnauthorizedWindow time.Duration `yaml:"UnauthorizedWindow,omitempty"`
+}
+
 type OrdererMetrics struct {
 	Provider string         `yaml:"Provider"`
 	Statsd   *OrdererStatsd `yaml:"Statsd,omitempty"`
@@ -146,6 +154,18 @@ type OrdererStatsd struct {
 }
 
 type ChannelParticipation struct {
-	Enabled            bool   `yaml:"Enabled"`
-	MaxRequestBodySize string `yaml:"MaxRequestBodySize,omitempty"`
+	Enabled                       bool          `yaml:"Enabled"`
+	MaxRequestBodySize            string        `yaml:"MaxRequestBodySize,omitempty"`
+	MaxConcurrentJoins            uint32        `yaml:"MaxConcurrentJoins,omitempty"`
+	WebhookURL                    string        `yaml:"WebhookURL,omitempty"`
+	WebhookTimeout                time.Duration `yaml:"WebhookTimeout,omitempty"`
+	ProtectConsenters             bool          `yaml:"ProtectConsenters,omitempty"`
+	ProtectSoleConsenter          bool          `yaml:"ProtectSoleConsenter,omitempty"`
+	SpoolThreshold                string        `yaml:"SpoolThreshold,omitempty"`
+	OrdererEndpoint               string        `yaml:"OrdererEndpoint,omitempty"`
+	MetricsEnabled                bool          `yaml:"MetricsEnabled,omitempty"`
+	MinFreeDiskSpace              string        `yaml:"MinFreeDiskSpace,omitempty"`
+	EventsInterval                time.Duration `yaml:"EventsInterval,omitempty"`
+	AuthorizedOrganizations       []string      `yaml:"AuthorizedOrganizations,omitempty"`
+	AuthorizedOrganizationalUnits []string      `yaml:"AuthorizedOrganizationalUnits,omitempty"`
 }
@@ -1804,6 +1804,21 @@ func (n *Network) OrdererAddress(o *Orderer, portName PortName) string {
 	return fmt.Sprintf("127.0.0.1:%d", n.OrdererPort(o, portName))
 }
 
+// OrdererAdminAddress returns the address (host and port) of the admin endpoint
+// of the Orderer. The host is the one the endpoint listens on, as set by
+// Admin.ListenAddress in the orderer configuration, unless it listens on all
+// interfaces, in which case it is available on the loopback address.
+func (n *Network) OrdererAdminAddress(o *Orderer) string {
+	host := "127.0.0.1"
+	if admin := n.ReadOrdererConfig(o).Admin; admin != nil {
+		listenHost, _, err := net.SplitHostPort(admin.ListenAddress)
+		if err == nil && listenHost != "" && !net.ParseIP(listenHost).IsUnspecified() {
+			host = listenHost
+		}
+	}
+	return net.JoinHostPort(host, strconv.Itoa(int(n.OrdererPort(o, AdminPort))))
+}
+
 // OrdererPort returns the named port reserved for the Orderer instance.
 func (n *Network) OrdererPort(o *Orderer, portName PortName) uint16 {
 	ordererPorts := n.PortsByOrdererID[o.ID()]
@@ -21,26 +21,47 @@ func OrdererOperationalClients(n *Network, o *Orderer) (authClient, unauthClient
 	return operationalClients(n, n.OrdererLocalTLSDir(o))
 }
 
+// OrdererAdminClients returns clients for the admin endpoint of an orderer,
+// using the TLS materials of the orderer's Admin configuration.
+func OrdererAdminClients(n *Network, o *Orderer) (authClient, unauthClient *http.Client) {
+	ordererConfig := n.ReadOrdererConfig(o)
+	if ordererConfig.Admin == nil || ordererConfig.Admin.TLS == nil || ordererConfig.Admin.TLS.Certificate == "" {
+		return OrdererOperationalClients(n, o)
+	}
+
+	adminTLS := ordererConfig.Admin.TLS
+	caCerts := append(append([]string{}, adminTLS.RootCAs...), adminTLS.ClientRootCAs...)
+	return httpClients(n, adminTLS.Certificate, adminTLS.PrivateKey, caCerts...)
+}
+
 func PeerOperationalClients(n *Network, p *Peer) (authClient, unauthClient *http.Client) {
 	return operationalClients(n, n.PeerLocalTLSDir(p))
 }
 
 func operationalClients(n *Network, tlsDir string) (authClient, unauthClient *http.Client) {
-	fingerprint := "http::" + tlsDir
+	return httpClients(
+		n,
+		filepath.Join(tlsDir, "server.crt"),
+		filepath.Join(tlsDir, "server.key"),
+		filepath.Join(tlsDir, "ca.crt"),
+	)
+}
+
+func httpClients(n *Network, certFile, keyFile string, caCertFiles ...string) (authClient, unauthClient *http.Client) {
+	fingerprint := "http::" + certFile
 	if d := n.throttleDuration(fingerprint); d > 0 {
 		time.Sleep(d)
 	}
 
-	clientCert, err := tls.LoadX509KeyPair(
-		filepath.Join(tlsDir, "server.crt"),
-		filepath.Join(tlsDir, "server.key"),
-	)
+	clientCert, err := tls.LoadX509KeyPair(certFile, keyFile)
 	Expect(err).NotTo(HaveOccurred())
 
 	clientCertPool := x509.NewCertPool()
-	caCert, err := ioutil.ReadFile(filepath.Join(tlsDir, "ca.crt"))
-	Expect(err).NotTo(HaveOccurred())
-	clientCertPool.AppendCertsFromPEM(caCert)
+	for _, caCertFile := range caCertFiles {
+		caCert, err := ioutil.ReadFile(caCertFile)
+		Expect(err).NotTo(HaveOccurred())
+		clientCertPool.AppendCertsFromPEM(caCert)
+	}
 
 	authenticatedClient := &http.Client{
 		Transport: &http.Transport{
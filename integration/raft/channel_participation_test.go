@@ -24,6 +24,7 @@ import (
 	"github.com/hyperledger/fabric-config/configtx"
 	"github.com/hyperledger/fabric-config/configtx/orderer"
 	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/crypto/tlsgen"
 	"github.com/hyperledger/fabric/common/ledger/blockledger/fileledger"
 	"github.com/hyperledger/fabric/common/metrics/disabled"
 	"github.com/hyperledger/fabric/integration/channelparticipation"
@@ -349,7 +350,7 @@ var _ = Describe("ChannelParticipation", func() {
 			})
 
 			By("attempting to join with an invalid block")
-			channelparticipationJoinFailure(network, orderer3, "nice-try", &common.Block{}, http.StatusBadRequest, "invalid join block: block is not a config block")
+			channelparticipationJoinFailure(network, orderer3, "nice-try", &common.Block{}, http.StatusBadRequest, "invalid join block: block has no header")
 
 			By("attempting to join a channel that already exists")
 			channelparticipationJoinFailure(network, orderer3, "participation-trophy", genesisBlock, http.StatusMethodNotAllowed, "cannot join: channel already exists")
@@ -1021,9 +1022,46 @@ var _ = Describe("ChannelParticipation", func() {
 			}, network.EventuallyTimeout).Should(Equal(expectedChannelInfo))
 		})
 
+		It("uses the admin endpoint TLS materials when they differ from the orderer's", func() {
+			orderer1 := network.Orderer("orderer1")
+
+			By("configuring the admin endpoint of orderer1 with its own TLS CA")
+			adminTLSDir := filepath.Join(testDir, "admin-tls")
+			err := os.MkdirAll(adminTLSDir, 0o755)
+			Expect(err).NotTo(HaveOccurred())
+			adminCA, err := tlsgen.NewCA()
+			Expect(err).NotTo(HaveOccurred())
+			adminKeyPair, err := adminCA.NewServerCertKeyPair("127.0.0.1")
+			Expect(err).NotTo(HaveOccurred())
+			err = ioutil.WriteFile(filepath.Join(adminTLSDir, "ca.crt"), adminCA.CertBytes(), 0o644)
+			Expect(err).NotTo(HaveOccurred())
+			err = ioutil.WriteFile(filepath.Join(adminTLSDir, "server.crt"), adminKeyPair.Cert, 0o644)
+			Expect(err).NotTo(HaveOccurred())
+			err = ioutil.WriteFile(filepath.Join(adminTLSDir, "server.key"), adminKeyPair.Key, 0o600)
+			Expect(err).NotTo(HaveOccurred())
+
+			ordererConfig := network.ReadOrdererConfig(orderer1)
+			ordererConfig.Admin.TLS.Certificate = filepath.Join(adminTLSDir, "server.crt")
+			ordererConfig.Admin.TLS.PrivateKey = filepath.Join(adminTLSDir, "server.key")
+			ordererConfig.Admin.TLS.RootCAs = []string{filepath.Join(adminTLSDir, "ca.crt")}
+			ordererConfig.Admin.TLS.ClientRootCAs = []string{filepath.Join(adminTLSDir, "ca.crt")}
+			network.WriteOrdererConfig(orderer1, ordererConfig)
+			startOrderer(orderer1)
+
+			By("connecting with the admin clients")
+			cl := channelparticipation.List(network, orderer1)
+			Expect(cl).To(Equal(channelparticipation.ChannelList{}))
+
+			By("failing to connect with the operational clients")
+			authClient, _ := nwo.OrdererOperationalClients(network, orderer1)
+			listChannelsURL := fmt.Sprintf("https://%s/participation/v1/channels", network.OrdererAdminAddress(orderer1))
+			_, err = authClient.Get(listChannelsURL)
+			Expect(err).To(MatchError(ContainSubstring("certificate signed by unknown authority")))
+		})
+
 		It("requires a client certificate to connect when TLS is enabled", func() {
 			orderer := network.Orderer("orderer1")
-			_, unauthClient := nwo.OrdererOperationalClients(network, orderer)
+			_, unauthClient := nwo.OrdererAdminClients(network, orderer)
 			ordererAddress := fmt.Sprintf("127.0.0.1:%d", network.OrdererPort(orderer, nwo.AdminPort))
 			listChannelsURL := fmt.Sprintf("https://%s/participation/v1/channels", ordererAddress)
 
@@ -1625,9 +1663,9 @@ type errorResponse struct {
 func channelparticipationJoinFailure(n *nwo.Network, o *nwo.Orderer, channel string, block *common.Block, expectedStatus int, expectedError string) {
 	blockBytes, err := proto.Marshal(block)
 	Expect(err).NotTo(HaveOccurred())
-	url := fmt.Sprintf("https://127.0.0.1:%d/participation/v1/channels", n.OrdererPort(o, nwo.AdminPort))
+	url := fmt.Sprintf("https://%s/participation/v1/channels", n.OrdererAdminAddress(o))
 	req := channelparticipation.GenerateJoinRequest(url, channel, blockBytes)
-	authClient, _ := nwo.OrdererOperationalClients(n, o)
+	authClient, _ := nwo.OrdererAdminClients(n, o)
 
 	doBodyFailure(authClient, req, expectedStatus, expectedError)
 }
@@ -1647,8 +1685,8 @@ func doBodyFailure(client *http.Client, req *http.Request, expectedStatus int, e
 }
 
 func channelparticipationRemoveFailure(n *nwo.Network, o *nwo.Orderer, channel string, expectedStatus int, expectedError string) {
-	authClient, _ := nwo.OrdererOperationalClients(n, o)
-	url := fmt.Sprintf("https://127.0.0.1:%d/participation/v1/channels/%s", n.OrdererPort(o, nwo.AdminPort), channel)
+	authClient, _ := nwo.OrdererAdminClients(n, o)
+	url := fmt.Sprintf("https://%s/participation/v1/channels/%s", n.OrdererAdminAddress(o), channel)
 
 	req, err := http.NewRequest(http.MethodDelete, url, nil)
 	Expect(err).NotTo(HaveOccurred())
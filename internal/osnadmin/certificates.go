@@ -0,0 +1,67 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package osnadmin
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+)
+
+// Retrieves the TLS certificate chain presented by an OSN admin endpoint.
+// Server certificate verification is skipped so that the chain can be
+// inspected even when it would otherwise fail to verify.
+func ServerCertificates(ordererAddress string, tlsClientCert tls.Certificate) ([]*x509.Certificate, error) {
+	conn, err := tls.Dial("tcp", ordererAddress, &tls.Config{
+		Certificates:       []tls.Certificate{tlsClientCert},
+		InsecureSkipVerify: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	return conn.ConnectionState().PeerCertificates, nil
+}
+
+// Adds the PEM-encoded certificates of every *.pem and *.crt file in a
+// directory to the pool, e.g. a directory of trusted roots distributed as
+// one file per CA. Subdirectories and other files are ignored. It fails when
+// a file holds no certificate, or the directory holds no such file.
+func AppendCertsFromDir(pool *x509.CertPool, dir string) error {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var added int
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch filepath.Ext(entry.Name()) {
+		case ".pem", ".crt":
+		default:
+			continue
+		}
+		certPath := filepath.Join(dir, entry.Name())
+		certPEM, err := ioutil.ReadFile(certPath)
+		if err != nil {
+			return err
+		}
+		if !pool.AppendCertsFromPEM(certPEM) {
+			return fmt.Errorf("no PEM-encoded certificate in %s", certPath)
+		}
+		added++
+	}
+	if added == 0 {
+		return fmt.Errorf("no *.pem or *.crt file in %s", dir)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package osnadmin
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/internal/pkg/identity"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/hyperledger/fabric/protoutil"
+)
+
+// Switches a channel of an OSN to a consensus state, types.ConsensusStateNormal
+// or types.ConsensusStateMaintenance. The OSN computes the config update,
+// which is checked and signed by the signer before it is submitted, so the
+// signer must satisfy the modification policy of the consensus type. The
+// response of the OSN is returned as is when it cannot compute the update.
+func SetConsensusState(osnURL, channelID, state string, signer identity.SignerSerializer, caCertPool *x509.CertPool, tlsClientCert tls.Certificate, clientOpts ClientOptions) (*http.Response, error) {
+	url := channelURL(osnURL, channelID)
+
+	body, err := json.Marshal(types.ChannelConfigPatch{ConsensusState: &state})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url+"/configupdate", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	req.Header.Set("Accept", "application/octet-stream")
+
+	resp, err := httpDo(req, caCertPool, tlsClientCert, clientOpts)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+	configUpdateBytes, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	configUpdateEnv, err := signConsensusStateUpdate(configUpdateBytes, channelID, state, signer)
+	if err != nil {
+		return nil, err
+	}
+	body, err = proto.Marshal(configUpdateEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err = http.NewRequest(http.MethodPatch, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	return httpDo(req, caCertPool, tlsClientCert, clientOpts)
+}
+
+// signConsensusStateUpdate signs the config update computed by the OSN, once
+// it is known to only switch the consensus state of the channel, as whatever
+// it changes is authorized by the signature.
+func signConsensusStateUpdate(configUpdateBytes []byte, channelID, state string, signer identity.SignerSerializer) (*cb.ConfigUpdateEnvelope, error) {
+	configUpdate := &cb.ConfigUpdate{}
+	if err := proto.Unmarshal(configUpdateBytes, configUpdate); err != nil {
+		return nil, fmt.Errorf("unmarshalling config update: %s", err)
+	}
+	if configUpdate.ChannelId != channelID {
+		return nil, fmt.Errorf("config update is for channel %s, not %s", configUpdate.ChannelId, channelID)
+	}
+
+	writeSet := configUpdate.GetWriteSet()
+	ordererGroup := writeSet.GetGroups()[channelconfig.OrdererGroupKey]
+	consensusTypeValue := ordererGroup.GetValues()[channelconfig.ConsensusTypeKey]
+	if len(writeSet.GetGroups()) != 1 || len(writeSet.GetValues()) != 0 || len(writeSet.GetPolicies()) != 0 ||
+		len(ordererGroup.GetGroups()) != 0 || len(ordererGroup.GetValues()) != 1 || len(ordererGroup.GetPolicies()) != 0 ||
+		consensusTypeValue == nil {
+		return nil, fmt.Errorf("config update changes more than the consensus type")
+	}
+	consensusType := &ab.ConsensusType{}
+	if err := proto.Unmarshal(consensusTypeValue.Value, consensusType); err != nil {
+		return nil, fmt.Errorf("unmarshalling consensus type: %s", err)
+	}
+	expected := ab.ConsensusType_STATE_NORMAL
+	if state == types.ConsensusStateMaintenance {
+		expected = ab.ConsensusType_STATE_MAINTENANCE
+	}
+	if consensusType.State != expected {
+		return nil, fmt.Errorf("config update sets the consensus state to %s, not %s", consensusType.State, expected)
+	}
+
+	sigHeader, err := protoutil.NewSignatureHeader(signer)
+	if err != nil {
+		return nil, fmt.Errorf("creating signature header: %s", err)
+	}
+	configSig := &cb.ConfigSignature{SignatureHeader: protoutil.MarshalOrPanic(sigHeader)}
+	configSig.Signature, err = signer.Sign(util.ConcatenateBytes(configSig.SignatureHeader, configUpdateBytes))
+	if err != nil {
+		return nil, fmt.Errorf("signing config update: %s", err)
+	}
+
+	return &cb.ConfigUpdateEnvelope{
+		ConfigUpdate: configUpdateBytes,
+		Signatures:   []*cb.ConfigSignature{configSig},
+	}, nil
+}
@@ -0,0 +1,161 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package osnadmin
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// The time allowed for each network operation of a diagnosis.
+const diagnoseTimeout = 10 * time.Second
+
+// Diagnosis carries the outcome of a single check run by Diagnose.
+type Diagnosis struct {
+	// The description of the check, e.g. "TCP connect to orderer.example.com:9443".
+	Check string
+	// The reason the check failed, nil if it passed or was skipped.
+	Err error
+	// Whether the check was not run, because a previous check failed or TLS is disabled.
+	Skipped bool
+}
+
+// Diagnose checks, in order, that the admin endpoint of an OSN resolves, accepts
+// TCP connections, completes a TLS handshake with a server certificate signed by
+// the CA pool, accepts the client certificate, and serves the channel list.
+// The checks that follow a failed check are skipped, so that the first failure
+// is the one to look into. TLS checks are skipped when caCertPool is nil. The
+// path prefix is that of OSNURL, and the channel list is requested with the
+// client options.
+func Diagnose(ordererAddress, pathPrefix string, caCertPool *x509.CertPool, tlsClientCert tls.Certificate, clientOpts ClientOptions) []Diagnosis {
+	host, _, addressErr := net.SplitHostPort(ordererAddress)
+	if addressErr != nil {
+		host = ordererAddress
+	}
+	tlsEnabled := caCertPool != nil
+
+	osnURL := OSNURL("http", ordererAddress, pathPrefix)
+	if tlsEnabled {
+		osnURL = OSNURL("https", ordererAddress, pathPrefix)
+	}
+
+	checks := []struct {
+		name       string
+		requireTLS bool
+		run        func() error
+	}{
+		{
+			name: fmt.Sprintf("DNS resolve %s", host),
+			run: func() error {
+				if addressErr != nil {
+					return addressErr
+				}
+				_, err := net.LookupHost(host)
+				return err
+			},
+		},
+		{
+			name: fmt.Sprintf("TCP connect to %s", ordererAddress),
+			run: func() error {
+				conn, err := net.DialTimeout("tcp", ordererAddress, diagnoseTimeout)
+				if err != nil {
+					return err
+				}
+				return conn.Close()
+			},
+		},
+		{
+			name:       "TLS handshake and server certificate",
+			requireTLS: true,
+			run: func() error {
+				// the server may require a client certificate, which is only checked
+				// by the next step, so the handshake is limited to TLS 1.3 where the
+				// client completes it before the server verifies the client certificate
+				conn, err := tls.DialWithDialer(&net.Dialer{Timeout: diagnoseTimeout}, "tcp", ordererAddress, &tls.Config{
+					RootCAs:    caCertPool,
+					MinVersion: tls.VersionTLS13,
+				})
+				if err != nil {
+					return withClockSkewHint(err, time.Now())
+				}
+				return conn.Close()
+			},
+		},
+		{
+			name:       "TLS client certificate accepted",
+			requireTLS: true,
+			run: func() error {
+				return checkClientCertificate(ordererAddress, osnURL, caCertPool, tlsClientCert, clientOpts.UserAgent)
+			},
+		},
+		{
+			name: "List channels",
+			run: func() error {
+				resp, err := ListAllChannels(osnURL, caCertPool, tlsClientCert, clientOpts)
+				if err != nil {
+					return err
+				}
+				defer resp.Body.Close()
+				if resp.StatusCode != http.StatusOK {
+					return fmt.Errorf("unexpected status: %s", resp.Status)
+				}
+				return nil
+			},
+		},
+	}
+
+	var diagnoses []Diagnosis
+	failed := false
+	for _, check := range checks {
+		if failed || (check.requireTLS && !tlsEnabled) {
+			diagnoses = append(diagnoses, Diagnosis{Check: check.name, Skipped: true})
+			continue
+		}
+		err := check.run()
+		diagnoses = append(diagnoses, Diagnosis{Check: check.name, Err: err})
+		failed = err != nil
+	}
+
+	return diagnoses
+}
+
+// checkClientCertificate sends a request over a TLS connection that presents
+// the client certificate. With TLS 1.3 a rejected client certificate is only
+// reported by the server after the handshake, when the response is read.
+func checkClientCertificate(ordererAddress, osnURL string, caCertPool *x509.CertPool, tlsClientCert tls.Certificate, userAgent string) error {
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: diagnoseTimeout}, "tcp", ordererAddress, &tls.Config{
+		RootCAs:      caCertPool,
+		Certificates: []tls.Certificate{tlsClientCert},
+	})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(diagnoseTimeout)); err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodGet, channelsURL(osnURL), nil)
+	if err != nil {
+		return err
+	}
+	req.Close = true
+	setUserAgent(req, userAgent)
+	if err := req.Write(conn); err != nil {
+		return err
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
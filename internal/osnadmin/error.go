@@ -0,0 +1,89 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package osnadmin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/hyperledger/fabric/orderer/common/types"
+)
+
+// Codes of the known failures reported by an OSN.
+const (
+	CodeSystemChannelExists     = "system-channel-exists"
+	CodeChannelAlreadyExists    = "channel-already-exists"
+	CodeAppChannelsAlreadyExist = "app-channels-already-exist"
+	CodeChannelNotExist         = "channel-not-exist"
+	CodeChannelPendingRemoval   = "channel-pending-removal"
+	CodeChannelRemovalFailure   = "channel-removal-failure"
+	CodeUnknown                 = "unknown"
+)
+
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{types.ErrSystemChannelExists, CodeSystemChannelExists},
+	{types.ErrChannelAlreadyExists, CodeChannelAlreadyExists},
+	{types.ErrAppChannelsAlreadyExists, CodeAppChannelsAlreadyExist},
+	{types.ErrChannelNotExist, CodeChannelNotExist},
+	{types.ErrChannelPendingRemoval, CodeChannelPendingRemoval},
+	{types.ErrChannelRemovalFailure, CodeChannelRemovalFailure},
+}
+
+// OSNError is returned when an OSN responds with a non-2xx status code.
+type OSNError struct {
+	// The HTTP status code of the response.
+	StatusCode int
+	// The code of the failure, one of the Code constants.
+	Code string
+	// The error message returned by the OSN.
+	Message string
+}
+
+func (e *OSNError) Error() string {
+	return fmt.Sprintf("OSN responded with status %d: %s", e.StatusCode, e.Message)
+}
+
+// CheckResponse returns an *OSNError when the response has a non-2xx status
+// code, in which case the response body is consumed and closed.
+func CheckResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	bodyBytes, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("reading http response body: %s", err)
+	}
+
+	message := strings.TrimSpace(string(bodyBytes))
+	errResp := &types.ErrorResponse{}
+	if err := json.Unmarshal(bodyBytes, errResp); err == nil && errResp.Error != "" {
+		message = errResp.Error
+	}
+
+	return &OSNError{
+		StatusCode: resp.StatusCode,
+		Code:       errorCode(message),
+		Message:    message,
+	}
+}
+
+func errorCode(message string) string {
+	for _, ec := range errorCodes {
+		if strings.HasSuffix(message, ec.err.Error()) {
+			return ec.code
+		}
+	}
+	return CodeUnknown
+}
@@ -0,0 +1,76 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package osnadmin_test
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/fabric/internal/osnadmin"
+	"github.com/hyperledger/fabric/orderer/common/channelparticipation"
+	"github.com/hyperledger/fabric/orderer/common/channelparticipation/mocks"
+	"github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckResponse(t *testing.T) {
+	fakeManager := &mocks.ChannelManagement{}
+	h := channelparticipation.NewHTTPHandler(localconfig.ChannelParticipation{Enabled: true}, fakeManager)
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	t.Run("success", func(t *testing.T) {
+		resp, err := osnadmin.ListAllChannels(server.URL, nil, tls.Certificate{}, osnadmin.ClientOptions{})
+		require.NoError(t, err)
+		require.NoError(t, osnadmin.CheckResponse(resp))
+		resp.Body.Close()
+	})
+
+	knownFailures := []struct {
+		name               string
+		removeErr          error
+		expectedStatusCode int
+		expectedCode       string
+	}{
+		{"system channel exists", types.ErrSystemChannelExists, http.StatusMethodNotAllowed, osnadmin.CodeSystemChannelExists},
+		{"channel does not exist", types.ErrChannelNotExist, http.StatusNotFound, osnadmin.CodeChannelNotExist},
+		{"channel pending removal", types.ErrChannelPendingRemoval, http.StatusConflict, osnadmin.CodeChannelPendingRemoval},
+		{"some other error", errors.New("oops"), http.StatusBadRequest, osnadmin.CodeUnknown},
+	}
+
+	for _, tc := range knownFailures {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeManager.RemoveChannelReturns(tc.removeErr)
+			resp, err := osnadmin.Remove(server.URL, "my-channel", nil, tls.Certificate{}, osnadmin.ClientOptions{})
+			require.NoError(t, err)
+
+			err = osnadmin.CheckResponse(resp)
+			var osnErr *osnadmin.OSNError
+			require.True(t, errors.As(err, &osnErr))
+			require.Equal(t, tc.expectedStatusCode, osnErr.StatusCode)
+			require.Equal(t, tc.expectedCode, osnErr.Code)
+			require.Equal(t, "cannot remove: "+tc.removeErr.Error(), osnErr.Message)
+		})
+	}
+
+	t.Run("non-JSON body", func(t *testing.T) {
+		resp, err := osnadmin.ListSingleChannel(server.URL+"/oops", "my-channel", nil, tls.Certificate{}, osnadmin.ClientOptions{})
+		require.NoError(t, err)
+
+		err = osnadmin.CheckResponse(resp)
+		var osnErr *osnadmin.OSNError
+		require.True(t, errors.As(err, &osnErr))
+		require.Equal(t, http.StatusNotFound, osnErr.StatusCode)
+		require.Equal(t, osnadmin.CodeUnknown, osnErr.Code)
+		require.Equal(t, "404 page not found", osnErr.Message)
+		require.EqualError(t, err, "OSN responded with status 404: 404 page not found")
+	})
+}
@@ -0,0 +1,93 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package osnadmin
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Subscribes to the Server-Sent Events that report the progress of a channel
+// an OSN is onboarding. The stream is bounded by the context rather than by
+// the Timeout of the client options, as it lasts as long as the onboarding.
+func ChannelEvents(ctx context.Context, osnURL, channelID string, caCertPool *x509.CertPool, tlsClientCert tls.Certificate, clientOpts ClientOptions) (*http.Response, error) {
+	url := channelURL(osnURL, channelID) + "/events"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	setUserAgent(req, clientOpts.UserAgent)
+
+	client := httpClient(caCertPool, tlsClientCert, clientOpts)
+	client.Timeout = 0
+	resp, err := client.Do(req)
+	return resp, withClockSkewHint(err, time.Now())
+}
+
+// Event is a Server-Sent Event.
+type Event struct {
+	// The name of the event, "message" when not set by the server.
+	Name string
+	// The data of the event, with the lines of multi-line data joined by "\n".
+	Data string
+}
+
+// EventReader reads the Server-Sent Events of a stream, as specified by the
+// HTML Living Standard. Comments, and the id and retry fields, are ignored.
+type EventReader struct {
+	scanner *bufio.Scanner
+}
+
+func NewEventReader(r io.Reader) *EventReader {
+	return &EventReader{scanner: bufio.NewScanner(r)}
+}
+
+// Next returns the next event of the stream, or io.EOF when the stream ends.
+// An event that is not terminated by a blank line is discarded.
+func (r *EventReader) Next() (Event, error) {
+	var (
+		name string
+		data []string
+	)
+	for r.scanner.Scan() {
+		line := r.scanner.Text()
+		if line == "" {
+			if data == nil {
+				// an event with no data is not dispatched
+				name = ""
+				continue
+			}
+			if name == "" {
+				name = "message"
+			}
+			return Event{Name: name, Data: strings.Join(data, "\n")}, nil
+		}
+
+		field, value := line, ""
+		if i := strings.Index(line, ":"); i >= 0 {
+			field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+		switch field {
+		case "event":
+			name = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+	if err := r.scanner.Err(); err != nil {
+		return Event{}, err
+	}
+	return Event{}, io.EOF
+}
@@ -0,0 +1,84 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package osnadmin_test
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/internal/osnadmin"
+	"github.com/hyperledger/fabric/orderer/common/channelparticipation"
+	"github.com/hyperledger/fabric/orderer/common/channelparticipation/mocks"
+	"github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventReader(t *testing.T) {
+	stream := ": a comment\n" +
+		"event: progress\n" +
+		"data: {\"height\":1}\n" +
+		"\n" +
+		"id: 2\n" +
+		"data:first line\n" +
+		"data: second line\n" +
+		"\n" +
+		"event: no-data\n" +
+		"\n" +
+		"event: error\n" +
+		"data: {\"error\":\"channel does not exist\"}\n" +
+		"\n" +
+		"event: unterminated\n" +
+		"data: lost\n"
+	reader := osnadmin.NewEventReader(strings.NewReader(stream))
+
+	var events []osnadmin.Event
+	for {
+		event, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		events = append(events, event)
+	}
+
+	require.Equal(t, []osnadmin.Event{
+		{Name: "progress", Data: `{"height":1}`},
+		{Name: "message", Data: "first line\nsecond line"},
+		{Name: "error", Data: `{"error":"channel does not exist"}`},
+	}, events)
+}
+
+func TestChannelEvents(t *testing.T) {
+	fakeManager := &mocks.ChannelManagement{}
+	fakeManager.ChannelInfoReturnsOnCall(0, types.ChannelInfo{Name: "my-channel", Status: types.StatusOnBoarding, Height: 1}, nil)
+	fakeManager.ChannelInfoReturnsOnCall(1, types.ChannelInfo{Name: "my-channel", Status: types.StatusActive, Height: 2}, nil)
+	h := channelparticipation.NewHTTPHandler(localconfig.ChannelParticipation{Enabled: true, EventsInterval: time.Millisecond}, fakeManager)
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	resp, err := osnadmin.ChannelEvents(context.Background(), server.URL, "my-channel", nil, tls.Certificate{}, osnadmin.ClientOptions{})
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	reader := osnadmin.NewEventReader(resp.Body)
+	for _, status := range []string{`"status":"onboarding"`, `"status":"active"`} {
+		event, err := reader.Next()
+		require.NoError(t, err)
+		require.Equal(t, "progress", event.Name)
+		require.Contains(t, event.Data, status)
+	}
+	_, err = reader.Next()
+	require.Equal(t, io.EOF, err)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package osnadmin
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/internal/pkg/identity"
+	"github.com/hyperledger/fabric/protoutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// FetchDialTimeout is the time allowed to connect to the deliver service of an orderer.
+var FetchDialTimeout = 10 * time.Second
+
+// FetchConfigBlock retrieves the latest config block of a channel from the
+// deliver service of an orderer. The deliver requests are signed by the
+// signer, which must satisfy the Readers policy of the channel.
+func FetchConfigBlock(ordererAddress, channelID string, signer identity.SignerSerializer, caCertPool *x509.CertPool, tlsClientCert tls.Certificate) (*cb.Block, error) {
+	dialOpts := []grpc.DialOption{grpc.WithBlock()}
+	var tlsCertHash []byte
+	if caCertPool != nil {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+			RootCAs:      caCertPool,
+			Certificates: []tls.Certificate{tlsClientCert},
+		})))
+		if len(tlsClientCert.Certificate) != 0 {
+			tlsCertHash = util.ComputeSHA256(tlsClientCert.Certificate[0])
+		}
+	} else {
+		dialOpts = append(dialOpts, grpc.WithInsecure())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), FetchDialTimeout)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, ordererAddress, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to %s: %s", ordererAddress, err)
+	}
+	defer conn.Close()
+
+	deliverClient, err := ab.NewAtomicBroadcastClient(conn).Deliver(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("creating deliver client: %s", err)
+	}
+	defer deliverClient.CloseSend()
+
+	fetch := func(position *ab.SeekPosition) (*cb.Block, error) {
+		env, err := protoutil.CreateSignedEnvelopeWithTLSBinding(
+			cb.HeaderType_DELIVER_SEEK_INFO,
+			channelID,
+			signer,
+			&ab.SeekInfo{
+				Start:    position,
+				Stop:     position,
+				Behavior: ab.SeekInfo_BLOCK_UNTIL_READY,
+			},
+			int32(0),
+			uint64(0),
+			tlsCertHash,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("signing seek request: %s", err)
+		}
+		if err := deliverClient.Send(env); err != nil {
+			return nil, fmt.Errorf("sending seek request: %s", err)
+		}
+		return readBlock(deliverClient)
+	}
+
+	newest, err := fetch(&ab.SeekPosition{Type: &ab.SeekPosition_Newest{Newest: &ab.SeekNewest{}}})
+	if err != nil {
+		return nil, fmt.Errorf("fetching newest block: %s", err)
+	}
+	lastConfigIndex, err := protoutil.GetLastConfigIndexFromBlock(newest)
+	if err != nil {
+		return nil, fmt.Errorf("reading last config index: %s", err)
+	}
+	if lastConfigIndex == newest.GetHeader().GetNumber() {
+		return newest, nil
+	}
+
+	configBlock, err := fetch(&ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: lastConfigIndex}}})
+	if err != nil {
+		return nil, fmt.Errorf("fetching config block %d: %s", lastConfigIndex, err)
+	}
+
+	return configBlock, nil
+}
+
+// readBlock reads a block followed by the status that ends the delivery.
+func readBlock(deliverClient ab.AtomicBroadcast_DeliverClient) (*cb.Block, error) {
+	msg, err := deliverClient.Recv()
+	if err != nil {
+		return nil, fmt.Errorf("receiving block: %s", err)
+	}
+	block := msg.GetBlock()
+	if block == nil {
+		return nil, fmt.Errorf("expected a block, got status: %s", msg.GetStatus())
+	}
+
+	msg, err = deliverClient.Recv()
+	if err != nil {
+		return nil, fmt.Errorf("receiving status: %s", err)
+	}
+	if status := msg.GetStatus(); status != cb.Status_SUCCESS {
+		return nil, fmt.Errorf("expected status SUCCESS, got: %s", status)
+	}
+
+	return block, nil
+}
@@ -9,26 +9,125 @@ package osnadmin
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"strings"
+	"time"
 )
 
-func httpClient(caCertPool *x509.CertPool, tlsClientCert tls.Certificate) *http.Client {
+// ClientOptions carries the settings of the requests to the admin endpoint of
+// an OSN, other than its TLS materials. The zero value means no timeout, that
+// any trusted server certificate is accepted, and the default User-Agent.
+type ClientOptions struct {
+	// The time allowed for a request, including reading the response body.
+	// Zero means no timeout.
+	Timeout time.Duration
+	// A subject alternative name, a DNS name, IP address or URI, that the TLS
+	// certificate of the OSN must contain in addition to being trusted. Empty
+	// means that any trusted certificate is accepted.
+	ExpectedSAN string
+	// The User-Agent header sent with every request, e.g. so that a web
+	// application firewall in front of the OSN can tell osnadmin traffic
+	// apart. Empty means the default of net/http.
+	UserAgent string
+}
+
+func httpClient(caCertPool *x509.CertPool, tlsClientCert tls.Certificate, clientOpts ClientOptions) *http.Client {
+	tlsConfig := &tls.Config{
+		RootCAs:      caCertPool,
+		Certificates: []tls.Certificate{tlsClientCert},
+	}
+	if clientOpts.ExpectedSAN != "" {
+		tlsConfig.VerifyConnection = verifySAN(clientOpts.ExpectedSAN)
+	}
 	return &http.Client{
+		Timeout: clientOpts.Timeout,
 		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				RootCAs:      caCertPool,
-				Certificates: []tls.Certificate{tlsClientCert},
-			},
+			TLSClientConfig: tlsConfig,
 		},
 	}
 }
 
-func httpDo(req *http.Request, caCertPool *x509.CertPool, tlsClientCert tls.Certificate) (*http.Response, error) {
-	client := httpClient(caCertPool, tlsClientCert)
-	return client.Do(req)
+// verifySAN returns a check rejecting a connection whose server certificate
+// does not contain the subject alternative name. Unlike host name
+// verification, the name must match exactly, wildcards are not expanded.
+func verifySAN(san string) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return fmt.Errorf("the OSN presented no TLS certificate")
+		}
+		if !hasSAN(cs.PeerCertificates[0], san) {
+			return fmt.Errorf("the TLS certificate of the OSN does not contain the subject alternative name %s", san)
+		}
+		return nil
+	}
 }
 
-func httpGet(url string, caCertPool *x509.CertPool, tlsClientCert tls.Certificate) (*http.Response, error) {
-	client := httpClient(caCertPool, tlsClientCert)
-	return client.Get(url)
+func hasSAN(cert *x509.Certificate, san string) bool {
+	if ip := net.ParseIP(san); ip != nil {
+		for _, certIP := range cert.IPAddresses {
+			if certIP.Equal(ip) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, name := range cert.DNSNames {
+		if strings.EqualFold(name, san) {
+			return true
+		}
+	}
+	for _, uri := range cert.URIs {
+		if uri.String() == san {
+			return true
+		}
+	}
+	return false
+}
+
+func httpDo(req *http.Request, caCertPool *x509.CertPool, tlsClientCert tls.Certificate, clientOpts ClientOptions) (*http.Response, error) {
+	setUserAgent(req, clientOpts.UserAgent)
+	client := httpClient(caCertPool, tlsClientCert, clientOpts)
+	resp, err := client.Do(req)
+	return resp, withClockSkewHint(err, time.Now())
+}
+
+func httpGet(url string, caCertPool *x509.CertPool, tlsClientCert tls.Certificate, clientOpts ClientOptions) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return httpDo(req, caCertPool, tlsClientCert, clientOpts)
+}
+
+// setUserAgent sets the User-Agent header of a request, unless the user agent
+// is empty or the request already sets one.
+func setUserAgent(req *http.Request, userAgent string) {
+	if userAgent != "" && req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+}
+
+// withClockSkewHint adds a hint about the clock of this host to an error
+// caused by a server certificate that is not valid at the local time, as
+// such an error is more often due to clock skew than to a bad certificate.
+func withClockSkewHint(err error, now time.Time) error {
+	var certErr x509.CertificateInvalidError
+	if !errors.As(err, &certErr) || certErr.Reason != x509.Expired || certErr.Cert == nil {
+		return err
+	}
+
+	cert := certErr.Cert
+	switch {
+	case now.Before(cert.NotBefore):
+		return fmt.Errorf("%w\nHint: the local time %s is %s before the certificate of %q becomes valid at %s, check whether the clock of this host is behind",
+			err, now.UTC().Format(time.RFC3339), cert.NotBefore.Sub(now).Round(time.Second), cert.Subject.CommonName, cert.NotBefore.UTC().Format(time.RFC3339))
+	case now.After(cert.NotAfter):
+		return fmt.Errorf("%w\nHint: the local time %s is %s after the certificate of %q expired at %s, the certificate must be renewed unless the clock of this host is ahead",
+			err, now.UTC().Format(time.RFC3339), now.Sub(cert.NotAfter).Round(time.Second), cert.Subject.CommonName, cert.NotAfter.UTC().Format(time.RFC3339))
+	default:
+		return err
+	}
 }
@@ -0,0 +1,51 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package osnadmin_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/internal/osnadmin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserAgent(t *testing.T) {
+	var userAgents []string
+	server := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		userAgents = append(userAgents, req.Header.Get("User-Agent"))
+		resp.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	clientOpts := osnadmin.ClientOptions{UserAgent: "osnadmin/test"}
+	_, err := osnadmin.ListAllChannels(server.URL, nil, tls.Certificate{}, clientOpts)
+	require.NoError(t, err)
+	_, err = osnadmin.Remove(server.URL, "my-channel", nil, tls.Certificate{}, clientOpts)
+	require.NoError(t, err)
+	require.Equal(t, []string{"osnadmin/test", "osnadmin/test"}, userAgents)
+
+	_, err = osnadmin.ListAllChannels(server.URL, nil, tls.Certificate{}, osnadmin.ClientOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "Go-http-client/1.1", userAgents[2])
+}
+
+func TestClientOptionsTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	_, err := osnadmin.ListAllChannels(server.URL, nil, tls.Certificate{}, osnadmin.ClientOptions{Timeout: 50 * time.Millisecond})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Client.Timeout exceeded")
+}
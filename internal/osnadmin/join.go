@@ -8,28 +8,69 @@ package osnadmin
 
 import (
 	"bytes"
+	"compress/gzip"
 	"crypto/tls"
 	"crypto/x509"
-	"fmt"
+	"io"
 	"mime/multipart"
 	"net/http"
 )
 
+// The multipart form field name of the config block expected by the OSN.
+const DefaultJoinFieldName = "config-block"
+
 // Joins an OSN to a new or existing channel.
-func Join(osnURL string, blockBytes []byte, caCertPool *x509.CertPool, tlsClientCert tls.Certificate) (*http.Response, error) {
-	url := fmt.Sprintf("%s/participation/v1/channels", osnURL)
-	req, err := createJoinRequest(url, blockBytes)
+func Join(osnURL string, blockBytes []byte, caCertPool *x509.CertPool, tlsClientCert tls.Certificate, clientOpts ClientOptions) (*http.Response, error) {
+	return JoinWithFieldName(osnURL, DefaultJoinFieldName, blockBytes, caCertPool, tlsClientCert, clientOpts)
+}
+
+// Joins an OSN to a new or existing channel, sending the config block in the
+// multipart form field with the given name.
+func JoinWithFieldName(osnURL, fieldName string, blockBytes []byte, caCertPool *x509.CertPool, tlsClientCert tls.Certificate, clientOpts ClientOptions) (*http.Response, error) {
+	return JoinWithOptions(osnURL, blockBytes, JoinOptions{FieldName: fieldName}, caCertPool, tlsClientCert, clientOpts)
+}
+
+// JoinOptions control how the config block is sent by JoinWithOptions.
+type JoinOptions struct {
+	// The multipart form field name of the config block, DefaultJoinFieldName if empty.
+	FieldName string
+	// Whether the multipart body is gzip compressed and sent with Content-Encoding: gzip.
+	Compress bool
+	// Whether the OSN only validates the join, with the dryRun query parameter, without creating the channel.
+	DryRun bool
+}
+
+// Joins an OSN to a new or existing channel, sending the config block as
+// set by the options.
+func JoinWithOptions(osnURL string, blockBytes []byte, opts JoinOptions, caCertPool *x509.CertPool, tlsClientCert tls.Certificate, clientOpts ClientOptions) (*http.Response, error) {
+	url := channelsURL(osnURL)
+	if opts.DryRun {
+		url += "?dryRun=true"
+	}
+	req, err := createJoinRequest(url, blockBytes, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	return httpDo(req, caCertPool, tlsClientCert)
+	return httpDo(req, caCertPool, tlsClientCert, clientOpts)
 }
 
-func createJoinRequest(url string, blockBytes []byte) (*http.Request, error) {
+func createJoinRequest(url string, blockBytes []byte, opts JoinOptions) (*http.Request, error) {
+	fieldName := opts.FieldName
+	if fieldName == "" {
+		fieldName = DefaultJoinFieldName
+	}
+
 	joinBody := new(bytes.Buffer)
-	writer := multipart.NewWriter(joinBody)
-	part, err := writer.CreateFormFile("config-block", "config.block")
+	var body io.Writer = joinBody
+	var gzipWriter *gzip.Writer
+	if opts.Compress {
+		gzipWriter = gzip.NewWriter(joinBody)
+		body = gzipWriter
+	}
+
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(fieldName, "config.block")
 	if err != nil {
 		return nil, err
 	}
@@ -38,12 +79,20 @@ func createJoinRequest(url string, blockBytes []byte) (*http.Request, error) {
 	if err != nil {
 		return nil, err
 	}
+	if gzipWriter != nil {
+		if err := gzipWriter.Close(); err != nil {
+			return nil, err
+		}
+	}
 
 	req, err := http.NewRequest(http.MethodPost, url, joinBody)
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if opts.Compress {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
 
 	return req, nil
 }
@@ -0,0 +1,50 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package osnadmin_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/config/configtest"
+	"github.com/hyperledger/fabric/internal/configtxgen/encoder"
+	"github.com/hyperledger/fabric/internal/configtxgen/genesisconfig"
+	"github.com/hyperledger/fabric/internal/osnadmin"
+	"github.com/hyperledger/fabric/orderer/common/channelparticipation"
+	"github.com/hyperledger/fabric/orderer/common/channelparticipation/mocks"
+	"github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoinFromMemory(t *testing.T) {
+	fakeManager := &mocks.ChannelManagement{}
+	fakeManager.JoinChannelReturns(types.ChannelInfo{Name: "my-channel", ConsensusRelation: "consenter", Status: "active", Height: 1}, nil)
+	h := channelparticipation.NewHTTPHandler(localconfig.ChannelParticipation{Enabled: true, MaxRequestBodySize: 1024 * 1024}, fakeManager)
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	// the block is generated and marshaled in memory, without going through the filesystem
+	conf := genesisconfig.Load(genesisconfig.SampleAppChannelInsecureSoloProfile, configtest.GetDevConfigDir())
+	block := encoder.New(conf).GenesisBlockForChannel("my-channel")
+	blockBytes := protoutil.MarshalOrPanic(block)
+
+	resp, err := osnadmin.Join(server.URL, blockBytes, nil, tls.Certificate{}, osnadmin.ClientOptions{})
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	require.Equal(t, 1, fakeManager.JoinChannelCallCount())
+	channelID, joinedBlock, isAppChannel := fakeManager.JoinChannelArgsForCall(0)
+	require.Equal(t, "my-channel", channelID)
+	require.True(t, proto.Equal(block, joinedBlock))
+	require.True(t, isAppChannel)
+}
@@ -9,20 +9,19 @@ package osnadmin
 import (
 	"crypto/tls"
 	"crypto/x509"
-	"fmt"
 	"net/http"
 )
 
 // Lists the channels an OSN is a member of.
-func ListAllChannels(osnURL string, caCertPool *x509.CertPool, tlsClientCert tls.Certificate) (*http.Response, error) {
-	url := fmt.Sprintf("%s/participation/v1/channels", osnURL)
+func ListAllChannels(osnURL string, caCertPool *x509.CertPool, tlsClientCert tls.Certificate, clientOpts ClientOptions) (*http.Response, error) {
+	url := channelsURL(osnURL)
 
-	return httpGet(url, caCertPool, tlsClientCert)
+	return httpGet(url, caCertPool, tlsClientCert, clientOpts)
 }
 
 // Lists a single channel an OSN is a member of.
-func ListSingleChannel(osnURL, channelID string, caCertPool *x509.CertPool, tlsClientCert tls.Certificate) (*http.Response, error) {
-	url := fmt.Sprintf("%s/participation/v1/channels/%s", osnURL, channelID)
+func ListSingleChannel(osnURL, channelID string, caCertPool *x509.CertPool, tlsClientCert tls.Certificate, clientOpts ClientOptions) (*http.Response, error) {
+	url := channelURL(osnURL, channelID)
 
-	return httpGet(url, caCertPool, tlsClientCert)
+	return httpGet(url, caCertPool, tlsClientCert, clientOpts)
 }
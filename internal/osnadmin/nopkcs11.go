@@ -0,0 +1,20 @@
+// +build !pkcs11
+
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package osnadmin
+
+import (
+	"crypto/tls"
+	"errors"
+)
+
+// PKCS11ClientCertificate is not supported when built without the pkcs11
+// build tag.
+func PKCS11ClientCertificate(certFile string, opts PKCS11Opts) (tls.Certificate, error) {
+	return tls.Certificate{}, errors.New("PKCS#11 support is not enabled in this build of osnadmin, rebuild with the pkcs11 build tag")
+}
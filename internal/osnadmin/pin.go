@@ -0,0 +1,58 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package osnadmin
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// PinnedCertPool returns a cert pool that trusts only the TLS certificate
+// presented by an OSN admin endpoint, provided that its SHA-256 fingerprint
+// matches the one pinned in the pin file. When the pin file does not exist,
+// the certificate is trusted on first use: its fingerprint is recorded in the
+// pin file, and pinned reports true.
+func PinnedCertPool(ordererAddress, pinFile string, tlsClientCert tls.Certificate) (pool *x509.CertPool, pinned bool, err error) {
+	certs, err := ServerCertificates(ordererAddress, tlsClientCert)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(certs) == 0 {
+		return nil, false, fmt.Errorf("the OSN presented no TLS certificate")
+	}
+	fingerprint := CertificateFingerprint(certs[0])
+
+	pin, err := ioutil.ReadFile(pinFile)
+	switch {
+	case os.IsNotExist(err):
+		if err := ioutil.WriteFile(pinFile, []byte(fingerprint+"\n"), 0o600); err != nil {
+			return nil, false, fmt.Errorf("recording pin: %s", err)
+		}
+		pinned = true
+	case err != nil:
+		return nil, false, fmt.Errorf("reading pin: %s", err)
+	case !strings.EqualFold(strings.TrimSpace(string(pin)), fingerprint):
+		return nil, false, fmt.Errorf("the TLS certificate of the OSN does not match the pin in %s: expected SHA-256 fingerprint %s, got %s", pinFile, strings.TrimSpace(string(pin)), fingerprint)
+	}
+
+	pool = x509.NewCertPool()
+	pool.AddCert(certs[0])
+	return pool, pinned, nil
+}
+
+// CertificateFingerprint returns the hex encoded SHA-256 digest of the DER
+// encoding of the certificate.
+func CertificateFingerprint(cert *x509.Certificate) string {
+	digest := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(digest[:])
+}
@@ -0,0 +1,83 @@
+// +build pkcs11
+
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package osnadmin
+
+import (
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/pkcs11"
+	"github.com/hyperledger/fabric/bccsp/signer"
+	"github.com/hyperledger/fabric/bccsp/sw"
+)
+
+// PKCS11ClientCertificate loads the PEM-encoded client certificate from
+// certFile and pairs it with the private key held by the PKCS#11 token that
+// matches the public key of the certificate. The private key never leaves
+// the token; TLS handshakes are signed by the token.
+func PKCS11ClientCertificate(certFile string, opts PKCS11Opts) (tls.Certificate, error) {
+	certPEM, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("reading client certificate: %s", err)
+	}
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return tls.Certificate{}, fmt.Errorf("no PEM data found in client certificate %s", certFile)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("parsing client certificate: %s", err)
+	}
+	pubKey, ok := cert.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return tls.Certificate{}, fmt.Errorf("client certificate public key must be ECDSA, got %T", cert.PublicKey)
+	}
+
+	csp, err := pkcs11.New(
+		pkcs11.PKCS11Opts{
+			Security: pubKey.Curve.Params().BitSize,
+			Hash:     "SHA2",
+			Library:  opts.Library,
+			Pin:      opts.Pin,
+			Label:    opts.Label,
+		},
+		sw.NewDummyKeyStore(),
+	)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("initializing PKCS#11 provider: %s", err)
+	}
+
+	// the SKI of the public key identifies the key pair on the token
+	pubBCCSPKey, err := csp.KeyImport(pubKey, &bccsp.ECDSAGoPublicKeyImportOpts{Temporary: true})
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("importing client certificate public key: %s", err)
+	}
+	privKey, err := csp.GetKey(pubBCCSPKey.SKI())
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("finding client private key on PKCS#11 token: %s", err)
+	}
+	if !privKey.Private() {
+		return tls.Certificate{}, fmt.Errorf("PKCS#11 token does not hold the private key of the client certificate")
+	}
+	tokenSigner, err := signer.New(csp, privKey)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("creating PKCS#11 signer: %s", err)
+	}
+
+	return tls.Certificate{
+		Certificate: [][]byte{cert.Raw},
+		PrivateKey:  tokenSigner,
+		Leaf:        cert,
+	}, nil
+}
@@ -0,0 +1,143 @@
+// +build pkcs11
+
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package osnadmin_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/pkcs11"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/internal/osnadmin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPKCS11ClientCertificate(t *testing.T) {
+	lib, pin, label := pkcs11.FindPKCS11Lib()
+	if lib == "" {
+		t.Skip("PKCS#11 library not found")
+	}
+
+	// generate the client key pair on the token
+	csp, err := pkcs11.New(pkcs11.PKCS11Opts{
+		Security: 256,
+		Hash:     "SHA2",
+		Library:  lib,
+		Pin:      pin,
+		Label:    label,
+	}, sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	key, err := csp.KeyGen(&bccsp.ECDSAP256KeyGenOpts{Temporary: false})
+	require.NoError(t, err)
+	pubKey, err := key.PublicKey()
+	require.NoError(t, err)
+	pubKeyBytes, err := pubKey.Bytes()
+	require.NoError(t, err)
+	clientPubKey, err := x509.ParsePKIXPublicKey(pubKeyBytes)
+	require.NoError(t, err)
+
+	// issue a client certificate for the token key pair
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "pkcs11-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+	clientTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "pkcs11-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientDER, err := x509.CreateCertificate(rand.Reader, clientTemplate, caCert, clientPubKey, caKey)
+	require.NoError(t, err)
+
+	tempDir, err := ioutil.TempDir("", "osnadmin-pkcs11")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	clientCertFile := filepath.Join(tempDir, "client-cert.pem")
+	err = ioutil.WriteFile(clientCertFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: clientDER}), 0o640)
+	require.NoError(t, err)
+
+	// the server requires a client certificate issued by the CA
+	var peerCerts []*x509.Certificate
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		peerCerts = r.TLS.PeerCertificates
+		w.WriteHeader(http.StatusOK)
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(caCert)
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	server.StartTLS()
+	defer server.Close()
+	serverCAs := x509.NewCertPool()
+	serverCAs.AddCert(server.Certificate())
+
+	tlsClientCert, err := osnadmin.PKCS11ClientCertificate(clientCertFile, osnadmin.PKCS11Opts{
+		Library: lib,
+		Pin:     pin,
+		Label:   label,
+	})
+	require.NoError(t, err)
+	_, isECDSAKey := tlsClientCert.PrivateKey.(*ecdsa.PrivateKey)
+	require.False(t, isECDSAKey, "private key must not be extracted from the token")
+
+	// the handshake succeeds only if the token signs with the private key of the certificate
+	resp, err := osnadmin.ListAllChannels(server.URL, serverCAs, tlsClientCert)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, peerCerts, 1)
+	require.Equal(t, clientDER, peerCerts[0].Raw)
+
+	t.Run("when the certificate does not match a key on the token", func(t *testing.T) {
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		otherDER, err := x509.CreateCertificate(rand.Reader, clientTemplate, caCert, &otherKey.PublicKey, caKey)
+		require.NoError(t, err)
+		otherCertFile := filepath.Join(tempDir, "other-cert.pem")
+		err = ioutil.WriteFile(otherCertFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: otherDER}), 0o640)
+		require.NoError(t, err)
+
+		_, err = osnadmin.PKCS11ClientCertificate(otherCertFile, osnadmin.PKCS11Opts{
+			Library: lib,
+			Pin:     pin,
+			Label:   label,
+		})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,17 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package osnadmin
+
+// PKCS11Opts identifies the PKCS#11 token that holds the client private key.
+type PKCS11Opts struct {
+	// The path to the PKCS#11 library.
+	Library string
+	// The user PIN of the token.
+	Pin string
+	// The label of the token.
+	Label string
+}
@@ -0,0 +1,34 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package osnadmin
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// Probe checks that an OSN admin endpoint is alive by listing its channels.
+// Besides a 2xx status, a 401 is accepted, as the OSN must be up, and the TLS
+// handshake must have succeeded, for it to respond at all.
+func Probe(osnURL string, caCertPool *x509.CertPool, tlsClientCert tls.Certificate, clientOpts ClientOptions) error {
+	resp, err := ListAllChannels(osnURL, caCertPool, tlsClientCert, clientOpts)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// drain the body so that the connection is closed cleanly
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode/100 == 2 || resp.StatusCode == http.StatusUnauthorized {
+		return nil
+	}
+	return fmt.Errorf("unexpected status: %s", resp.Status)
+}
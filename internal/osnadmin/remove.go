@@ -9,18 +9,39 @@ package osnadmin
 import (
 	"crypto/tls"
 	"crypto/x509"
-	"fmt"
 	"net/http"
 )
 
 // Removes an OSN from an existing channel.
-func Remove(osnURL, channelID string, caCertPool *x509.CertPool, tlsClientCert tls.Certificate) (*http.Response, error) {
-	url := fmt.Sprintf("%s/participation/v1/channels/%s", osnURL, channelID)
+func Remove(osnURL, channelID string, caCertPool *x509.CertPool, tlsClientCert tls.Certificate, clientOpts ClientOptions) (*http.Response, error) {
+	url := channelURL(osnURL, channelID)
 
 	req, err := http.NewRequest(http.MethodDelete, url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	return httpDo(req, caCertPool, tlsClientCert)
+	return httpDo(req, caCertPool, tlsClientCert, clientOpts)
+}
+
+// RemoveIfExists removes an OSN from a channel, treating a channel that does
+// not exist as already removed, so that removals can safely be repeated. It
+// reports whether the channel was removed by this call, and returns an
+// *OSNError when the OSN responds with any other non-2xx status code.
+func RemoveIfExists(osnURL, channelID string, caCertPool *x509.CertPool, tlsClientCert tls.Certificate, clientOpts ClientOptions) (bool, error) {
+	resp, err := Remove(osnURL, channelID, caCertPool, tlsClientCert, clientOpts)
+	if err != nil {
+		return false, err
+	}
+
+	err = CheckResponse(resp)
+	if osnErr, ok := err.(*OSNError); ok && osnErr.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+
+	return true, nil
 }
@@ -0,0 +1,59 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package osnadmin_test
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/fabric/internal/osnadmin"
+	"github.com/hyperledger/fabric/orderer/common/channelparticipation"
+	"github.com/hyperledger/fabric/orderer/common/channelparticipation/mocks"
+	"github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveIfExists(t *testing.T) {
+	fakeManager := &mocks.ChannelManagement{}
+	h := channelparticipation.NewHTTPHandler(localconfig.ChannelParticipation{Enabled: true}, fakeManager)
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	t.Run("present", func(t *testing.T) {
+		fakeManager.RemoveChannelReturns(nil)
+		removed, err := osnadmin.RemoveIfExists(server.URL, "my-channel", nil, tls.Certificate{}, osnadmin.ClientOptions{})
+		require.NoError(t, err)
+		require.True(t, removed)
+	})
+
+	t.Run("already removed", func(t *testing.T) {
+		fakeManager.RemoveChannelReturns(types.ErrChannelNotExist)
+		removed, err := osnadmin.RemoveIfExists(server.URL, "my-channel", nil, tls.Certificate{}, osnadmin.ClientOptions{})
+		require.NoError(t, err)
+		require.False(t, removed)
+	})
+
+	t.Run("other failure", func(t *testing.T) {
+		fakeManager.RemoveChannelReturns(types.ErrChannelPendingRemoval)
+		removed, err := osnadmin.RemoveIfExists(server.URL, "my-channel", nil, tls.Certificate{}, osnadmin.ClientOptions{})
+		require.False(t, removed)
+		var osnErr *osnadmin.OSNError
+		require.True(t, errors.As(err, &osnErr))
+		require.Equal(t, http.StatusConflict, osnErr.StatusCode)
+		require.Equal(t, osnadmin.CodeChannelPendingRemoval, osnErr.Code)
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		removed, err := osnadmin.RemoveIfExists("http://127.0.0.1:0", "my-channel", nil, tls.Certificate{}, osnadmin.ClientOptions{})
+		require.Error(t, err)
+		require.False(t, removed)
+	})
+}
@@ -0,0 +1,129 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package osnadmin
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	RetryOnConnRefused = "connrefused"
+	RetryOnConnReset   = "connreset"
+	RetryOnTimeout     = "timeout"
+
+	// DefaultRetryOn retries transient network errors only.
+	DefaultRetryOn = RetryOnConnRefused + "," + RetryOnConnReset + "," + RetryOnTimeout
+)
+
+// RetryPolicy determines whether, how often, and how many times a request
+// to an OSN is retried.
+type RetryPolicy struct {
+	// The maximum number of retries after the initial attempt.
+	Retries int
+	// The time to wait between attempts.
+	Interval time.Duration
+	// The HTTP status codes that are retried.
+	StatusCodes map[int]bool
+	// The named network errors that are retried.
+	Errors map[string]bool
+	// Report is called once a request is done, with the number of attempts
+	// made and the reason each retried attempt was retried, e.g. "503" or
+	// "connrefused"; nil means the attempts are not reported.
+	Report func(attempts int, reasons []string)
+}
+
+// NewRetryPolicy creates a retry policy from a comma separated list of HTTP
+// status codes and named network errors (connrefused, connreset, timeout).
+func NewRetryPolicy(retries int, interval time.Duration, retryOn string) (RetryPolicy, error) {
+	policy := RetryPolicy{
+		Retries:     retries,
+		Interval:    interval,
+		StatusCodes: map[int]bool{},
+		Errors:      map[string]bool{},
+	}
+
+	for _, token := range strings.Split(retryOn, ",") {
+		token = strings.TrimSpace(token)
+		switch token {
+		case "":
+		case RetryOnConnRefused, RetryOnConnReset, RetryOnTimeout:
+			policy.Errors[token] = true
+		default:
+			code, err := strconv.Atoi(token)
+			if err != nil || code < 100 || code > 599 {
+				return RetryPolicy{}, fmt.Errorf("unknown retry condition: %s", token)
+			}
+			policy.StatusCodes[code] = true
+		}
+	}
+
+	return policy, nil
+}
+
+// NonIdempotent returns the policy for requests that are not idempotent,
+// such as joins. Of the network errors, only a refused connection is
+// retried, as the request never reached the OSN; after a reset or a timeout
+// the OSN may have processed it. The status codes are retried as listed, as
+// the OSN responded to the request.
+func (p RetryPolicy) NonIdempotent() RetryPolicy {
+	errs := map[string]bool{}
+	if p.Errors[RetryOnConnRefused] {
+		errs[RetryOnConnRefused] = true
+	}
+	p.Errors = errs
+	return p
+}
+
+// Retry invokes the request function until it succeeds with a status code
+// that is not retried, fails with an error that is not retried, or the
+// retries are exhausted. The response or error of the last attempt is
+// returned.
+func Retry(policy RetryPolicy, request func() (*http.Response, error)) (*http.Response, error) {
+	var reasons []string
+	for attempt := 0; ; attempt++ {
+		resp, err := request()
+		reason, retry := policy.shouldRetry(resp, err)
+		if attempt >= policy.Retries || !retry {
+			if policy.Report != nil {
+				policy.Report(attempt+1, reasons)
+			}
+			return resp, err
+		}
+		reasons = append(reasons, reason)
+		if resp != nil {
+			resp.Body.Close()
+		}
+		time.Sleep(policy.Interval)
+	}
+}
+
+// shouldRetry returns the retry condition of --retry-on that the outcome of
+// an attempt meets, and whether it is retried.
+func (p RetryPolicy) shouldRetry(resp *http.Response, err error) (string, bool) {
+	if err != nil {
+		var netErr net.Error
+		switch {
+		case errors.Is(err, syscall.ECONNREFUSED):
+			return RetryOnConnRefused, p.Errors[RetryOnConnRefused]
+		case errors.Is(err, syscall.ECONNRESET):
+			return RetryOnConnReset, p.Errors[RetryOnConnReset]
+		case errors.As(err, &netErr) && netErr.Timeout():
+			return RetryOnTimeout, p.Errors[RetryOnTimeout]
+		default:
+			return "", false
+		}
+	}
+
+	return strconv.Itoa(resp.StatusCode), p.StatusCodes[resp.StatusCode]
+}
@@ -0,0 +1,36 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package osnadmin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// channelsPath is the path of the channel participation API, relative to the
+// base URL of the admin endpoint of an OSN.
+const channelsPath = "/participation/v1/channels"
+
+// OSNURL returns the base URL of the admin endpoint of an OSN, that the paths
+// of the channel participation API are appended to. The path prefix is the
+// path a gateway exposes the admin endpoint under, e.g. /orderer1, and may be
+// empty; leading and trailing slashes are optional.
+func OSNURL(scheme, ordererAddress, pathPrefix string) string {
+	prefix := strings.Trim(pathPrefix, "/")
+	if prefix == "" {
+		return fmt.Sprintf("%s://%s", scheme, ordererAddress)
+	}
+	return fmt.Sprintf("%s://%s/%s", scheme, ordererAddress, prefix)
+}
+
+func channelsURL(osnURL string) string {
+	return osnURL + channelsPath
+}
+
+func channelURL(osnURL, channelID string) string {
+	return fmt.Sprintf("%s%s/%s", osnURL, channelsPath, channelID)
+}
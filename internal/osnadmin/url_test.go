@@ -0,0 +1,94 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package osnadmin_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/fabric/internal/osnadmin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOSNURL(t *testing.T) {
+	tests := []struct {
+		pathPrefix  string
+		expectedURL string
+	}{
+		{pathPrefix: "", expectedURL: "https://orderer.example.com:7053"},
+		{pathPrefix: "/", expectedURL: "https://orderer.example.com:7053"},
+		{pathPrefix: "/orderer1", expectedURL: "https://orderer.example.com:7053/orderer1"},
+		{pathPrefix: "orderer1/", expectedURL: "https://orderer.example.com:7053/orderer1"},
+		{pathPrefix: "/gateway/orderer1/", expectedURL: "https://orderer.example.com:7053/gateway/orderer1"},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.expectedURL, osnadmin.OSNURL("https", "orderer.example.com:7053", tt.pathPrefix), "path prefix %q", tt.pathPrefix)
+	}
+}
+
+func TestPathPrefix(t *testing.T) {
+	var method, path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+	}))
+	defer server.Close()
+
+	osnURL := osnadmin.OSNURL("http", server.Listener.Addr().String(), "/orderer1")
+
+	tests := []struct {
+		name           string
+		request        func() (*http.Response, error)
+		expectedMethod string
+		expectedPath   string
+	}{
+		{
+			name: "join",
+			request: func() (*http.Response, error) {
+				return osnadmin.Join(osnURL, []byte("block"), nil, tls.Certificate{}, osnadmin.ClientOptions{})
+			},
+			expectedMethod: http.MethodPost,
+			expectedPath:   "/orderer1/participation/v1/channels",
+		},
+		{
+			name: "list all",
+			request: func() (*http.Response, error) {
+				return osnadmin.ListAllChannels(osnURL, nil, tls.Certificate{}, osnadmin.ClientOptions{})
+			},
+			expectedMethod: http.MethodGet,
+			expectedPath:   "/orderer1/participation/v1/channels",
+		},
+		{
+			name: "list single",
+			request: func() (*http.Response, error) {
+				return osnadmin.ListSingleChannel(osnURL, "my-channel", nil, tls.Certificate{}, osnadmin.ClientOptions{})
+			},
+			expectedMethod: http.MethodGet,
+			expectedPath:   "/orderer1/participation/v1/channels/my-channel",
+		},
+		{
+			name: "remove",
+			request: func() (*http.Response, error) {
+				return osnadmin.Remove(osnURL, "my-channel", nil, tls.Certificate{}, osnadmin.ClientOptions{})
+			},
+			expectedMethod: http.MethodDelete,
+			expectedPath:   "/orderer1/participation/v1/channels/my-channel",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tt.request()
+			require.NoError(t, err)
+			resp.Body.Close()
+			require.Equal(t, tt.expectedMethod, method)
+			require.Equal(t, tt.expectedPath, path)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channelparticipation
+
+import "sync"
+
+// channelLocks serializes the operations on each channel, e.g. a join and a remove of the same channel, while the
+// operations on different channels proceed in parallel.
+type channelLocks struct {
+	mutex sync.Mutex
+	locks map[string]*channelLock
+}
+
+type channelLock struct {
+	sync.Mutex
+	// refs counts the operations holding or waiting for the lock, which is discarded when there are none left.
+	refs int
+}
+
+func newChannelLocks() *channelLocks {
+	return &channelLocks{locks: map[string]*channelLock{}}
+}
+
+// lock waits until no other operation holds the lock of the channel, and returns the function that releases it.
+func (c *channelLocks) lock(channelID string) (unlock func()) {
+	c.mutex.Lock()
+	l, ok := c.locks[channelID]
+	if !ok {
+		l = &channelLock{}
+		c.locks[channelID] = l
+	}
+	l.refs++
+	c.mutex.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		l.refs--
+		if l.refs == 0 {
+			delete(c.locks, channelID)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channelparticipation
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric/orderer/common/types"
+)
+
+// configCache keeps the fields derived from the config of each channel, so that the config is not parsed again on
+// every request, e.g. when listing many channels verbosely. The entry of a channel is tied to the config sequence the
+// fields were derived from. Every config update increments the sequence, which invalidates the entry, so a field of
+// a replaced config is never served.
+//
+// The name and sequence repeat only when a channel is removed and joined again, since a rejoined channel starts over
+// at the sequence of its join block. The handler drops the entry of a channel on every removal and on every join,
+// which covers a channel removed outside of the API, so an entry never outlives the config it was derived from.
+// Keying the entries by the hash of the config block instead would cost a ledger read per channel per request, which
+// is what the cache is there to avoid.
+type configCache struct {
+	mutex   sync.Mutex
+	entries map[string]*configCacheEntry
+}
+
+type configCacheEntry struct {
+	sequence       uint64
+	capabilities   *types.ChannelCapabilities
+	consenterCount *int
+}
+
+func newConfigCache() *configCache {
+	return &configCache{entries: map[string]*configCacheEntry{}}
+}
+
+// capabilities returns the cached capabilities of the config of the channel, or loads and caches them when the
+// config sequence of the channel changed. They are loaded without caching when the sequence is not known.
+func (c *configCache) capabilities(info types.ChannelInfo, load func() (types.ChannelCapabilities, error)) (types.ChannelCapabilities, error) {
+	if info.ConfigSequence == nil {
+		return load()
+	}
+
+	c.mutex.Lock()
+	entry := c.entries[info.Name]
+	if entry != nil && entry.sequence == *info.ConfigSequence && entry.capabilities != nil {
+		capabilities := *entry.capabilities
+		c.mutex.Unlock()
+		return capabilities, nil
+	}
+	c.mutex.Unlock()
+
+	// the config is parsed without holding the lock, so that other channels are not held up
+	capabilities, err := load()
+	if err != nil {
+		return types.ChannelCapabilities{}, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entryFor(info.Name, *info.ConfigSequence).capabilities = &capabilities
+	return capabilities, nil
+}
+
+// consenterCount returns the cached number of consenters in the config of the channel, or loads and caches it when
+// the config sequence of the channel changed. It is loaded without caching when the sequence is not known.
+func (c *configCache) consenterCount(info types.ChannelInfo, load func() (int, error)) (int, error) {
+	if info.ConfigSequence == nil {
+		return load()
+	}
+
+	c.mutex.Lock()
+	entry := c.entries[info.Name]
+	if entry != nil && entry.sequence == *info.ConfigSequence && entry.consenterCount != nil {
+		count := *entry.consenterCount
+		c.mutex.Unlock()
+		return count, nil
+	}
+	c.mutex.Unlock()
+
+	count, err := load()
+	if err != nil {
+		return 0, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entryFor(info.Name, *info.ConfigSequence).consenterCount = &count
+	return count, nil
+}
+
+// entryFor returns the entry of the channel for the config sequence, replacing the entry of another sequence.
+// The caller must hold the lock.
+func (c *configCache) entryFor(channelID string, sequence uint64) *configCacheEntry {
+	entry := c.entries[channelID]
+	if entry == nil || entry.sequence != sequence {
+		entry = &configCacheEntry{sequence: sequence}
+		c.entries[channelID] = entry
+	}
+	return entry
+}
+
+// remove drops the entry of a channel, e.g. when it is removed, so that a channel joined again under the same name
+// starts from its own config even though its config sequence starts over.
+func (c *configCache) remove(channelID string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.entries, channelID)
+}
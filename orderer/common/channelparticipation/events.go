@@ -0,0 +1,128 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channelparticipation
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/hyperledger/fabric/common/fabhttp"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	eventStreamContentType = "text/event-stream"
+
+	// the Server-Sent Event carrying the channel information when its status or height changes
+	sseEventProgress = "progress"
+	// the Server-Sent Event carrying the error that ended the stream, e.g. the channel was removed
+	sseEventError = "error"
+
+	// the time allowed to write an event; the write timeout of the server would otherwise end the stream
+	sseWriteTimeout = 30 * time.Second
+)
+
+// serveEvents streams the status and height of a channel as Server-Sent Events, so that clients follow the progress
+// of onboarding instead of polling. The channel is checked at the configured interval, and an event is sent whenever
+// its status or height changes. The stream ends once the channel is no longer onboarding, when the channel cannot be
+// found anymore, or when the client goes away. The write deadline of the connection is moved before every event, as
+// onboarding usually lasts longer than the write timeout of the admin server.
+func (h *HTTPHandler) serveEvents(resp http.ResponseWriter, req *http.Request) {
+	if !accepts(req, eventStreamContentType) {
+		h.sendResponseJsonError(resp, http.StatusNotAcceptable, errors.Errorf("response Content-Type is %s only", eventStreamContentType))
+		return
+	}
+
+	flusher, ok := resp.(http.Flusher)
+	if !ok {
+		h.sendResponseJsonError(resp, http.StatusInternalServerError, errors.New("streaming is not supported"))
+		return
+	}
+
+	channelID, err := h.extractChannelID(req, resp)
+	if err != nil {
+		return
+	}
+
+	info, err := h.channelProgress(channelID)
+	if err != nil {
+		h.sendResponseJsonError(resp, http.StatusNotFound, err)
+		return
+	}
+
+	resp.Header().Set("Content-Type", eventStreamContentType)
+	resp.Header().Set("Cache-Control", "no-store")
+	resp.WriteHeader(http.StatusOK)
+
+	ticker := time.NewTicker(h.config.EventsInterval)
+	defer ticker.Stop()
+
+	for {
+		h.extendWriteDeadline(req, channelID)
+		if err := h.sendEvent(resp, sseEventProgress, info); err != nil {
+			h.logger.Debugf("Failed to send event for: %s, err: %s", channelID, err)
+			return
+		}
+		flusher.Flush()
+		if info.Status != types.StatusOnBoarding {
+			return
+		}
+
+		next := info
+		for next.Status == info.Status && next.Height == info.Height {
+			select {
+			case <-req.Context().Done():
+				return
+			case <-ticker.C:
+			}
+
+			next, err = h.channelProgress(channelID)
+			if err != nil {
+				// e.g. the channel was removed while onboarding
+				h.extendWriteDeadline(req, channelID)
+				if err := h.sendEvent(resp, sseEventError, &types.ErrorResponse{Error: err.Error()}); err != nil {
+					h.logger.Debugf("Failed to send event for: %s, err: %s", channelID, err)
+				}
+				flusher.Flush()
+				return
+			}
+		}
+		info = next
+	}
+}
+
+// extendWriteDeadline allows the next event of the stream to be written within sseWriteTimeout.
+func (h *HTTPHandler) extendWriteDeadline(req *http.Request, channelID string) {
+	if err := fabhttp.SetWriteDeadline(req, time.Now().Add(sseWriteTimeout)); err != nil {
+		h.logger.Debugf("Failed to extend the write deadline of the events of: %s, err: %s", channelID, err)
+	}
+}
+
+// channelProgress returns the channel information sent in the events of a channel.
+func (h *HTTPHandler) channelProgress(channelID string) (types.ChannelInfo, error) {
+	info, err := h.channelInfo(channelID)
+	if err != nil {
+		return types.ChannelInfo{}, err
+	}
+	info.URL = path.Join(URLBaseV1Channels, info.Name)
+	info.OrdererEndpoint = h.config.OrdererEndpoint
+	return info, nil
+}
+
+// sendEvent writes a Server-Sent Event, whose data is the content encoded as a single line of JSON.
+func (h *HTTPHandler) sendEvent(resp http.ResponseWriter, event string, content interface{}) error {
+	data, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(resp, "event: %s\ndata: %s\n\n", event, data)
+	return err
+}
@@ -0,0 +1,87 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channelparticipation
+
+import (
+	"net/http"
+
+	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// statuses are the channel statuses that the number of channels is reported for, including those with no channel.
+var statuses = []types.Status{types.StatusInactive, types.StatusActive, types.StatusOnBoarding, types.StatusFailed, types.StatusRemoving}
+
+// apiMetrics are the metrics of the channel participation API, served in the Prometheus text format. They are kept
+// in a registry of their own, apart from the metrics of the operations service.
+type apiMetrics struct {
+	registry *prometheus.Registry
+	joins    prometheus.Counter
+	removes  prometheus.Counter
+}
+
+func newAPIMetrics(summary func() types.ChannelsSummary) *apiMetrics {
+	m := &apiMetrics{
+		registry: prometheus.NewRegistry(),
+		joins: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "participation",
+			Name:      "joins_total",
+			Help:      "The number of channels joined through the channel participation API.",
+		}),
+		removes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "participation",
+			Name:      "removes_total",
+			Help:      "The number of channels removed through the channel participation API.",
+		}),
+	}
+	m.registry.MustRegister(m.joins, m.removes, newChannelsCollector(summary))
+	return m
+}
+
+// observe counts a channel lifecycle event.
+func (m *apiMetrics) observe(eventType string) {
+	switch eventType {
+	case types.ChannelEventJoin:
+		m.joins.Inc()
+	case types.ChannelEventRemove:
+		m.removes.Inc()
+	}
+}
+
+func (m *apiMetrics) handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// channelsCollector reports the number of channels in each status, as summarized when the metrics are scraped.
+type channelsCollector struct {
+	desc    *prometheus.Desc
+	summary func() types.ChannelsSummary
+}
+
+func newChannelsCollector(summary func() types.ChannelsSummary) *channelsCollector {
+	return &channelsCollector{
+		desc: prometheus.NewDesc(
+			"participation_channels",
+			"The number of channels in each status, including the system channel.",
+			[]string{"status"},
+			nil,
+		),
+		summary: summary,
+	}
+}
+
+func (c *channelsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *channelsCollector) Collect(ch chan<- prometheus.Metric) {
+	summary := c.summary()
+	for _, status := range statuses {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(summary.Statuses[status]), string(status))
+	}
+}
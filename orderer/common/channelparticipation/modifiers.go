@@ -0,0 +1,57 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channelparticipation
+
+import (
+	"net/http"
+	"sync"
+)
+
+// modifiers keeps the identity of the client that last joined or removed each channel, for audit trails. It is kept
+// in memory, so the identities of the operations done before the orderer last restarted are not known.
+type modifiers struct {
+	mutex    sync.Mutex
+	channels map[string]string
+}
+
+func newModifiers() *modifiers {
+	return &modifiers{channels: map[string]string{}}
+}
+
+// set records the subject of the client that modified the channel; an empty subject forgets the previous one, as
+// the identity of the last modification is not known.
+func (m *modifiers) set(channelID, subject string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if subject == "" {
+		delete(m.channels, channelID)
+		return
+	}
+	m.channels[channelID] = subject
+}
+
+func (m *modifiers) get(channelID string) string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.channels[channelID]
+}
+
+// remove drops the entry of a channel that is gone.
+func (m *modifiers) remove(channelID string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.channels, channelID)
+}
+
+// clientSubject returns the subject of the client certificate of a request, e.g. "CN=admin,OU=admin,O=Org1", or an
+// empty string when the client presented no certificate.
+func clientSubject(req *http.Request) string {
+	if req.TLS == nil || len(req.TLS.PeerCertificates) == 0 {
+		return ""
+	}
+	return req.TLS.PeerCertificates[0].Subject.String()
+}
@@ -0,0 +1,107 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channelparticipation
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/pkg/errors"
+)
+
+// projectedChannelList is a ChannelList whose channels are reduced to the requested fields.
+type projectedChannelList struct {
+	SystemChannel map[string]json.RawMessage   `json:"systemChannel"`
+	Channels      []map[string]json.RawMessage `json:"channels"`
+	Count         int                          `json:"count"`
+	Revision      uint64                       `json:"revision"`
+}
+
+// parseFields returns the JSON keys listed, comma separated, in the fields query parameter of the request, or
+// nil when the parameter is absent. Every key must be a JSON key of the model.
+func parseFields(req *http.Request, model interface{}) ([]string, error) {
+	param := req.URL.Query().Get("fields")
+	if param == "" {
+		return nil, nil
+	}
+
+	known := jsonKeys(reflect.TypeOf(model))
+	var fields []string
+	for _, field := range strings.Split(param, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !known[field] {
+			return nil, errors.Errorf("unknown field: %s", field)
+		}
+		fields = append(fields, field)
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("no fields in the fields query parameter")
+	}
+	return fields, nil
+}
+
+// jsonKeys returns the JSON keys of the fields of a struct type.
+func jsonKeys(t reflect.Type) map[string]bool {
+	keys := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		key := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if key != "" && key != "-" {
+			keys[key] = true
+		}
+	}
+	return keys
+}
+
+// project reduces the JSON encoding of the value to the fields. A field that is omitted from the encoding when
+// empty is left out.
+func project(v interface{}, fields []string) (map[string]json.RawMessage, error) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	all := map[string]json.RawMessage{}
+	if err := json.Unmarshal(encoded, &all); err != nil {
+		return nil, err
+	}
+
+	projected := map[string]json.RawMessage{}
+	for _, field := range fields {
+		if value, ok := all[field]; ok {
+			projected[field] = value
+		}
+	}
+	return projected, nil
+}
+
+// projectChannelList reduces the system channel and the channels of the list to the fields.
+func projectChannelList(channelList types.ChannelList, fields []string) (projectedChannelList, error) {
+	projected := projectedChannelList{Count: channelList.Count, Revision: channelList.Revision}
+	if channelList.SystemChannel != nil {
+		systemChannel, err := project(*channelList.SystemChannel, fields)
+		if err != nil {
+			return projectedChannelList{}, err
+		}
+		projected.SystemChannel = systemChannel
+	}
+	if channelList.Channels != nil {
+		projected.Channels = make([]map[string]json.RawMessage, 0, len(channelList.Channels))
+	}
+	for _, info := range channelList.Channels {
+		channel, err := project(info, fields)
+		if err != nil {
+			return projectedChannelList{}, err
+		}
+		projected.Channels = append(projected.Channels, channel)
+	}
+	return projected, nil
+}
@@ -0,0 +1,67 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channelparticipation
+
+import (
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/hyperledger/fabric/orderer/common/types/msgs"
+)
+
+// The media type of protobuf encoded responses.
+const protobufContentType = "application/x-protobuf"
+
+func channelListToProto(channelList types.ChannelList) *msgs.ChannelList {
+	list := &msgs.ChannelList{Count: uint64(channelList.Count), Revision: channelList.Revision}
+	if channelList.SystemChannel != nil {
+		list.SystemChannel = channelInfoShortToProto(*channelList.SystemChannel)
+	}
+	for _, info := range channelList.Channels {
+		list.Channels = append(list.Channels, channelInfoShortToProto(info))
+	}
+	return list
+}
+
+func channelInfoShortToProto(info types.ChannelInfoShort) *msgs.ChannelInfoShort {
+	return &msgs.ChannelInfoShort{
+		Name: info.Name,
+		Url:  info.URL,
+	}
+}
+
+func channelInfoToProto(info types.ChannelInfo) *msgs.ChannelInfo {
+	infoProto := &msgs.ChannelInfo{
+		Name:              info.Name,
+		Url:               info.URL,
+		ConsensusRelation: string(info.ConsensusRelation),
+		Status:            string(info.Status),
+		Height:            info.Height,
+		OrdererEndpoint:   info.OrdererEndpoint,
+		RequiresRestart:   info.RequiresRestart,
+		LastModifiedBy:    info.LastModifiedBy,
+	}
+	if info.JoinedFromGenesis != nil {
+		infoProto.JoinedFromGenesis = &wrappers.BoolValue{Value: *info.JoinedFromGenesis}
+	}
+	if info.Capabilities != nil {
+		infoProto.Capabilities = &msgs.ChannelCapabilities{
+			Channel:     info.Capabilities.Channel,
+			Orderer:     info.Capabilities.Orderer,
+			Application: info.Capabilities.Application,
+		}
+	}
+	if info.LedgerBytes != nil {
+		infoProto.LedgerBytes = &wrappers.UInt64Value{Value: *info.LedgerBytes}
+	}
+	if info.ConfigSequence != nil {
+		infoProto.ConfigSequence = &wrappers.UInt64Value{Value: *info.ConfigSequence}
+	}
+	if info.TargetHeight != nil {
+		infoProto.TargetHeight = &wrappers.UInt64Value{Value: *info.TargetHeight}
+	}
+	return infoProto
+}
@@ -0,0 +1,45 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channelparticipation
+
+import "sync"
+
+// removals tracks the channels that are removed asynchronously, which are reported with the removing status from the
+// time the removal is accepted until the channel is gone.
+type removals struct {
+	mutex    sync.Mutex
+	channels map[string]struct{}
+}
+
+func newRemovals() *removals {
+	return &removals{channels: map[string]struct{}{}}
+}
+
+// start marks the channel as being removed, and reports false if it already is.
+func (r *removals) start(channelID string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, ok := r.channels[channelID]; ok {
+		return false
+	}
+	r.channels[channelID] = struct{}{}
+	return true
+}
+
+// done clears the mark of the channel, once it is gone or its removal failed.
+func (r *removals) done(channelID string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.channels, channelID)
+}
+
+func (r *removals) removing(channelID string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	_, ok := r.channels[channelID]
+	return ok
+}
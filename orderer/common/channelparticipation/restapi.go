@@ -7,13 +7,21 @@ SPDX-License-Identifier: Apache-2.0
 package channelparticipation
 
 import (
+	"compress/gzip"
+	"context"
+	"encoding/base64"
 	"encoding/json"
+	"io"
 	"io/ioutil"
 	"mime"
 	"mime/multipart"
 	"net/http"
 	"path"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/gorilla/mux"
@@ -28,15 +36,45 @@ import (
 const (
 	URLBaseV1              = "/participation/v1/"
 	URLBaseV1Channels      = URLBaseV1 + "channels"
+	URLBaseV1Status        = URLBaseV1 + "status"
+	URLBaseV1Metrics       = URLBaseV1 + "metrics"
 	FormDataConfigBlockKey = "config-block"
+	IfNotExistsHeader      = "If-Not-Exists"
+	PreferHeader           = "Prefer"
 
 	channelIDKey        = "channelID"
 	urlWithChannelIDKey = URLBaseV1Channels + "/{" + channelIDKey + "}"
+	urlJoinBlock        = urlWithChannelIDKey + "/joinblock"
+	urlEvents           = urlWithChannelIDKey + "/events"
+	urlConfigUpdate     = urlWithChannelIDKey + "/configupdate"
+
+	// the seconds a client is asked to wait before retrying while the orderer is loading its channels
+	loadingRetryAfter = "5"
+
+	// the preference of a client for a response that carries the representation of the resource, see RFC 7240
+	returnRepresentation = "representation"
+	// the preference of a client for a response before the request is processed, see RFC 7240
+	respondAsync = "respond-async"
+)
+
+// joinContentTypes are the media types of the join request bodies, in the order of preference.
+var joinContentTypes = []string{"multipart/form-data", "application/json"}
+
+const (
+	// removalPollInterval is the interval at which an asynchronous removal checks whether the ledger of the channel
+	// is released.
+	removalPollInterval = 100 * time.Millisecond
+	// defaultRemovalTimeout bounds the wait of an asynchronous removal when RemovalTimeout is not set.
+	defaultRemovalTimeout = 10 * time.Minute
 )
 
 //go:generate counterfeiter -o mocks/channel_management.go -fake-name ChannelManagement . ChannelManagement
 
 type ChannelManagement interface {
+	// Loading reports whether the orderer is still loading its channels after a restart, in which case the
+	// channel list is incomplete.
+	Loading() bool
+
 	// ChannelList returns a slice of ChannelInfoShort containing all application channels (excluding the system
 	// channel), and ChannelInfoShort of the system channel (nil if does not exist).
 	// The URL fields are empty, and are to be completed by the caller.
@@ -46,12 +84,37 @@ type ChannelManagement interface {
 	// The URL field is empty, and is to be completed by the caller.
 	ChannelInfo(channelID string) (types.ChannelInfo, error)
 
+	// ChannelCapabilities provides the capabilities of the config of a channel.
+	ChannelCapabilities(channelID string) (types.ChannelCapabilities, error)
+
+	// ConsenterCount provides the number of consenters in the config of a channel.
+	ConsenterCount(channelID string) (int, error)
+
+	// LedgerBytes provides the approximate size on disk of the block files of a channel.
+	LedgerBytes(channelID string) (uint64, error)
+
+	// FreeDiskSpace provides the disk space available to the ledgers of the channels.
+	FreeDiskSpace() (uint64, error)
+
+	// JoinBlock provides the marshaled config block the orderer joined a channel with.
+	JoinBlock(channelID string) ([]byte, error)
+
 	// JoinChannel instructs the orderer to create a channel and join it with the provided config block.
 	// The URL field is empty, and is to be completed by the caller.
 	JoinChannel(channelID string, configBlock *cb.Block, isAppChannel bool) (types.ChannelInfo, error)
 
+	// ValidateJoin runs the checks of JoinChannel without creating the channel, and provides the information the
+	// channel would have once joined. The URL field is empty, and is to be completed by the caller.
+	ValidateJoin(channelID string, configBlock *cb.Block, isAppChannel bool) (types.ChannelInfo, error)
+
 	// RemoveChannel instructs the orderer to remove a channel.
 	RemoveChannel(channelID string) error
+
+	// ComputeConfigUpdate provides the unsigned config update that applies the patch to a channel.
+	ComputeConfigUpdate(channelID string, patch types.ChannelConfigPatch) (*cb.ConfigUpdate, error)
+
+	// UpdateChannelConfig instructs the orderer to submit a config update, signed by the client, to a channel.
+	UpdateChannelConfig(channelID string, configUpdateEnv *cb.ConfigUpdateEnvelope) error
 }
 
 // HTTPHandler handles all the HTTP requests to the channel participation API.
@@ -60,25 +123,74 @@ type HTTPHandler struct {
 	config    localconfig.ChannelParticipation
 	registrar ChannelManagement
 	router    *mux.Router
+	// joinSlots bounds the number of concurrent join and remove operations; nil means unbounded.
+	joinSlots chan struct{}
+	// webhook is notified of joined and removed channels, and of changes to their consensus relation; nil means
+	// disabled.
+	webhook *webhook
+	// channelLocks serializes the join, remove and update operations on each channel.
+	channelLocks *channelLocks
+	// metrics are served in the Prometheus text format; nil means disabled.
+	metrics *apiMetrics
+	// configCache keeps the fields derived from the config of each channel.
+	configCache *configCache
+	// removals tracks the channels that are removed asynchronously.
+	removals *removals
+	// modifiers keeps the identity of the client that last joined or removed each channel.
+	modifiers *modifiers
+	// joinCountLock serializes counting the channels and joining one, so that concurrent joins do not exceed
+	// MaxChannels.
+	joinCountLock sync.Mutex
+	// maxRequestBodySize is read and written atomically, so that the limit can be changed while requests are served.
+	maxRequestBodySize uint32
 }
 
 func NewHTTPHandler(config localconfig.ChannelParticipation, registrar ChannelManagement) *HTTPHandler {
 	handler := &HTTPHandler{
-		logger:    flogging.MustGetLogger("orderer.commmon.channelparticipation"),
-		config:    config,
-		registrar: registrar,
-		router:    mux.NewRouter(),
+		logger:       flogging.MustGetLogger("orderer.commmon.channelparticipation"),
+		config:       config,
+		registrar:    registrar,
+		router:       mux.NewRouter(),
+		channelLocks: newChannelLocks(),
+		configCache:  newConfigCache(),
+		removals:     newRemovals(),
+		modifiers:    newModifiers(),
+
+		maxRequestBodySize: config.MaxRequestBodySize,
+	}
+	if config.MaxConcurrentJoins > 0 {
+		handler.joinSlots = make(chan struct{}, config.MaxConcurrentJoins)
+	}
+	if config.WebhookURL != "" {
+		handler.webhook = newWebhook(handler.logger, config.WebhookURL, config.WebhookTimeout)
+	}
+	if config.MetricsEnabled {
+		handler.metrics = newAPIMetrics(handler.channelsSummary)
 	}
 
 	// swagger:operation GET /v1/participation/channels/{channelID} channels listChannel
 	// ---
 	// summary: Returns detailed channel information for a specific channel Ordering Service Node (OSN) has joined.
+	// description: The response is encoded as protobuf when requested with Accept: application/x-protobuf.
+	// produces:
+	//   - application/json
+	//   - application/x-protobuf
 	// parameters:
 	// - name: channelID
 	//   in: path
 	//   description: Channel ID
 	//   required: true
 	//   type: string
+	// - name: verbose
+	//   in: query
+	//   description: Include the capabilities of the channel config and the size of the channel ledger
+	//   required: false
+	//   type: boolean
+	// - name: fields
+	//   in: query
+	//   description: Only include these comma separated JSON keys of the channel, e.g. "name,height"
+	//   required: false
+	//   type: string
 	// responses:
 	//    '200':
 	//       description: Successfully retrieved channel.
@@ -91,8 +203,68 @@ func NewHTTPHandler(config localconfig.ChannelParticipation, registrar ChannelMa
 	//        Cache-Control:
 	//         description: The directives for caching responses
 	//         type: string
+	//    '400':
+	//       description: A field is unknown.
+	//    '503':
+	//       description: The orderer is still loading its channels after a restart, retry after the Retry-After seconds.
+
+	handler.router.HandleFunc(urlWithChannelIDKey, handler.requireLoaded(handler.serveListOne)).Methods(http.MethodGet)
+
+	// swagger:operation GET /v1/participation/channels/{channelID}/joinblock channels getJoinBlock
+	// ---
+	// summary: Returns the config block an Ordering Service Node (OSN) joined a channel with.
+	// description: The join block is only kept for channels joined through the channel participation API.
+	// produces:
+	//   - application/octet-stream
+	// parameters:
+	// - name: channelID
+	//   in: path
+	//   description: Channel ID
+	//   required: true
+	//   type: string
+	// responses:
+	//    '200':
+	//       description: Successfully retrieved the protobuf encoded join block.
+	//       schema:
+	//         type: string
+	//         format: binary
+	//    '404':
+	//      description: The channel, or its join block, does not exist.
+	//    '409':
+	//      description: The channel is pending removal.
+	//    '503':
+	//      description: The orderer is still loading its channels after a restart, retry after the Retry-After seconds.
+
+	handler.router.HandleFunc(urlJoinBlock, handler.requireLoaded(handler.serveJoinBlock)).Methods(http.MethodGet)
+	handler.router.HandleFunc(urlJoinBlock, handler.serveNotAllowed)
+
+	// swagger:operation GET /v1/participation/channels/{channelID}/events channels channelEvents
+	// ---
+	// summary: Streams the status and height of a channel as Server-Sent Events while the channel is onboarding.
+	// description: |
+	//   A progress event carrying the channel information is sent first, and then whenever the status or height of the channel changes.
+	//   The stream ends once the channel is no longer onboarding, or with an error event when the channel cannot be found anymore.
+	//   Only served when enabled by the config.
+	// produces:
+	//   - text/event-stream
+	// parameters:
+	// - name: channelID
+	//   in: path
+	//   description: Channel ID
+	//   required: true
+	//   type: string
+	// responses:
+	//    '200':
+	//       description: Successfully subscribed to the events of the channel.
+	//    '404':
+	//       description: The channel does not exist, or the events are disabled.
+	//    '503':
+	//       description: The orderer is still loading its channels after a restart, retry after the Retry-After seconds.
 
-	handler.router.HandleFunc(urlWithChannelIDKey, handler.serveListOne).Methods(http.MethodGet)
+	if config.EventsInterval > 0 {
+		handler.router.HandleFunc(urlEvents, handler.requireLoaded(handler.serveEvents)).Methods(http.MethodGet)
+		handler.router.HandleFunc(urlEvents, handler.serveNotAllowed)
+	}
 
 	// swagger:operation DELETE /v1/participation/channels/{channelID} channels removeChannel
 	// ---
@@ -103,7 +275,46 @@ func NewHTTPHandler(config localconfig.ChannelParticipation, registrar ChannelMa
 	//   description: Channel ID
 	//   required: true
 	//   type: string
+	// - name: force
+	//   in: query
+	//   description: Remove the channel even if the OSN is an active consenter, or the sole consenter, of it, when consenters are protected
+	//   required: false
+	//   type: boolean
+	// - name: Prefer
+	//   in: header
+	//   description: Set to return=representation to respond with the channel information at the time of removal, or to respond-async to respond before the channel is removed
+	//   required: false
+	//   type: string
+	// - name: return
+	//   in: query
+	//   description: Same as the Prefer header, set to representation
+	//   required: false
+	//   type: string
+	// - name: async
+	//   in: query
+	//   description: Same as the respond-async preference of the Prefer header
+	//   required: false
+	//   type: boolean
 	// responses:
+	//    '200':
+	//      description: Successfully removed channel, and return=representation was preferred.
+	//      schema:
+	//        "$ref": "#/definitions/channelInfo"
+	//      headers:
+	//       Preference-Applied:
+	//         description: The preference that was applied, return=representation
+	//         type: string
+	//    '202':
+	//      description: The removal of the channel was accepted, and respond-async was preferred. The channel is reported with the removing status until it is gone.
+	//      schema:
+	//        "$ref": "#/definitions/channelInfo"
+	//      headers:
+	//       Location:
+	//         description: The URL of the channel
+	//         type: string
+	//       Preference-Applied:
+	//         description: The preference that was applied, respond-async
+	//         type: string
 	//    '204':
 	//      description: Successfully removed channel.
 	//    '400':
@@ -113,14 +324,127 @@ func NewHTTPHandler(config localconfig.ChannelParticipation, registrar ChannelMa
 	//    '405':
 	//      description: The system channel exists, removal is not allowed.
 	//    '409':
+	//      description: The channel is pending removal, or being removed, or the OSN is an active consenter, or the sole consenter, of a protected channel.
+	//    '429':
+	//      description: Too many concurrent join or remove requests.
+	//    '503':
+	//      description: The orderer is still loading its channels after a restart, retry after the Retry-After seconds.
+
+	handler.router.HandleFunc(urlWithChannelIDKey, handler.requireLoaded(handler.limitJoins(handler.serveRemove))).Methods(http.MethodDelete)
+
+	// swagger:operation POST /v1/participation/channels/{channelID}/configupdate channels computeConfigUpdate
+	// ---
+	// summary: Translates a JSON merge patch of the orderer batch parameters, or the consensus state, of a channel into a config update.
+	// description: |
+	//   This is the first of the two steps that apply a patch such as {"batchTimeout":"3s"}. Only the fields of channelConfigPatch are allowed.
+	//   The config update is not signed. It must be signed by the identities that satisfy the modification policy of the patched values,
+	//   typically the orderer Admins, and then submitted with PATCH as the second step.
+	// parameters:
+	// - name: channelID
+	//   in: path
+	//   description: Channel ID
+	//   required: true
+	//   type: string
+	// - name: patch
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/channelConfigPatch"
+	// responses:
+	//    '200':
+	//       description: Successfully computed the protobuf encoded config update.
+	//       schema:
+	//         type: string
+	//         format: binary
+	//    '400':
+	//      description: Bad request.
+	//    '404':
+	//      description: The channel does not exist.
+	//    '406':
+	//      description: The request does not accept application/octet-stream.
+	//    '409':
+	//      description: The channel is pending removal, or already in the consensus state.
+	//    '503':
+	//      description: The orderer is still loading its channels after a restart, retry after the Retry-After seconds.
+	// consumes:
+	//   - application/merge-patch+json
+	//   - application/json
+	// produces:
+	//   - application/octet-stream
+
+	handler.router.HandleFunc(urlConfigUpdate, handler.requireLoaded(handler.serveComputeConfigUpdate)).Methods(http.MethodPost).HeadersRegexp(
+		"Content-Type", "application/(merge-patch\\+)?json")
+	handler.router.HandleFunc(urlConfigUpdate, handler.serveBadContentType).Methods(http.MethodPost)
+	handler.router.HandleFunc(urlConfigUpdate, handler.serveNotAllowed)
+
+	// swagger:operation PATCH /v1/participation/channels/{channelID} channels updateChannelConfig
+	// ---
+	// summary: Submits a config update, signed by the client, that changes the orderer batch parameters, or the consensus state, of a channel.
+	// description: |
+	//   This is the second of the two steps that apply a patch such as {"batchTimeout":"3s"}, after the patch was translated into a config update
+	//   with POST /v1/participation/channels/{channelID}/configupdate.
+	//   The body is a protobuf encoded ConfigUpdateEnvelope, whose signatures must satisfy the modification policy of the values it changes.
+	//   The orderer does not sign the config update itself, so that the patch is authorized by the channel policies and not by the access
+	//   to the admin endpoint. Config updates that change more than the fields of channelConfigPatch are rejected.
+	// parameters:
+	// - name: channelID
+	//   in: path
+	//   description: Channel ID
+	//   required: true
+	//   type: string
+	// - name: configUpdateEnvelope
+	//   in: body
+	//   required: true
+	//   schema:
+	//     type: string
+	//     format: binary
+	// responses:
+	//    '202':
+	//      description: Successfully submitted the config update.
+	//      headers:
+	//       Location:
+	//        description: The URL of the channel
+	//        type: string
+	//    '400':
+	//      description: Bad request, or the config update was rejected.
+	//    '404':
+	//      description: The channel does not exist.
+	//    '409':
 	//      description: The channel is pending removal.
+	//    '503':
+	//      description: The orderer is still loading its channels after a restart, retry after the Retry-After seconds.
+	// consumes:
+	//   - application/octet-stream
 
-	handler.router.HandleFunc(urlWithChannelIDKey, handler.serveRemove).Methods(http.MethodDelete)
+	handler.router.HandleFunc(urlWithChannelIDKey, handler.requireLoaded(handler.serveUpdateConfig)).Methods(http.MethodPatch).Headers(
+		"Content-Type", "application/octet-stream")
+	handler.router.HandleFunc(urlWithChannelIDKey, handler.serveBadContentType).Methods(http.MethodPatch)
 	handler.router.HandleFunc(urlWithChannelIDKey, handler.serveNotAllowed)
 
 	// swagger:operation GET /v1/participation/channels channels listChannels
 	// ---
 	// summary: Returns the complete list of channels an Ordering Service Node (OSN) has joined.
+	// description: The response is encoded as protobuf when requested with Accept: application/x-protobuf.
+	// produces:
+	//   - application/json
+	//   - application/x-protobuf
+	// parameters:
+	// - name: prefix
+	//   in: query
+	//   description: Only list the channels whose names start with the prefix
+	//   required: false
+	//   type: string
+	// - name: relation
+	//   in: query
+	//   description: Only list the channels in which the orderer has the consensus relation
+	//   required: false
+	//   type: string
+	//   enum: [consenter, follower, config-tracker, other]
+	// - name: fields
+	//   in: query
+	//   description: Only include these comma separated JSON keys of the channels, e.g. "name"
+	//   required: false
+	//   type: string
 	// responses:
 	//    '200':
 	//       description: Successfully retrieved channels.
@@ -133,19 +457,53 @@ func NewHTTPHandler(config localconfig.ChannelParticipation, registrar ChannelMa
 	//        Cache-Control:
 	//         description: The directives for caching responses
 	//         type: string
+	//    '400':
+	//       description: The relation is not a known consensus relation, or a field is unknown.
+	//    '503':
+	//       description: The orderer is still loading its channels after a restart, retry after the Retry-After seconds.
 
-	handler.router.HandleFunc(URLBaseV1Channels, handler.serveListAll).Methods(http.MethodGet)
+	handler.router.HandleFunc(URLBaseV1Channels, handler.requireLoaded(handler.serveListAll)).Methods(http.MethodGet)
 
 	// swagger:operation POST /v1/participation/channels channels joinChannel
 	// ---
 	// summary: Joins an Ordering Service Node (OSN) to a channel.
-	// description: If a channel does not yet exist, it will be created.
+	// description: |
+	//   If a channel does not yet exist, it will be created.
+	//   The config block is sent either as the configBlock part of a multipart form, or as a JSON joinRequest.
 	// parameters:
 	// - name: configBlock
 	//   in: formData
 	//   type: string
 	//   required: true
+	// - name: If-Not-Exists
+	//   in: header
+	//   description: Respond with the existing channel instead of an error if the channel was already joined
+	//   required: false
+	//   type: boolean
+	// - name: ifNotExists
+	//   in: query
+	//   description: Same as the If-Not-Exists header
+	//   required: false
+	//   type: boolean
+	// - name: Content-Encoding
+	//   in: header
+	//   description: Set to gzip when the request body is gzip compressed
+	//   required: false
+	//   type: string
+	// - name: dryRun
+	//   in: query
+	//   description: Only validate the join, responding with the channel information it would result in, without creating the channel
+	//   required: false
+	//   type: boolean
 	// responses:
+	//    '200':
+	//      description: The channel was already joined and If-Not-Exists was set, or the join was validated with dryRun.
+	//      schema:
+	//        "$ref": "#/definitions/channelInfo"
+	//      headers:
+	//       Content-Type:
+	//         description: The media type of the resource
+	//         type: string
 	//    '201':
 	//      description: Successfully joined channel.
 	//      schema:
@@ -158,7 +516,7 @@ func NewHTTPHandler(config localconfig.ChannelParticipation, registrar ChannelMa
 	//        description: The URL to redirect a page to
 	//        type: string
 	//    '400':
-	//      description: Cannot join channel.
+	//      description: Cannot join channel, e.g. the config block requires capabilities the OSN does not support, its data hash does not match its data, or it lists a consenter twice.
 	//    '403':
 	//      description: The client is trying to join the system-channel that does not exist, but application channels exist.
 	//    '405':
@@ -168,17 +526,90 @@ func NewHTTPHandler(config localconfig.ChannelParticipation, registrar ChannelMa
 	//                   The client is trying to join the system-channel, and it exists.
 	//    '409':
 	//      description: The client is trying to join a channel that is currently being removed.
+	//    '415':
+	//      description: The Content-Encoding of the request body is not supported.
+	//    '422':
+	//      description: The config block was parsed, but cannot be used to join a channel.
+	//    '429':
+	//      description: Too many concurrent join or remove requests.
 	//    '500':
 	//      description: Removal of channel failed.
+	//    '503':
+	//      description: The orderer is still loading its channels after a restart, retry after the Retry-After seconds.
+	//    '507':
+	//      description: The free disk space is below the configured minimum to join a channel, or the orderer has the configured maximum number of channels.
 	// consumes:
 	//   - multipart/form-data
+	//   - application/json
 
-	handler.router.HandleFunc(URLBaseV1Channels, handler.serveJoin).Methods(http.MethodPost).HeadersRegexp(
+	handler.router.HandleFunc(URLBaseV1Channels, handler.requireLoaded(handler.limitJoins(handler.decodeContentEncoding(handler.serveJoin)))).Methods(http.MethodPost).HeadersRegexp(
 		"Content-Type", "multipart/form-data*")
+	handler.router.HandleFunc(URLBaseV1Channels, handler.requireLoaded(handler.limitJoins(handler.decodeContentEncoding(handler.serveJoinJSON)))).Methods(http.MethodPost).HeadersRegexp(
+		"Content-Type", "application/json")
 	handler.router.HandleFunc(URLBaseV1Channels, handler.serveBadContentType).Methods(http.MethodPost)
 
+	// swagger:operation OPTIONS /v1/participation/channels channels channelsOptions
+	// ---
+	// summary: Returns the content types and the optional features supported by the channel participation API.
+	// responses:
+	//    '200':
+	//       description: Successfully retrieved the capabilities of the API.
+	//       schema:
+	//         "$ref": "#/definitions/apiCapabilities"
+	//       headers:
+	//        Content-Type:
+	//          description: The media type of the resource
+	//          type: string
+	//        Accept-Post:
+	//          description: The media types accepted when joining a channel
+	//          type: string
+	//        Accept-Encoding:
+	//          description: The content codings accepted when joining a channel
+	//          type: string
+	//        Allow:
+	//          description: The methods supported by the resource
+	//          type: string
+
+	handler.router.HandleFunc(URLBaseV1Channels, handler.serveOptions).Methods(http.MethodOptions)
+
 	handler.router.HandleFunc(URLBaseV1Channels, handler.serveNotAllowed)
 
+	// swagger:operation GET /v1/participation/status channels channelsSummary
+	// ---
+	// summary: Returns an aggregate summary of the channels an Ordering Service Node (OSN) has joined.
+	// responses:
+	//    '200':
+	//       description: Successfully retrieved the summary.
+	//       schema:
+	//         "$ref": "#/definitions/channelsSummary"
+	//       headers:
+	//        Content-Type:
+	//          description: The media type of the resource
+	//          type: string
+	//        Cache-Control:
+	//         description: The directives for caching responses
+	//         type: string
+
+	handler.router.HandleFunc(URLBaseV1Status, handler.serveStatus).Methods(http.MethodGet)
+	handler.router.HandleFunc(URLBaseV1Status, handler.serveNotAllowed)
+
+	// swagger:operation GET /v1/participation/metrics channels metrics
+	// ---
+	// summary: Returns the metrics of the channel participation API in the Prometheus text format.
+	// description: The joins and removes through the API, and the number of channels in each status. Only served when enabled by the config.
+	// produces:
+	//   - text/plain
+	// responses:
+	//    '200':
+	//       description: Successfully retrieved the metrics.
+	//    '404':
+	//       description: The metrics are disabled.
+
+	if handler.metrics != nil {
+		handler.router.Handle(URLBaseV1Metrics, handler.metrics.handler()).Methods(http.MethodGet)
+		handler.router.HandleFunc(URLBaseV1Metrics, handler.serveNotAllowed)
+	}
+
 	handler.router.HandleFunc(URLBaseV1, handler.redirectBaseV1).Methods(http.MethodGet)
 
 	return handler
@@ -191,124 +622,710 @@ func (h *HTTPHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if err := h.authorize(req); err != nil {
+		h.logger.Warnf("Client request not authorized, URL: %s, Method: %s, RemoteAddr: %s, err: %s", req.URL, req.Method, req.RemoteAddr, err)
+		h.sendResponseJsonError(resp, http.StatusForbidden, err)
+		return
+	}
+
 	h.router.ServeHTTP(resp, req)
 }
 
+// authorize checks the organization and organizational unit of the client certificate against the allowlists
+// of the config. Any client is authorized when the allowlists are empty.
+func (h *HTTPHandler) authorize(req *http.Request) error {
+	orgs := h.config.AuthorizedOrganizations
+	ous := h.config.AuthorizedOrganizationalUnits
+	if len(orgs) == 0 && len(ous) == 0 {
+		return nil
+	}
+
+	if req.TLS == nil || len(req.TLS.PeerCertificates) == 0 {
+		return errors.New("client certificate required")
+	}
+	subject := req.TLS.PeerCertificates[0].Subject
+	if len(orgs) > 0 && !containsAny(orgs, subject.Organization) {
+		return errors.Errorf("client certificate organization %v is not authorized", subject.Organization)
+	}
+	if len(ous) > 0 && !containsAny(ous, subject.OrganizationalUnit) {
+		return errors.Errorf("client certificate organizational unit %v is not authorized", subject.OrganizationalUnit)
+	}
+	return nil
+}
+
+// containsAny reports whether any of the values is in the allowed list.
+func containsAny(allowed, values []string) bool {
+	for _, v := range values {
+		for _, a := range allowed {
+			if v == a {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // List all channels
 func (h *HTTPHandler) serveListAll(resp http.ResponseWriter, req *http.Request) {
-	_, err := negotiateContentType(req) // Only application/json responses for now
+	contentType, err := negotiateListContentType(req)
 	if err != nil {
 		h.sendResponseJsonError(resp, http.StatusNotAcceptable, err)
 		return
 	}
-	channelList := h.registrar.ChannelList()
+	fields, err := h.parseFieldsFor(req, contentType, types.ChannelInfoShort{})
+	if err != nil {
+		h.sendResponseJsonError(resp, http.StatusBadRequest, err)
+		return
+	}
+	allChannels := h.registrar.ChannelList()
+	count := len(allChannels.Channels)
+	if allChannels.SystemChannel != nil {
+		count++
+	}
+	channelList := filterChannelList(allChannels, req.URL.Query().Get("prefix"))
+	if relation := req.URL.Query().Get("relation"); relation != "" {
+		consensusRelation, err := types.ParseConsensusRelation(relation)
+		if err != nil {
+			h.sendResponseJsonError(resp, http.StatusBadRequest, err)
+			return
+		}
+		channelList = h.filterChannelListByRelation(channelList, consensusRelation)
+	}
 	if channelList.SystemChannel != nil && channelList.SystemChannel.Name != "" {
 		channelList.SystemChannel.URL = path.Join(URLBaseV1Channels, channelList.SystemChannel.Name)
 	}
 	for i, info := range channelList.Channels {
 		channelList.Channels[i].URL = path.Join(URLBaseV1Channels, info.Name)
 	}
+	channelList.Count = count
+	channelList.Revision = allChannels.Revision
 	resp.Header().Set("Cache-Control", "no-store")
+	if contentType == protobufContentType {
+		h.sendResponseProtoOK(resp, channelListToProto(channelList))
+		return
+	}
+	if fields != nil {
+		projected, err := projectChannelList(channelList, fields)
+		if err != nil {
+			h.sendResponseJsonError(resp, http.StatusInternalServerError, err)
+			return
+		}
+		h.sendResponseOK(resp, projected)
+		return
+	}
 	h.sendResponseOK(resp, channelList)
 }
 
+// parseFieldsFor returns the fields of the model the response is to be reduced to, which is only supported for
+// JSON responses.
+func (h *HTTPHandler) parseFieldsFor(req *http.Request, contentType string, model interface{}) ([]string, error) {
+	fields, err := parseFields(req, model)
+	if err != nil {
+		return nil, err
+	}
+	if fields != nil && contentType == protobufContentType {
+		return nil, errors.New("the fields query parameter is only supported for JSON responses")
+	}
+	return fields, nil
+}
+
+// filterChannelList keeps only the channels whose names start with the prefix, the system channel included.
+// An empty prefix keeps all the channels.
+func filterChannelList(channelList types.ChannelList, prefix string) types.ChannelList {
+	if prefix == "" {
+		return channelList
+	}
+
+	filtered := types.ChannelList{Channels: []types.ChannelInfoShort{}}
+	if channelList.SystemChannel != nil && strings.HasPrefix(channelList.SystemChannel.Name, prefix) {
+		filtered.SystemChannel = channelList.SystemChannel
+	}
+	for _, info := range channelList.Channels {
+		if strings.HasPrefix(info.Name, prefix) {
+			filtered.Channels = append(filtered.Channels, info)
+		}
+	}
+	return filtered
+}
+
+// filterChannelListByRelation keeps only the channels, the system channel included, in which the orderer has the
+// consensus relation. A channel that is removed while filtering is left out.
+func (h *HTTPHandler) filterChannelListByRelation(channelList types.ChannelList, relation types.ConsensusRelation) types.ChannelList {
+	hasRelation := func(channelID string) bool {
+		info, err := h.registrar.ChannelInfo(channelID)
+		if err != nil {
+			h.logger.Debugf("Failed to get channel info for: %s, err: %s", channelID, err)
+			return false
+		}
+		return info.ConsensusRelation == relation
+	}
+
+	filtered := types.ChannelList{Channels: []types.ChannelInfoShort{}}
+	if channelList.SystemChannel != nil && hasRelation(channelList.SystemChannel.Name) {
+		filtered.SystemChannel = channelList.SystemChannel
+	}
+	for _, info := range channelList.Channels {
+		if hasRelation(info.Name) {
+			filtered.Channels = append(filtered.Channels, info)
+		}
+	}
+	return filtered
+}
+
 // List a single channel
 func (h *HTTPHandler) serveListOne(resp http.ResponseWriter, req *http.Request) {
-	_, err := negotiateContentType(req) // Only application/json responses for now
+	contentType, err := negotiateListContentType(req)
 	if err != nil {
 		h.sendResponseJsonError(resp, http.StatusNotAcceptable, err)
 		return
 	}
+	fields, err := h.parseFieldsFor(req, contentType, types.ChannelInfo{})
+	if err != nil {
+		h.sendResponseJsonError(resp, http.StatusBadRequest, err)
+		return
+	}
 
 	channelID, err := h.extractChannelID(req, resp)
 	if err != nil {
 		return
 	}
 
-	infoFull, err := h.registrar.ChannelInfo(channelID)
+	infoFull, err := h.channelInfo(channelID)
 	if err != nil {
 		h.sendResponseJsonError(resp, http.StatusNotFound, err)
 		return
 	}
 	infoFull.URL = path.Join(URLBaseV1Channels, infoFull.Name)
+	infoFull.OrdererEndpoint = h.config.OrdererEndpoint
+
+	if verbose, _ := strconv.ParseBool(req.URL.Query().Get("verbose")); verbose {
+		capabilities, err := h.configCache.capabilities(infoFull, func() (types.ChannelCapabilities, error) {
+			return h.registrar.ChannelCapabilities(channelID)
+		})
+		if err != nil {
+			// e.g. the channel is pending removal and has no config
+			h.logger.Debugf("Failed to get channel capabilities for: %s, err: %s", channelID, err)
+		} else {
+			infoFull.Capabilities = &capabilities
+		}
+		ledgerBytes, err := h.registrar.LedgerBytes(channelID)
+		if err != nil {
+			h.logger.Debugf("Failed to get ledger size for: %s, err: %s", channelID, err)
+		} else {
+			infoFull.LedgerBytes = &ledgerBytes
+		}
+	}
 
 	resp.Header().Set("Cache-Control", "no-store")
+	if contentType == protobufContentType {
+		h.sendResponseProtoOK(resp, channelInfoToProto(infoFull))
+		return
+	}
+	if fields != nil {
+		projected, err := project(infoFull, fields)
+		if err != nil {
+			h.sendResponseJsonError(resp, http.StatusInternalServerError, err)
+			return
+		}
+		h.sendResponseOK(resp, projected)
+		return
+	}
 	h.sendResponseOK(resp, infoFull)
 }
 
-func (h *HTTPHandler) redirectBaseV1(resp http.ResponseWriter, req *http.Request) {
-	http.Redirect(resp, req, URLBaseV1Channels, http.StatusFound)
-}
-
-// Join a channel.
-// Expect multipart/form-data.
-func (h *HTTPHandler) serveJoin(resp http.ResponseWriter, req *http.Request) {
-	_, err := negotiateContentType(req) // Only application/json responses for now
-	if err != nil {
-		h.sendResponseJsonError(resp, http.StatusNotAcceptable, err)
+// Get the block a channel was joined with
+func (h *HTTPHandler) serveJoinBlock(resp http.ResponseWriter, req *http.Request) {
+	if !accepts(req, "application/octet-stream") {
+		h.sendResponseJsonError(resp, http.StatusNotAcceptable, errors.New("response Content-Type is application/octet-stream only"))
 		return
 	}
 
-	_, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	channelID, err := h.extractChannelID(req, resp)
 	if err != nil {
-		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Wrap(err, "cannot parse Mime media type"))
 		return
 	}
 
-	block := h.multipartFormDataBodyToBlock(params, req, resp)
-	if block == nil {
+	blockBytes, err := h.registrar.JoinBlock(channelID)
+	switch err {
+	case nil:
+	case types.ErrChannelNotExist, types.ErrJoinBlockNotExist:
+		h.sendResponseJsonError(resp, http.StatusNotFound, err)
+		return
+	case types.ErrChannelPendingRemoval:
+		h.sendResponseJsonError(resp, http.StatusConflict, err)
+		return
+	default:
+		h.sendResponseJsonError(resp, http.StatusInternalServerError, err)
 		return
 	}
 
-	channelID, isAppChannel, err := ValidateJoinBlock(block)
-	if err != nil {
-		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.WithMessage(err, "invalid join block"))
-		return
+	resp.Header().Set("Content-Type", "application/octet-stream")
+	resp.Header().Set("Cache-Control", "no-store")
+	resp.WriteHeader(http.StatusOK)
+	if _, err := resp.Write(blockBytes); err != nil {
+		h.logger.Errorf("failed to write join block, err: %s", err)
 	}
+}
 
-	info, err := h.registrar.JoinChannel(channelID, block, isAppChannel)
+// accepts reports whether the request accepts a response of the media type.
+func accepts(req *http.Request, mediaType string) bool {
+	acceptReq := req.Header.Get("Accept")
+	if len(acceptReq) == 0 {
+		return true
+	}
+
+	mediaRange := strings.SplitN(mediaType, "/", 2)[0] + "/*"
+	for _, opt := range strings.Split(acceptReq, ",") {
+		if strings.Contains(opt, mediaType) ||
+			strings.Contains(opt, mediaRange) ||
+			strings.Contains(opt, "*/*") {
+			return true
+		}
+	}
+	return false
+}
+
+// Summarize all channels
+func (h *HTTPHandler) serveStatus(resp http.ResponseWriter, req *http.Request) {
+	_, err := negotiateContentType(req) // Only application/json responses for now
 	if err != nil {
-		h.sendJoinError(err, resp)
+		h.sendResponseJsonError(resp, http.StatusNotAcceptable, err)
 		return
 	}
-	info.URL = path.Join(URLBaseV1Channels, info.Name)
 
-	h.logger.Debugf("Successfully joined channel: %s", info.URL)
-	h.sendResponseCreated(resp, info.URL, info)
+	resp.Header().Set("Cache-Control", "no-store")
+	h.sendResponseOK(resp, h.channelsSummary())
 }
 
-// Expect a multipart/form-data with a single part, of type file, with key FormDataConfigBlockKey.
+// channelsSummary counts the channels in each status.
+func (h *HTTPHandler) channelsSummary() types.ChannelsSummary {
+	channelList := h.registrar.ChannelList()
+	summary := types.ChannelsSummary{
+		Statuses: make(map[types.Status]int),
+	}
+	var names []string
+	if channelList.SystemChannel != nil && channelList.SystemChannel.Name != "" {
+		summary.SystemChannel = true
+		names = append(names, channelList.SystemChannel.Name)
+	}
+	for _, info := range channelList.Channels {
+		names = append(names, info.Name)
+	}
+	for _, name := range names {
+		info, err := h.channelInfo(name)
+		if err != nil {
+			// the channel was removed after it was listed
+			h.logger.Debugf("Failed to get channel info for: %s, err: %s", name, err)
+			continue
+		}
+		summary.Total++
+		summary.Statuses[info.Status]++
+	}
+	// the channels listed while the orderer is loading may be incomplete
+	summary.Ready = !h.registrar.Loading() && summary.Statuses[types.StatusOnBoarding] == 0 && summary.Statuses[types.StatusFailed] == 0
+
+	return summary
+}
+
+// requireLoaded rejects a request with 503 while the orderer is still loading its channels after a restart,
+// so that clients retry instead of acting on an incomplete view of the channels.
+func (h *HTTPHandler) requireLoaded(next http.HandlerFunc) http.HandlerFunc {
+	return func(resp http.ResponseWriter, req *http.Request) {
+		if h.registrar.Loading() {
+			resp.Header().Set("Retry-After", loadingRetryAfter)
+			h.sendResponseJsonError(resp, http.StatusServiceUnavailable, errors.New("the orderer is still loading its channels"))
+			return
+		}
+		next(resp, req)
+	}
+}
+
+// checkDiskSpace rejects a join with 507 when the free disk space is below the configured minimum, so that a channel
+// is not created only to fail writing its blocks. The join proceeds when the free disk space cannot be checked.
+func (h *HTTPHandler) checkDiskSpace(resp http.ResponseWriter) bool {
+	if h.config.MinFreeDiskSpace == 0 {
+		return true
+	}
+	free, err := h.registrar.FreeDiskSpace()
+	if err != nil {
+		h.logger.Warningf("Failed to check the free disk space, joining anyway: %s", err)
+		return true
+	}
+	if free < h.config.MinFreeDiskSpace {
+		h.sendResponseJsonError(resp, http.StatusInsufficientStorage,
+			errors.Errorf("insufficient disk space: %d bytes free, the minimum to join a channel is %d bytes", free, h.config.MinFreeDiskSpace))
+		return false
+	}
+	return true
+}
+
+// checkMaxChannels rejects a join with 507 when the orderer already has the configured maximum number of channels,
+// counting the system channel. A join of a channel that already exists is let through, so that it fails, or is
+// served idempotently, as without the limit.
+func (h *HTTPHandler) checkMaxChannels(resp http.ResponseWriter, channelID string) bool {
+	list := h.registrar.ChannelList()
+	count := len(list.Channels)
+	if list.SystemChannel != nil {
+		count++
+		if list.SystemChannel.Name == channelID {
+			return true
+		}
+	}
+	for _, channel := range list.Channels {
+		if channel.Name == channelID {
+			return true
+		}
+	}
+	if count >= int(h.config.MaxChannels) {
+		h.sendResponseJsonError(resp, http.StatusInsufficientStorage,
+			errors.Errorf("maximum number of channels reached: the orderer has %d channels, the maximum is %d", count, h.config.MaxChannels))
+		return false
+	}
+	return true
+}
+
+// joinSlotKey is the context key of the slot taken by a request that limitJoins let through.
+type joinSlotKey struct{}
+
+// joinSlot is the slot of a join or remove request, released once the request is served unless it was handed over
+// to an operation that outlives the request.
+type joinSlot struct {
+	handedOver bool
+}
+
+// limitJoins rejects a request with 429 when the maximum number of concurrent join and remove
+// operations is already in progress.
+func (h *HTTPHandler) limitJoins(next http.HandlerFunc) http.HandlerFunc {
+	if h.joinSlots == nil {
+		return next
+	}
+
+	return func(resp http.ResponseWriter, req *http.Request) {
+		select {
+		case h.joinSlots <- struct{}{}:
+			slot := &joinSlot{}
+			defer func() {
+				if !slot.handedOver {
+					<-h.joinSlots
+				}
+			}()
+			next(resp, req.WithContext(context.WithValue(req.Context(), joinSlotKey{}, slot)))
+		default:
+			h.sendResponseJsonError(resp, http.StatusTooManyRequests, errors.New("too many concurrent join or remove requests"))
+		}
+	}
+}
+
+// takeJoinSlot hands the slot of the request over to an operation that outlives the request, which must call the
+// returned function once it is over.
+func (h *HTTPHandler) takeJoinSlot(req *http.Request) (release func()) {
+	slot, ok := req.Context().Value(joinSlotKey{}).(*joinSlot)
+	if !ok {
+		return func() {}
+	}
+	slot.handedOver = true
+	return func() { <-h.joinSlots }
+}
+
+// decodeContentEncoding replaces the body of a gzip encoded request with a reader of the decompressed body,
+// so that MaxRequestBodySize applies to the decompressed size. Other encodings are rejected.
+func (h *HTTPHandler) decodeContentEncoding(next http.HandlerFunc) http.HandlerFunc {
+	return func(resp http.ResponseWriter, req *http.Request) {
+		switch encoding := req.Header.Get("Content-Encoding"); encoding {
+		case "", "identity":
+		case "gzip":
+			body, err := gzip.NewReader(req.Body)
+			if err != nil {
+				h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Wrap(err, "cannot decode gzip request body"))
+				return
+			}
+			defer body.Close()
+			req.Body = body
+		default:
+			h.sendResponseJsonError(resp, http.StatusUnsupportedMediaType, errors.Errorf("unsupported Content-Encoding: %s", encoding))
+			return
+		}
+
+		next(resp, req)
+	}
+}
+
+// MaxRequestBodySize returns the current limit on the size of request bodies.
+func (h *HTTPHandler) MaxRequestBodySize() uint32 {
+	return atomic.LoadUint32(&h.maxRequestBodySize)
+}
+
+// SetMaxRequestBodySize changes the limit on the size of request bodies, e.g. when the configuration is reloaded,
+// so that it can be raised before a large join without restarting the orderer. Requests that are already reading
+// their body keep the limit they started with.
+func (h *HTTPHandler) SetMaxRequestBodySize(size uint32) {
+	old := atomic.SwapUint32(&h.maxRequestBodySize, size)
+	if old != size {
+		h.logger.Infof("MaxRequestBodySize changed from %d to %d bytes", old, size)
+	}
+}
+
+func (h *HTTPHandler) redirectBaseV1(resp http.ResponseWriter, req *http.Request) {
+	http.Redirect(resp, req, URLBaseV1Channels, http.StatusFound)
+}
+
+// Join a channel.
+// Expect multipart/form-data.
+func (h *HTTPHandler) serveJoin(resp http.ResponseWriter, req *http.Request) {
+	_, err := negotiateContentType(req) // Only application/json responses for now
+	if err != nil {
+		h.sendResponseJsonError(resp, http.StatusNotAcceptable, err)
+		return
+	}
+
+	_, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Wrap(err, "cannot parse Mime media type"))
+		return
+	}
+
+	block := h.multipartFormDataBodyToBlock(params, req, resp)
+	if block == nil {
+		return
+	}
+
+	h.joinWithBlock(resp, req, block)
+}
+
+// Join a channel with a JSON body.
+// Expect a types.JoinRequest carrying the base64 encoded config block.
+func (h *HTTPHandler) serveJoinJSON(resp http.ResponseWriter, req *http.Request) {
+	_, err := negotiateContentType(req) // Only application/json responses for now
+	if err != nil {
+		h.sendResponseJsonError(resp, http.StatusNotAcceptable, err)
+		return
+	}
+
+	block, err := h.jsonBodyToBlock(resp, req)
+	if err != nil {
+		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.WithMessage(err, "invalid join request"))
+		return
+	}
+
+	h.joinWithBlock(resp, req, block)
+}
+
+// Join a channel with a config block that was read from the request body.
+func (h *HTTPHandler) joinWithBlock(resp http.ResponseWriter, req *http.Request, block *cb.Block) {
+	// A corrupted block is rejected before its content is looked at, as it would fail later when written to the ledger.
+	if err := ValidateBlockDataHash(block); err != nil {
+		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.WithMessage(err, "invalid join block"))
+		return
+	}
+
+	// An orderer that joins a channel it cannot process would be stuck onboarding.
+	channelID, isAppChannel, err := ValidateJoinBlock(block)
+	if err == nil {
+		err = ValidateJoinBlockCapabilities(block)
+	}
+	if err != nil {
+		h.sendJoinBlockErrors(resp, block)
+		return
+	}
+	if err := ValidateJoinBlockConsenters(block); err != nil {
+		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.WithMessage(err, "invalid join block"))
+		return
+	}
+
+	if !h.checkDiskSpace(resp) {
+		return
+	}
+
+	defer h.channelLocks.lock(channelID)()
+
+	if h.config.MaxChannels > 0 {
+		h.joinCountLock.Lock()
+		defer h.joinCountLock.Unlock()
+		if !h.checkMaxChannels(resp, channelID) {
+			return
+		}
+	}
+
+	if dryRun(req) {
+		h.validateJoin(resp, req, channelID, block, isAppChannel)
+		return
+	}
+
+	info, err := h.registrar.JoinChannel(channelID, block, isAppChannel)
+	if err == types.ErrChannelAlreadyExists && joinIfNotExists(req) {
+		h.serveExistingChannel(resp, channelID)
+		return
+	}
+	if err != nil {
+		h.sendJoinError(err, resp)
+		return
+	}
+	info.URL = path.Join(URLBaseV1Channels, info.Name)
+	info.OrdererEndpoint = h.config.OrdererEndpoint
+	info.LastModifiedBy = clientSubject(req)
+
+	h.logger.Debugf("Successfully joined channel: %s", info.URL)
+	h.logModification(req, "joined", channelID)
+	// a channel removed outside of the API may leave an entry behind
+	h.configCache.remove(channelID)
+	h.removals.done(channelID)
+	h.modifiers.set(channelID, info.LastModifiedBy)
+	h.notify(types.ChannelEventJoin, info)
+	h.sendResponseCreated(resp, info.URL, info)
+}
+
+// validateJoin responds to a dry-run join with 200 and the information the channel would have once joined, or with
+// the error the join would fail with, without creating the channel.
+func (h *HTTPHandler) validateJoin(resp http.ResponseWriter, req *http.Request, channelID string, block *cb.Block, isAppChannel bool) {
+	info, err := h.registrar.ValidateJoin(channelID, block, isAppChannel)
+	if err == types.ErrChannelAlreadyExists && joinIfNotExists(req) {
+		h.serveExistingChannel(resp, channelID)
+		return
+	}
+	if err != nil {
+		h.sendJoinError(err, resp)
+		return
+	}
+	info.URL = path.Join(URLBaseV1Channels, info.Name)
+	info.OrdererEndpoint = h.config.OrdererEndpoint
+
+	h.logger.Debugf("Validated join of channel: %s", info.URL)
+	h.sendResponseOK(resp, info)
+}
+
+// jsonBodyToBlock decodes a types.JoinRequest. Errors name the offending field, e.g. "configBlock: invalid base64".
+func (h *HTTPHandler) jsonBodyToBlock(resp http.ResponseWriter, req *http.Request) (*cb.Block, error) {
+	decoder := json.NewDecoder(http.MaxBytesReader(resp, req.Body, int64(h.MaxRequestBodySize())))
+	decoder.DisallowUnknownFields()
+	joinReq := types.JoinRequest{}
+	if err := decoder.Decode(&joinReq); err != nil {
+		if typeErr, ok := err.(*json.UnmarshalTypeError); ok {
+			return nil, errors.Errorf("%s: expected %s, got %s", typeErr.Field, typeErr.Type, typeErr.Value)
+		}
+		return nil, errors.Wrap(err, "cannot decode request body")
+	}
+
+	if joinReq.ConfigBlock == "" {
+		return nil, errors.New("configBlock: missing")
+	}
+	blockBytes, err := base64.StdEncoding.DecodeString(joinReq.ConfigBlock)
+	if err != nil {
+		return nil, errors.Wrap(err, "configBlock: invalid base64")
+	}
+	block := &cb.Block{}
+	if err := proto.Unmarshal(blockBytes, block); err != nil {
+		return nil, errors.Wrap(err, "configBlock: cannot unmarshal into a block")
+	}
+
+	return block, nil
+}
+
+// Respond to an idempotent join of a channel that already exists with the info of that channel.
+func (h *HTTPHandler) serveExistingChannel(resp http.ResponseWriter, channelID string) {
+	info, err := h.channelInfo(channelID)
+	if err != nil {
+		h.sendJoinError(err, resp)
+		return
+	}
+	info.URL = path.Join(URLBaseV1Channels, info.Name)
+	info.OrdererEndpoint = h.config.OrdererEndpoint
+
+	h.logger.Debugf("Channel already joined: %s", info.URL)
+	h.sendResponseOK(resp, info)
+}
+
+// preferReturnRepresentation reports whether the client prefers a response with the representation of the resource,
+// with the Prefer header or the return query parameter.
+func preferReturnRepresentation(req *http.Request) bool {
+	for _, prefer := range req.Header.Values(PreferHeader) {
+		for _, preference := range strings.Split(prefer, ",") {
+			if strings.EqualFold(strings.TrimSpace(preference), "return="+returnRepresentation) {
+				return true
+			}
+		}
+	}
+	return req.URL.Query().Get("return") == returnRepresentation
+}
+
+// preferRespondAsync reports whether the client prefers a response before the request is processed, with the Prefer
+// header or the async query parameter.
+func preferRespondAsync(req *http.Request) bool {
+	for _, prefer := range req.Header.Values(PreferHeader) {
+		for _, preference := range strings.Split(prefer, ",") {
+			if strings.EqualFold(strings.TrimSpace(preference), respondAsync) {
+				return true
+			}
+		}
+	}
+	async, _ := strconv.ParseBool(req.URL.Query().Get("async"))
+	return async
+}
+
+// dryRun reports whether the client asked to only validate a join, with the dryRun query parameter.
+func dryRun(req *http.Request) bool {
+	dryRun, _ := strconv.ParseBool(req.URL.Query().Get("dryRun"))
+	return dryRun
+}
+
+// joinIfNotExists reports whether the client asked for an idempotent join, either with the IfNotExistsHeader
+// header or with the ifNotExists query parameter.
+func joinIfNotExists(req *http.Request) bool {
+	if ifNotExists, _ := strconv.ParseBool(req.Header.Get(IfNotExistsHeader)); ifNotExists {
+		return true
+	}
+	ifNotExists, _ := strconv.ParseBool(req.URL.Query().Get("ifNotExists"))
+	return ifNotExists
+}
+
+// Expect a multipart/form-data with a single part, of type file, with key FormDataConfigBlockKey.
 func (h *HTTPHandler) multipartFormDataBodyToBlock(params map[string]string, req *http.Request, resp http.ResponseWriter) *cb.Block {
 	boundary := params["boundary"]
 	reader := multipart.NewReader(
-		http.MaxBytesReader(resp, req.Body, int64(h.config.MaxRequestBodySize)),
+		http.MaxBytesReader(resp, req.Body, int64(h.MaxRequestBodySize())),
 		boundary,
 	)
-	form, err := reader.ReadForm(2 * int64(h.config.MaxRequestBodySize))
+	maxMemory := 2 * int64(h.MaxRequestBodySize())
+	if h.config.SpoolThreshold > 0 {
+		// File parts larger than the threshold are spooled to a temporary file instead of being buffered in memory
+		// with the rest of the form.
+		maxMemory = int64(h.config.SpoolThreshold)
+	}
+	form, err := reader.ReadForm(maxMemory)
 	if err != nil {
 		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Wrap(err, "cannot read form from request body"))
 		return nil
 	}
+	defer func() {
+		if err := form.RemoveAll(); err != nil {
+			h.logger.Warnf("Failed to remove the temporary files of the join request: %s", err)
+		}
+	}()
 
-	if _, exist := form.File[FormDataConfigBlockKey]; !exist {
+	fileHeaders, exist := form.File[FormDataConfigBlockKey]
+	if !exist {
 		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Errorf("form does not contains part key: %s", FormDataConfigBlockKey))
 		return nil
 	}
 
-	if len(form.File) != 1 || len(form.Value) != 0 {
+	// Any part other than a single file part with key FormDataConfigBlockKey is rejected, including repeated
+	// parts with that key, so that the block that is joined never depends on the order of the parts.
+	if len(form.File) != 1 || len(form.Value) != 0 || len(fileHeaders) != 1 {
 		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.New("form contains too many parts"))
 		return nil
 	}
 
-	fileHeader := form.File[FormDataConfigBlockKey][0]
+	fileHeader := fileHeaders[0]
 	file, err := fileHeader.Open()
 	if err != nil {
 		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Wrapf(err, "cannot open file part %s from request body", FormDataConfigBlockKey))
 		return nil
 	}
+	defer file.Close()
 
-	blockBytes, err := ioutil.ReadAll(file)
-	if err != nil {
+	// A block cannot be unmarshaled from a stream, so a spooled file part is still read into memory once, into a
+	// buffer of its exact size. Spooling only bounds the memory used while the form is read.
+	blockBytes := make([]byte, fileHeader.Size)
+	if _, err := io.ReadFull(file, blockBytes); err != nil {
 		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Wrapf(err, "cannot read file part %s from request body", FormDataConfigBlockKey))
 		return nil
 	}
@@ -376,9 +1393,65 @@ func (h *HTTPHandler) serveRemove(resp http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	// the checks below must not race with a join of the channel
+	defer h.channelLocks.lock(channelID)()
+
+	if h.removals.removing(channelID) {
+		h.sendResponseJsonError(resp, http.StatusConflict, errors.WithMessage(types.ErrChannelPendingRemoval, "cannot remove"))
+		return
+	}
+
+	if h.config.ProtectConsenters {
+		if force, _ := strconv.ParseBool(req.URL.Query().Get("force")); !force && h.isOrderingFor(channelID) {
+			h.sendResponseJsonError(resp, http.StatusConflict,
+				errors.Errorf("cannot remove: this orderer is an active consenter of channel %s, use force=true to remove it anyway", channelID))
+			return
+		}
+	}
+
+	if h.config.ProtectSoleConsenter {
+		if force, _ := strconv.ParseBool(req.URL.Query().Get("force")); !force && h.isSoleConsenterOf(channelID) {
+			h.sendResponseJsonError(resp, http.StatusConflict,
+				errors.Errorf("cannot remove: this orderer is the sole consenter of channel %s, use force=true to remove it anyway", channelID))
+			return
+		}
+	}
+
+	if preferRespondAsync(req) {
+		h.acceptRemove(resp, req, channelID)
+		return
+	}
+
+	// the channel information is gone once the channel is removed
+	var finalInfo *types.ChannelInfo
+	if preferReturnRepresentation(req) {
+		info, err := h.registrar.ChannelInfo(channelID)
+		if err != nil {
+			h.logger.Debugf("Failed to get channel info for: %s, err: %s", channelID, err)
+			info = types.ChannelInfo{Name: channelID}
+		}
+		info.URL = path.Join(URLBaseV1Channels, channelID)
+		info.OrdererEndpoint = h.config.OrdererEndpoint
+		info.LastModifiedBy = clientSubject(req)
+		finalInfo = &info
+	}
+
 	err = h.registrar.RemoveChannel(channelID)
 	if err == nil {
 		h.logger.Debugf("Successfully removed channel: %s", channelID)
+		h.logModification(req, "removed", channelID)
+		h.configCache.remove(channelID)
+		h.modifiers.remove(channelID)
+		h.notify(types.ChannelEventRemove, types.ChannelInfo{
+			Name:           channelID,
+			URL:            path.Join(URLBaseV1Channels, channelID),
+			LastModifiedBy: clientSubject(req),
+		})
+		if finalInfo != nil {
+			resp.Header().Set("Preference-Applied", "return="+returnRepresentation)
+			h.sendResponseOK(resp, finalInfo)
+			return
+		}
 		resp.WriteHeader(http.StatusNoContent)
 		return
 	}
@@ -397,6 +1470,315 @@ func (h *HTTPHandler) serveRemove(resp http.ResponseWriter, req *http.Request) {
 	}
 }
 
+// acceptRemove responds with 202 Accepted and removes the channel in the background, so that the removal of a channel
+// with a large ledger does not time out the client. The checks that RemoveChannel would fail are done before the
+// removal is accepted, and the channel is reported with the removing status until it is gone. The removal keeps the
+// slot of the request until it is over, so that it counts against MaxConcurrentJoins.
+func (h *HTTPHandler) acceptRemove(resp http.ResponseWriter, req *http.Request, channelID string) {
+	if channelList := h.registrar.ChannelList(); channelList.SystemChannel != nil && channelList.SystemChannel.Name != channelID {
+		h.sendResponseNotAllowed(resp, errors.WithMessage(types.ErrSystemChannelExists, "cannot remove"), http.MethodGet)
+		return
+	}
+	info, err := h.registrar.ChannelInfo(channelID)
+	if err != nil {
+		h.sendResponseJsonError(resp, http.StatusNotFound, errors.WithMessage(err, "cannot remove"))
+		return
+	}
+	if !h.removals.start(channelID) {
+		h.sendResponseJsonError(resp, http.StatusConflict, errors.WithMessage(types.ErrChannelPendingRemoval, "cannot remove"))
+		return
+	}
+
+	subject := clientSubject(req)
+	h.modifiers.set(channelID, subject)
+	go h.removeAsync(channelID, subject, h.takeJoinSlot(req))
+
+	info.Status = types.StatusRemoving
+	info.URL = path.Join(URLBaseV1Channels, channelID)
+	info.OrdererEndpoint = h.config.OrdererEndpoint
+	info.LastModifiedBy = subject
+	h.logger.Debugf("Accepted removal of channel: %s", channelID)
+	h.logModification(req, "removal accepted", channelID)
+
+	resp.Header().Set("Location", info.URL)
+	resp.Header().Set("Preference-Applied", respondAsync)
+	encoder := json.NewEncoder(resp)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusAccepted)
+	if err := encoder.Encode(info); err != nil {
+		h.logger.Errorf("failed to encode content, err: %s", err)
+	}
+}
+
+// removeAsync removes a channel whose removal was accepted from the client with the subject, and releases the slot
+// of the removal once it is over. As the registrar releases the ledger of the channel in the background once
+// RemoveChannel returns, the removal is over once the channel is gone, or once its ledger failed to be removed. The
+// wait is bounded by RemovalTimeout, so that a ledger that is never released does not hold the slot for good.
+func (h *HTTPHandler) removeAsync(channelID, subject string, release func()) {
+	defer release()
+
+	if err := h.removeChannelAsync(channelID, subject); err != nil {
+		h.logger.Warningf("Failed to remove channel: %s, err: %s", channelID, err)
+		h.removals.done(channelID)
+		return
+	}
+
+	timeout := h.config.RemovalTimeout
+	if timeout <= 0 {
+		timeout = defaultRemovalTimeout
+	}
+	deadline := time.Now().Add(timeout)
+	for !h.ledgerReleased(channelID) {
+		if time.Now().After(deadline) {
+			h.logger.Warningf("The ledger of channel: %s is not released after %s, the channel is no longer reported as removing", channelID, timeout)
+			h.removals.done(channelID)
+			return
+		}
+		time.Sleep(removalPollInterval)
+	}
+}
+
+// removeChannelAsync calls RemoveChannel for a removal accepted from the client with the subject.
+func (h *HTTPHandler) removeChannelAsync(channelID, subject string) error {
+	defer h.channelLocks.lock(channelID)()
+
+	if err := h.registrar.RemoveChannel(channelID); err != nil {
+		return err
+	}
+	h.logger.Debugf("Successfully removed channel: %s", channelID)
+	h.configCache.remove(channelID)
+	h.notify(types.ChannelEventRemove, types.ChannelInfo{
+		Name:           channelID,
+		URL:            path.Join(URLBaseV1Channels, channelID),
+		LastModifiedBy: subject,
+	})
+	return nil
+}
+
+// ledgerReleased reports whether the ledger of a channel removed asynchronously is released, and clears the removing
+// status of the channel once it is. A channel joined again in the meantime ends the removal too.
+func (h *HTTPHandler) ledgerReleased(channelID string) bool {
+	defer h.channelLocks.lock(channelID)()
+
+	if !h.removals.removing(channelID) {
+		return true
+	}
+	info, err := h.registrar.ChannelInfo(channelID)
+	switch {
+	case err == types.ErrChannelNotExist:
+		h.modifiers.remove(channelID)
+	case err == nil && info.Status == types.StatusFailed:
+		h.logger.Warningf("Failed to release the ledger of channel: %s", channelID)
+	default:
+		return false
+	}
+	h.removals.done(channelID)
+	return true
+}
+
+// channelInfo returns the information of a channel, with the removing status while it is removed asynchronously,
+// and the identity of the client that last joined or removed it. A ledger that failed to be removed is reported as is.
+func (h *HTTPHandler) channelInfo(channelID string) (types.ChannelInfo, error) {
+	info, err := h.registrar.ChannelInfo(channelID)
+	if err == nil {
+		info.LastModifiedBy = h.modifiers.get(channelID)
+		if info.Status != types.StatusFailed && h.removals.removing(channelID) {
+			info.Status = types.StatusRemoving
+		}
+	}
+	return info, err
+}
+
+// logModification logs a join or remove of a channel with the identity of the client, for audit trails.
+func (h *HTTPHandler) logModification(req *http.Request, operation, channelID string) {
+	subject := clientSubject(req)
+	if subject == "" {
+		subject = "unknown, no client certificate"
+	}
+	h.logger.Infof("Channel %s %s, client: %s, RemoteAddr: %s", channelID, operation, subject, req.RemoteAddr)
+}
+
+// isOrderingFor reports whether the orderer takes part in ordering a channel, that is, whether it is a consenter
+// or runs a non-cluster consensus type. Channels that cannot be found are left to RemoveChannel to report.
+func (h *HTTPHandler) isOrderingFor(channelID string) bool {
+	info, err := h.registrar.ChannelInfo(channelID)
+	if err != nil {
+		return false
+	}
+	return info.ConsensusRelation == types.ConsensusRelationConsenter || info.ConsensusRelation == types.ConsensusRelationOther
+}
+
+// isSoleConsenterOf reports whether the orderer is the only consenter of a channel. Channels that cannot be found,
+// or whose consenter set cannot be read, are left to RemoveChannel.
+func (h *HTTPHandler) isSoleConsenterOf(channelID string) bool {
+	info, err := h.registrar.ChannelInfo(channelID)
+	if err != nil || info.ConsensusRelation != types.ConsensusRelationConsenter {
+		return false
+	}
+	count, err := h.configCache.consenterCount(info, func() (int, error) {
+		return h.registrar.ConsenterCount(channelID)
+	})
+	if err != nil {
+		h.logger.Debugf("Failed to get the consenter count of channel: %s, err: %s", channelID, err)
+		return false
+	}
+	return count == 1
+}
+
+// serveComputeConfigUpdate responds with the unsigned config update that applies a patch to a channel, for the client
+// to sign and submit with PATCH.
+func (h *HTTPHandler) serveComputeConfigUpdate(resp http.ResponseWriter, req *http.Request) {
+	if !accepts(req, "application/octet-stream") {
+		h.sendResponseJsonError(resp, http.StatusNotAcceptable, errors.New("response Content-Type is application/octet-stream only"))
+		return
+	}
+
+	channelID, err := h.extractChannelID(req, resp)
+	if err != nil {
+		return
+	}
+
+	decoder := json.NewDecoder(http.MaxBytesReader(resp, req.Body, int64(h.MaxRequestBodySize())))
+	decoder.DisallowUnknownFields()
+	patch := types.ChannelConfigPatch{}
+	if err := decoder.Decode(&patch); err != nil {
+		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Wrap(err, "cannot decode config patch"))
+		return
+	}
+	if err := validateConfigPatch(patch); err != nil {
+		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.WithMessage(err, "invalid config patch"))
+		return
+	}
+
+	configUpdate, err := h.registrar.ComputeConfigUpdate(channelID, patch)
+	if err != nil {
+		h.logger.Debugf("Failed to compute config update: %s, err: %s", channelID, err)
+		h.sendUpdateConfigError(err, resp)
+		return
+	}
+	configUpdateBytes, err := proto.Marshal(configUpdate)
+	if err != nil {
+		h.sendResponseJsonError(resp, http.StatusInternalServerError, errors.Wrap(err, "cannot marshal config update"))
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/octet-stream")
+	resp.Header().Set("Cache-Control", "no-store")
+	resp.WriteHeader(http.StatusOK)
+	if _, err := resp.Write(configUpdateBytes); err != nil {
+		h.logger.Errorf("failed to write config update, err: %s", err)
+	}
+}
+
+// serveUpdateConfig submits a config update signed by the client to a channel.
+func (h *HTTPHandler) serveUpdateConfig(resp http.ResponseWriter, req *http.Request) {
+	_, err := negotiateContentType(req) // Only application/json responses for now
+	if err != nil {
+		h.sendResponseJsonError(resp, http.StatusNotAcceptable, err)
+		return
+	}
+
+	channelID, err := h.extractChannelID(req, resp)
+	if err != nil {
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(resp, req.Body, int64(h.MaxRequestBodySize())))
+	if err != nil {
+		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Wrap(err, "cannot read config update envelope"))
+		return
+	}
+	configUpdateEnv := &cb.ConfigUpdateEnvelope{}
+	if err := proto.Unmarshal(body, configUpdateEnv); err != nil {
+		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Wrap(err, "cannot unmarshal config update envelope"))
+		return
+	}
+	if len(configUpdateEnv.ConfigUpdate) == 0 {
+		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.New("config update envelope carries no config update"))
+		return
+	}
+	if len(configUpdateEnv.Signatures) == 0 {
+		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.New("config update envelope is not signed"))
+		return
+	}
+
+	defer h.channelLocks.lock(channelID)()
+	err = h.registrar.UpdateChannelConfig(channelID, configUpdateEnv)
+	if err == nil {
+		h.logger.Debugf("Successfully submitted config update for channel: %s", channelID)
+		h.logModification(req, "config updated", channelID)
+		resp.Header().Set("Location", path.Join(URLBaseV1Channels, channelID))
+		resp.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	h.logger.Debugf("Failed to update channel config: %s, err: %s", channelID, err)
+	h.sendUpdateConfigError(err, resp)
+}
+
+func (h *HTTPHandler) sendUpdateConfigError(err error, resp http.ResponseWriter) {
+	switch err {
+	case types.ErrChannelNotExist:
+		h.sendResponseJsonError(resp, http.StatusNotFound, errors.WithMessage(err, "cannot update"))
+	case types.ErrChannelPendingRemoval, types.ErrConsensusStateUnchanged:
+		h.sendResponseJsonError(resp, http.StatusConflict, errors.WithMessage(err, "cannot update"))
+	default:
+		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.WithMessage(err, "cannot update"))
+	}
+}
+
+func validateConfigPatch(patch types.ChannelConfigPatch) error {
+	if patch == (types.ChannelConfigPatch{}) {
+		return errors.New("no fields to update")
+	}
+	if patch.ConsensusState != nil {
+		if *patch.ConsensusState != types.ConsensusStateNormal && *patch.ConsensusState != types.ConsensusStateMaintenance {
+			return errors.Errorf("unknown consensusState: %s", *patch.ConsensusState)
+		}
+		if patch != (types.ChannelConfigPatch{ConsensusState: patch.ConsensusState}) {
+			return errors.New("consensusState cannot be combined with other fields")
+		}
+	}
+	if patch.BatchTimeout != nil {
+		timeout, err := time.ParseDuration(*patch.BatchTimeout)
+		if err != nil {
+			return errors.Wrap(err, "invalid batchTimeout")
+		}
+		if timeout <= 0 {
+			return errors.Errorf("batchTimeout must be positive: %s", *patch.BatchTimeout)
+		}
+	}
+	if patch.MaxMessageCount != nil && *patch.MaxMessageCount == 0 {
+		return errors.New("maxMessageCount must be positive")
+	}
+	if patch.AbsoluteMaxBytes != nil && *patch.AbsoluteMaxBytes == 0 {
+		return errors.New("absoluteMaxBytes must be positive")
+	}
+	if patch.PreferredMaxBytes != nil && *patch.PreferredMaxBytes == 0 {
+		return errors.New("preferredMaxBytes must be positive")
+	}
+	return nil
+}
+
+// RelationChanged notifies the webhook that the consensus relation of a channel changed, e.g. when a follower became a
+// consenter. It is meant to be registered with the registrar, since such changes are not made through the API.
+func (h *HTTPHandler) RelationChanged(info types.ChannelInfo) {
+	info.URL = path.Join(URLBaseV1Channels, info.Name)
+	info.OrdererEndpoint = h.config.OrdererEndpoint
+	h.logger.Debugf("Consensus relation of channel %s changed to %s", info.Name, info.ConsensusRelation)
+	h.notify(types.ChannelEventRelation, info)
+}
+
+func (h *HTTPHandler) notify(eventType string, info types.ChannelInfo) {
+	if h.metrics != nil {
+		h.metrics.observe(eventType)
+	}
+	if h.webhook == nil {
+		return
+	}
+	h.webhook.notify(types.ChannelEvent{Type: eventType, Channel: info})
+}
+
 func (h *HTTPHandler) serveBadContentType(resp http.ResponseWriter, req *http.Request) {
 	err := errors.Errorf("unsupported Content-Type: %s", req.Header.Values("Content-Type"))
 	h.sendResponseJsonError(resp, http.StatusBadRequest, err)
@@ -405,12 +1787,78 @@ func (h *HTTPHandler) serveBadContentType(resp http.ResponseWriter, req *http.Re
 func (h *HTTPHandler) serveNotAllowed(resp http.ResponseWriter, req *http.Request) {
 	err := errors.Errorf("invalid request method: %s", req.Method)
 
+	if route := mux.CurrentRoute(req); route != nil {
+		if pathTemplate, _ := route.GetPathTemplate(); pathTemplate == urlJoinBlock || pathTemplate == urlEvents {
+			h.sendResponseNotAllowed(resp, err, http.MethodGet)
+			return
+		}
+		if pathTemplate, _ := route.GetPathTemplate(); pathTemplate == urlConfigUpdate {
+			h.sendResponseNotAllowed(resp, err, http.MethodPost)
+			return
+		}
+	}
+
 	if _, ok := mux.Vars(req)[channelIDKey]; ok {
-		h.sendResponseNotAllowed(resp, err, http.MethodGet, http.MethodDelete)
+		h.sendResponseNotAllowed(resp, err, http.MethodGet, http.MethodDelete, http.MethodPatch)
+		return
+	}
+
+	if req.URL.Path == URLBaseV1Status || req.URL.Path == URLBaseV1Metrics {
+		h.sendResponseNotAllowed(resp, err, http.MethodGet)
 		return
 	}
 
-	h.sendResponseNotAllowed(resp, err, http.MethodGet, http.MethodPost)
+	h.sendResponseNotAllowed(resp, err, http.MethodGet, http.MethodPost, http.MethodOptions)
+}
+
+// serveOptions lets clients discover the join content types and the optional features of the API.
+func (h *HTTPHandler) serveOptions(resp http.ResponseWriter, req *http.Request) {
+	resp.Header().Set("Allow", strings.Join([]string{http.MethodGet, http.MethodPost, http.MethodOptions}, ", "))
+	resp.Header().Set("Accept-Post", strings.Join(joinContentTypes, ", "))
+	// the content codings of join request bodies that are decoded, see RFC 7694
+	resp.Header().Set("Accept-Encoding", "gzip")
+	h.sendResponseOK(resp, h.capabilities())
+}
+
+// capabilities returns the capabilities of the API, including the optional features enabled by the config.
+func (h *HTTPHandler) capabilities() types.APICapabilities {
+	features := []string{
+		types.FeatureFiltering,
+		types.FeatureIdempotentJoin,
+		types.FeatureVerbose,
+		types.FeatureConfigPatch,
+		types.FeatureFields,
+		types.FeatureDryRun,
+	}
+	if h.joinSlots != nil {
+		features = append(features, types.FeatureJoinLimit)
+	}
+	if h.config.ProtectConsenters {
+		features = append(features, types.FeatureProtectConsenters)
+	}
+	if h.config.ProtectSoleConsenter {
+		features = append(features, types.FeatureProtectSoleConsenter)
+	}
+	if h.webhook != nil {
+		features = append(features, types.FeatureWebhook)
+	}
+	if h.metrics != nil {
+		features = append(features, types.FeatureMetrics)
+	}
+	if h.config.MinFreeDiskSpace > 0 {
+		features = append(features, types.FeatureDiskSpaceCheck)
+	}
+	if h.config.EventsInterval > 0 {
+		features = append(features, types.FeatureEvents)
+	}
+	if h.config.MaxChannels > 0 {
+		features = append(features, types.FeatureChannelLimit)
+	}
+
+	return types.APICapabilities{
+		JoinContentTypes: joinContentTypes,
+		Features:         features,
+	}
 }
 
 func negotiateContentType(req *http.Request) (string, error) {
@@ -431,6 +1879,29 @@ func negotiateContentType(req *http.Request) (string, error) {
 	return "", errors.New("response Content-Type is application/json only")
 }
 
+// negotiateListContentType is like negotiateContentType, for the responses that
+// can also be encoded as protobuf. The first acceptable media type is chosen.
+func negotiateListContentType(req *http.Request) (string, error) {
+	acceptReq := req.Header.Get("Accept")
+	if len(acceptReq) == 0 {
+		return "application/json", nil
+	}
+
+	options := strings.Split(acceptReq, ",")
+	for _, opt := range options {
+		if strings.Contains(opt, protobufContentType) {
+			return protobufContentType, nil
+		}
+		if strings.Contains(opt, "application/json") ||
+			strings.Contains(opt, "application/*") ||
+			strings.Contains(opt, "*/*") {
+			return "application/json", nil
+		}
+	}
+
+	return "", errors.Errorf("response Content-Type is application/json or %s only", protobufContentType)
+}
+
 func (h *HTTPHandler) sendResponseJsonError(resp http.ResponseWriter, code int, err error) {
 	encoder := json.NewEncoder(resp)
 	resp.Header().Set("Content-Type", "application/json")
@@ -440,6 +1911,36 @@ func (h *HTTPHandler) sendResponseJsonError(resp http.ResponseWriter, code int,
 	}
 }
 
+// sendJoinBlockErrors responds with every reason the join block cannot be used, so that they can all be fixed at
+// once. The status is 422 when the content of the block is invalid, or otherwise 400 when it requires capabilities
+// this orderer does not support.
+func (h *HTTPHandler) sendJoinBlockErrors(resp http.ResponseWriter, block *cb.Block) {
+	invalid, unsupported := JoinBlockErrors(block)
+	code := http.StatusBadRequest
+	if len(invalid) > 0 {
+		code = http.StatusUnprocessableEntity
+	}
+	var messages []string
+	for _, err := range invalid {
+		messages = append(messages, errors.WithMessage(err, "invalid join block").Error())
+	}
+	for _, err := range unsupported {
+		messages = append(messages, errors.WithMessage(err, "unsupported join block").Error())
+	}
+	if len(messages) == 0 {
+		// not expected, as the block failed the same checks before
+		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.New("invalid join block"))
+		return
+	}
+
+	encoder := json.NewEncoder(resp)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(code)
+	if err := encoder.Encode(&types.ErrorResponse{Error: messages[0], Errors: messages}); err != nil {
+		h.logger.Errorf("failed to encode error, err: %s", err)
+	}
+}
+
 func (h *HTTPHandler) sendResponseOK(resp http.ResponseWriter, content interface{}) {
 	encoder := json.NewEncoder(resp)
 	resp.Header().Set("Content-Type", "application/json")
@@ -449,6 +1950,19 @@ func (h *HTTPHandler) sendResponseOK(resp http.ResponseWriter, content interface
 	}
 }
 
+func (h *HTTPHandler) sendResponseProtoOK(resp http.ResponseWriter, content proto.Message) {
+	contentBytes, err := proto.Marshal(content)
+	if err != nil {
+		h.sendResponseJsonError(resp, http.StatusInternalServerError, errors.WithMessage(err, "failed to marshal content"))
+		return
+	}
+	resp.Header().Set("Content-Type", protobufContentType)
+	resp.WriteHeader(http.StatusOK)
+	if _, err := resp.Write(contentBytes); err != nil {
+		h.logger.Errorf("failed to write content, err: %s", err)
+	}
+}
+
 func (h *HTTPHandler) sendResponseCreated(resp http.ResponseWriter, location string, content interface{}) {
 	encoder := json.NewEncoder(resp)
 	resp.Header().Set("Location", location)
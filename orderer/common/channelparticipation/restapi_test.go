@@ -8,20 +8,36 @@ package channelparticipation_test
 
 import (
 	"bytes"
+	"compress/gzip"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/orderer/etcdraft"
+	"github.com/hyperledger/fabric/common/fabhttp"
 	"github.com/hyperledger/fabric/orderer/common/channelparticipation"
 	"github.com/hyperledger/fabric/orderer/common/channelparticipation/mocks"
 	"github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/hyperledger/fabric/orderer/common/types/msgs"
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
@@ -45,10 +61,186 @@ func TestHTTPHandler_ServeHTTP_Disabled(t *testing.T) {
 	checkErrorResponse(t, http.StatusServiceUnavailable, "channel participation API is disabled", resp)
 }
 
+func TestHTTPHandler_ServeHTTP_ClientAuthorization(t *testing.T) {
+	t.Run("organization allowed", func(t *testing.T) {
+		config := localconfig.ChannelParticipation{Enabled: true, AuthorizedOrganizations: []string{"Org1", "Org2"}}
+		_, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := withClientCert(httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels, nil), pkix.Name{Organization: []string{"Org2"}})
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+	})
+
+	t.Run("organization not allowed", func(t *testing.T) {
+		config := localconfig.ChannelParticipation{Enabled: true, AuthorizedOrganizations: []string{"Org1", "Org2"}}
+		fakeManager, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := withClientCert(httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels, nil), pkix.Name{Organization: []string{"Org3"}})
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusForbidden, "client certificate organization [Org3] is not authorized", resp)
+		require.Equal(t, 0, fakeManager.ChannelListCallCount())
+	})
+
+	t.Run("organizational unit allowed", func(t *testing.T) {
+		config := localconfig.ChannelParticipation{Enabled: true, MaxRequestBodySize: 1024 * 1024, AuthorizedOrganizationalUnits: []string{"admin"}}
+		fakeManager, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := withClientCert(genJoinRequestFormData(t, validBlockBytes("ch-id")), pkix.Name{OrganizationalUnit: []string{"orderer", "admin"}})
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusCreated, resp.Result().StatusCode)
+		require.Equal(t, 1, fakeManager.JoinChannelCallCount())
+	})
+
+	t.Run("organizational unit not allowed", func(t *testing.T) {
+		config := localconfig.ChannelParticipation{Enabled: true, MaxRequestBodySize: 1024 * 1024, AuthorizedOrganizationalUnits: []string{"admin"}}
+		fakeManager, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := withClientCert(genJoinRequestFormData(t, validBlockBytes("ch-id")), pkix.Name{OrganizationalUnit: []string{"client"}})
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusForbidden, "client certificate organizational unit [client] is not authorized", resp)
+		require.Equal(t, 0, fakeManager.JoinChannelCallCount())
+	})
+
+	t.Run("both must match", func(t *testing.T) {
+		config := localconfig.ChannelParticipation{
+			Enabled:                       true,
+			AuthorizedOrganizations:       []string{"Org1"},
+			AuthorizedOrganizationalUnits: []string{"admin"},
+		}
+		_, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := withClientCert(httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels, nil), pkix.Name{Organization: []string{"Org1"}, OrganizationalUnit: []string{"client"}})
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusForbidden, "client certificate organizational unit [client] is not authorized", resp)
+
+		resp = httptest.NewRecorder()
+		req = withClientCert(httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels, nil), pkix.Name{Organization: []string{"Org1"}, OrganizationalUnit: []string{"admin"}})
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+	})
+
+	t.Run("no client certificate", func(t *testing.T) {
+		config := localconfig.ChannelParticipation{Enabled: true, AuthorizedOrganizations: []string{"Org1"}}
+		_, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels, nil)
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusForbidden, "client certificate required", resp)
+	})
+
+	t.Run("no allowlist", func(t *testing.T) {
+		config := localconfig.ChannelParticipation{Enabled: true}
+		_, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels, nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+	})
+}
+
+func TestHTTPHandler_ServeHTTP_Loading(t *testing.T) {
+	config := localconfig.ChannelParticipation{
+		Enabled:            true,
+		MaxRequestBodySize: 1024 * 1024,
+	}
+	fakeManager, h := setup(config, t)
+	fakeManager.ChannelListReturns(types.ChannelList{Channels: []types.ChannelInfoShort{{Name: "app-channel"}}})
+	fakeManager.ChannelInfoReturns(types.ChannelInfo{Name: "app-channel"}, nil)
+	fakeManager.JoinChannelReturns(types.ChannelInfo{Name: "app-channel"}, nil)
+	fakeManager.JoinBlockReturns([]byte("join-block"), nil)
+	fakeManager.ComputeConfigUpdateReturns(&common.ConfigUpdate{ChannelId: "app-channel"}, nil)
+
+	requests := []struct {
+		name         string
+		newRequest   func() *http.Request
+		expectedCode int
+	}{
+		{
+			name: "list all",
+			newRequest: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "list single",
+			newRequest: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"/app-channel", nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "join",
+			newRequest:   func() *http.Request { return genJoinRequestFormData(t, validBlockBytes("app-channel")) },
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name: "remove",
+			newRequest: func() *http.Request {
+				return httptest.NewRequest(http.MethodDelete, channelparticipation.URLBaseV1Channels+"/app-channel", nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name: "join block",
+			newRequest: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"/app-channel/joinblock", nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "compute config update",
+			newRequest: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, channelparticipation.URLBaseV1Channels+"/app-channel/configupdate", strings.NewReader(`{"batchTimeout":"3s"}`))
+				req.Header.Set("Content-Type", "application/merge-patch+json")
+				req.Header.Set("Accept", "application/octet-stream")
+				return req
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "update config",
+			newRequest: func() *http.Request {
+				body := protoutil.MarshalOrPanic(&common.ConfigUpdateEnvelope{
+					ConfigUpdate: protoutil.MarshalOrPanic(&common.ConfigUpdate{ChannelId: "app-channel"}),
+					Signatures:   []*common.ConfigSignature{{SignatureHeader: []byte("header"), Signature: []byte("signature")}},
+				})
+				req := httptest.NewRequest(http.MethodPatch, channelparticipation.URLBaseV1Channels+"/app-channel", bytes.NewReader(body))
+				req.Header.Set("Content-Type", "application/octet-stream")
+				return req
+			},
+			expectedCode: http.StatusAccepted,
+		},
+	}
+
+	for _, r := range requests {
+		t.Run(r.name, func(t *testing.T) {
+			fakeManager.LoadingReturns(true)
+			resp := httptest.NewRecorder()
+			h.ServeHTTP(resp, r.newRequest())
+			checkErrorResponse(t, http.StatusServiceUnavailable, "the orderer is still loading its channels", resp)
+			require.Equal(t, "5", resp.Result().Header.Get("Retry-After"))
+
+			fakeManager.LoadingReturns(false)
+			resp = httptest.NewRecorder()
+			h.ServeHTTP(resp, r.newRequest())
+			require.Equal(t, r.expectedCode, resp.Result().StatusCode)
+			require.Empty(t, resp.Result().Header.Get("Retry-After"))
+		})
+	}
+
+	t.Run("status is served while loading", func(t *testing.T) {
+		fakeManager.LoadingReturns(true)
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Status, nil))
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+	})
+}
+
 func TestHTTPHandler_ServeHTTP_InvalidMethods(t *testing.T) {
 	config := localconfig.ChannelParticipation{Enabled: true}
 	_, h := setup(config, t)
-	invalidMethods := []string{http.MethodConnect, http.MethodHead, http.MethodOptions, http.MethodPatch, http.MethodPut, http.MethodTrace}
+	invalidMethods := []string{http.MethodConnect, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodTrace}
 
 	t.Run("on /channels/ch-id", func(t *testing.T) {
 		invalidMethodsExt := append(invalidMethods, http.MethodPost)
@@ -57,19 +249,96 @@ func TestHTTPHandler_ServeHTTP_InvalidMethods(t *testing.T) {
 			req := httptest.NewRequest(method, path.Join(channelparticipation.URLBaseV1Channels, "ch-id"), nil)
 			h.ServeHTTP(resp, req)
 			checkErrorResponse(t, http.StatusMethodNotAllowed, fmt.Sprintf("invalid request method: %s", method), resp)
-			require.Equal(t, "GET, DELETE", resp.Result().Header.Get("Allow"), "%s", method)
+			require.Equal(t, "GET, DELETE, PATCH", resp.Result().Header.Get("Allow"), "%s", method)
+		}
+	})
+
+	t.Run("on /channels/ch-id/joinblock", func(t *testing.T) {
+		invalidMethodsExt := append(invalidMethods, http.MethodPost, http.MethodDelete, http.MethodPatch)
+		for _, method := range invalidMethodsExt {
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(method, path.Join(channelparticipation.URLBaseV1Channels, "ch-id", "joinblock"), nil)
+			h.ServeHTTP(resp, req)
+			checkErrorResponse(t, http.StatusMethodNotAllowed, fmt.Sprintf("invalid request method: %s", method), resp)
+			require.Equal(t, "GET", resp.Result().Header.Get("Allow"), "%s", method)
+		}
+	})
+
+	t.Run("on /channels/ch-id/configupdate", func(t *testing.T) {
+		invalidMethodsExt := append(invalidMethods, http.MethodGet, http.MethodDelete, http.MethodPatch)
+		for _, method := range invalidMethodsExt {
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(method, path.Join(channelparticipation.URLBaseV1Channels, "ch-id", "configupdate"), nil)
+			h.ServeHTTP(resp, req)
+			checkErrorResponse(t, http.StatusMethodNotAllowed, fmt.Sprintf("invalid request method: %s", method), resp)
+			require.Equal(t, "POST", resp.Result().Header.Get("Allow"), "%s", method)
 		}
 	})
 
 	t.Run("on /channels", func(t *testing.T) {
-		invalidMethodsExt := append(invalidMethods, http.MethodDelete)
+		invalidMethodsExt := []string{http.MethodConnect, http.MethodHead, http.MethodPut, http.MethodTrace, http.MethodDelete, http.MethodPatch}
 		for _, method := range invalidMethodsExt {
 			resp := httptest.NewRecorder()
 			req := httptest.NewRequest(method, channelparticipation.URLBaseV1Channels, nil)
 			h.ServeHTTP(resp, req)
 			checkErrorResponse(t, http.StatusMethodNotAllowed, fmt.Sprintf("invalid request method: %s", method), resp)
-			require.Equal(t, "GET, POST", resp.Result().Header.Get("Allow"), "%s", method)
+			require.Equal(t, "GET, POST, OPTIONS", resp.Result().Header.Get("Allow"), "%s", method)
+		}
+	})
+}
+
+func TestHTTPHandler_ServeHTTP_Options(t *testing.T) {
+	serveOptions := func(t *testing.T, h *channelparticipation.HTTPHandler) types.APICapabilities {
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodOptions, channelparticipation.URLBaseV1Channels, nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+		require.Equal(t, "application/json", resp.Result().Header.Get("Content-Type"))
+		require.Equal(t, "GET, POST, OPTIONS", resp.Result().Header.Get("Allow"))
+		require.Equal(t, "multipart/form-data, application/json", resp.Result().Header.Get("Accept-Post"))
+		require.Equal(t, "gzip", resp.Result().Header.Get("Accept-Encoding"))
+
+		capabilities := types.APICapabilities{}
+		err := json.Unmarshal(resp.Body.Bytes(), &capabilities)
+		require.NoError(t, err, "body: %s", resp.Body.String())
+		require.Equal(t, []string{"multipart/form-data", "application/json"}, capabilities.JoinContentTypes)
+		return capabilities
+	}
+
+	t.Run("default features", func(t *testing.T) {
+		config := localconfig.ChannelParticipation{Enabled: true}
+		_, h := setup(config, t)
+
+		capabilities := serveOptions(t, h)
+		require.Equal(t, []string{"filtering", "idempotent-join", "verbose", "config-patch", "fields", "dry-run"}, capabilities.Features)
+	})
+
+	t.Run("features enabled by the config", func(t *testing.T) {
+		config := localconfig.ChannelParticipation{
+			Enabled:              true,
+			MaxConcurrentJoins:   2,
+			ProtectConsenters:    true,
+			ProtectSoleConsenter: true,
+			WebhookURL:           "http://127.0.0.1:0/events",
+			MetricsEnabled:       true,
+			MinFreeDiskSpace:     1024,
+			EventsInterval:       time.Second,
+			MaxChannels:          10,
 		}
+		_, h := setup(config, t)
+
+		capabilities := serveOptions(t, h)
+		require.Equal(t, []string{"filtering", "idempotent-join", "verbose", "config-patch", "fields", "dry-run", "join-limit", "protect-consenters", "protect-sole-consenter", "webhook", "metrics", "disk-space-check", "events", "channel-limit"}, capabilities.Features)
+	})
+
+	t.Run("disabled API", func(t *testing.T) {
+		config := localconfig.ChannelParticipation{Enabled: false}
+		_, h := setup(config, t)
+
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodOptions, channelparticipation.URLBaseV1Channels, nil)
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusServiceUnavailable, "channel participation API is disabled", resp)
 	})
 }
 
@@ -110,7 +379,7 @@ func TestHTTPHandler_ServeHTTP_ListErrors(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"/ok", nil)
 		req.Header.Set("Accept", "text/html")
 		h.ServeHTTP(resp, req)
-		checkErrorResponse(t, http.StatusNotAcceptable, "response Content-Type is application/json only", resp)
+		checkErrorResponse(t, http.StatusNotAcceptable, "response Content-Type is application/json or application/x-protobuf only", resp)
 	})
 }
 
@@ -146,6 +415,158 @@ func TestHTTPHandler_ServeHTTP_ListAll(t *testing.T) {
 		}
 		require.True(t, m["app-channel1"])
 		require.True(t, m["app-channel2"])
+		require.Equal(t, 3, listAll.Count)
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		fakeManager.ChannelListReturns(types.ChannelList{
+			Channels: []types.ChannelInfoShort{
+				{Name: "tenant1-channel1"},
+				{Name: "tenant2-channel1"},
+				{Name: "tenant1-channel2"},
+			},
+			SystemChannel: &types.ChannelInfoShort{Name: "system-channel"},
+		})
+
+		listWithPrefix := func(t *testing.T, prefix string) types.ChannelList {
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"?prefix="+url.QueryEscape(prefix), nil)
+			h.ServeHTTP(resp, req)
+			require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+
+			listAll := types.ChannelList{}
+			err := json.Unmarshal(resp.Body.Bytes(), &listAll)
+			require.NoError(t, err, "cannot be unmarshaled")
+			return listAll
+		}
+
+		t.Run("matching", func(t *testing.T) {
+			require.Equal(t, types.ChannelList{
+				Channels: []types.ChannelInfoShort{
+					{Name: "tenant1-channel1", URL: channelparticipation.URLBaseV1Channels + "/tenant1-channel1"},
+					{Name: "tenant1-channel2", URL: channelparticipation.URLBaseV1Channels + "/tenant1-channel2"},
+				},
+				Count: 4,
+			}, listWithPrefix(t, "tenant1-"))
+		})
+
+		t.Run("matching system channel", func(t *testing.T) {
+			require.Equal(t, types.ChannelList{
+				Channels:      []types.ChannelInfoShort{},
+				SystemChannel: &types.ChannelInfoShort{Name: "system-channel", URL: channelparticipation.URLBaseV1Channels + "/system-channel"},
+				Count:         4,
+			}, listWithPrefix(t, "sys"))
+		})
+
+		t.Run("non-matching", func(t *testing.T) {
+			require.Equal(t, types.ChannelList{
+				Channels: []types.ChannelInfoShort{},
+				Count:    4,
+			}, listWithPrefix(t, "tenant3-"))
+		})
+
+		t.Run("empty", func(t *testing.T) {
+			listAll := listWithPrefix(t, "")
+			require.Len(t, listAll.Channels, 3)
+			require.NotNil(t, listAll.SystemChannel)
+			require.Equal(t, 4, listAll.Count)
+		})
+
+		t.Run("count is the total when the list is truncated", func(t *testing.T) {
+			listAll := listWithPrefix(t, "tenant2-")
+			require.Len(t, listAll.Channels, 1)
+			require.Nil(t, listAll.SystemChannel)
+			require.Equal(t, 4, listAll.Count)
+		})
+	})
+
+	t.Run("relation", func(t *testing.T) {
+		fakeManager.ChannelListReturns(types.ChannelList{
+			Channels: []types.ChannelInfoShort{
+				{Name: "consenter-channel"},
+				{Name: "follower-channel"},
+				{Name: "tracker-channel"},
+				{Name: "removed-channel"},
+			},
+			SystemChannel: &types.ChannelInfoShort{Name: "system-channel"},
+		})
+		relations := map[string]types.ConsensusRelation{
+			"system-channel":    types.ConsensusRelationConsenter,
+			"consenter-channel": types.ConsensusRelationConsenter,
+			"follower-channel":  types.ConsensusRelationFollower,
+			"tracker-channel":   types.ConsensusRelationConfigTracker,
+		}
+		fakeManager.ChannelInfoStub = func(channelID string) (types.ChannelInfo, error) {
+			relation, ok := relations[channelID]
+			if !ok {
+				return types.ChannelInfo{}, types.ErrChannelNotExist
+			}
+			return types.ChannelInfo{Name: channelID, ConsensusRelation: relation}, nil
+		}
+		defer func() { fakeManager.ChannelInfoStub = nil }()
+
+		listWithRelation := func(t *testing.T, relation string) types.ChannelList {
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"?relation="+relation, nil)
+			h.ServeHTTP(resp, req)
+			require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+
+			listAll := types.ChannelList{}
+			err := json.Unmarshal(resp.Body.Bytes(), &listAll)
+			require.NoError(t, err, "cannot be unmarshaled")
+			return listAll
+		}
+
+		t.Run("consenter", func(t *testing.T) {
+			require.Equal(t, types.ChannelList{
+				Channels: []types.ChannelInfoShort{
+					{Name: "consenter-channel", URL: channelparticipation.URLBaseV1Channels + "/consenter-channel"},
+				},
+				SystemChannel: &types.ChannelInfoShort{Name: "system-channel", URL: channelparticipation.URLBaseV1Channels + "/system-channel"},
+				Count:         5,
+			}, listWithRelation(t, "consenter"))
+		})
+
+		t.Run("follower", func(t *testing.T) {
+			require.Equal(t, types.ChannelList{
+				Channels: []types.ChannelInfoShort{
+					{Name: "follower-channel", URL: channelparticipation.URLBaseV1Channels + "/follower-channel"},
+				},
+				Count: 5,
+			}, listWithRelation(t, "follower"))
+		})
+
+		t.Run("config-tracker", func(t *testing.T) {
+			require.Equal(t, types.ChannelList{
+				Channels: []types.ChannelInfoShort{
+					{Name: "tracker-channel", URL: channelparticipation.URLBaseV1Channels + "/tracker-channel"},
+				},
+				Count: 5,
+			}, listWithRelation(t, "config-tracker"))
+		})
+
+		t.Run("with prefix", func(t *testing.T) {
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"?relation=consenter&prefix=sys", nil)
+			h.ServeHTTP(resp, req)
+			require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+
+			listAll := types.ChannelList{}
+			err := json.Unmarshal(resp.Body.Bytes(), &listAll)
+			require.NoError(t, err, "cannot be unmarshaled")
+			require.Equal(t, types.ChannelList{
+				Channels:      []types.ChannelInfoShort{},
+				SystemChannel: &types.ChannelInfoShort{Name: "system-channel", URL: channelparticipation.URLBaseV1Channels + "/system-channel"},
+				Count:         5,
+			}, listAll)
+		})
+
+		t.Run("unknown relation", func(t *testing.T) {
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"?relation=observer", nil)
+			h.ServeHTTP(resp, req)
+			checkErrorResponse(t, http.StatusBadRequest, "unknown consensus relation: observer", resp)
+		})
 	})
 
 	t.Run("no channels, empty channels", func(t *testing.T) {
@@ -199,6 +620,156 @@ func TestHTTPHandler_ServeHTTP_ListAll(t *testing.T) {
 	})
 }
 
+func TestHTTPHandler_ServeHTTP_OrdererEndpoint(t *testing.T) {
+	config := localconfig.ChannelParticipation{
+		Enabled:            true,
+		MaxRequestBodySize: 1024 * 1024,
+		OrdererEndpoint:    "orderer1.example.com:7050",
+	}
+	fakeManager, h := setup(config, t)
+	fakeManager.ChannelInfoReturns(types.ChannelInfo{
+		Name:              "app-channel",
+		ConsensusRelation: "consenter",
+		Status:            "active",
+		Height:            3,
+	}, nil)
+	fakeManager.JoinChannelReturns(types.ChannelInfo{
+		Name:              "app-channel",
+		ConsensusRelation: "consenter",
+		Status:            "active",
+		Height:            1,
+	}, nil)
+
+	t.Run("list single", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"/app-channel", nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+
+		infoResp := types.ChannelInfo{}
+		err := json.Unmarshal(resp.Body.Bytes(), &infoResp)
+		require.NoError(t, err, "cannot be unmarshaled")
+		require.Equal(t, "orderer1.example.com:7050", infoResp.OrdererEndpoint)
+	})
+
+	t.Run("join", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, genJoinRequestFormData(t, validBlockBytes("app-channel")))
+		require.Equal(t, http.StatusCreated, resp.Result().StatusCode)
+
+		infoResp := types.ChannelInfo{}
+		err := json.Unmarshal(resp.Body.Bytes(), &infoResp)
+		require.NoError(t, err, "cannot be unmarshaled")
+		require.Equal(t, "orderer1.example.com:7050", infoResp.OrdererEndpoint)
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		fakeManager, h := setup(localconfig.ChannelParticipation{Enabled: true}, t)
+		fakeManager.ChannelInfoReturns(types.ChannelInfo{Name: "app-channel"}, nil)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"/app-channel", nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+		require.NotContains(t, resp.Body.String(), "ordererEndpoint")
+	})
+}
+
+func TestHTTPHandler_ServeHTTP_Protobuf(t *testing.T) {
+	config := localconfig.ChannelParticipation{Enabled: true}
+	fakeManager, h := setup(config, t)
+	joinedFromGenesis := false
+	configSequence := uint64(2)
+	fakeManager.ChannelListReturns(types.ChannelList{
+		SystemChannel: &types.ChannelInfoShort{Name: "system-channel"},
+		Channels:      []types.ChannelInfoShort{{Name: "app-channel1"}, {Name: "app-channel2"}},
+	})
+	fakeManager.ChannelInfoReturns(types.ChannelInfo{
+		Name:              "app-channel1",
+		ConsensusRelation: "consenter",
+		Status:            "active",
+		Height:            3,
+		JoinedFromGenesis: &joinedFromGenesis,
+		ConfigSequence:    &configSequence,
+	}, nil)
+	fakeManager.ChannelCapabilitiesReturns(types.ChannelCapabilities{
+		Channel: []string{"V2_0"},
+		Orderer: []string{"V2_0"},
+	}, nil)
+	fakeManager.LedgerBytesReturns(4096, nil)
+
+	t.Run("list all", func(t *testing.T) {
+		for _, accept := range []string{"application/x-protobuf", "application/x-protobuf, application/json"} {
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels, nil)
+			req.Header.Set("Accept", accept)
+			h.ServeHTTP(resp, req)
+			require.Equal(t, http.StatusOK, resp.Result().StatusCode, "Accept: %s", accept)
+			require.Equal(t, "application/x-protobuf", resp.Result().Header.Get("Content-Type"), "Accept: %s", accept)
+
+			listResp := &msgs.ChannelList{}
+			err := proto.Unmarshal(resp.Body.Bytes(), listResp)
+			require.NoError(t, err, "cannot be unmarshaled")
+			require.True(t, proto.Equal(&msgs.ChannelList{
+				SystemChannel: &msgs.ChannelInfoShort{Name: "system-channel", Url: "/participation/v1/channels/system-channel"},
+				Channels: []*msgs.ChannelInfoShort{
+					{Name: "app-channel1", Url: "/participation/v1/channels/app-channel1"},
+					{Name: "app-channel2", Url: "/participation/v1/channels/app-channel2"},
+				},
+				Count: 3,
+			}, listResp), "Accept: %s", accept)
+		}
+	})
+
+	t.Run("list single", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"/app-channel1?verbose=true", nil)
+		req.Header.Set("Accept", "application/x-protobuf")
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+		require.Equal(t, "application/x-protobuf", resp.Result().Header.Get("Content-Type"))
+
+		infoResp := &msgs.ChannelInfo{}
+		err := proto.Unmarshal(resp.Body.Bytes(), infoResp)
+		require.NoError(t, err, "cannot be unmarshaled")
+		require.Equal(t, "app-channel1", infoResp.Name)
+		require.Equal(t, "/participation/v1/channels/app-channel1", infoResp.Url)
+		require.Equal(t, "consenter", infoResp.ConsensusRelation)
+		require.Equal(t, "active", infoResp.Status)
+		require.Equal(t, uint64(3), infoResp.Height)
+		require.NotNil(t, infoResp.JoinedFromGenesis)
+		require.False(t, infoResp.JoinedFromGenesis.Value)
+		require.Equal(t, uint64(2), infoResp.ConfigSequence.Value)
+		require.Equal(t, []string{"V2_0"}, infoResp.Capabilities.Channel)
+		require.Equal(t, []string{"V2_0"}, infoResp.Capabilities.Orderer)
+		require.Empty(t, infoResp.Capabilities.Application)
+		require.Equal(t, uint64(4096), infoResp.LedgerBytes.Value)
+	})
+
+	t.Run("JSON preferred", func(t *testing.T) {
+		for _, accept := range []string{"", "application/json", "application/json, application/x-protobuf", "*/*"} {
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"/app-channel1", nil)
+			req.Header.Set("Accept", accept)
+			h.ServeHTTP(resp, req)
+			require.Equal(t, http.StatusOK, resp.Result().StatusCode, "Accept: %s", accept)
+			require.Equal(t, "application/json", resp.Result().Header.Get("Content-Type"), "Accept: %s", accept)
+
+			infoResp := types.ChannelInfo{}
+			err := json.Unmarshal(resp.Body.Bytes(), &infoResp)
+			require.NoError(t, err, "cannot be unmarshaled, Accept: %s", accept)
+			require.Equal(t, "app-channel1", infoResp.Name)
+		}
+	})
+
+	t.Run("other resources are JSON only", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Status, nil)
+		req.Header.Set("Accept", "application/x-protobuf")
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusNotAcceptable, "response Content-Type is application/json only", resp)
+	})
+}
+
 func TestHTTPHandler_ServeHTTP_ListSingle(t *testing.T) {
 	config := localconfig.ChannelParticipation{Enabled: true}
 	fakeManager, h := setup(config, t)
@@ -230,19 +801,289 @@ func TestHTTPHandler_ServeHTTP_ListSingle(t *testing.T) {
 		}, infoResp)
 	})
 
-	t.Run("channel does not exists", func(t *testing.T) {
-		fakeManager.ChannelInfoReturns(types.ChannelInfo{}, errors.New("not found"))
+	t.Run("channel onboarding", func(t *testing.T) {
+		targetHeight := uint64(11)
+		fakeManager.ChannelInfoReturns(types.ChannelInfo{
+			Name:              "app-channel",
+			ConsensusRelation: "follower",
+			Status:            "onboarding",
+			Height:            4,
+			TargetHeight:      &targetHeight,
+		}, nil)
 		resp := httptest.NewRecorder()
 		req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"/app-channel", nil)
 		h.ServeHTTP(resp, req)
-		checkErrorResponse(t, http.StatusNotFound, "not found", resp)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+		require.Contains(t, resp.Body.String(), `"targetHeight":11`)
+
+		infoResp := types.ChannelInfo{}
+		err := json.Unmarshal(resp.Body.Bytes(), &infoResp)
+		require.NoError(t, err, "cannot be unmarshaled")
+		require.NotNil(t, infoResp.TargetHeight)
+		require.Equal(t, uint64(11), *infoResp.TargetHeight)
 	})
-}
 
-func TestHTTPHandler_ServeHTTP_Join(t *testing.T) {
-	config := localconfig.ChannelParticipation{
-		Enabled:            true,
-		MaxRequestBodySize: 1024 * 1024,
+	t.Run("known status and consensus relation values", func(t *testing.T) {
+		for _, relation := range types.ConsensusRelations() {
+			for _, status := range types.Statuses() {
+				fakeManager.ChannelInfoReturns(types.ChannelInfo{
+					Name:              "app-channel",
+					ConsensusRelation: relation,
+					Status:            status,
+					Height:            3,
+				}, nil)
+				resp := httptest.NewRecorder()
+				req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"/app-channel", nil)
+				h.ServeHTTP(resp, req)
+				require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+
+				infoResp := map[string]interface{}{}
+				err := json.Unmarshal(resp.Body.Bytes(), &infoResp)
+				require.NoError(t, err, "cannot be unmarshaled")
+				respRelation, err := types.ParseConsensusRelation(infoResp["consensusRelation"].(string))
+				require.NoError(t, err)
+				require.Equal(t, relation, respRelation)
+				respStatus, err := types.ParseStatus(infoResp["status"].(string))
+				require.NoError(t, err)
+				require.Equal(t, status, respStatus)
+			}
+		}
+	})
+
+	t.Run("verbose", func(t *testing.T) {
+		fakeManager.ChannelInfoReturns(types.ChannelInfo{
+			Name:              "app-channel",
+			ConsensusRelation: "consenter",
+			Status:            "active",
+			Height:            3,
+		}, nil)
+		fakeManager.ChannelCapabilitiesReturns(types.ChannelCapabilities{
+			Channel:     []string{"V2_0"},
+			Orderer:     []string{"V2_0"},
+			Application: []string{"V2_0"},
+		}, nil)
+		fakeManager.LedgerBytesReturns(4096, nil)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"/app-channel?verbose=true", nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+		require.Equal(t, 1, fakeManager.ChannelCapabilitiesCallCount())
+		require.Equal(t, "app-channel", fakeManager.ChannelCapabilitiesArgsForCall(0))
+
+		infoResp := types.ChannelInfo{}
+		err := json.Unmarshal(resp.Body.Bytes(), &infoResp)
+		require.NoError(t, err, "cannot be unmarshaled")
+		require.Equal(t, &types.ChannelCapabilities{
+			Channel:     []string{"V2_0"},
+			Orderer:     []string{"V2_0"},
+			Application: []string{"V2_0"},
+		}, infoResp.Capabilities)
+		require.Equal(t, 1, fakeManager.LedgerBytesCallCount())
+		require.Equal(t, "app-channel", fakeManager.LedgerBytesArgsForCall(0))
+		require.NotNil(t, infoResp.LedgerBytes)
+		require.Equal(t, uint64(4096), *infoResp.LedgerBytes)
+
+		t.Run("capabilities unavailable", func(t *testing.T) {
+			fakeManager.ChannelCapabilitiesReturns(types.ChannelCapabilities{}, types.ErrChannelPendingRemoval)
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"/app-channel?verbose=true", nil)
+			h.ServeHTTP(resp, req)
+			require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+			require.NotContains(t, resp.Body.String(), "capabilities")
+		})
+
+		t.Run("ledger size unavailable", func(t *testing.T) {
+			fakeManager.LedgerBytesReturns(0, types.ErrChannelNotExist)
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"/app-channel?verbose=true", nil)
+			h.ServeHTTP(resp, req)
+			require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+			require.NotContains(t, resp.Body.String(), "ledgerBytes")
+		})
+
+		t.Run("not verbose", func(t *testing.T) {
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"/app-channel", nil)
+			h.ServeHTTP(resp, req)
+			require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+			require.NotContains(t, resp.Body.String(), "capabilities")
+			require.NotContains(t, resp.Body.String(), "ledgerBytes")
+			require.Equal(t, 3, fakeManager.ChannelCapabilitiesCallCount())
+			require.Equal(t, 3, fakeManager.LedgerBytesCallCount())
+		})
+	})
+
+	t.Run("channel does not exists", func(t *testing.T) {
+		fakeManager.ChannelInfoReturns(types.ChannelInfo{}, errors.New("not found"))
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"/app-channel", nil)
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusNotFound, "not found", resp)
+	})
+}
+
+func TestHTTPHandler_ServeHTTP_Fields(t *testing.T) {
+	config := localconfig.ChannelParticipation{Enabled: true}
+	fakeManager, h := setup(config, t)
+	fakeManager.ChannelListReturns(types.ChannelList{
+		SystemChannel: &types.ChannelInfoShort{Name: "system-channel"},
+		Channels:      []types.ChannelInfoShort{{Name: "app-channel1"}, {Name: "app-channel2"}},
+		Revision:      7,
+	})
+	fakeManager.ChannelInfoReturns(types.ChannelInfo{
+		Name:              "app-channel1",
+		ConsensusRelation: "consenter",
+		Status:            "active",
+		Height:            3,
+	}, nil)
+
+	t.Run("list all", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"?fields=name", nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+		require.Equal(t, "application/json", resp.Result().Header.Get("Content-Type"))
+		require.JSONEq(t, `{
+			"systemChannel": {"name": "system-channel"},
+			"channels": [{"name": "app-channel1"}, {"name": "app-channel2"}],
+			"count": 3,
+			"revision": 7
+		}`, resp.Body.String())
+	})
+
+	t.Run("list single", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"/app-channel1?fields=name,height", nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+		require.Equal(t, "application/json", resp.Result().Header.Get("Content-Type"))
+		require.JSONEq(t, `{"name": "app-channel1", "height": 3}`, resp.Body.String())
+	})
+
+	t.Run("omitted field", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"/app-channel1?fields=name,capabilities", nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+		require.JSONEq(t, `{"name": "app-channel1"}`, resp.Body.String())
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"/app-channel1?fields=name,color", nil)
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "unknown field: color", resp)
+	})
+
+	t.Run("field of a single channel when listing all", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"?fields=height", nil)
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "unknown field: height", resp)
+	})
+
+	t.Run("no fields", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"?fields=,", nil)
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "no fields in the fields query parameter", resp)
+	})
+
+	t.Run("protobuf", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"?fields=name", nil)
+		req.Header.Set("Accept", "application/x-protobuf")
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "the fields query parameter is only supported for JSON responses", resp)
+	})
+}
+
+func TestHTTPHandler_ServeHTTP_Status(t *testing.T) {
+	config := localconfig.ChannelParticipation{Enabled: true}
+
+	t.Run("seeded channels", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.ChannelListReturns(types.ChannelList{
+			Channels: []types.ChannelInfoShort{
+				{Name: "app-channel1"},
+				{Name: "app-channel2"},
+				{Name: "app-channel3"},
+				{Name: "app-channel4"},
+				{Name: "removed-channel"},
+			},
+			SystemChannel: &types.ChannelInfoShort{Name: "system-channel"},
+		})
+		statuses := map[string]types.Status{
+			"system-channel": types.StatusActive,
+			"app-channel1":   types.StatusActive,
+			"app-channel2":   types.StatusOnBoarding,
+			"app-channel3":   types.StatusActive,
+			"app-channel4":   types.StatusInactive,
+		}
+		fakeManager.ChannelInfoStub = func(channelID string) (types.ChannelInfo, error) {
+			status, ok := statuses[channelID]
+			if !ok {
+				return types.ChannelInfo{}, types.ErrChannelNotExist
+			}
+			return types.ChannelInfo{Name: channelID, Status: status}, nil
+		}
+
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Status, nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+		require.Equal(t, "application/json", resp.Result().Header.Get("Content-Type"))
+		require.Equal(t, "no-store", resp.Result().Header.Get("Cache-Control"))
+
+		summary := types.ChannelsSummary{}
+		err := json.Unmarshal(resp.Body.Bytes(), &summary)
+		require.NoError(t, err, "cannot be unmarshaled")
+		require.Equal(t, types.ChannelsSummary{
+			SystemChannel: true,
+			Ready:         false,
+			Total:         5,
+			Statuses: map[types.Status]int{
+				types.StatusActive:     3,
+				types.StatusOnBoarding: 1,
+				types.StatusInactive:   1,
+			},
+		}, summary)
+		require.Equal(t, 6, fakeManager.ChannelInfoCallCount())
+	})
+
+	t.Run("no channels", func(t *testing.T) {
+		_, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Status, nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+		require.JSONEq(t, `{"systemChannel":false,"ready":true,"total":0,"statuses":{}}`, resp.Body.String())
+	})
+
+	t.Run("loading", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.LoadingReturns(true)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Status, nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+		require.JSONEq(t, `{"systemChannel":false,"ready":false,"total":0,"statuses":{}}`, resp.Body.String())
+	})
+
+	t.Run("invalid method", func(t *testing.T) {
+		_, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, channelparticipation.URLBaseV1Status, nil)
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusMethodNotAllowed, "invalid request method: POST", resp)
+		require.Equal(t, "GET", resp.Result().Header.Get("Allow"))
+	})
+}
+
+func TestHTTPHandler_ServeHTTP_Join(t *testing.T) {
+	config := localconfig.ChannelParticipation{
+		Enabled:            true,
+		MaxRequestBodySize: 1024 * 1024,
 	}
 
 	t.Run("created ok", func(t *testing.T) {
@@ -272,6 +1113,66 @@ func TestHTTPHandler_ServeHTTP_Join(t *testing.T) {
 		}, infoResp)
 	})
 
+	t.Run("unsupported capability", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := genJoinRequestFormData(t, protoutil.MarshalOrPanic(blockWithChannelCapabilities("ch-id", "V3_0")))
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "unsupported join block: Channel capability V3_0 is required but not supported", resp)
+		require.Equal(t, 0, fakeManager.JoinChannelCallCount())
+	})
+
+	t.Run("several validation errors", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		required := &common.Capabilities{Capabilities: map[string]*common.Capability{"V3_0": {}}}
+		block := blockWithGroupsAndValues(
+			map[string]*common.ConfigGroup{},
+			map[string]*common.ConfigValue{"Capabilities": {Value: protoutil.MarshalOrPanic(required)}},
+			"ch-id",
+		)
+		resp := httptest.NewRecorder()
+		req := genJoinRequestFormData(t, protoutil.MarshalOrPanic(block))
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusUnprocessableEntity, resp.Result().StatusCode)
+		respErr := &types.ErrorResponse{}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), respErr))
+		require.Equal(t, types.ErrorResponse{
+			Error: "invalid join block: invalid config: must have at least one of application or consortiums",
+			Errors: []string{
+				"invalid join block: invalid config: must have at least one of application or consortiums",
+				"unsupported join block: Channel capability V3_0 is required but not supported",
+			},
+		}, *respErr)
+		require.Equal(t, 0, fakeManager.JoinChannelCallCount())
+	})
+
+	t.Run("system channel requires restart", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.JoinChannelReturns(types.ChannelInfo{
+			Name:              "sys-channel",
+			ConsensusRelation: "consenter",
+			Status:            "inactive",
+			Height:            1,
+			RequiresRestart:   true,
+		}, nil)
+
+		resp := httptest.NewRecorder()
+		sysBlockBytes := protoutil.MarshalOrPanic(blockWithGroups(map[string]*common.ConfigGroup{
+			"Consortiums": {},
+		}, "sys-channel"))
+		req := genJoinRequestFormData(t, sysBlockBytes)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusCreated, resp.Result().StatusCode)
+		_, _, isAppChannel := fakeManager.JoinChannelArgsForCall(0)
+		require.False(t, isAppChannel)
+
+		infoResp := types.ChannelInfo{}
+		err := json.Unmarshal(resp.Body.Bytes(), &infoResp)
+		require.NoError(t, err, "cannot be unmarshaled")
+		require.True(t, infoResp.RequiresRestart)
+		require.Contains(t, resp.Body.String(), `"requiresRestart":true`)
+	})
+
 	t.Run("Error: System Channel Exists", func(t *testing.T) {
 		fakeManager, h := setup(config, t)
 		fakeManager.JoinChannelReturns(types.ChannelInfo{}, types.ErrSystemChannelExists)
@@ -321,9 +1222,53 @@ func TestHTTPHandler_ServeHTTP_Join(t *testing.T) {
 	t.Run("bad body - invalid join block", func(t *testing.T) {
 		_, h := setup(config, t)
 		resp := httptest.NewRecorder()
+		req := genJoinRequestFormData(t, protoutil.MarshalOrPanic(nonConfigBlock()))
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusUnprocessableEntity, "invalid join block: block is not a config block", resp)
+	})
+
+	t.Run("bad body - block without header", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		resp := httptest.NewRecorder()
 		req := genJoinRequestFormData(t, []byte{})
 		h.ServeHTTP(resp, req)
-		checkErrorResponse(t, http.StatusBadRequest, "invalid join block: block is not a config block", resp)
+		checkErrorResponse(t, http.StatusBadRequest, "invalid join block: block has no header", resp)
+		require.Equal(t, 0, fakeManager.JoinChannelCallCount())
+	})
+
+	t.Run("bad body - tampered data hash", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		block := blockWithGroups(map[string]*common.ConfigGroup{"Application": {}}, "ch-id")
+		dataHash := protoutil.BlockDataHash(block.Data)
+		block.Header.DataHash = append([]byte{}, dataHash...)
+		block.Header.DataHash[0] ^= 0xff
+		resp := httptest.NewRecorder()
+		req := genJoinRequestFormData(t, protoutil.MarshalOrPanic(block))
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest,
+			fmt.Sprintf("invalid join block: block data hash %x does not match the hash of the block data %x", block.Header.DataHash, dataHash), resp)
+		require.Equal(t, 0, fakeManager.JoinChannelCallCount())
+	})
+
+	t.Run("bad body - config block without application or consortiums", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		blockBytes := protoutil.MarshalOrPanic(blockWithGroups(map[string]*common.ConfigGroup{}, "ch-id"))
+		req := genJoinRequestFormData(t, blockBytes)
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusUnprocessableEntity, "invalid join block: invalid config: must have at least one of application or consortiums", resp)
+		require.Equal(t, 0, fakeManager.JoinChannelCallCount())
+	})
+
+	t.Run("bad body - duplicate consenter", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		consenter := &etcdraft.Consenter{Host: "orderer1", Port: 7050, ServerTlsCert: []byte("cert1"), ClientTlsCert: []byte("cert1")}
+		blockBytes := protoutil.MarshalOrPanic(blockWithConsenters("ch-id", consenter, consenter))
+		resp := httptest.NewRecorder()
+		req := genJoinRequestFormData(t, blockBytes)
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "invalid join block: duplicate consenter orderer1:7050", resp)
+		require.Equal(t, 0, fakeManager.JoinChannelCallCount())
 	})
 
 	t.Run("content type mismatch", func(t *testing.T) {
@@ -395,6 +1340,86 @@ func TestHTTPHandler_ServeHTTP_Join(t *testing.T) {
 		checkErrorResponse(t, http.StatusBadRequest, "form contains too many parts", resp)
 	})
 
+	t.Run("form-data: bad form - no parts", func(t *testing.T) {
+		_, h := setup(config, t)
+		resp := httptest.NewRecorder()
+
+		joinBody := new(bytes.Buffer)
+		writer := multipart.NewWriter(joinBody)
+		err := writer.Close()
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, channelparticipation.URLBaseV1Channels, joinBody)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "form does not contains part key: config-block", resp)
+	})
+
+	t.Run("form-data: bad form - key is not a file part", func(t *testing.T) {
+		_, h := setup(config, t)
+		resp := httptest.NewRecorder()
+
+		joinBody := new(bytes.Buffer)
+		writer := multipart.NewWriter(joinBody)
+		part, err := writer.CreateFormField(channelparticipation.FormDataConfigBlockKey)
+		require.NoError(t, err)
+		part.Write(validBlockBytes("ch-id"))
+		err = writer.Close()
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, channelparticipation.URLBaseV1Channels, joinBody)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "form does not contains part key: config-block", resp)
+	})
+
+	t.Run("form-data: bad form - extra file part", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		resp := httptest.NewRecorder()
+
+		joinBody := new(bytes.Buffer)
+		writer := multipart.NewWriter(joinBody)
+		part, err := writer.CreateFormFile(channelparticipation.FormDataConfigBlockKey, "join-config.block")
+		require.NoError(t, err)
+		part.Write(validBlockBytes("ch-id"))
+		part, err = writer.CreateFormFile("another-block", "another-config.block")
+		require.NoError(t, err)
+		part.Write(validBlockBytes("ch-id"))
+		err = writer.Close()
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, channelparticipation.URLBaseV1Channels, joinBody)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "form contains too many parts", resp)
+		require.Equal(t, 0, fakeManager.JoinChannelCallCount())
+	})
+
+	t.Run("form-data: bad form - repeated key", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		resp := httptest.NewRecorder()
+
+		joinBody := new(bytes.Buffer)
+		writer := multipart.NewWriter(joinBody)
+		for _, channelID := range []string{"ch-one", "ch-two"} {
+			part, err := writer.CreateFormFile(channelparticipation.FormDataConfigBlockKey, "join-config.block")
+			require.NoError(t, err)
+			part.Write(validBlockBytes(channelID))
+		}
+		err := writer.Close()
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, channelparticipation.URLBaseV1Channels, joinBody)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "form contains too many parts", resp)
+		require.Equal(t, 0, fakeManager.JoinChannelCallCount())
+	})
+
 	t.Run("body larger that MaxRequestBodySize", func(t *testing.T) {
 		config := localconfig.ChannelParticipation{
 			Enabled:            true,
@@ -406,95 +1431,2217 @@ func TestHTTPHandler_ServeHTTP_Join(t *testing.T) {
 		h.ServeHTTP(resp, req)
 		checkErrorResponse(t, http.StatusBadRequest, "cannot read form from request body: multipart: NextPart: http: request body too large", resp)
 	})
-}
 
-func TestHTTPHandler_ServeHTTP_Remove(t *testing.T) {
-	config := localconfig.ChannelParticipation{Enabled: true}
-	fakeManager, h := setup(config, t)
+	t.Run("MaxRequestBodySize changed at runtime", func(t *testing.T) {
+		config := localconfig.ChannelParticipation{
+			Enabled:            true,
+			MaxRequestBodySize: 1,
+		}
+		fakeManager, h := setup(config, t)
+		fakeManager.JoinChannelReturns(types.ChannelInfo{Name: "ch-id", ConsensusRelation: "consenter", Status: "active", Height: 1}, nil)
+		require.Equal(t, uint32(1), h.MaxRequestBodySize())
 
-	type testDef struct {
-		name         string
-		channel      string
-		fakeReturns  error
-		expectedCode int
-		expectedErr  error
-	}
+		resp := httptest.NewRecorder()
+		req := genJoinRequestFormData(t, validBlockBytes("ch-id"))
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "cannot read form from request body: multipart: NextPart: http: request body too large", resp)
 
-	testCases := []testDef{
-		{
-			name:         "success",
-			channel:      "my-channel",
-			fakeReturns:  nil,
-			expectedCode: http.StatusNoContent,
-			expectedErr:  nil,
+		h.SetMaxRequestBodySize(1024 * 1024)
+		require.Equal(t, uint32(1024*1024), h.MaxRequestBodySize())
+		resp = httptest.NewRecorder()
+		req = genJoinRequestFormData(t, validBlockBytes("ch-id"))
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusCreated, resp.Result().StatusCode)
+		require.Equal(t, 1, fakeManager.JoinChannelCallCount())
+
+		h.SetMaxRequestBodySize(1)
+		resp = httptest.NewRecorder()
+		req = genJoinRequestFormData(t, validBlockBytes("ch-id"))
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "cannot read form from request body: multipart: NextPart: http: request body too large", resp)
+		require.Equal(t, 1, fakeManager.JoinChannelCallCount())
+	})
+}
+
+func TestHTTPHandler_ServeHTTP_JoinBlock(t *testing.T) {
+	config := localconfig.ChannelParticipation{
+		Enabled:            true,
+		MaxRequestBodySize: 1024 * 1024,
+	}
+
+	getJoinBlock := func(h *channelparticipation.HTTPHandler, accept string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path.Join(channelparticipation.URLBaseV1Channels, "ch-id", "joinblock"), nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		h.ServeHTTP(resp, req)
+		return resp
+	}
+
+	t.Run("join then get", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		joinBlocks := map[string][]byte{}
+		fakeManager.JoinChannelStub = func(channelID string, block *common.Block, _ bool) (types.ChannelInfo, error) {
+			joinBlocks[channelID] = protoutil.MarshalOrPanic(block)
+			return types.ChannelInfo{Name: channelID, ConsensusRelation: "consenter", Status: "active", Height: 1}, nil
+		}
+		fakeManager.JoinBlockStub = func(channelID string) ([]byte, error) {
+			blockBytes, ok := joinBlocks[channelID]
+			if !ok {
+				return nil, types.ErrChannelNotExist
+			}
+			return blockBytes, nil
+		}
+
+		sentBlockBytes := validBlockBytes("ch-id")
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, genJoinRequestFormData(t, sentBlockBytes))
+		require.Equal(t, http.StatusCreated, resp.Result().StatusCode)
+
+		for _, accept := range []string{"", "application/octet-stream", "*/*"} {
+			resp = getJoinBlock(h, accept)
+			require.Equal(t, http.StatusOK, resp.Result().StatusCode, "Accept: %s", accept)
+			require.Equal(t, "application/octet-stream", resp.Result().Header.Get("Content-Type"))
+			require.Equal(t, "no-store", resp.Result().Header.Get("Cache-Control"))
+			require.Equal(t, joinBlocks["ch-id"], resp.Body.Bytes())
+
+			sentBlock, err := protoutil.UnmarshalBlock(sentBlockBytes)
+			require.NoError(t, err)
+			gotBlock, err := protoutil.UnmarshalBlock(resp.Body.Bytes())
+			require.NoError(t, err)
+			require.True(t, proto.Equal(sentBlock, gotBlock))
+		}
+	})
+
+	t.Run("Error: Channel does not exist", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.JoinBlockReturns(nil, types.ErrChannelNotExist)
+		resp := getJoinBlock(h, "")
+		checkErrorResponse(t, http.StatusNotFound, "channel does not exist", resp)
+	})
+
+	t.Run("Error: Join block does not exist", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.JoinBlockReturns(nil, types.ErrJoinBlockNotExist)
+		resp := getJoinBlock(h, "")
+		checkErrorResponse(t, http.StatusNotFound, "join block does not exist", resp)
+	})
+
+	t.Run("Error: Channel pending removal", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.JoinBlockReturns(nil, types.ErrChannelPendingRemoval)
+		resp := getJoinBlock(h, "")
+		checkErrorResponse(t, http.StatusConflict, "channel pending removal", resp)
+	})
+
+	t.Run("Error: read failure", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.JoinBlockReturns(nil, errors.New("disk on fire"))
+		resp := getJoinBlock(h, "")
+		checkErrorResponse(t, http.StatusInternalServerError, "disk on fire", resp)
+	})
+
+	t.Run("Error: Accept not acceptable", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		resp := getJoinBlock(h, "application/json")
+		checkErrorResponse(t, http.StatusNotAcceptable, "response Content-Type is application/octet-stream only", resp)
+		require.Equal(t, 0, fakeManager.JoinBlockCallCount())
+	})
+}
+
+func TestHTTPHandler_ServeHTTP_JoinGzip(t *testing.T) {
+	config := localconfig.ChannelParticipation{
+		Enabled:            true,
+		MaxRequestBodySize: 1024 * 1024,
+	}
+
+	// gzipRequest compresses the body of a join request
+	gzipRequest := func(t *testing.T, req *http.Request) *http.Request {
+		body, err := ioutil.ReadAll(req.Body)
+		require.NoError(t, err)
+		compressed := new(bytes.Buffer)
+		writer := gzip.NewWriter(compressed)
+		_, err = writer.Write(body)
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		gzipReq := httptest.NewRequest(http.MethodPost, channelparticipation.URLBaseV1Channels, compressed)
+		gzipReq.Header.Set("Content-Type", req.Header.Get("Content-Type"))
+		gzipReq.Header.Set("Content-Encoding", "gzip")
+		return gzipReq
+	}
+
+	t.Run("multipart form", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.JoinChannelReturns(types.ChannelInfo{Name: "ch-id"}, nil)
+
+		blockBytes := validBlockBytes("ch-id")
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, gzipRequest(t, genJoinRequestFormData(t, blockBytes)))
+		require.Equal(t, http.StatusCreated, resp.Result().StatusCode)
+
+		require.Equal(t, 1, fakeManager.JoinChannelCallCount())
+		_, block, _ := fakeManager.JoinChannelArgsForCall(0)
+		require.Equal(t, blockBytes, protoutil.MarshalOrPanic(block))
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.JoinChannelReturns(types.ChannelInfo{Name: "ch-id"}, nil)
+
+		blockBytes := validBlockBytes("ch-id")
+		body := fmt.Sprintf(`{"configBlock": "%s"}`, base64.StdEncoding.EncodeToString(blockBytes))
+		req := httptest.NewRequest(http.MethodPost, channelparticipation.URLBaseV1Channels, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, gzipRequest(t, req))
+		require.Equal(t, http.StatusCreated, resp.Result().StatusCode)
+
+		require.Equal(t, 1, fakeManager.JoinChannelCallCount())
+		_, block, _ := fakeManager.JoinChannelArgsForCall(0)
+		require.Equal(t, blockBytes, protoutil.MarshalOrPanic(block))
+	})
+
+	t.Run("the size limit applies to the decompressed body", func(t *testing.T) {
+		config := localconfig.ChannelParticipation{
+			Enabled:            true,
+			MaxRequestBodySize: 1024,
+		}
+		fakeManager, h := setup(config, t)
+
+		// zeros compress to far less than the limit
+		req := gzipRequest(t, genJoinRequestFormData(t, make([]byte, 64*1024)))
+		require.True(t, req.ContentLength < 1024)
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "cannot read form from request body: http: request body too large", resp)
+		require.Equal(t, 0, fakeManager.JoinChannelCallCount())
+	})
+
+	t.Run("identity", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.JoinChannelReturns(types.ChannelInfo{Name: "ch-id"}, nil)
+
+		req := genJoinRequestFormData(t, validBlockBytes("ch-id"))
+		req.Header.Set("Content-Encoding", "identity")
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusCreated, resp.Result().StatusCode)
+		require.Equal(t, 1, fakeManager.JoinChannelCallCount())
+	})
+
+	t.Run("invalid gzip", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+
+		req := genJoinRequestFormData(t, validBlockBytes("ch-id"))
+		req.Header.Set("Content-Encoding", "gzip")
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "cannot decode gzip request body: gzip: invalid header", resp)
+		require.Equal(t, 0, fakeManager.JoinChannelCallCount())
+	})
+
+	t.Run("unsupported encoding", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+
+		req := genJoinRequestFormData(t, validBlockBytes("ch-id"))
+		req.Header.Set("Content-Encoding", "br")
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusUnsupportedMediaType, "unsupported Content-Encoding: br", resp)
+		require.Equal(t, 0, fakeManager.JoinChannelCallCount())
+	})
+}
+
+func TestHTTPHandler_ServeHTTP_JoinSpooled(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "join-spool")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	// multipart forms spool file parts to os.TempDir()
+	defer os.Setenv("TMPDIR", os.Getenv("TMPDIR"))
+	os.Setenv("TMPDIR", tmpDir)
+
+	config := localconfig.ChannelParticipation{
+		Enabled:            true,
+		MaxRequestBodySize: 4 * 1024 * 1024,
+		SpoolThreshold:     64 * 1024,
+	}
+	fakeManager, h := setup(config, t)
+	fakeManager.JoinChannelReturns(types.ChannelInfo{Name: "ch-id"}, nil)
+
+	largeBlock := blockWithGroups(map[string]*common.ConfigGroup{"Application": {}}, "ch-id")
+	largeBlock.Metadata = &common.BlockMetadata{Metadata: [][]byte{bytes.Repeat([]byte{0xab}, 2*1024*1024)}}
+	largeBlockBytes := protoutil.MarshalOrPanic(largeBlock)
+
+	// the temporary files are observed while the request body is read, as they are removed before joining
+	spooledFiles := 0
+	req := genJoinRequestFormData(t, largeBlockBytes)
+	req.Body = &observingReader{
+		Reader: req.Body,
+		observe: func() {
+			files, err := ioutil.ReadDir(tmpDir)
+			require.NoError(t, err)
+			if len(files) > spooledFiles {
+				spooledFiles = len(files)
+			}
+		},
+	}
+
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusCreated, resp.Result().StatusCode)
+
+	require.Equal(t, 1, fakeManager.JoinChannelCallCount())
+	_, block, _ := fakeManager.JoinChannelArgsForCall(0)
+	require.Equal(t, largeBlockBytes, protoutil.MarshalOrPanic(block))
+
+	require.Equal(t, 1, spooledFiles, "the block is spooled to a temporary file")
+	leftovers, err := ioutil.ReadDir(tmpDir)
+	require.NoError(t, err)
+	require.Empty(t, leftovers, "the temporary file is removed")
+}
+
+// observingReader calls observe after every read.
+type observingReader struct {
+	io.Reader
+	observe func()
+}
+
+func (r *observingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.observe()
+	return n, err
+}
+
+func (r *observingReader) Close() error {
+	return nil
+}
+
+func TestHTTPHandler_ServeHTTP_JoinJSON(t *testing.T) {
+	config := localconfig.ChannelParticipation{
+		Enabled:            true,
+		MaxRequestBodySize: 1024 * 1024,
+	}
+
+	genJoinRequestJSON := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, channelparticipation.URLBaseV1Channels, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	t.Run("created ok", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.JoinChannelReturns(types.ChannelInfo{
+			Name:              "ch-id",
+			ConsensusRelation: "consenter",
+			Status:            "active",
+			Height:            1,
+		}, nil)
+
+		resp := httptest.NewRecorder()
+		blockBytes := validBlockBytes("ch-id")
+		body := fmt.Sprintf(`{"configBlock": "%s"}`, base64.StdEncoding.EncodeToString(blockBytes))
+		h.ServeHTTP(resp, genJoinRequestJSON(body))
+		require.Equal(t, http.StatusCreated, resp.Result().StatusCode)
+		require.Equal(t, channelparticipation.URLBaseV1Channels+"/ch-id", resp.Result().Header.Get("Location"))
+
+		require.Equal(t, 1, fakeManager.JoinChannelCallCount())
+		channelID, block, isAppChannel := fakeManager.JoinChannelArgsForCall(0)
+		require.Equal(t, "ch-id", channelID)
+		require.Equal(t, blockBytes, protoutil.MarshalOrPanic(block))
+		require.True(t, isAppChannel)
+	})
+
+	testCases := []struct {
+		name        string
+		body        string
+		expectedErr string
+	}{
+		{
+			name:        "invalid JSON",
+			body:        `{"configBlock": `,
+			expectedErr: "invalid join request: cannot decode request body: unexpected EOF",
 		},
 		{
-			name:         "bad channel ID",
-			channel:      "My-Channel",
-			fakeReturns:  nil,
-			expectedCode: http.StatusBadRequest,
-			expectedErr:  errors.New("invalid channel ID: 'My-Channel' contains illegal characters"),
+			name:        "missing field",
+			body:        `{}`,
+			expectedErr: "invalid join request: configBlock: missing",
 		},
 		{
-			name:         "channel does not exist",
-			channel:      "my-channel",
-			fakeReturns:  types.ErrChannelNotExist,
-			expectedCode: http.StatusNotFound,
-			expectedErr:  errors.Wrap(types.ErrChannelNotExist, "cannot remove"),
+			name:        "wrong type",
+			body:        `{"configBlock": 42}`,
+			expectedErr: "invalid join request: configBlock: expected string, got number",
 		},
 		{
-			name:         "some other error",
-			channel:      "my-channel",
-			fakeReturns:  os.ErrInvalid,
-			expectedCode: http.StatusBadRequest,
-			expectedErr:  errors.Wrap(os.ErrInvalid, "cannot remove"),
+			name:        "unknown field",
+			body:        `{"configBlock": "AA==", "channelID": "ch-id"}`,
+			expectedErr: `invalid join request: cannot decode request body: json: unknown field "channelID"`,
+		},
+		{
+			name:        "bad base64",
+			body:        `{"configBlock": "not base64!"}`,
+			expectedErr: "invalid join request: configBlock: invalid base64: illegal base64 data at input byte 3",
+		},
+		{
+			name:        "not a block",
+			body:        fmt.Sprintf(`{"configBlock": "%s"}`, base64.StdEncoding.EncodeToString([]byte("not a block"))),
+			expectedErr: "invalid join request: configBlock: cannot unmarshal into a block: ",
 		},
 	}
 
-	for _, testCase := range testCases {
-		t.Run(testCase.name, func(t *testing.T) {
-			fakeManager.RemoveChannelReturns(testCase.fakeReturns)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeManager, h := setup(config, t)
 			resp := httptest.NewRecorder()
-			target := path.Join(channelparticipation.URLBaseV1Channels, testCase.channel)
-			req := httptest.NewRequest(http.MethodDelete, target, nil)
-			h.ServeHTTP(resp, req)
+			h.ServeHTTP(resp, genJoinRequestJSON(tc.body))
+			require.Equal(t, http.StatusBadRequest, resp.Result().StatusCode)
+			require.Equal(t, "application/json", resp.Result().Header.Get("Content-Type"))
 
-			if testCase.expectedErr == nil {
-				require.Equal(t, testCase.expectedCode, resp.Result().StatusCode)
-				require.Equal(t, 0, resp.Body.Len(), "empty body")
-			} else {
-				checkErrorResponse(t, testCase.expectedCode, testCase.expectedErr.Error(), resp)
-			}
+			errResp := types.ErrorResponse{}
+			err := json.Unmarshal(resp.Body.Bytes(), &errResp)
+			require.NoError(t, err, "body: %s", resp.Body.String())
+			require.True(t, strings.HasPrefix(errResp.Error, tc.expectedErr), "error: %s", errResp.Error)
+			require.Equal(t, 0, fakeManager.JoinChannelCallCount())
 		})
 	}
+}
 
-	t.Run("Error: System Channel Exists", func(t *testing.T) {
+func TestHTTPHandler_ServeHTTP_JoinIfNotExists(t *testing.T) {
+	config := localconfig.ChannelParticipation{
+		Enabled:            true,
+		MaxRequestBodySize: 1024 * 1024,
+	}
+	info := types.ChannelInfo{
+		Name:              "ch-id",
+		ConsensusRelation: "consenter",
+		Status:            "active",
+		Height:            1,
+	}
+	expectedInfo := info
+	expectedInfo.URL = channelparticipation.URLBaseV1Channels + "/ch-id"
+
+	joinTwice := func(t *testing.T, setIfNotExists func(req *http.Request)) (*mocks.ChannelManagement, *httptest.ResponseRecorder, *httptest.ResponseRecorder) {
 		fakeManager, h := setup(config, t)
-		fakeManager.RemoveChannelReturns(types.ErrSystemChannelExists)
+		fakeManager.JoinChannelReturnsOnCall(0, info, nil)
+		fakeManager.JoinChannelReturnsOnCall(1, types.ChannelInfo{}, types.ErrChannelAlreadyExists)
+		fakeManager.ChannelInfoReturns(info, nil)
+
+		first := httptest.NewRecorder()
+		req := genJoinRequestFormData(t, validBlockBytes("ch-id"))
+		setIfNotExists(req)
+		h.ServeHTTP(first, req)
+
+		second := httptest.NewRecorder()
+		req = genJoinRequestFormData(t, validBlockBytes("ch-id"))
+		setIfNotExists(req)
+		h.ServeHTTP(second, req)
+
+		return fakeManager, first, second
+	}
+
+	checkInfo := func(t *testing.T, statusCode int, resp *httptest.ResponseRecorder) {
+		require.Equal(t, statusCode, resp.Result().StatusCode)
+		require.Equal(t, "application/json", resp.Result().Header.Get("Content-Type"))
+		infoResp := types.ChannelInfo{}
+		err := json.Unmarshal(resp.Body.Bytes(), &infoResp)
+		require.NoError(t, err, "cannot be unmarshaled")
+		require.Equal(t, expectedInfo, infoResp)
+	}
+
+	t.Run("header", func(t *testing.T) {
+		fakeManager, first, second := joinTwice(t, func(req *http.Request) {
+			req.Header.Set(channelparticipation.IfNotExistsHeader, "true")
+		})
+		checkInfo(t, http.StatusCreated, first)
+		checkInfo(t, http.StatusOK, second)
+		require.Equal(t, 2, fakeManager.JoinChannelCallCount())
+		require.Equal(t, 1, fakeManager.ChannelInfoCallCount())
+		require.Equal(t, "ch-id", fakeManager.ChannelInfoArgsForCall(0))
+	})
+
+	t.Run("query", func(t *testing.T) {
+		_, first, second := joinTwice(t, func(req *http.Request) {
+			req.URL.RawQuery = "ifNotExists=true"
+		})
+		checkInfo(t, http.StatusCreated, first)
+		checkInfo(t, http.StatusOK, second)
+	})
+
+	t.Run("not set", func(t *testing.T) {
+		fakeManager, first, second := joinTwice(t, func(req *http.Request) {
+			req.Header.Set(channelparticipation.IfNotExistsHeader, "false")
+		})
+		checkInfo(t, http.StatusCreated, first)
+		checkErrorResponse(t, http.StatusMethodNotAllowed, "cannot join: channel already exists", second)
+		require.Equal(t, 0, fakeManager.ChannelInfoCallCount())
+	})
+
+	t.Run("other join errors are not masked", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.JoinChannelReturns(types.ChannelInfo{}, types.ErrChannelPendingRemoval)
 		resp := httptest.NewRecorder()
-		req := httptest.NewRequest(http.MethodDelete, path.Join(channelparticipation.URLBaseV1Channels, "my-channel"), nil)
+		req := genJoinRequestFormData(t, validBlockBytes("ch-id"))
+		req.Header.Set(channelparticipation.IfNotExistsHeader, "true")
 		h.ServeHTTP(resp, req)
-		checkErrorResponse(t, http.StatusMethodNotAllowed, "cannot remove: system channel exists", resp)
-		require.Equal(t, "GET", resp.Result().Header.Get("Allow"))
+		checkErrorResponse(t, http.StatusConflict, "cannot join: channel pending removal", resp)
+		require.Equal(t, 0, fakeManager.ChannelInfoCallCount())
 	})
+}
 
-	t.Run("Error: Channel Pending Removal", func(t *testing.T) {
+func TestHTTPHandler_ServeHTTP_JoinDryRun(t *testing.T) {
+	config := localconfig.ChannelParticipation{
+		Enabled:            true,
+		MaxRequestBodySize: 1024 * 1024,
+	}
+
+	dryRunJoin := func(h *channelparticipation.HTTPHandler, blockBytes []byte) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		req := genJoinRequestFormData(t, blockBytes)
+		req.URL.RawQuery = "dryRun=true"
+		h.ServeHTTP(resp, req)
+		return resp
+	}
+
+	t.Run("valid block", func(t *testing.T) {
 		fakeManager, h := setup(config, t)
-		fakeManager.RemoveChannelReturns(types.ErrChannelPendingRemoval)
+		targetHeight := uint64(11)
+		fakeManager.ValidateJoinReturns(types.ChannelInfo{
+			Name:              "ch-id",
+			ConsensusRelation: "follower",
+			Status:            "onboarding",
+			TargetHeight:      &targetHeight,
+		}, nil)
+
+		resp := dryRunJoin(h, validBlockBytes("ch-id"))
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+		require.Equal(t, "application/json", resp.Result().Header.Get("Content-Type"))
+		require.Empty(t, resp.Result().Header.Get("Location"))
+		infoResp := types.ChannelInfo{}
+		err := json.Unmarshal(resp.Body.Bytes(), &infoResp)
+		require.NoError(t, err, "cannot be unmarshaled")
+		require.Equal(t, types.ChannelInfo{
+			Name:              "ch-id",
+			URL:               channelparticipation.URLBaseV1Channels + "/ch-id",
+			ConsensusRelation: "follower",
+			Status:            "onboarding",
+			TargetHeight:      &targetHeight,
+		}, infoResp)
+
+		require.Equal(t, 1, fakeManager.ValidateJoinCallCount())
+		channelID, block, isAppChannel := fakeManager.ValidateJoinArgsForCall(0)
+		require.Equal(t, "ch-id", channelID)
+		require.NotNil(t, block)
+		require.True(t, isAppChannel)
+		require.Equal(t, 0, fakeManager.JoinChannelCallCount())
+	})
+
+	t.Run("invalid block", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		resp := dryRunJoin(h, protoutil.MarshalOrPanic(blockWithGroups(map[string]*common.ConfigGroup{}, "ch-id")))
+		checkErrorResponse(t, http.StatusUnprocessableEntity, "invalid join block: invalid config: must have at least one of application or consortiums", resp)
+		require.Equal(t, 0, fakeManager.ValidateJoinCallCount())
+		require.Equal(t, 0, fakeManager.JoinChannelCallCount())
+	})
+
+	t.Run("join would fail", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.ValidateJoinReturns(types.ChannelInfo{}, types.ErrChannelAlreadyExists)
+		resp := dryRunJoin(h, validBlockBytes("ch-id"))
+		checkErrorResponse(t, http.StatusMethodNotAllowed, "cannot join: channel already exists", resp)
+		require.Equal(t, 0, fakeManager.JoinChannelCallCount())
+	})
+
+	t.Run("not set", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.JoinChannelReturns(types.ChannelInfo{Name: "ch-id"}, nil)
 		resp := httptest.NewRecorder()
-		req := httptest.NewRequest(http.MethodDelete, path.Join(channelparticipation.URLBaseV1Channels, "my-channel"), nil)
+		req := genJoinRequestFormData(t, validBlockBytes("ch-id"))
+		req.URL.RawQuery = "dryRun=false"
 		h.ServeHTTP(resp, req)
-		checkErrorResponse(t, http.StatusConflict, "cannot remove: channel pending removal", resp)
+		require.Equal(t, http.StatusCreated, resp.Result().StatusCode)
+		require.Equal(t, 0, fakeManager.ValidateJoinCallCount())
+		require.Equal(t, 1, fakeManager.JoinChannelCallCount())
 	})
 }
 
-func setup(config localconfig.ChannelParticipation, t *testing.T) (*mocks.ChannelManagement, *channelparticipation.HTTPHandler) {
+func TestHTTPHandler_ServeHTTP_JoinChunked(t *testing.T) {
+	config := localconfig.ChannelParticipation{
+		Enabled:            true,
+		MaxRequestBodySize: 1024 * 1024,
+	}
+
+	// postChunked streams the form to the server through a pipe, so that the client uses chunked transfer
+	// encoding and does not send a Content-Length header.
+	postChunked := func(t *testing.T, url string, blockBytes []byte) *http.Response {
+		pr, pw := io.Pipe()
+		writer := multipart.NewWriter(pw)
+		go func() {
+			part, err := writer.CreateFormFile(channelparticipation.FormDataConfigBlockKey, "join-config.block")
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			part.Write(blockBytes)
+			pw.CloseWithError(writer.Close())
+		}()
+
+		req, err := http.NewRequest(http.MethodPost, url+channelparticipation.URLBaseV1Channels, pr)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		require.Equal(t, int64(0), req.ContentLength)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("created ok", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.JoinChannelReturns(types.ChannelInfo{
+			Name:              "app-channel",
+			ConsensusRelation: "consenter",
+			Status:            "active",
+			Height:            1,
+		}, nil)
+		var transferEncoding []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			transferEncoding = r.TransferEncoding
+			h.ServeHTTP(w, r)
+		}))
+		defer server.Close()
+
+		resp := postChunked(t, server.URL, validBlockBytes("ch-id"))
+		defer resp.Body.Close()
+		require.Equal(t, []string{"chunked"}, transferEncoding)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		require.Equal(t, 1, fakeManager.JoinChannelCallCount())
+		channelID, _, _ := fakeManager.JoinChannelArgsForCall(0)
+		require.Equal(t, "ch-id", channelID)
+	})
+
+	t.Run("body larger that MaxRequestBodySize", func(t *testing.T) {
+		config := localconfig.ChannelParticipation{
+			Enabled:            true,
+			MaxRequestBodySize: 1024,
+		}
+		fakeManager, h := setup(config, t)
+		server := httptest.NewServer(h)
+		defer server.Close()
+
+		resp := postChunked(t, server.URL, make([]byte, 4096))
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		respErr := &types.ErrorResponse{}
+		err := json.NewDecoder(resp.Body).Decode(respErr)
+		require.NoError(t, err)
+		require.Contains(t, respErr.Error, "http: request body too large")
+		require.Equal(t, 0, fakeManager.JoinChannelCallCount())
+	})
+}
+
+func TestHTTPHandler_ServeHTTP_Remove(t *testing.T) {
+	config := localconfig.ChannelParticipation{Enabled: true}
+	fakeManager, h := setup(config, t)
+
+	type testDef struct {
+		name         string
+		channel      string
+		fakeReturns  error
+		expectedCode int
+		expectedErr  error
+	}
+
+	testCases := []testDef{
+		{
+			name:         "success",
+			channel:      "my-channel",
+			fakeReturns:  nil,
+			expectedCode: http.StatusNoContent,
+			expectedErr:  nil,
+		},
+		{
+			name:         "bad channel ID",
+			channel:      "My-Channel",
+			fakeReturns:  nil,
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  errors.New("invalid channel ID: 'My-Channel' contains illegal characters"),
+		},
+		{
+			name:         "channel does not exist",
+			channel:      "my-channel",
+			fakeReturns:  types.ErrChannelNotExist,
+			expectedCode: http.StatusNotFound,
+			expectedErr:  errors.Wrap(types.ErrChannelNotExist, "cannot remove"),
+		},
+		{
+			name:         "some other error",
+			channel:      "my-channel",
+			fakeReturns:  os.ErrInvalid,
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  errors.Wrap(os.ErrInvalid, "cannot remove"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			fakeManager.RemoveChannelReturns(testCase.fakeReturns)
+			resp := httptest.NewRecorder()
+			target := path.Join(channelparticipation.URLBaseV1Channels, testCase.channel)
+			req := httptest.NewRequest(http.MethodDelete, target, nil)
+			h.ServeHTTP(resp, req)
+
+			if testCase.expectedErr == nil {
+				require.Equal(t, testCase.expectedCode, resp.Result().StatusCode)
+				require.Equal(t, 0, resp.Body.Len(), "empty body")
+			} else {
+				checkErrorResponse(t, testCase.expectedCode, testCase.expectedErr.Error(), resp)
+			}
+		})
+	}
+
+	t.Run("Error: System Channel Exists", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.RemoveChannelReturns(types.ErrSystemChannelExists)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, path.Join(channelparticipation.URLBaseV1Channels, "my-channel"), nil)
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusMethodNotAllowed, "cannot remove: system channel exists", resp)
+		require.Equal(t, "GET", resp.Result().Header.Get("Allow"))
+	})
+
+	t.Run("Error: Channel Pending Removal", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.RemoveChannelReturns(types.ErrChannelPendingRemoval)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, path.Join(channelparticipation.URLBaseV1Channels, "my-channel"), nil)
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusConflict, "cannot remove: channel pending removal", resp)
+	})
+
+	t.Run("return representation", func(t *testing.T) {
+		finalInfo := types.ChannelInfo{
+			Name:              "my-channel",
+			ConsensusRelation: types.ConsensusRelationFollower,
+			Status:            types.StatusActive,
+			Height:            42,
+		}
+
+		for name, setPreference := range map[string]func(req *http.Request){
+			"with the Prefer header": func(req *http.Request) {
+				req.Header.Set("Prefer", "handling=lenient, return=representation")
+			},
+			"with the return query parameter": func(req *http.Request) {
+				req.URL.RawQuery = "return=representation"
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				fakeManager, h := setup(config, t)
+				fakeManager.ChannelInfoReturns(finalInfo, nil)
+				resp := httptest.NewRecorder()
+				req := httptest.NewRequest(http.MethodDelete, path.Join(channelparticipation.URLBaseV1Channels, "my-channel"), nil)
+				setPreference(req)
+				h.ServeHTTP(resp, req)
+
+				require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+				require.Equal(t, "application/json", resp.Result().Header.Get("Content-Type"))
+				require.Equal(t, "return=representation", resp.Result().Header.Get("Preference-Applied"))
+				info := types.ChannelInfo{}
+				require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &info))
+				expectedInfo := finalInfo
+				expectedInfo.URL = "/participation/v1/channels/my-channel"
+				require.Equal(t, expectedInfo, info)
+				require.Equal(t, 1, fakeManager.RemoveChannelCallCount())
+			})
+		}
+
+		t.Run("not preferred", func(t *testing.T) {
+			fakeManager, h := setup(config, t)
+			fakeManager.ChannelInfoReturns(finalInfo, nil)
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodDelete, path.Join(channelparticipation.URLBaseV1Channels, "my-channel"), nil)
+			req.Header.Set("Prefer", "return=minimal")
+			h.ServeHTTP(resp, req)
+
+			require.Equal(t, http.StatusNoContent, resp.Result().StatusCode)
+			require.Equal(t, 0, resp.Body.Len(), "empty body")
+			require.Equal(t, 0, fakeManager.ChannelInfoCallCount())
+		})
+
+		t.Run("remove fails", func(t *testing.T) {
+			fakeManager, h := setup(config, t)
+			fakeManager.ChannelInfoReturns(types.ChannelInfo{}, types.ErrChannelNotExist)
+			fakeManager.RemoveChannelReturns(types.ErrChannelNotExist)
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodDelete, path.Join(channelparticipation.URLBaseV1Channels, "my-channel"), nil)
+			req.Header.Set("Prefer", "return=representation")
+			h.ServeHTTP(resp, req)
+
+			checkErrorResponse(t, http.StatusNotFound, "cannot remove: channel does not exist", resp)
+			require.Empty(t, resp.Result().Header.Get("Preference-Applied"))
+		})
+	})
+}
+
+func TestHTTPHandler_ServeHTTP_RemoveAsync(t *testing.T) {
+	config := localconfig.ChannelParticipation{Enabled: true}
+	activeInfo := types.ChannelInfo{Name: "my-channel", ConsensusRelation: types.ConsensusRelationConsenter, Status: types.StatusActive, Height: 5}
+
+	removeAsync := func(h *channelparticipation.HTTPHandler, setPreference func(*http.Request)) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, path.Join(channelparticipation.URLBaseV1Channels, "my-channel"), nil)
+		setPreference(req)
+		h.ServeHTTP(resp, req)
+		return resp
+	}
+	preferAsync := func(req *http.Request) { req.Header.Set("Prefer", "respond-async") }
+	// setupAsync returns a handler whose registrar returns the channel information set with setChannelInfo, which
+	// may be called while a removal polls the registrar.
+	setupAsync := func(config localconfig.ChannelParticipation, t *testing.T) (fakeManager *mocks.ChannelManagement, h *channelparticipation.HTTPHandler, setChannelInfo func(types.ChannelInfo, error)) {
+		fakeManager, h = setup(config, t)
+		var (
+			lock sync.Mutex
+			info types.ChannelInfo
+			err  error
+		)
+		fakeManager.ChannelInfoStub = func(string) (types.ChannelInfo, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			return info, err
+		}
+		setChannelInfo = func(i types.ChannelInfo, e error) {
+			lock.Lock()
+			defer lock.Unlock()
+			info, err = i, e
+		}
+		return fakeManager, h, setChannelInfo
+	}
+	getStatus := func(h *channelparticipation.HTTPHandler) (int, types.Status) {
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path.Join(channelparticipation.URLBaseV1Channels, "my-channel"), nil)
+		h.ServeHTTP(resp, req)
+		info := types.ChannelInfo{}
+		json.Unmarshal(resp.Body.Bytes(), &info)
+		return resp.Result().StatusCode, info.Status
+	}
+
+	t.Run("accepted, removing, then gone", func(t *testing.T) {
+		fakeManager, h, setChannelInfo := setupAsync(config, t)
+		setChannelInfo(activeInfo, nil)
+		release := make(chan struct{})
+		fakeManager.RemoveChannelStub = func(string) error {
+			<-release
+			return nil
+		}
+
+		resp := removeAsync(h, preferAsync)
+		require.Equal(t, http.StatusAccepted, resp.Result().StatusCode)
+		require.Equal(t, "/participation/v1/channels/my-channel", resp.Result().Header.Get("Location"))
+		require.Equal(t, "respond-async", resp.Result().Header.Get("Preference-Applied"))
+		info := types.ChannelInfo{}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &info))
+		require.Equal(t, types.StatusRemoving, info.Status)
+		require.Equal(t, uint64(5), info.Height)
+
+		code, status := getStatus(h)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, types.StatusRemoving, status)
+
+		// a second removal is refused while the first is in progress
+		resp = removeAsync(h, preferAsync)
+		checkErrorResponse(t, http.StatusConflict, "cannot remove: channel pending removal", resp)
+
+		close(release)
+		require.Eventually(t, func() bool { return fakeManager.RemoveChannelCallCount() == 1 }, time.Minute, 10*time.Millisecond)
+
+		// the ledger is still being released
+		_, status = getStatus(h)
+		require.Equal(t, types.StatusRemoving, status)
+
+		setChannelInfo(types.ChannelInfo{}, types.ErrChannelNotExist)
+		code, _ = getStatus(h)
+		require.Equal(t, http.StatusNotFound, code)
+		// the removal is over once the ledger is released
+		require.Eventually(t, func() bool { return removeAsync(h, preferAsync).Code == http.StatusNotFound }, time.Minute, 10*time.Millisecond)
+
+		// a channel joined again under the same name is not reported as removing
+		setChannelInfo(activeInfo, nil)
+		_, status = getStatus(h)
+		require.Equal(t, types.StatusActive, status)
+	})
+
+	t.Run("ledger not released before the removal timeout", func(t *testing.T) {
+		config := config
+		config.MaxConcurrentJoins = 1
+		config.RemovalTimeout = 200 * time.Millisecond
+		fakeManager, h, setChannelInfo := setupAsync(config, t)
+		// the registrar keeps reporting the channel, as if its ledger was never released
+		setChannelInfo(activeInfo, nil)
+
+		resp := removeAsync(h, preferAsync)
+		require.Equal(t, http.StatusAccepted, resp.Result().StatusCode)
+		require.Eventually(t, func() bool { return fakeManager.RemoveChannelCallCount() == 1 }, time.Minute, 10*time.Millisecond)
+		_, status := getStatus(h)
+		require.Equal(t, types.StatusRemoving, status)
+
+		// the removal gives up waiting, and releases its slot of MaxConcurrentJoins
+		require.Eventually(t, func() bool {
+			_, status := getStatus(h)
+			return status == types.StatusActive
+		}, time.Minute, 10*time.Millisecond)
+		require.Eventually(t, func() bool { return removeAsync(h, preferAsync).Code == http.StatusAccepted }, time.Minute, 10*time.Millisecond)
+	})
+
+	t.Run("async query parameter", func(t *testing.T) {
+		fakeManager, h, setChannelInfo := setupAsync(config, t)
+		setChannelInfo(activeInfo, nil)
+		resp := removeAsync(h, func(req *http.Request) { req.URL.RawQuery = "async=true" })
+		require.Equal(t, http.StatusAccepted, resp.Result().StatusCode)
+		require.Eventually(t, func() bool { return fakeManager.RemoveChannelCallCount() == 1 }, time.Minute, 10*time.Millisecond)
+		require.Equal(t, "my-channel", fakeManager.RemoveChannelArgsForCall(0))
+	})
+
+	t.Run("removal fails", func(t *testing.T) {
+		fakeManager, h, setChannelInfo := setupAsync(config, t)
+		setChannelInfo(activeInfo, nil)
+		fakeManager.RemoveChannelReturns(errors.New("halt failed"))
+		resp := removeAsync(h, preferAsync)
+		require.Equal(t, http.StatusAccepted, resp.Result().StatusCode)
+		require.Eventually(t, func() bool {
+			_, status := getStatus(h)
+			return status == types.StatusActive
+		}, time.Minute, 10*time.Millisecond)
+	})
+
+	t.Run("ledger removal fails", func(t *testing.T) {
+		fakeManager, h, setChannelInfo := setupAsync(config, t)
+		setChannelInfo(activeInfo, nil)
+		resp := removeAsync(h, preferAsync)
+		require.Equal(t, http.StatusAccepted, resp.Result().StatusCode)
+		require.Eventually(t, func() bool { return fakeManager.RemoveChannelCallCount() == 1 }, time.Minute, 10*time.Millisecond)
+
+		setChannelInfo(types.ChannelInfo{Name: "my-channel", Status: types.StatusFailed}, nil)
+		_, status := getStatus(h)
+		require.Equal(t, types.StatusFailed, status)
+		// the removal is over, so that it can be retried
+		require.Eventually(t, func() bool { return removeAsync(h, preferAsync).Code == http.StatusAccepted }, time.Minute, 10*time.Millisecond)
+	})
+
+	t.Run("removal holds a join slot until the ledger is released", func(t *testing.T) {
+		fakeManager, h, setChannelInfo := setupAsync(localconfig.ChannelParticipation{Enabled: true, MaxRequestBodySize: 1024 * 1024, MaxConcurrentJoins: 1}, t)
+		setChannelInfo(activeInfo, nil)
+		resp := removeAsync(h, preferAsync)
+		require.Equal(t, http.StatusAccepted, resp.Result().StatusCode)
+		require.Eventually(t, func() bool { return fakeManager.RemoveChannelCallCount() == 1 }, time.Minute, 10*time.Millisecond)
+
+		resp = httptest.NewRecorder()
+		h.ServeHTTP(resp, genJoinRequestFormData(t, validBlockBytes("other-channel")))
+		checkErrorResponse(t, http.StatusTooManyRequests, "too many concurrent join or remove requests", resp)
+
+		setChannelInfo(types.ChannelInfo{}, types.ErrChannelNotExist)
+		fakeManager.JoinChannelReturns(types.ChannelInfo{Name: "other-channel"}, nil)
+		require.Eventually(t, func() bool {
+			resp := httptest.NewRecorder()
+			h.ServeHTTP(resp, genJoinRequestFormData(t, validBlockBytes("other-channel")))
+			return resp.Code == http.StatusCreated
+		}, time.Minute, 10*time.Millisecond)
+	})
+
+	t.Run("channel does not exist", func(t *testing.T) {
+		fakeManager, h, setChannelInfo := setupAsync(config, t)
+		setChannelInfo(types.ChannelInfo{}, types.ErrChannelNotExist)
+		resp := removeAsync(h, preferAsync)
+		checkErrorResponse(t, http.StatusNotFound, "cannot remove: channel does not exist", resp)
+		require.Equal(t, 0, fakeManager.RemoveChannelCallCount())
+	})
+
+	t.Run("system channel exists", func(t *testing.T) {
+		fakeManager, h, setChannelInfo := setupAsync(config, t)
+		fakeManager.ChannelListReturns(types.ChannelList{SystemChannel: &types.ChannelInfoShort{Name: "system-channel"}})
+		setChannelInfo(activeInfo, nil)
+		resp := removeAsync(h, preferAsync)
+		checkErrorResponse(t, http.StatusMethodNotAllowed, "cannot remove: system channel exists", resp)
+		require.Equal(t, 0, fakeManager.RemoveChannelCallCount())
+	})
+}
+
+func TestHTTPHandler_ServeHTTP_RemoveProtectConsenters(t *testing.T) {
+	config := localconfig.ChannelParticipation{Enabled: true, ProtectConsenters: true}
+
+	remove := func(h *channelparticipation.HTTPHandler, query string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, path.Join(channelparticipation.URLBaseV1Channels, "my-channel")+query, nil)
+		h.ServeHTTP(resp, req)
+		return resp
+	}
+
+	for _, relation := range []types.ConsensusRelation{types.ConsensusRelationFollower, types.ConsensusRelationConfigTracker} {
+		t.Run(string(relation)+" removal allowed", func(t *testing.T) {
+			fakeManager, h := setup(config, t)
+			fakeManager.ChannelInfoReturns(types.ChannelInfo{Name: "my-channel", ConsensusRelation: relation}, nil)
+			resp := remove(h, "")
+			require.Equal(t, http.StatusNoContent, resp.Result().StatusCode)
+			require.Equal(t, 1, fakeManager.RemoveChannelCallCount())
+		})
+	}
+
+	for _, relation := range []types.ConsensusRelation{types.ConsensusRelationConsenter, types.ConsensusRelationOther} {
+		t.Run(string(relation)+" removal blocked", func(t *testing.T) {
+			fakeManager, h := setup(config, t)
+			fakeManager.ChannelInfoReturns(types.ChannelInfo{Name: "my-channel", ConsensusRelation: relation}, nil)
+			resp := remove(h, "")
+			checkErrorResponse(t, http.StatusConflict, "cannot remove: this orderer is an active consenter of channel my-channel, use force=true to remove it anyway", resp)
+			require.Equal(t, 0, fakeManager.RemoveChannelCallCount())
+		})
+	}
+
+	t.Run("consenter removal forced", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.ChannelInfoReturns(types.ChannelInfo{Name: "my-channel", ConsensusRelation: types.ConsensusRelationConsenter}, nil)
+		resp := remove(h, "?force=true")
+		require.Equal(t, http.StatusNoContent, resp.Result().StatusCode)
+		require.Equal(t, 1, fakeManager.RemoveChannelCallCount())
+		require.Equal(t, 0, fakeManager.ChannelInfoCallCount())
+	})
+
+	t.Run("channel does not exist", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.ChannelInfoReturns(types.ChannelInfo{}, types.ErrChannelNotExist)
+		fakeManager.RemoveChannelReturns(types.ErrChannelNotExist)
+		resp := remove(h, "")
+		checkErrorResponse(t, http.StatusNotFound, "cannot remove: channel does not exist", resp)
+	})
+
+	t.Run("not protected", func(t *testing.T) {
+		fakeManager, h := setup(localconfig.ChannelParticipation{Enabled: true}, t)
+		fakeManager.ChannelInfoReturns(types.ChannelInfo{Name: "my-channel", ConsensusRelation: types.ConsensusRelationConsenter}, nil)
+		resp := remove(h, "")
+		require.Equal(t, http.StatusNoContent, resp.Result().StatusCode)
+		require.Equal(t, 0, fakeManager.ChannelInfoCallCount())
+	})
+}
+
+func TestHTTPHandler_ServeHTTP_RemoveProtectSoleConsenter(t *testing.T) {
+	config := localconfig.ChannelParticipation{Enabled: true, ProtectSoleConsenter: true}
+
+	remove := func(h *channelparticipation.HTTPHandler, query string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, path.Join(channelparticipation.URLBaseV1Channels, "my-channel")+query, nil)
+		h.ServeHTTP(resp, req)
+		return resp
+	}
+
+	t.Run("sole consenter removal blocked", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.ChannelInfoReturns(types.ChannelInfo{Name: "my-channel", ConsensusRelation: types.ConsensusRelationConsenter}, nil)
+		fakeManager.ConsenterCountReturns(1, nil)
+		resp := remove(h, "")
+		checkErrorResponse(t, http.StatusConflict, "cannot remove: this orderer is the sole consenter of channel my-channel, use force=true to remove it anyway", resp)
+		require.Equal(t, 0, fakeManager.RemoveChannelCallCount())
+		require.Equal(t, "my-channel", fakeManager.ConsenterCountArgsForCall(0))
+	})
+
+	t.Run("sole consenter removal forced", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.ChannelInfoReturns(types.ChannelInfo{Name: "my-channel", ConsensusRelation: types.ConsensusRelationConsenter}, nil)
+		fakeManager.ConsenterCountReturns(1, nil)
+		resp := remove(h, "?force=true")
+		require.Equal(t, http.StatusNoContent, resp.Result().StatusCode)
+		require.Equal(t, 1, fakeManager.RemoveChannelCallCount())
+		require.Equal(t, 0, fakeManager.ConsenterCountCallCount())
+	})
+
+	t.Run("one of several consenters removal allowed", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.ChannelInfoReturns(types.ChannelInfo{Name: "my-channel", ConsensusRelation: types.ConsensusRelationConsenter}, nil)
+		fakeManager.ConsenterCountReturns(3, nil)
+		resp := remove(h, "")
+		require.Equal(t, http.StatusNoContent, resp.Result().StatusCode)
+		require.Equal(t, 1, fakeManager.RemoveChannelCallCount())
+	})
+
+	t.Run("follower removal allowed", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.ChannelInfoReturns(types.ChannelInfo{Name: "my-channel", ConsensusRelation: types.ConsensusRelationFollower}, nil)
+		resp := remove(h, "")
+		require.Equal(t, http.StatusNoContent, resp.Result().StatusCode)
+		require.Equal(t, 1, fakeManager.RemoveChannelCallCount())
+		require.Equal(t, 0, fakeManager.ConsenterCountCallCount())
+	})
+
+	t.Run("consenter set cannot be read", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.ChannelInfoReturns(types.ChannelInfo{Name: "my-channel", ConsensusRelation: types.ConsensusRelationConsenter}, nil)
+		fakeManager.ConsenterCountReturns(0, errors.New("consensus type solo has no consenter set"))
+		resp := remove(h, "")
+		require.Equal(t, http.StatusNoContent, resp.Result().StatusCode)
+		require.Equal(t, 1, fakeManager.RemoveChannelCallCount())
+	})
+}
+
+func TestHTTPHandler_ServeHTTP_Webhook(t *testing.T) {
+	events := make(chan types.ChannelEvent, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		event := types.ChannelEvent{}
+		err := json.NewDecoder(r.Body).Decode(&event)
+		require.NoError(t, err)
+		events <- event
+	}))
+	defer receiver.Close()
+
+	config := localconfig.ChannelParticipation{
+		Enabled:            true,
+		MaxRequestBodySize: 1024 * 1024,
+		WebhookURL:         receiver.URL,
+		WebhookTimeout:     time.Second,
+	}
+
+	t.Run("join", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.JoinChannelReturns(types.ChannelInfo{
+			Name:              "ch-id",
+			ConsensusRelation: types.ConsensusRelationFollower,
+			Status:            types.StatusOnBoarding,
+			Height:            1,
+		}, nil)
+		resp := httptest.NewRecorder()
+		req := genJoinRequestFormData(t, validBlockBytes("ch-id"))
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusCreated, resp.Result().StatusCode)
+
+		select {
+		case event := <-events:
+			require.Equal(t, types.ChannelEvent{
+				Type: types.ChannelEventJoin,
+				Channel: types.ChannelInfo{
+					Name:              "ch-id",
+					URL:               "/participation/v1/channels/ch-id",
+					ConsensusRelation: types.ConsensusRelationFollower,
+					Status:            types.StatusOnBoarding,
+					Height:            1,
+				},
+			}, event)
+		case <-time.After(5 * time.Second):
+			t.Fatal("webhook was not notified of the join")
+		}
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		_, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, path.Join(channelparticipation.URLBaseV1Channels, "ch-id"), nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusNoContent, resp.Result().StatusCode)
+
+		select {
+		case event := <-events:
+			require.Equal(t, types.ChannelEventRemove, event.Type)
+			require.Equal(t, "ch-id", event.Channel.Name)
+		case <-time.After(5 * time.Second):
+			t.Fatal("webhook was not notified of the removal")
+		}
+	})
+
+	t.Run("relation", func(t *testing.T) {
+		_, h := setup(config, t)
+		h.RelationChanged(types.ChannelInfo{
+			Name:              "ch-id",
+			ConsensusRelation: types.ConsensusRelationConsenter,
+			Status:            types.StatusActive,
+			Height:            12,
+		})
+
+		select {
+		case event := <-events:
+			require.Equal(t, types.ChannelEvent{
+				Type: types.ChannelEventRelation,
+				Channel: types.ChannelInfo{
+					Name:              "ch-id",
+					URL:               "/participation/v1/channels/ch-id",
+					ConsensusRelation: types.ConsensusRelationConsenter,
+					Status:            types.StatusActive,
+					Height:            12,
+				},
+			}, event)
+		case <-time.After(5 * time.Second):
+			t.Fatal("webhook was not notified of the relation change")
+		}
+	})
+
+	t.Run("failed operations are not notified", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.RemoveChannelReturns(types.ErrChannelNotExist)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, path.Join(channelparticipation.URLBaseV1Channels, "ch-id"), nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusNotFound, resp.Result().StatusCode)
+
+		select {
+		case event := <-events:
+			t.Fatalf("unexpected event: %v", event)
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+
+	t.Run("unreachable webhook does not block the operation", func(t *testing.T) {
+		unreachable := httptest.NewServer(http.NotFoundHandler())
+		unreachable.Close()
+		config := config
+		config.WebhookURL = unreachable.URL
+
+		fakeManager, h := setup(config, t)
+		fakeManager.JoinChannelReturns(types.ChannelInfo{Name: "ch-id"}, nil)
+		resp := httptest.NewRecorder()
+		req := genJoinRequestFormData(t, validBlockBytes("ch-id"))
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusCreated, resp.Result().StatusCode)
+	})
+}
+
+func TestHTTPHandler_ServeHTTP_Metrics(t *testing.T) {
+	config := localconfig.ChannelParticipation{
+		Enabled:            true,
+		MaxRequestBodySize: 1024 * 1024,
+		MetricsEnabled:     true,
+	}
+
+	t.Run("scrape", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.JoinChannelReturns(types.ChannelInfo{Name: "ch-id"}, nil)
+		fakeManager.ChannelListReturns(types.ChannelList{
+			Channels: []types.ChannelInfoShort{{Name: "app-channel1"}, {Name: "app-channel2"}},
+		})
+		fakeManager.ChannelInfoReturnsOnCall(0, types.ChannelInfo{Name: "app-channel1", Status: types.StatusActive}, nil)
+		fakeManager.ChannelInfoReturnsOnCall(1, types.ChannelInfo{Name: "app-channel2", Status: types.StatusOnBoarding}, nil)
+
+		resp := httptest.NewRecorder()
+		req := genJoinRequestFormData(t, validBlockBytes("ch-id"))
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusCreated, resp.Result().StatusCode)
+
+		resp = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodDelete, path.Join(channelparticipation.URLBaseV1Channels, "ch-id"), nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusNoContent, resp.Result().StatusCode)
+
+		resp = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Metrics, nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+		require.Contains(t, resp.Result().Header.Get("Content-Type"), "text/plain")
+
+		body := resp.Body.String()
+		require.Contains(t, body, "# TYPE participation_joins_total counter")
+		require.Contains(t, body, "participation_joins_total 1\n")
+		require.Contains(t, body, "# TYPE participation_removes_total counter")
+		require.Contains(t, body, "participation_removes_total 1\n")
+		require.Contains(t, body, "# TYPE participation_channels gauge")
+		require.Contains(t, body, `participation_channels{status="active"} 1`)
+		require.Contains(t, body, `participation_channels{status="onboarding"} 1`)
+		require.Contains(t, body, `participation_channels{status="failed"} 0`)
+		require.Contains(t, body, `participation_channels{status="inactive"} 0`)
+	})
+
+	t.Run("bad method", func(t *testing.T) {
+		_, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, channelparticipation.URLBaseV1Metrics, nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusMethodNotAllowed, resp.Result().StatusCode)
+		require.Equal(t, "GET", resp.Result().Header.Get("Allow"))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		config := config
+		config.MetricsEnabled = false
+		_, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Metrics, nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusNotFound, resp.Result().StatusCode)
+	})
+}
+
+func TestHTTPHandler_ServeHTTP_MinFreeDiskSpace(t *testing.T) {
+	config := localconfig.ChannelParticipation{
+		Enabled:            true,
+		MaxRequestBodySize: 1024 * 1024,
+		MinFreeDiskSpace:   1024 * 1024 * 1024,
+	}
+
+	t.Run("below the minimum", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.FreeDiskSpaceReturns(1024*1024, nil)
+		resp := httptest.NewRecorder()
+		req := genJoinRequestFormData(t, validBlockBytes("ch-id"))
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusInsufficientStorage, "insufficient disk space: 1048576 bytes free, the minimum to join a channel is 1073741824 bytes", resp)
+		require.Equal(t, 0, fakeManager.JoinChannelCallCount())
+	})
+
+	t.Run("above the minimum", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.FreeDiskSpaceReturns(2*1024*1024*1024, nil)
+		fakeManager.JoinChannelReturns(types.ChannelInfo{Name: "ch-id"}, nil)
+		resp := httptest.NewRecorder()
+		req := genJoinRequestFormData(t, validBlockBytes("ch-id"))
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusCreated, resp.Result().StatusCode)
+		require.Equal(t, 1, fakeManager.JoinChannelCallCount())
+	})
+
+	t.Run("check fails", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.FreeDiskSpaceReturns(0, errors.New("statfs failed"))
+		fakeManager.JoinChannelReturns(types.ChannelInfo{Name: "ch-id"}, nil)
+		resp := httptest.NewRecorder()
+		req := genJoinRequestFormData(t, validBlockBytes("ch-id"))
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusCreated, resp.Result().StatusCode)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		config := config
+		config.MinFreeDiskSpace = 0
+		fakeManager, h := setup(config, t)
+		fakeManager.JoinChannelReturns(types.ChannelInfo{Name: "ch-id"}, nil)
+		resp := httptest.NewRecorder()
+		req := genJoinRequestFormData(t, validBlockBytes("ch-id"))
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusCreated, resp.Result().StatusCode)
+		require.Equal(t, 0, fakeManager.FreeDiskSpaceCallCount())
+	})
+}
+
+func TestHTTPHandler_ServeHTTP_MaxChannels(t *testing.T) {
+	config := localconfig.ChannelParticipation{
+		Enabled:            true,
+		MaxRequestBodySize: 1024 * 1024,
+		MaxChannels:        2,
+	}
+
+	// the fake manager lists the channels it joined
+	setupJoined := func(t *testing.T, config localconfig.ChannelParticipation) (*mocks.ChannelManagement, *channelparticipation.HTTPHandler) {
+		fakeManager, h := setup(config, t)
+		var channels []types.ChannelInfoShort
+		fakeManager.JoinChannelCalls(func(channelID string, _ *common.Block, _ bool) (types.ChannelInfo, error) {
+			for _, channel := range channels {
+				if channel.Name == channelID {
+					return types.ChannelInfo{}, types.ErrChannelAlreadyExists
+				}
+			}
+			channels = append(channels, types.ChannelInfoShort{Name: channelID})
+			return types.ChannelInfo{Name: channelID}, nil
+		})
+		fakeManager.ChannelListCalls(func() types.ChannelList {
+			return types.ChannelList{Channels: append([]types.ChannelInfoShort(nil), channels...)}
+		})
+		return fakeManager, h
+	}
+
+	join := func(h *channelparticipation.HTTPHandler, channelID string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		req := genJoinRequestFormData(t, validBlockBytes(channelID))
+		h.ServeHTTP(resp, req)
+		return resp
+	}
+
+	t.Run("join up to the limit", func(t *testing.T) {
+		fakeManager, h := setupJoined(t, config)
+		require.Equal(t, http.StatusCreated, join(h, "ch-1").Result().StatusCode)
+		require.Equal(t, http.StatusCreated, join(h, "ch-2").Result().StatusCode)
+
+		resp := join(h, "ch-3")
+		checkErrorResponse(t, http.StatusInsufficientStorage, "maximum number of channels reached: the orderer has 2 channels, the maximum is 2", resp)
+		require.Equal(t, 2, fakeManager.JoinChannelCallCount())
+	})
+
+	t.Run("existing channel at the limit", func(t *testing.T) {
+		_, h := setupJoined(t, config)
+		require.Equal(t, http.StatusCreated, join(h, "ch-1").Result().StatusCode)
+		require.Equal(t, http.StatusCreated, join(h, "ch-2").Result().StatusCode)
+
+		resp := join(h, "ch-2")
+		checkErrorResponse(t, http.StatusMethodNotAllowed, "cannot join: channel already exists", resp)
+	})
+
+	t.Run("system channel is counted", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.ChannelListReturns(types.ChannelList{
+			SystemChannel: &types.ChannelInfoShort{Name: "system-channel"},
+			Channels:      []types.ChannelInfoShort{{Name: "ch-1"}},
+		})
+		resp := join(h, "ch-2")
+		checkErrorResponse(t, http.StatusInsufficientStorage, "maximum number of channels reached: the orderer has 2 channels, the maximum is 2", resp)
+		require.Equal(t, 0, fakeManager.JoinChannelCallCount())
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		config := config
+		config.MaxChannels = 0
+		fakeManager, h := setupJoined(t, config)
+		for _, channelID := range []string{"ch-1", "ch-2", "ch-3"} {
+			require.Equal(t, http.StatusCreated, join(h, channelID).Result().StatusCode)
+		}
+		require.Equal(t, 0, fakeManager.ChannelListCallCount())
+	})
+}
+
+func TestHTTPHandler_ServeHTTP_Events(t *testing.T) {
+	config := localconfig.ChannelParticipation{
+		Enabled:        true,
+		EventsInterval: time.Millisecond,
+	}
+	eventsURL := path.Join(channelparticipation.URLBaseV1Channels, "app-channel", "events")
+
+	t.Run("onboarding", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.ChannelInfoReturnsOnCall(0, types.ChannelInfo{Name: "app-channel", Status: types.StatusOnBoarding, Height: 1}, nil)
+		fakeManager.ChannelInfoReturnsOnCall(1, types.ChannelInfo{Name: "app-channel", Status: types.StatusOnBoarding, Height: 1}, nil)
+		fakeManager.ChannelInfoReturnsOnCall(2, types.ChannelInfo{Name: "app-channel", Status: types.StatusOnBoarding, Height: 50}, nil)
+		fakeManager.ChannelInfoReturnsOnCall(3, types.ChannelInfo{Name: "app-channel", Status: types.StatusActive, Height: 100}, nil)
+		server := httptest.NewServer(h)
+		defer server.Close()
+
+		resp, err := http.Get(server.URL + eventsURL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+		require.Equal(t, "no-store", resp.Header.Get("Cache-Control"))
+
+		events := readEvents(t, resp.Body)
+		require.Len(t, events, 3)
+		var heights []uint64
+		for _, event := range events {
+			require.Equal(t, "progress", event.name)
+			info := types.ChannelInfo{}
+			require.NoError(t, json.Unmarshal([]byte(event.data), &info))
+			require.Equal(t, "/participation/v1/channels/app-channel", info.URL)
+			heights = append(heights, info.Height)
+		}
+		require.Equal(t, []uint64{1, 50, 100}, heights)
+		require.Contains(t, events[2].data, `"status":"active"`)
+		require.Equal(t, 4, fakeManager.ChannelInfoCallCount())
+	})
+
+	t.Run("outlives the write timeout of the server", func(t *testing.T) {
+		config := config
+		config.EventsInterval = 50 * time.Millisecond
+		fakeManager, h := setup(config, t)
+		var calls uint64
+		fakeManager.ChannelInfoStub = func(string) (types.ChannelInfo, error) {
+			call := atomic.AddUint64(&calls, 1)
+			if call > 8 {
+				return types.ChannelInfo{Name: "app-channel", Status: types.StatusActive, Height: call}, nil
+			}
+			return types.ChannelInfo{Name: "app-channel", Status: types.StatusOnBoarding, Height: call}, nil
+		}
+		server := httptest.NewUnstartedServer(h)
+		server.Config.WriteTimeout = 100 * time.Millisecond
+		server.Config.ConnContext = fabhttp.ConnContext
+		server.Start()
+		defer server.Close()
+
+		start := time.Now()
+		resp, err := http.Get(server.URL + eventsURL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		events := readEvents(t, resp.Body)
+		require.Greater(t, int64(time.Since(start)), int64(server.Config.WriteTimeout))
+		require.Len(t, events, 9)
+		require.Contains(t, events[8].data, `"status":"active"`)
+	})
+
+	t.Run("already active", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.ChannelInfoReturns(types.ChannelInfo{Name: "app-channel", Status: types.StatusActive, Height: 100}, nil)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, eventsURL, nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+		events := readEvents(t, resp.Body)
+		require.Len(t, events, 1)
+		require.Contains(t, events[0].data, `"status":"active"`)
+	})
+
+	t.Run("removed while onboarding", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.ChannelInfoReturnsOnCall(0, types.ChannelInfo{Name: "app-channel", Status: types.StatusOnBoarding, Height: 1}, nil)
+		fakeManager.ChannelInfoReturnsOnCall(1, types.ChannelInfo{}, types.ErrChannelNotExist)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, eventsURL, nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+		events := readEvents(t, resp.Body)
+		require.Len(t, events, 2)
+		require.Equal(t, "progress", events[0].name)
+		require.Equal(t, "error", events[1].name)
+		require.Equal(t, `{"error":"channel does not exist"}`, events[1].data)
+	})
+
+	t.Run("channel does not exist", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.ChannelInfoReturns(types.ChannelInfo{}, types.ErrChannelNotExist)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, eventsURL, nil)
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusNotFound, "channel does not exist", resp)
+	})
+
+	t.Run("not acceptable", func(t *testing.T) {
+		_, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, eventsURL, nil)
+		req.Header.Set("Accept", "application/json")
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusNotAcceptable, "response Content-Type is text/event-stream only", resp)
+	})
+
+	t.Run("bad method", func(t *testing.T) {
+		_, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, eventsURL, nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusMethodNotAllowed, resp.Result().StatusCode)
+		require.Equal(t, "GET", resp.Result().Header.Get("Allow"))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		config := config
+		config.EventsInterval = 0
+		_, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, eventsURL, nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusNotFound, resp.Result().StatusCode)
+	})
+}
+
+type sseEvent struct {
+	name string
+	data string
+}
+
+// readEvents reads the Server-Sent Events of a stream until it ends.
+func readEvents(t *testing.T, r io.Reader) []sseEvent {
+	body, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+
+	var events []sseEvent
+	for _, block := range strings.Split(strings.TrimSuffix(string(body), "\n\n"), "\n\n") {
+		event := sseEvent{}
+		for _, line := range strings.Split(block, "\n") {
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				event.name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				event.data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestHTTPHandler_ServeHTTP_MaxConcurrentJoins(t *testing.T) {
+	config := localconfig.ChannelParticipation{
+		Enabled:            true,
+		MaxRequestBodySize: 1024 * 1024,
+		MaxConcurrentJoins: 2,
+	}
+	fakeManager, h := setup(config, t)
+
+	var (
+		lock        sync.Mutex
+		inFlight    int
+		maxInFlight int
+	)
+	release := make(chan struct{})
+	fakeManager.JoinChannelStub = func(channelID string, block *common.Block, isAppChannel bool) (types.ChannelInfo, error) {
+		lock.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		lock.Unlock()
+
+		<-release
+
+		lock.Lock()
+		inFlight--
+		lock.Unlock()
+		return types.ChannelInfo{Name: channelID}, nil
+	}
+
+	const numJoins = 10
+	codes := make(chan int, numJoins)
+	// different channels, as the operations on one channel are serialized anyway
+	for i := 0; i < numJoins; i++ {
+		go func(i int) {
+			resp := httptest.NewRecorder()
+			req := genJoinRequestFormData(t, validBlockBytes(fmt.Sprintf("ch-id%d", i)))
+			h.ServeHTTP(resp, req)
+			codes <- resp.Result().StatusCode
+		}(i)
+	}
+
+	// the requests in excess of the limit are rejected while the others are in progress
+	for i := 0; i < numJoins-2; i++ {
+		require.Equal(t, http.StatusTooManyRequests, <-codes)
+	}
+	close(release)
+	for i := 0; i < 2; i++ {
+		require.Equal(t, http.StatusCreated, <-codes)
+	}
+	require.Equal(t, 2, maxInFlight)
+
+	t.Run("slots are released", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		req := genJoinRequestFormData(t, validBlockBytes("ch-id"))
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusCreated, resp.Result().StatusCode)
+	})
+
+	t.Run("list is unbounded", func(t *testing.T) {
+		h := channelparticipation.NewHTTPHandler(localconfig.ChannelParticipation{Enabled: true, MaxConcurrentJoins: 1}, fakeManager)
+		fakeManager.ChannelListReturns(types.ChannelList{})
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels, nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+	})
+}
+
+func TestHTTPHandler_ServeHTTP_ComputeConfigUpdate(t *testing.T) {
+	config := localconfig.ChannelParticipation{Enabled: true, MaxRequestBodySize: 1024}
+
+	computeRequest := func(body, contentType string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, path.Join(channelparticipation.URLBaseV1Channels, "my-channel", "configupdate"), strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		return req
+	}
+
+	t.Run("allowed field", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		configUpdate := &common.ConfigUpdate{ChannelId: "my-channel", WriteSet: &common.ConfigGroup{Version: 1}}
+		fakeManager.ComputeConfigUpdateReturns(configUpdate, nil)
+		resp := httptest.NewRecorder()
+		req := computeRequest(`{"batchTimeout":"3s"}`, "application/merge-patch+json")
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+		require.Equal(t, "application/octet-stream", resp.Result().Header.Get("Content-Type"))
+		require.Equal(t, protoutil.MarshalOrPanic(configUpdate), resp.Body.Bytes())
+
+		require.Equal(t, 1, fakeManager.ComputeConfigUpdateCallCount())
+		channelID, patch := fakeManager.ComputeConfigUpdateArgsForCall(0)
+		require.Equal(t, "my-channel", channelID)
+		require.NotNil(t, patch.BatchTimeout)
+		require.Equal(t, "3s", *patch.BatchTimeout)
+		require.Nil(t, patch.MaxMessageCount)
+		// computing a config update submits nothing
+		require.Equal(t, 0, fakeManager.UpdateChannelConfigCallCount())
+	})
+
+	t.Run("application/json", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.ComputeConfigUpdateReturns(&common.ConfigUpdate{ChannelId: "my-channel"}, nil)
+		resp := httptest.NewRecorder()
+		req := computeRequest(`{"maxMessageCount":100}`, "application/json")
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+
+		_, patch := fakeManager.ComputeConfigUpdateArgsForCall(0)
+		require.NotNil(t, patch.MaxMessageCount)
+		require.Equal(t, uint32(100), *patch.MaxMessageCount)
+	})
+
+	t.Run("rejected field", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := computeRequest(`{"consensusType":"solo"}`, "application/merge-patch+json")
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, `cannot decode config patch: json: unknown field "consensusType"`, resp)
+		require.Equal(t, 0, fakeManager.ComputeConfigUpdateCallCount())
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := computeRequest(`{"batchTimeout":"-1s"}`, "application/merge-patch+json")
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "invalid config patch: batchTimeout must be positive: -1s", resp)
+		require.Equal(t, 0, fakeManager.ComputeConfigUpdateCallCount())
+	})
+
+	t.Run("empty patch", func(t *testing.T) {
+		_, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := computeRequest(`{}`, "application/merge-patch+json")
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "invalid config patch: no fields to update", resp)
+	})
+
+	t.Run("consensus state", func(t *testing.T) {
+		for _, state := range []string{"maintenance", "normal"} {
+			fakeManager, h := setup(config, t)
+			fakeManager.ComputeConfigUpdateReturns(&common.ConfigUpdate{ChannelId: "my-channel"}, nil)
+			resp := httptest.NewRecorder()
+			req := computeRequest(`{"consensusState":"`+state+`"}`, "application/merge-patch+json")
+			h.ServeHTTP(resp, req)
+			require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+
+			_, patch := fakeManager.ComputeConfigUpdateArgsForCall(0)
+			require.NotNil(t, patch.ConsensusState)
+			require.Equal(t, state, *patch.ConsensusState)
+		}
+	})
+
+	t.Run("unknown consensus state", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := computeRequest(`{"consensusState":"paused"}`, "application/merge-patch+json")
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "invalid config patch: unknown consensusState: paused", resp)
+		require.Equal(t, 0, fakeManager.ComputeConfigUpdateCallCount())
+	})
+
+	t.Run("consensus state with other fields", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := computeRequest(`{"consensusState":"maintenance","batchTimeout":"3s"}`, "application/merge-patch+json")
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "invalid config patch: consensusState cannot be combined with other fields", resp)
+		require.Equal(t, 0, fakeManager.ComputeConfigUpdateCallCount())
+	})
+
+	t.Run("bad content type", func(t *testing.T) {
+		_, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := computeRequest(`{"batchTimeout":"3s"}`, "text/plain")
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "unsupported Content-Type: [text/plain]", resp)
+	})
+
+	t.Run("bad accept header", func(t *testing.T) {
+		_, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := computeRequest(`{"batchTimeout":"3s"}`, "application/json")
+		req.Header.Set("Accept", "application/json")
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusNotAcceptable, "response Content-Type is application/octet-stream only", resp)
+	})
+
+	t.Run("registrar errors", func(t *testing.T) {
+		for _, testCase := range []struct {
+			fakeReturns  error
+			expectedCode int
+		}{
+			{types.ErrChannelNotExist, http.StatusNotFound},
+			{types.ErrChannelPendingRemoval, http.StatusConflict},
+			{types.ErrConsensusStateUnchanged, http.StatusConflict},
+			{os.ErrInvalid, http.StatusBadRequest},
+		} {
+			fakeManager, h := setup(config, t)
+			fakeManager.ComputeConfigUpdateReturns(nil, testCase.fakeReturns)
+			resp := httptest.NewRecorder()
+			req := computeRequest(`{"batchTimeout":"3s"}`, "application/merge-patch+json")
+			h.ServeHTTP(resp, req)
+			checkErrorResponse(t, testCase.expectedCode, "cannot update: "+testCase.fakeReturns.Error(), resp)
+		}
+	})
+}
+
+func TestHTTPHandler_ServeHTTP_UpdateConfig(t *testing.T) {
+	config := localconfig.ChannelParticipation{Enabled: true, MaxRequestBodySize: 1024}
+
+	signedUpdate := &common.ConfigUpdateEnvelope{
+		ConfigUpdate: protoutil.MarshalOrPanic(&common.ConfigUpdate{ChannelId: "my-channel"}),
+		Signatures:   []*common.ConfigSignature{{SignatureHeader: []byte("header"), Signature: []byte("signature")}},
+	}
+	patchRequest := func(body []byte, contentType string) *http.Request {
+		req := httptest.NewRequest(http.MethodPatch, path.Join(channelparticipation.URLBaseV1Channels, "my-channel"), bytes.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		return req
+	}
+
+	t.Run("signed config update", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := patchRequest(protoutil.MarshalOrPanic(signedUpdate), "application/octet-stream")
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusAccepted, resp.Result().StatusCode)
+		require.Equal(t, "/participation/v1/channels/my-channel", resp.Result().Header.Get("Location"))
+
+		require.Equal(t, 1, fakeManager.UpdateChannelConfigCallCount())
+		channelID, configUpdateEnv := fakeManager.UpdateChannelConfigArgsForCall(0)
+		require.Equal(t, "my-channel", channelID)
+		require.True(t, proto.Equal(signedUpdate, configUpdateEnv))
+	})
+
+	t.Run("unsigned config update", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		unsigned := &common.ConfigUpdateEnvelope{ConfigUpdate: signedUpdate.ConfigUpdate}
+		req := patchRequest(protoutil.MarshalOrPanic(unsigned), "application/octet-stream")
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "config update envelope is not signed", resp)
+		require.Equal(t, 0, fakeManager.UpdateChannelConfigCallCount())
+	})
+
+	t.Run("no config update", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := patchRequest(nil, "application/octet-stream")
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "config update envelope carries no config update", resp)
+		require.Equal(t, 0, fakeManager.UpdateChannelConfigCallCount())
+	})
+
+	t.Run("garbage", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := patchRequest([]byte{1, 2, 3}, "application/octet-stream")
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusBadRequest, resp.Result().StatusCode)
+		require.Contains(t, resp.Body.String(), "cannot unmarshal config update envelope")
+		require.Equal(t, 0, fakeManager.UpdateChannelConfigCallCount())
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := patchRequest(make([]byte, 2048), "application/octet-stream")
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "cannot read config update envelope: http: request body too large", resp)
+		require.Equal(t, 0, fakeManager.UpdateChannelConfigCallCount())
+	})
+
+	t.Run("JSON merge patch", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := patchRequest([]byte(`{"batchTimeout":"3s"}`), "application/merge-patch+json")
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "unsupported Content-Type: [application/merge-patch+json]", resp)
+		require.Equal(t, 0, fakeManager.UpdateChannelConfigCallCount())
+	})
+
+	t.Run("registrar errors", func(t *testing.T) {
+		for _, testCase := range []struct {
+			fakeReturns  error
+			expectedCode int
+		}{
+			{types.ErrChannelNotExist, http.StatusNotFound},
+			{types.ErrChannelPendingRemoval, http.StatusConflict},
+			{errors.New("config update rejected: implicit policy evaluation failed"), http.StatusBadRequest},
+		} {
+			fakeManager, h := setup(config, t)
+			fakeManager.UpdateChannelConfigReturns(testCase.fakeReturns)
+			resp := httptest.NewRecorder()
+			req := patchRequest(protoutil.MarshalOrPanic(signedUpdate), "application/octet-stream")
+			h.ServeHTTP(resp, req)
+			checkErrorResponse(t, testCase.expectedCode, "cannot update: "+testCase.fakeReturns.Error(), resp)
+		}
+	})
+}
+
+func TestHTTPHandler_ServeHTTP_ChannelLocks(t *testing.T) {
+	config := localconfig.ChannelParticipation{Enabled: true, MaxRequestBodySize: 1024 * 1024}
+
+	t.Run("operations on one channel serialize", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		// the fake registrar keeps the channel state without synchronization,
+		// and counts the operations that overlap
+		var (
+			exists     bool
+			inProgress int32
+			overlaps   int32
+		)
+		enter := func() {
+			if atomic.AddInt32(&inProgress, 1) > 1 {
+				atomic.AddInt32(&overlaps, 1)
+			}
+			time.Sleep(time.Millisecond)
+		}
+		leave := func() { atomic.AddInt32(&inProgress, -1) }
+		fakeManager.JoinChannelStub = func(channelID string, _ *common.Block, _ bool) (types.ChannelInfo, error) {
+			enter()
+			defer leave()
+			if exists {
+				return types.ChannelInfo{}, types.ErrChannelAlreadyExists
+			}
+			exists = true
+			return types.ChannelInfo{Name: channelID}, nil
+		}
+		fakeManager.RemoveChannelStub = func(string) error {
+			enter()
+			defer leave()
+			if !exists {
+				return types.ErrChannelNotExist
+			}
+			exists = false
+			return nil
+		}
+
+		var (
+			wg              sync.WaitGroup
+			joined, removed int32
+		)
+		for i := 0; i < 20; i++ {
+			joinReq := genJoinRequestFormData(t, validBlockBytes("ch-id"))
+			removeReq := httptest.NewRequest(http.MethodDelete, path.Join(channelparticipation.URLBaseV1Channels, "ch-id"), nil)
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				resp := httptest.NewRecorder()
+				h.ServeHTTP(resp, joinReq)
+				if resp.Result().StatusCode == http.StatusCreated {
+					atomic.AddInt32(&joined, 1)
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				resp := httptest.NewRecorder()
+				h.ServeHTTP(resp, removeReq)
+				if resp.Result().StatusCode == http.StatusNoContent {
+					atomic.AddInt32(&removed, 1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		require.Zero(t, overlaps)
+		require.Equal(t, 20, fakeManager.JoinChannelCallCount())
+		require.Equal(t, 20, fakeManager.RemoveChannelCallCount())
+		// joins and removals alternate, so the channel exists only after one join more than removals
+		if exists {
+			require.Equal(t, removed+1, joined)
+		} else {
+			require.Equal(t, removed, joined)
+		}
+	})
+
+	t.Run("operations on different channels proceed in parallel", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		joining := make(chan struct{})
+		release := make(chan struct{})
+		fakeManager.JoinChannelStub = func(channelID string, _ *common.Block, _ bool) (types.ChannelInfo, error) {
+			close(joining)
+			<-release
+			return types.ChannelInfo{Name: channelID}, nil
+		}
+
+		joinReq := genJoinRequestFormData(t, validBlockBytes("ch-a"))
+		joined := make(chan struct{})
+		go func() {
+			defer close(joined)
+			h.ServeHTTP(httptest.NewRecorder(), joinReq)
+		}()
+		<-joining
+
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, path.Join(channelparticipation.URLBaseV1Channels, "ch-b"), nil))
+		require.Equal(t, http.StatusNoContent, resp.Result().StatusCode)
+
+		close(release)
+		<-joined
+	})
+}
+
+func TestHTTPHandler_ServeHTTP_ConfigCache(t *testing.T) {
+	getVerbose := func(t *testing.T, h *channelparticipation.HTTPHandler) types.ChannelInfo {
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"/app-channel?verbose=true", nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+		infoResp := types.ChannelInfo{}
+		err := json.Unmarshal(resp.Body.Bytes(), &infoResp)
+		require.NoError(t, err, "cannot be unmarshaled")
+		return infoResp
+	}
+	infoAt := func(sequence uint64) types.ChannelInfo {
+		return types.ChannelInfo{Name: "app-channel", ConsensusRelation: types.ConsensusRelationConsenter, Status: types.StatusActive, ConfigSequence: &sequence}
+	}
+
+	t.Run("capabilities cached until config update", func(t *testing.T) {
+		fakeManager, h := setup(localconfig.ChannelParticipation{Enabled: true}, t)
+		fakeManager.ChannelInfoReturns(infoAt(1), nil)
+		fakeManager.ChannelCapabilitiesReturns(types.ChannelCapabilities{Channel: []string{"V2_0"}, Orderer: []string{"V2_0"}}, nil)
+
+		for i := 0; i < 2; i++ {
+			infoResp := getVerbose(t, h)
+			require.Equal(t, []string{"V2_0"}, infoResp.Capabilities.Channel)
+		}
+		require.Equal(t, 1, fakeManager.ChannelCapabilitiesCallCount())
+
+		fakeManager.ChannelInfoReturns(infoAt(2), nil)
+		fakeManager.ChannelCapabilitiesReturns(types.ChannelCapabilities{Channel: []string{"V3_0"}, Orderer: []string{"V2_0"}}, nil)
+		infoResp := getVerbose(t, h)
+		require.Equal(t, []string{"V3_0"}, infoResp.Capabilities.Channel)
+		require.Equal(t, 2, fakeManager.ChannelCapabilitiesCallCount())
+
+		getVerbose(t, h)
+		require.Equal(t, 2, fakeManager.ChannelCapabilitiesCallCount())
+	})
+
+	t.Run("capabilities reloaded after remove", func(t *testing.T) {
+		fakeManager, h := setup(localconfig.ChannelParticipation{Enabled: true}, t)
+		fakeManager.ChannelInfoReturns(infoAt(1), nil)
+		fakeManager.ChannelCapabilitiesReturns(types.ChannelCapabilities{Channel: []string{"V2_0"}}, nil)
+		getVerbose(t, h)
+		require.Equal(t, 1, fakeManager.ChannelCapabilitiesCallCount())
+
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, channelparticipation.URLBaseV1Channels+"/app-channel", nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusNoContent, resp.Result().StatusCode)
+
+		// a channel joined again under the same name starts over at the same sequence
+		getVerbose(t, h)
+		require.Equal(t, 2, fakeManager.ChannelCapabilitiesCallCount())
+	})
+
+	// a channel joined again under the same name starts over at the same sequence, with a config that may differ
+	rejoin := func(t *testing.T, fakeManager *mocks.ChannelManagement, h *channelparticipation.HTTPHandler) {
+		fakeManager.JoinChannelReturns(infoAt(1), nil)
+		fakeManager.ChannelCapabilitiesReturns(types.ChannelCapabilities{Channel: []string{"V3_0"}}, nil)
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, genJoinRequestFormData(t, validBlockBytes("app-channel")))
+		require.Equal(t, http.StatusCreated, resp.Result().StatusCode)
+	}
+
+	t.Run("capabilities of a rejoined channel at the same sequence", func(t *testing.T) {
+		fakeManager, h := setup(localconfig.ChannelParticipation{Enabled: true, MaxRequestBodySize: 1024 * 1024}, t)
+		fakeManager.ChannelInfoReturns(infoAt(1), nil)
+		fakeManager.ChannelCapabilitiesReturns(types.ChannelCapabilities{Channel: []string{"V2_0"}}, nil)
+		require.Equal(t, []string{"V2_0"}, getVerbose(t, h).Capabilities.Channel)
+
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, channelparticipation.URLBaseV1Channels+"/app-channel", nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusNoContent, resp.Result().StatusCode)
+
+		rejoin(t, fakeManager, h)
+		require.Equal(t, []string{"V3_0"}, getVerbose(t, h).Capabilities.Channel)
+		require.Equal(t, 2, fakeManager.ChannelCapabilitiesCallCount())
+	})
+
+	t.Run("capabilities of a channel removed outside of the API and rejoined at the same sequence", func(t *testing.T) {
+		fakeManager, h := setup(localconfig.ChannelParticipation{Enabled: true, MaxRequestBodySize: 1024 * 1024}, t)
+		fakeManager.ChannelInfoReturns(infoAt(1), nil)
+		fakeManager.ChannelCapabilitiesReturns(types.ChannelCapabilities{Channel: []string{"V2_0"}}, nil)
+		require.Equal(t, []string{"V2_0"}, getVerbose(t, h).Capabilities.Channel)
+
+		// e.g. removed by the registrar along with the system channel, so no DELETE reaches the handler
+		rejoin(t, fakeManager, h)
+		require.Equal(t, []string{"V3_0"}, getVerbose(t, h).Capabilities.Channel)
+		require.Equal(t, 2, fakeManager.ChannelCapabilitiesCallCount())
+	})
+
+	t.Run("capabilities not cached without config sequence", func(t *testing.T) {
+		fakeManager, h := setup(localconfig.ChannelParticipation{Enabled: true}, t)
+		fakeManager.ChannelInfoReturns(types.ChannelInfo{Name: "app-channel", Status: types.StatusActive}, nil)
+		fakeManager.ChannelCapabilitiesReturns(types.ChannelCapabilities{Channel: []string{"V2_0"}}, nil)
+		getVerbose(t, h)
+		getVerbose(t, h)
+		require.Equal(t, 2, fakeManager.ChannelCapabilitiesCallCount())
+	})
+
+	t.Run("capabilities error not cached", func(t *testing.T) {
+		fakeManager, h := setup(localconfig.ChannelParticipation{Enabled: true}, t)
+		fakeManager.ChannelInfoReturns(infoAt(1), nil)
+		fakeManager.ChannelCapabilitiesReturnsOnCall(0, types.ChannelCapabilities{}, errors.New("channel is pending removal"))
+		fakeManager.ChannelCapabilitiesReturnsOnCall(1, types.ChannelCapabilities{Channel: []string{"V2_0"}}, nil)
+		infoResp := getVerbose(t, h)
+		require.Nil(t, infoResp.Capabilities)
+		infoResp = getVerbose(t, h)
+		require.Equal(t, []string{"V2_0"}, infoResp.Capabilities.Channel)
+		require.Equal(t, 2, fakeManager.ChannelCapabilitiesCallCount())
+	})
+
+	t.Run("consenter count cached until config update", func(t *testing.T) {
+		fakeManager, h := setup(localconfig.ChannelParticipation{Enabled: true, ProtectSoleConsenter: true}, t)
+		fakeManager.ChannelInfoReturns(infoAt(1), nil)
+		fakeManager.ConsenterCountReturns(1, nil)
+
+		for i := 0; i < 2; i++ {
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodDelete, channelparticipation.URLBaseV1Channels+"/app-channel", nil)
+			h.ServeHTTP(resp, req)
+			require.Equal(t, http.StatusConflict, resp.Result().StatusCode)
+		}
+		require.Equal(t, 1, fakeManager.ConsenterCountCallCount())
+
+		fakeManager.ChannelInfoReturns(infoAt(2), nil)
+		fakeManager.ConsenterCountReturns(3, nil)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, channelparticipation.URLBaseV1Channels+"/app-channel", nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusNoContent, resp.Result().StatusCode)
+		require.Equal(t, 2, fakeManager.ConsenterCountCallCount())
+	})
+}
+
+func BenchmarkHTTPHandler_ServeHTTP_ListVerbose(b *testing.B) {
+	configBlock := blockWithChannelCapabilities("app-channel", "V2_0")
+	// parses the config of the channel, as the registrar does
+	parseCapabilities := func(string) (types.ChannelCapabilities, error) {
+		envelope, err := protoutil.ExtractEnvelope(configBlock, 0)
+		if err != nil {
+			return types.ChannelCapabilities{}, err
+		}
+		payload, err := protoutil.UnmarshalPayload(envelope.Payload)
+		if err != nil {
+			return types.ChannelCapabilities{}, err
+		}
+		configEnv := &common.ConfigEnvelope{}
+		if err := proto.Unmarshal(payload.Data, configEnv); err != nil {
+			return types.ChannelCapabilities{}, err
+		}
+		required := &common.Capabilities{}
+		if err := proto.Unmarshal(configEnv.Config.ChannelGroup.Values["Capabilities"].Value, required); err != nil {
+			return types.ChannelCapabilities{}, err
+		}
+		capabilities := types.ChannelCapabilities{}
+		for capability := range required.Capabilities {
+			capabilities.Channel = append(capabilities.Channel, capability)
+		}
+		return capabilities, nil
+	}
+
+	run := func(b *testing.B, updateConfig bool) {
+		fakeManager := &mocks.ChannelManagement{}
+		h := channelparticipation.NewHTTPHandler(localconfig.ChannelParticipation{Enabled: true}, fakeManager)
+		fakeManager.ChannelCapabilitiesStub = parseCapabilities
+		sequence := uint64(1)
+		fakeManager.ChannelInfoStub = func(channelID string) (types.ChannelInfo, error) {
+			current := sequence
+			return types.ChannelInfo{Name: channelID, Status: types.StatusActive, ConfigSequence: &current}, nil
+		}
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if updateConfig {
+				sequence++
+			}
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, channelparticipation.URLBaseV1Channels+"/app-channel?verbose=true", nil)
+			h.ServeHTTP(resp, req)
+			if resp.Code != http.StatusOK {
+				b.Fatalf("unexpected status: %d", resp.Code)
+			}
+		}
+	}
+
+	b.Run("cached", func(b *testing.B) { run(b, false) })
+	b.Run("config updated every request", func(b *testing.B) { run(b, true) })
+}
+
+func TestHTTPHandler_ServeHTTP_LastModifiedBy(t *testing.T) {
+	config := localconfig.ChannelParticipation{
+		Enabled:            true,
+		MaxRequestBodySize: 1024 * 1024,
+	}
+	admin := pkix.Name{CommonName: "admin", OrganizationalUnit: []string{"admin"}, Organization: []string{"Org1"}}
+	activeInfo := types.ChannelInfo{Name: "ch-id", ConsensusRelation: types.ConsensusRelationConsenter, Status: types.StatusActive, Height: 1}
+
+	getInfo := func(h *channelparticipation.HTTPHandler) types.ChannelInfo {
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path.Join(channelparticipation.URLBaseV1Channels, "ch-id"), nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+		info := types.ChannelInfo{}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &info))
+		return info
+	}
+
+	t.Run("join", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.JoinChannelReturns(activeInfo, nil)
+		fakeManager.ChannelInfoReturns(activeInfo, nil)
+
+		resp := httptest.NewRecorder()
+		req := withClientCert(genJoinRequestFormData(t, validBlockBytes("ch-id")), admin)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusCreated, resp.Result().StatusCode)
+		info := types.ChannelInfo{}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &info))
+		require.Equal(t, "CN=admin,OU=admin,O=Org1", info.LastModifiedBy)
+
+		require.Equal(t, "CN=admin,OU=admin,O=Org1", getInfo(h).LastModifiedBy)
+	})
+
+	t.Run("join without a client certificate", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.JoinChannelReturns(activeInfo, nil)
+		fakeManager.ChannelInfoReturns(activeInfo, nil)
+
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, genJoinRequestFormData(t, validBlockBytes("ch-id")))
+		require.Equal(t, http.StatusCreated, resp.Result().StatusCode)
+		require.NotContains(t, resp.Body.String(), "lastModifiedBy")
+
+		require.Empty(t, getInfo(h).LastModifiedBy)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.ChannelInfoReturns(activeInfo, nil)
+
+		resp := httptest.NewRecorder()
+		req := withClientCert(httptest.NewRequest(http.MethodDelete, path.Join(channelparticipation.URLBaseV1Channels, "ch-id"), nil), admin)
+		req.Header.Set("Prefer", "return=representation")
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+		info := types.ChannelInfo{}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &info))
+		require.Equal(t, "CN=admin,OU=admin,O=Org1", info.LastModifiedBy)
+	})
+
+	t.Run("remove asynchronously", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.ChannelInfoReturns(activeInfo, nil)
+		release := make(chan struct{})
+		fakeManager.RemoveChannelStub = func(string) error {
+			<-release
+			return nil
+		}
+		defer close(release)
+
+		resp := httptest.NewRecorder()
+		req := withClientCert(httptest.NewRequest(http.MethodDelete, path.Join(channelparticipation.URLBaseV1Channels, "ch-id"), nil), admin)
+		req.Header.Set("Prefer", "respond-async")
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusAccepted, resp.Result().StatusCode)
+		info := types.ChannelInfo{}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &info))
+		require.Equal(t, "CN=admin,OU=admin,O=Org1", info.LastModifiedBy)
+
+		info = getInfo(h)
+		require.Equal(t, types.StatusRemoving, info.Status)
+		require.Equal(t, "CN=admin,OU=admin,O=Org1", info.LastModifiedBy)
+	})
+}
+
+func setup(config localconfig.ChannelParticipation, t *testing.T) (*mocks.ChannelManagement, *channelparticipation.HTTPHandler) {
 	fakeManager := &mocks.ChannelManagement{}
 	h := channelparticipation.NewHTTPHandler(config, fakeManager)
 	require.NotNilf(t, h, "cannot create handler")
 	return fakeManager, h
 }
 
+// withClientCert sets a client certificate with the subject on the TLS connection of the request.
+func withClientCert(req *http.Request, subject pkix.Name) *http.Request {
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Subject: subject}}}
+	return req
+}
+
 func checkErrorResponse(t *testing.T, expectedCode int, expectedErrMsg string, resp *httptest.ResponseRecorder) {
 	require.Equal(t, expectedCode, resp.Result().StatusCode)
 
@@ -7,9 +7,15 @@ SPDX-License-Identifier: Apache-2.0
 package channelparticipation
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"net"
+	"strconv"
 
+	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/orderer/etcdraft"
 	"github.com/hyperledger/fabric/bccsp/factory"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/protoutil"
@@ -19,31 +25,128 @@ import (
 // It returns the channel ID, and whether it is an system channel if it contains consortiums, or otherwise
 // an application channel if an application group exists. It returns an error when it cannot be used as a join-block.
 func ValidateJoinBlock(configBlock *cb.Block) (channelID string, isAppChannel bool, err error) {
-	if !protoutil.IsConfigBlock(configBlock) {
-		return "", false, errors.New("block is not a config block")
+	bundle, invalid, _ := checkJoinBlock(configBlock)
+	if len(invalid) > 0 {
+		return "", false, invalid[0]
 	}
 
-	envelope, err := protoutil.ExtractEnvelope(configBlock, 0)
-	if err != nil {
-		return "", false, err
+	channelID = bundle.ConfigtxValidator().ChannelID()
+	_, isSystemChannel := bundle.ConsortiumsConfig()
+	if !isSystemChannel {
+		_, isAppChannel = bundle.ApplicationConfig()
 	}
+	return channelID, isAppChannel, nil
+}
 
-	cryptoProvider := factory.GetDefault()
-	bundle, err := channelconfig.NewBundleFromEnvelope(envelope, cryptoProvider)
+// ValidateBlockDataHash checks that the data hash in the header of the block matches the hash of the block data, so
+// that a block corrupted in transit, or tampered with, is not written to the ledger of a new channel.
+func ValidateBlockDataHash(block *cb.Block) error {
+	if block.Header == nil {
+		return errors.New("block has no header")
+	}
+	if block.Data == nil {
+		return errors.New("block has no data")
+	}
+	if dataHash := protoutil.BlockDataHash(block.Data); !bytes.Equal(block.Header.DataHash, dataHash) {
+		return fmt.Errorf("block data hash %x does not match the hash of the block data %x", block.Header.DataHash, dataHash)
+	}
+	return nil
+}
+
+// ValidateJoinBlockCapabilities checks whether this orderer supports the channel and orderer capabilities required
+// by the config of the join block, so that it does not join a channel it cannot process. The error names the first
+// unsupported capability found.
+func ValidateJoinBlockCapabilities(configBlock *cb.Block) error {
+	bundle, invalid, unsupported := checkJoinBlock(configBlock)
+	if bundle == nil {
+		// the config cannot be parsed
+		return invalid[0]
+	}
+	if len(unsupported) > 0 {
+		return unsupported[0]
+	}
+	return nil
+}
+
+// ValidateJoinBlockConsenters checks that the etcdraft consenter set in the config of the join block does not list a
+// consenter twice under the same endpoint, as raft would then count a single orderer as several members. Entries of
+// the same endpoint are rejected whatever their TLS certificates, while the certificates of distinct endpoints are not
+// compared, since they may be empty or shared by orderers behind distinct endpoints. The config of other consensus
+// types is not checked.
+func ValidateJoinBlockConsenters(configBlock *cb.Block) error {
+	bundle, err := bundleFromBlock(configBlock)
 	if err != nil {
-		return "", false, err
+		return err
 	}
 
-	channelID = bundle.ConfigtxValidator().ChannelID()
+	oc, ok := bundle.OrdererConfig()
+	if !ok || oc.ConsensusType() != "etcdraft" {
+		return nil
+	}
+	metadata := &etcdraft.ConfigMetadata{}
+	if err := proto.Unmarshal(oc.ConsensusMetadata(), metadata); err != nil {
+		return fmt.Errorf("failed to unmarshal etcdraft metadata: %s", err)
+	}
+
+	endpoints := map[string]struct{}{}
+	for _, consenter := range metadata.Consenters {
+		if consenter == nil {
+			return errors.New("nil consenter in etcdraft metadata")
+		}
+		endpoint := net.JoinHostPort(consenter.Host, strconv.FormatUint(uint64(consenter.Port), 10))
+		if _, ok := endpoints[endpoint]; ok {
+			return fmt.Errorf("duplicate consenter %s", endpoint)
+		}
+		endpoints[endpoint] = struct{}{}
+	}
+	return nil
+}
+
+// JoinBlockErrors runs the checks of both ValidateJoinBlock and ValidateJoinBlockCapabilities, without stopping at the
+// first that fails, and returns the errors of the checks that make the block invalid, and those of the capabilities
+// this orderer does not support. The config is not checked when the block is not a config block, or its config cannot
+// be parsed.
+func JoinBlockErrors(configBlock *cb.Block) (invalid, unsupported []error) {
+	_, invalid, unsupported = checkJoinBlock(configBlock)
+	return invalid, unsupported
+}
+
+// checkJoinBlock runs the checks of a join block shared by ValidateJoinBlock, ValidateJoinBlockCapabilities and
+// JoinBlockErrors, so that they cannot disagree on whether a block is valid. It returns the bundle of the config, nil
+// when the config cannot be parsed, in which case invalid holds the reason.
+func checkJoinBlock(configBlock *cb.Block) (bundle *channelconfig.Bundle, invalid, unsupported []error) {
+	if !protoutil.IsConfigBlock(configBlock) {
+		return nil, []error{errors.New("block is not a config block")}, nil
+	}
+
+	bundle, err := bundleFromBlock(configBlock)
+	if err != nil {
+		return nil, []error{err}, nil
+	}
 
-	// Check channel type
 	_, isSystemChannel := bundle.ConsortiumsConfig()
-	if !isSystemChannel {
-		_, isAppChannel = bundle.ApplicationConfig()
-		if !isAppChannel {
-			return "", false, errors.New("invalid config: must have at least one of application or consortiums")
+	_, isAppChannel := bundle.ApplicationConfig()
+	if !isSystemChannel && !isAppChannel {
+		invalid = append(invalid, errors.New("invalid config: must have at least one of application or consortiums"))
+	}
+
+	if err := bundle.ChannelConfig().Capabilities().Supported(); err != nil {
+		unsupported = append(unsupported, err)
+	}
+	if oc, ok := bundle.OrdererConfig(); ok {
+		if err := oc.Capabilities().Supported(); err != nil {
+			unsupported = append(unsupported, err)
 		}
 	}
+	return bundle, invalid, unsupported
+}
 
-	return channelID, isAppChannel, err
+func bundleFromBlock(configBlock *cb.Block) (*channelconfig.Bundle, error) {
+	envelope, err := protoutil.ExtractEnvelope(configBlock, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	cryptoProvider := factory.GetDefault()
+	return channelconfig.NewBundleFromEnvelope(envelope, cryptoProvider)
 }
@@ -12,6 +12,8 @@ import (
 	"testing"
 
 	cb "github.com/hyperledger/fabric-protos-go/common"
+	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric-protos-go/orderer/etcdraft"
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/orderer/common/channelparticipation"
 	"github.com/hyperledger/fabric/protoutil"
@@ -107,8 +109,235 @@ func TestValidateJoinBlock(t *testing.T) {
 	}
 }
 
+func TestValidateBlockDataHash(t *testing.T) {
+	t.Run("matching", func(t *testing.T) {
+		block := blockWithChannelCapabilities("my-channel", "V2_0")
+		require.NoError(t, channelparticipation.ValidateBlockDataHash(block))
+	})
+
+	t.Run("tampered data", func(t *testing.T) {
+		block := blockWithChannelCapabilities("my-channel", "V2_0")
+		block.Data.Data = append(block.Data.Data, []byte("tampered"))
+		err := channelparticipation.ValidateBlockDataHash(block)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "does not match the hash of the block data")
+	})
+
+	t.Run("no header", func(t *testing.T) {
+		block := blockWithChannelCapabilities("my-channel", "V2_0")
+		block.Header = nil
+		require.EqualError(t, channelparticipation.ValidateBlockDataHash(block), "block has no header")
+	})
+
+	t.Run("no data", func(t *testing.T) {
+		block := blockWithChannelCapabilities("my-channel", "V2_0")
+		block.Data = nil
+		require.EqualError(t, channelparticipation.ValidateBlockDataHash(block), "block has no data")
+	})
+}
+
+func TestValidateJoinBlockCapabilities(t *testing.T) {
+	t.Run("supported", func(t *testing.T) {
+		block := blockWithChannelCapabilities("my-channel", "V2_0")
+		require.NoError(t, channelparticipation.ValidateJoinBlockCapabilities(block))
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		block := blockWithChannelCapabilities("my-channel", "V3_0")
+		err := channelparticipation.ValidateJoinBlockCapabilities(block)
+		require.EqualError(t, err, "Channel capability V3_0 is required but not supported")
+	})
+
+	t.Run("invalid bundle", func(t *testing.T) {
+		err := channelparticipation.ValidateJoinBlockCapabilities(nonConfigBlock())
+		require.Error(t, err)
+	})
+}
+
+func TestValidateJoinBlockConsenters(t *testing.T) {
+	consenter := func(host string, port uint32, cert string) *etcdraft.Consenter {
+		return &etcdraft.Consenter{Host: host, Port: port, ServerTlsCert: []byte(cert), ClientTlsCert: []byte(cert)}
+	}
+
+	t.Run("distinct consenters", func(t *testing.T) {
+		block := blockWithConsenters("my-channel",
+			consenter("orderer1", 7050, "cert1"),
+			consenter("orderer2", 7050, "cert2"),
+			consenter("orderer2", 7051, "cert3"),
+		)
+		require.NoError(t, channelparticipation.ValidateJoinBlockConsenters(block))
+	})
+
+	t.Run("duplicate endpoint", func(t *testing.T) {
+		block := blockWithConsenters("my-channel",
+			consenter("orderer1", 7050, "cert1"),
+			consenter("orderer1", 7050, "cert2"),
+		)
+		err := channelparticipation.ValidateJoinBlockConsenters(block)
+		require.EqualError(t, err, "duplicate consenter orderer1:7050")
+	})
+
+	t.Run("duplicate endpoint and certificate", func(t *testing.T) {
+		block := blockWithConsenters("my-channel",
+			consenter("orderer1", 7050, "cert1"),
+			consenter("orderer1", 7050, "cert1"),
+		)
+		err := channelparticipation.ValidateJoinBlockConsenters(block)
+		require.EqualError(t, err, "duplicate consenter orderer1:7050")
+	})
+
+	t.Run("certificate shared by distinct endpoints", func(t *testing.T) {
+		block := blockWithConsenters("my-channel",
+			consenter("orderer1", 7050, "cert1"),
+			&etcdraft.Consenter{Host: "orderer2", Port: 7050, ServerTlsCert: []byte("cert2"), ClientTlsCert: []byte("cert1")},
+			consenter("orderer3", 7050, "cert1"),
+		)
+		require.NoError(t, channelparticipation.ValidateJoinBlockConsenters(block))
+	})
+
+	t.Run("empty client certificates", func(t *testing.T) {
+		block := blockWithConsenters("my-channel",
+			&etcdraft.Consenter{Host: "orderer1", Port: 7050, ServerTlsCert: []byte("cert1")},
+			&etcdraft.Consenter{Host: "orderer2", Port: 7050, ServerTlsCert: []byte("cert2"), ClientTlsCert: []byte{}},
+			&etcdraft.Consenter{Host: "orderer3", Port: 7050, ServerTlsCert: []byte("cert3")},
+		)
+		require.NoError(t, channelparticipation.ValidateJoinBlockConsenters(block))
+	})
+
+	t.Run("not etcdraft", func(t *testing.T) {
+		require.NoError(t, channelparticipation.ValidateJoinBlockConsenters(blockWithChannelCapabilities("my-channel", "V2_0")))
+	})
+}
+
+func TestJoinBlockErrors(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		invalid, unsupported := channelparticipation.JoinBlockErrors(blockWithChannelCapabilities("my-channel", "V2_0"))
+		require.Empty(t, invalid)
+		require.Empty(t, unsupported)
+	})
+
+	t.Run("not a config block", func(t *testing.T) {
+		invalid, unsupported := channelparticipation.JoinBlockErrors(nonConfigBlock())
+		require.Len(t, invalid, 1)
+		require.EqualError(t, invalid[0], "block is not a config block")
+		require.Empty(t, unsupported)
+	})
+
+	t.Run("failing two checks", func(t *testing.T) {
+		required := &cb.Capabilities{Capabilities: map[string]*cb.Capability{"V3_0": {}}}
+		block := blockWithGroupsAndValues(
+			map[string]*cb.ConfigGroup{},
+			map[string]*cb.ConfigValue{"Capabilities": {Value: protoutil.MarshalOrPanic(required)}},
+			"my-channel",
+		)
+		invalid, unsupported := channelparticipation.JoinBlockErrors(block)
+		require.Len(t, invalid, 1)
+		require.EqualError(t, invalid[0], "invalid config: must have at least one of application or consortiums")
+		require.Len(t, unsupported, 1)
+		require.EqualError(t, unsupported[0], "Channel capability V3_0 is required but not supported")
+	})
+
+	t.Run("agrees with ValidateJoinBlock and ValidateJoinBlockCapabilities", func(t *testing.T) {
+		blocks := map[string]*cb.Block{
+			"valid":                  blockWithChannelCapabilities("my-channel", "V2_0"),
+			"unsupported capability": blockWithChannelCapabilities("my-channel", "V3_0"),
+			"not a config block":     nonConfigBlock(),
+			"invalid channel ID":     blockWithGroups(map[string]*cb.ConfigGroup{"Application": {}}, "My-Channel"),
+			"no channel type":        blockWithGroups(map[string]*cb.ConfigGroup{}, "my-channel"),
+		}
+		for name, block := range blocks {
+			invalid, unsupported := channelparticipation.JoinBlockErrors(block)
+			_, _, err := channelparticipation.ValidateJoinBlock(block)
+			if len(invalid) == 0 {
+				require.NoError(t, err, name)
+			} else {
+				require.EqualError(t, err, invalid[0].Error(), name)
+			}
+			switch {
+			case len(unsupported) > 0:
+				require.EqualError(t, channelparticipation.ValidateJoinBlockCapabilities(block), unsupported[0].Error(), name)
+			case len(invalid) == 0:
+				require.NoError(t, channelparticipation.ValidateJoinBlockCapabilities(block), name)
+			}
+		}
+	})
+}
+
+// blockWithChannelCapabilities returns an application channel config block that requires the channel capabilities.
+func blockWithChannelCapabilities(channelID string, capabilities ...string) *cb.Block {
+	required := &cb.Capabilities{Capabilities: map[string]*cb.Capability{}}
+	for _, capability := range capabilities {
+		required.Capabilities[capability] = &cb.Capability{}
+	}
+	return blockWithGroupsAndValues(
+		map[string]*cb.ConfigGroup{
+			"Application": {},
+		},
+		map[string]*cb.ConfigValue{
+			"Capabilities": {Value: protoutil.MarshalOrPanic(required)},
+		},
+		channelID,
+	)
+}
+
+// blockWithConsenters returns an application channel config block whose orderer group has the etcdraft consenters.
+func blockWithConsenters(channelID string, consenters ...*etcdraft.Consenter) *cb.Block {
+	return blockWithGroups(
+		map[string]*cb.ConfigGroup{
+			"Application": {},
+			"Orderer": {
+				Values: map[string]*cb.ConfigValue{
+					"ConsensusType": {
+						Value: protoutil.MarshalOrPanic(&ab.ConsensusType{
+							Type:     "etcdraft",
+							Metadata: protoutil.MarshalOrPanic(&etcdraft.ConfigMetadata{Consenters: consenters}),
+						}),
+					},
+					"BatchSize": {
+						Value: protoutil.MarshalOrPanic(&ab.BatchSize{
+							MaxMessageCount:   10,
+							AbsoluteMaxBytes:  1024 * 1024,
+							PreferredMaxBytes: 512 * 1024,
+						}),
+					},
+					"BatchTimeout": {
+						Value: protoutil.MarshalOrPanic(&ab.BatchTimeout{Timeout: "2s"}),
+					},
+				},
+			},
+		},
+		channelID,
+	)
+}
+
 func blockWithGroups(groups map[string]*cb.ConfigGroup, channelID string) *cb.Block {
-	return &cb.Block{
+	return blockWithGroupsAndValues(groups, nil, channelID)
+}
+
+// blockWithGroupsAndValues returns a config block with the groups, and the values in addition to the mandatory ones.
+func blockWithGroupsAndValues(groups map[string]*cb.ConfigGroup, values map[string]*cb.ConfigValue, channelID string) *cb.Block {
+	channelValues := map[string]*cb.ConfigValue{
+		"HashingAlgorithm": {
+			Value: protoutil.MarshalOrPanic(&cb.HashingAlgorithm{
+				Name: bccsp.SHA256,
+			}),
+		},
+		"BlockDataHashingStructure": {
+			Value: protoutil.MarshalOrPanic(&cb.BlockDataHashingStructure{
+				Width: math.MaxUint32,
+			}),
+		},
+		"OrdererAddresses": {
+			Value: protoutil.MarshalOrPanic(&cb.OrdererAddresses{
+				Addresses: []string{"localhost"},
+			}),
+		},
+	}
+	for key, value := range values {
+		channelValues[key] = value
+	}
+
+	return withHeader(&cb.Block{
 		Data: &cb.BlockData{
 			Data: [][]byte{
 				protoutil.MarshalOrPanic(&cb.Envelope{
@@ -117,23 +346,7 @@ func blockWithGroups(groups map[string]*cb.ConfigGroup, channelID string) *cb.Bl
 							Config: &cb.Config{
 								ChannelGroup: &cb.ConfigGroup{
 									Groups: groups,
-									Values: map[string]*cb.ConfigValue{
-										"HashingAlgorithm": {
-											Value: protoutil.MarshalOrPanic(&cb.HashingAlgorithm{
-												Name: bccsp.SHA256,
-											}),
-										},
-										"BlockDataHashingStructure": {
-											Value: protoutil.MarshalOrPanic(&cb.BlockDataHashingStructure{
-												Width: math.MaxUint32,
-											}),
-										},
-										"OrdererAddresses": {
-											Value: protoutil.MarshalOrPanic(&cb.OrdererAddresses{
-												Addresses: []string{"localhost"},
-											}),
-										},
-									},
+									Values: channelValues,
 								},
 							},
 						}),
@@ -147,11 +360,11 @@ func blockWithGroups(groups map[string]*cb.ConfigGroup, channelID string) *cb.Bl
 				}),
 			},
 		},
-	}
+	})
 }
 
 func nonConfigBlock() *cb.Block {
-	return &cb.Block{
+	return withHeader(&cb.Block{
 		Data: &cb.BlockData{
 			Data: [][]byte{
 				protoutil.MarshalOrPanic(&cb.Envelope{
@@ -165,5 +378,11 @@ func nonConfigBlock() *cb.Block {
 				}),
 			},
 		},
-	}
+	})
+}
+
+// withHeader sets the header of the block, with the hash of its data.
+func withHeader(block *cb.Block) *cb.Block {
+	block.Header = &cb.BlockHeader{DataHash: protoutil.BlockDataHash(block.Data)}
+	return block
 }
@@ -0,0 +1,59 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channelparticipation
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/orderer/common/types"
+)
+
+// webhook notifies an external URL of channel lifecycle events. Notifications
+// are best effort: they are sent asynchronously and failures are only logged.
+type webhook struct {
+	logger *flogging.FabricLogger
+	url    string
+	client *http.Client
+}
+
+func newWebhook(logger *flogging.FabricLogger, url string, timeout time.Duration) *webhook {
+	return &webhook{
+		logger: logger,
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (w *webhook) notify(event types.ChannelEvent) {
+	go w.send(event)
+}
+
+func (w *webhook) send(event types.ChannelEvent) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		w.logger.Warningf("Failed to marshal %s event for channel %s: %s", event.Type, event.Channel.Name, err)
+		return
+	}
+
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		w.logger.Warningf("Failed to notify webhook of %s event for channel %s: %s", event.Type, event.Channel.Name, err)
+		return
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		w.logger.Warningf("Webhook responded with status %d to %s event for channel %s", resp.StatusCode, event.Type, event.Channel.Name)
+		return
+	}
+
+	w.logger.Debugf("Notified webhook of %s event for channel %s", event.Type, event.Channel.Name)
+}
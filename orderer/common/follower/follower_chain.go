@@ -270,6 +270,15 @@ func (c *Chain) Height() uint64 {
 	return c.ledgerResources.Height()
 }
 
+// TargetHeight returns the height the ledger reaches once onboarding is done, that of the join-block, or 0 when the
+// chain was started without a join-block.
+func (c *Chain) TargetHeight() uint64 {
+	if c.joinBlock == nil {
+		return 0
+	}
+	return c.joinBlock.Header.Number + 1
+}
+
 func (c *Chain) IsRunning() bool {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
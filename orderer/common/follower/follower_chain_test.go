@@ -119,6 +119,7 @@ func TestFollowerNewChain(t *testing.T) {
 		consensusRelation, status := chain.StatusReport()
 		require.Equal(t, types.ConsensusRelationFollower, consensusRelation)
 		require.Equal(t, types.StatusOnBoarding, status)
+		require.Equal(t, uint64(11), chain.TargetHeight())
 	})
 
 	t.Run("with join block, in channel, empty ledger", func(t *testing.T) {
@@ -159,6 +160,7 @@ func TestFollowerNewChain(t *testing.T) {
 		consensusRelation, status := chain.StatusReport()
 		require.Equal(t, types.ConsensusRelationFollower, consensusRelation)
 		require.True(t, status == types.StatusActive)
+		require.Equal(t, uint64(0), chain.TargetHeight())
 	})
 
 	t.Run("can not find config block in chain", func(t *testing.T) {
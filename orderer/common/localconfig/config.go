@@ -201,15 +201,31 @@ type Statsd struct {
 
 // Admin configures the admin endpoint for the orderer.
 type Admin struct {
-	ListenAddress string
-	TLS           TLS
+	ListenAddress      string
+	TLS                TLS
+	UnauthorizedLimit  int
+	UnauthorizedWindow time.Duration
 }
 
 // ChannelParticipation provides the channel participation API configuration for the orderer.
 // Channel participation uses the same ListenAddress and TLS settings of the Operations service.
 type ChannelParticipation struct {
-	Enabled            bool
-	MaxRequestBodySize uint32
+	Enabled                       bool
+	MaxRequestBodySize            uint32
+	MaxConcurrentJoins            uint32
+	RemovalTimeout                time.Duration
+	WebhookURL                    string
+	WebhookTimeout                time.Duration
+	ProtectConsenters             bool
+	ProtectSoleConsenter          bool
+	SpoolThreshold                uint32
+	OrdererEndpoint               string
+	MetricsEnabled                bool
+	MinFreeDiskSpace              uint64
+	EventsInterval                time.Duration
+	MaxChannels                   uint32
+	AuthorizedOrganizations       []string
+	AuthorizedOrganizationalUnits []string
 }
 
 // Defaults carries the default orderer configuration values.
@@ -287,11 +303,24 @@ var Defaults = TopLevel{
 		Provider: "disabled",
 	},
 	ChannelParticipation: ChannelParticipation{
-		Enabled:            false,
-		MaxRequestBodySize: 1024 * 1024,
+		Enabled:              false,
+		MaxRequestBodySize:   1024 * 1024,
+		MaxConcurrentJoins:   0,
+		RemovalTimeout:       10 * time.Minute,
+		WebhookURL:           "",
+		WebhookTimeout:       5 * time.Second,
+		ProtectConsenters:    false,
+		ProtectSoleConsenter: false,
+		SpoolThreshold:       0,
+		OrdererEndpoint:      "",
+		MetricsEnabled:       false,
+		MinFreeDiskSpace:     0,
+		EventsInterval:       0,
+		MaxChannels:          0,
 	},
 	Admin: Admin{
-		ListenAddress: "127.0.0.1:0",
+		ListenAddress:      "127.0.0.1:0",
+		UnauthorizedWindow: time.Minute,
 	},
 }
 
@@ -351,6 +380,49 @@ func (c *configCache) load() (*TopLevel, error) {
 	return &uconf, nil
 }
 
+// Reload parses the orderer YAML file and environment again, bypassing the
+// configuration cached by Load, e.g. when the orderer is asked to pick up
+// changes at runtime. Unlike Load, invalid settings are returned as an error
+// rather than a panic, so that the running orderer can keep its current
+// configuration. The cache is replaced only by a valid configuration.
+func Reload() (*TopLevel, error) {
+	return cache.reload()
+}
+
+func (c *configCache) reload() (uconf *TopLevel, err error) {
+	config := viperutil.New()
+	config.SetConfigName("orderer")
+
+	if err := config.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("Error reading configuration: %s", err)
+	}
+
+	var conf TopLevel
+	if err := config.EnhancedExactUnmarshal(&conf); err != nil {
+		return nil, fmt.Errorf("Error unmarshalling config into struct: %s", err)
+	}
+	serializedConf, err := json.Marshal(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			uconf, err = nil, fmt.Errorf("Invalid configuration: %v", r)
+		}
+	}()
+	conf.completeInitialization(filepath.Dir(config.ConfigFileUsed()))
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.cache == nil {
+		c.cache = map[string][]byte{}
+	}
+	c.cache[config.ConfigFileUsed()] = serializedConf
+
+	return &conf, nil
+}
+
 func (c *TopLevel) completeInitialization(configDir string) {
 	defer func() {
 		// Translate any paths for cluster TLS configuration if applicable
@@ -489,6 +561,9 @@ func (c *TopLevel) completeInitialization(configDir string) {
 		case c.Admin.TLS.Enabled && !c.Admin.TLS.ClientAuthRequired:
 			logger.Panic("Admin.TLS.ClientAuthRequired must be set to true if Admin.TLS.Enabled is set to true")
 
+		case c.Admin.UnauthorizedLimit > 0 && c.Admin.UnauthorizedWindow <= 0:
+			logger.Panic("Admin.UnauthorizedWindow must be greater than zero if Admin.UnauthorizedLimit is set")
+
 		default:
 			return
 		}
@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -70,6 +71,46 @@ func TestLoadCached(t *testing.T) {
 	require.NotEqual(t, initial, updated, "expected %#v to not equal %#v", updated, initial)
 }
 
+func TestReload(t *testing.T) {
+	original, err := ioutil.ReadFile(filepath.Join(configtest.GetDevConfigDir(), "orderer.yaml"))
+	require.NoError(t, err)
+
+	name, err := ioutil.TempDir("", "hyperledger_fabric")
+	require.NoError(t, err)
+	defer os.RemoveAll(name)
+	configFile := filepath.Join(name, "orderer.yaml")
+	require.NoError(t, ioutil.WriteFile(configFile, original, 0o600))
+
+	os.Setenv("FABRIC_CFG_PATH", name)
+	defer os.Unsetenv("FABRIC_CFG_PATH")
+
+	cc := &configCache{}
+	initial, err := cc.load()
+	require.NoError(t, err)
+	require.Equal(t, uint32(1024*1024), initial.ChannelParticipation.MaxRequestBodySize)
+
+	// The cached configuration is bypassed and replaced by the reloaded one
+	edited := strings.Replace(string(original), "MaxRequestBodySize: 1 MB", "MaxRequestBodySize: 2 MB", 1)
+	require.NoError(t, ioutil.WriteFile(configFile, []byte(edited), 0o600))
+	reloaded, err := cc.reload()
+	require.NoError(t, err)
+	require.Equal(t, uint32(2*1024*1024), reloaded.ChannelParticipation.MaxRequestBodySize)
+	cached, err := cc.load()
+	require.NoError(t, err)
+	require.Equal(t, reloaded, cached)
+
+	// Invalid settings are returned as an error and leave the cache alone
+	edited = strings.Replace(edited, "UnauthorizedLimit: 0", "UnauthorizedLimit: 5", 1)
+	edited = strings.Replace(edited, "UnauthorizedWindow: 1m", "UnauthorizedWindow: 0s", 1)
+	require.NoError(t, ioutil.WriteFile(configFile, []byte(edited), 0o600))
+	reloaded, err = cc.reload()
+	require.EqualError(t, err, "Invalid configuration: Admin.UnauthorizedWindow must be greater than zero if Admin.UnauthorizedLimit is set")
+	require.Nil(t, reloaded)
+	cached, err = cc.load()
+	require.NoError(t, err)
+	require.Equal(t, uint32(2*1024*1024), cached.ChannelParticipation.MaxRequestBodySize)
+}
+
 func TestLoadMissingConfigFile(t *testing.T) {
 	envVar1 := "FABRIC_CFG_PATH"
 	envVal1 := "invalid fabric cfg path"
@@ -224,6 +265,30 @@ func TestAdminTLSConfig(t *testing.T) {
 	}
 }
 
+func TestAdminUnauthorizedLimit(t *testing.T) {
+	testCases := []struct {
+		name        string
+		limit       int
+		window      time.Duration
+		shouldPanic bool
+	}{
+		{name: "no limit", limit: 0, window: 0, shouldPanic: false},
+		{name: "limit and window", limit: 5, window: time.Minute, shouldPanic: false},
+		{name: "limit without window", limit: 5, window: 0, shouldPanic: true},
+		{name: "limit and negative window", limit: 5, window: -time.Minute, shouldPanic: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uconf := &TopLevel{Admin: Admin{UnauthorizedLimit: tc.limit, UnauthorizedWindow: tc.window}}
+			if tc.shouldPanic {
+				require.PanicsWithValue(t, "Admin.UnauthorizedWindow must be greater than zero if Admin.UnauthorizedLimit is set", func() { uconf.completeInitialization("/dummy/path") })
+			} else {
+				require.NotPanics(t, func() { uconf.completeInitialization("/dummy/path") }, "Should not panic")
+			}
+		})
+	}
+}
+
 func TestClusterDefaults(t *testing.T) {
 	cleanup := configtest.SetDevFabricConfigPath(t)
 	defer cleanup()
@@ -313,4 +378,16 @@ func TestChannelParticipationDefaults(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, cfg.ChannelParticipation.Enabled, Defaults.ChannelParticipation.Enabled)
 	require.Equal(t, cfg.ChannelParticipation.MaxRequestBodySize, Defaults.ChannelParticipation.MaxRequestBodySize)
+	require.Equal(t, cfg.ChannelParticipation.MaxConcurrentJoins, Defaults.ChannelParticipation.MaxConcurrentJoins)
+	require.Equal(t, cfg.ChannelParticipation.RemovalTimeout, Defaults.ChannelParticipation.RemovalTimeout)
+	require.Equal(t, cfg.ChannelParticipation.WebhookURL, Defaults.ChannelParticipation.WebhookURL)
+	require.Equal(t, cfg.ChannelParticipation.WebhookTimeout, Defaults.ChannelParticipation.WebhookTimeout)
+	require.Equal(t, cfg.ChannelParticipation.ProtectConsenters, Defaults.ChannelParticipation.ProtectConsenters)
+	require.Equal(t, cfg.ChannelParticipation.ProtectSoleConsenter, Defaults.ChannelParticipation.ProtectSoleConsenter)
+	require.Equal(t, cfg.ChannelParticipation.SpoolThreshold, Defaults.ChannelParticipation.SpoolThreshold)
+	require.Equal(t, cfg.ChannelParticipation.OrdererEndpoint, Defaults.ChannelParticipation.OrdererEndpoint)
+	require.Empty(t, cfg.ChannelParticipation.AuthorizedOrganizations)
+	require.Empty(t, Defaults.ChannelParticipation.AuthorizedOrganizations)
+	require.Empty(t, cfg.ChannelParticipation.AuthorizedOrganizationalUnits)
+	require.Empty(t, Defaults.ChannelParticipation.AuthorizedOrganizationalUnits)
 }
@@ -0,0 +1,24 @@
+// +build windows
+
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package multichannel
+
+import "golang.org/x/sys/windows"
+
+// freeDiskSpace returns the number of bytes available to the caller on the volume of a directory.
+func freeDiskSpace(dir string) (uint64, error) {
+	dirPtr, err := windows.UTF16PtrFromString(dir)
+	if err != nil {
+		return 0, err
+	}
+	var free uint64
+	if err := windows.GetDiskFreeSpaceEx(dirPtr, &free, nil, nil); err != nil {
+		return 0, err
+	}
+	return free, nil
+}
@@ -11,18 +11,25 @@ package multichannel
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric-protos-go/common"
+	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric-protos-go/orderer/etcdraft"
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/configtx"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/internal/configtxlator/update"
 	"github.com/hyperledger/fabric/internal/pkg/identity"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/common/cluster"
@@ -56,6 +63,16 @@ type Registrar struct {
 	pendingRemoval  map[string]consensus.StaticStatusReporter
 	systemChannelID string
 	systemChannel   *ChainSupport
+	// joinedFromGenesis records, for the channels joined with a join block, whether the join block was the
+	// genesis block. It is rebuilt at startup from the joined blocks kept on disk.
+	joinedFromGenesis map[string]bool
+	// loading is 1 until Initialize has loaded the channels found at startup; accessed atomically.
+	loading uint32
+	// revision is incremented on every change to the channels the orderer hosts, or to the consensus relation of
+	// one of them.
+	revision uint64
+	// relationObserver is notified when the consensus relation of a channel changes; nil means none.
+	relationObserver func(types.ChannelInfo)
 
 	consenters                  map[string]consensus.Consenter
 	ledgerFactory               blockledger.Factory
@@ -67,7 +84,8 @@ type Registrar struct {
 	clusterDialer               *cluster.PredicateDialer
 	channelParticipationMetrics *Metrics
 
-	joinBlockFileRepo *filerepo.Repo
+	joinBlockFileRepo   *filerepo.Repo
+	joinedBlockFileRepo *filerepo.Repo
 }
 
 // ConfigBlockOrPanic retrieves the last configuration block from the given ledger.
@@ -106,6 +124,8 @@ func NewRegistrar(
 		chains:                      make(map[string]*ChainSupport),
 		followers:                   make(map[string]*follower.Chain),
 		pendingRemoval:              make(map[string]consensus.StaticStatusReporter),
+		joinedFromGenesis:           make(map[string]bool),
+		loading:                     1,
 		ledgerFactory:               ledgerFactory,
 		signer:                      signer,
 		blockcutterMetrics:          blockcutter.NewMetrics(metricsProvider),
@@ -121,6 +141,10 @@ func NewRegistrar(
 		if err != nil {
 			logger.Panicf("Error initializing joinblock file repo: %s", err)
 		}
+		r.joinedBlockFileRepo, err = filerepo.New(filepath.Join(config.FileLedger.Location, "joinblocks"), "joined")
+		if err != nil {
+			logger.Panicf("Error initializing joined block file repo: %s", err)
+		}
 	}
 
 	return r
@@ -145,6 +169,13 @@ func (r *Registrar) Initialize(consenters map[string]consensus.Consenter) {
 	r.lock.Lock()
 	defer r.lock.Unlock()
 	r.startChannels()
+	atomic.StoreUint32(&r.loading, 0)
+}
+
+// Loading reports whether the registrar is still loading the channels found at startup, in which case the
+// channel list is incomplete.
+func (r *Registrar) Loading() bool {
+	return atomic.LoadUint32(&r.loading) == 1
 }
 
 func (r *Registrar) init(consenters map[string]consensus.Consenter) {
@@ -168,6 +199,10 @@ func (r *Registrar) init(consenters map[string]consensus.Consenter) {
 	} else {
 		r.initAppChannelsWhenSystemChannelExists(existingChannels)
 	}
+
+	// Recover whether the channels were joined from their genesis block, which the join-blocks above only tell for
+	// the channels that were still onboarding.
+	r.loadJoinedFromGenesis()
 }
 
 // startChannels starts internal go-routines in chains and followers.
@@ -281,6 +316,7 @@ func (r *Registrar) initAppChannels(existingChannels []string, channelsWithJoinB
 		if err != nil {
 			logger.Panicf("Error: %s, channel: %s", err, channelID)
 		}
+		r.joinedFromGenesis[channelID] = joinBlock.Header.Number == 0
 
 		isMember, err := clusterConsenter.IsChannelMember(joinBlock)
 		if err != nil {
@@ -396,17 +432,27 @@ func (r *Registrar) initLedgerResourcesClusterConsenter(configBlock *cb.Block) (
 	}
 
 	ordererConfig, _ := ledgerRes.OrdererConfig()
-	consenter, foundConsenter := r.consenters[ordererConfig.ConsensusType()]
+	clusterConsenter, err := r.clusterConsenter(ordererConfig.ConsensusType())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ledgerRes, clusterConsenter, nil
+}
+
+// clusterConsenter returns the consenter of a consensus type, which must be a consensus.ClusterConsenter.
+func (r *Registrar) clusterConsenter(consensusType string) (consensus.ClusterConsenter, error) {
+	consenter, foundConsenter := r.consenters[consensusType]
 	if !foundConsenter {
-		return nil, nil, errors.Errorf("failed to find a consenter for consensus type: %s", ordererConfig.ConsensusType())
+		return nil, errors.Errorf("failed to find a consenter for consensus type: %s", consensusType)
 	}
 
 	clusterConsenter, ok := consenter.(consensus.ClusterConsenter)
 	if !ok {
-		return nil, nil, errors.New("failed cast: clusterConsenter is not a consensus.ClusterConsenter")
+		return nil, errors.New("failed cast: clusterConsenter is not a consensus.ClusterConsenter")
 	}
 
-	return ledgerRes, clusterConsenter, nil
+	return clusterConsenter, nil
 }
 
 // SystemChannelID returns the ChannelID for the system channel.
@@ -484,6 +530,27 @@ func (r *Registrar) GetFollower(chainID string) *follower.Chain {
 }
 
 func (r *Registrar) newLedgerResources(configTx *cb.Envelope) (*ledgerResources, error) {
+	bundle, err := r.newBundle(configTx)
+	if err != nil {
+		return nil, err
+	}
+
+	ledger, err := r.ledgerFactory.GetOrCreate(bundle.ConfigtxValidator().ChannelID())
+	if err != nil {
+		return nil, errors.WithMessagef(err, "error getting ledger for channel: %s", bundle.ConfigtxValidator().ChannelID())
+	}
+
+	return &ledgerResources{
+		configResources: &configResources{
+			mutableResources: channelconfig.NewBundleSource(bundle, r.callbacks...),
+			bccsp:            r.bccsp,
+		},
+		ReadWriter: ledger,
+	}, nil
+}
+
+// newBundle creates the channelconfig bundle of a config transaction and checks its resources.
+func (r *Registrar) newBundle(configTx *cb.Envelope) (*channelconfig.Bundle, error) {
 	payload, err := protoutil.UnmarshalPayload(configTx.Payload)
 	if err != nil {
 		return nil, errors.WithMessage(err, "error umarshaling envelope to payload")
@@ -513,18 +580,7 @@ func (r *Registrar) newLedgerResources(configTx *cb.Envelope) (*ledgerResources,
 		return nil, errors.WithMessagef(err, "error checking bundle for channel: %s", chdr.ChannelId)
 	}
 
-	ledger, err := r.ledgerFactory.GetOrCreate(chdr.ChannelId)
-	if err != nil {
-		return nil, errors.WithMessagef(err, "error getting ledger for channel: %s", chdr.ChannelId)
-	}
-
-	return &ledgerResources{
-		configResources: &configResources{
-			mutableResources: channelconfig.NewBundleSource(bundle, r.callbacks...),
-			bccsp:            r.bccsp,
-		},
-		ReadWriter: ledger,
-	}, nil
+	return bundle, nil
 }
 
 // CreateChain makes the Registrar create a consensus.Chain with the given name.
@@ -593,11 +649,13 @@ func (r *Registrar) SwitchFollowerToChain(channelID string) {
 	delete(r.followers, channelID)
 	logger.Debugf("Removed follower for channel %s", channelID)
 	cs := r.createNewChain(configTx(lf))
+	r.revision++
 	if err := r.removeJoinBlock(channelID); err != nil {
 		logger.Panicf("Failed removing join-block for channel: %s: %v", channelID, err)
 	}
 	cs.start()
 	logger.Infof("Created and started channel %s", cs.ChannelID())
+	r.relationChanged(channelID)
 }
 
 // SwitchChainToFollower creates a follower.Chain from the tip of the ledger and removes the consensus.Chain.
@@ -635,8 +693,33 @@ func (r *Registrar) SwitchChainToFollower(channelName string) {
 		logger.Panicf("Failed to create follower.Chain for channel '%s', error: %s", channelName, err)
 	}
 	fChain.Start()
+	r.revision++
 
 	logger.Infof("Created and started a follower.Chain for channel %s", channelName)
+	r.relationChanged(channelName)
+}
+
+// ObserveRelationChanges registers a function that is called with the information of a channel whenever its consensus
+// relation changes, i.e. when a follower becomes a consenter, or a consenter is evicted and becomes a follower. The
+// function is called on its own go-routine, so it may call back on the Registrar. It replaces any previous one.
+func (r *Registrar) ObserveRelationChanges(observer func(types.ChannelInfo)) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.relationObserver = observer
+}
+
+// relationChanged notifies the relation observer, if any, of the new consensus relation of a channel. It must be
+// called with the lock held.
+func (r *Registrar) relationChanged(channelID string) {
+	if r.relationObserver == nil {
+		return
+	}
+	info, err := r.channelInfo(channelID)
+	if err != nil {
+		logger.Warnf("Failed to read the information of channel %s after its consensus relation changed: %s", channelID, err)
+		return
+	}
+	go r.relationObserver(info)
 }
 
 // ChannelsCount returns the count of the current total number of channels.
@@ -664,7 +747,7 @@ func (r *Registrar) ChannelList() types.ChannelList {
 	r.lock.RLock()
 	defer r.lock.RUnlock()
 
-	list := types.ChannelList{}
+	list := types.ChannelList{Revision: r.revision}
 
 	if r.systemChannelID != "" {
 		list.SystemChannel = &types.ChannelInfoShort{Name: r.systemChannelID}
@@ -694,17 +777,29 @@ func (r *Registrar) ChannelInfo(channelID string) (types.ChannelInfo, error) {
 	r.lock.RLock()
 	defer r.lock.RUnlock()
 
+	return r.channelInfo(channelID)
+}
+
+func (r *Registrar) channelInfo(channelID string) (types.ChannelInfo, error) {
 	info := types.ChannelInfo{Name: channelID}
 
 	if c, ok := r.chains[channelID]; ok {
 		info.Height = c.Height()
 		info.ConsensusRelation, info.Status = c.StatusReport()
+		info.JoinedFromGenesis = r.joinedFromGenesisOf(channelID)
+		sequence := c.Sequence()
+		info.ConfigSequence = &sequence
 		return info, nil
 	}
 
 	if f, ok := r.followers[channelID]; ok {
 		info.Height = f.Height()
 		info.ConsensusRelation, info.Status = f.StatusReport()
+		info.JoinedFromGenesis = r.joinedFromGenesisOf(channelID)
+		info.ConfigSequence = r.followerConfigSequence(channelID)
+		if targetHeight := f.TargetHeight(); info.Status == types.StatusOnBoarding && targetHeight > 0 {
+			info.TargetHeight = &targetHeight
+		}
 		return info, nil
 	}
 
@@ -720,33 +815,235 @@ func (r *Registrar) ChannelInfo(channelID string) (types.ChannelInfo, error) {
 	return types.ChannelInfo{}, types.ErrChannelNotExist
 }
 
-// JoinChannel instructs the orderer to create a channel and join it with the provided config block.
-// The URL field is empty, and is to be completed by the caller.
-func (r *Registrar) JoinChannel(channelID string, configBlock *cb.Block, isAppChannel bool) (info types.ChannelInfo, err error) {
-	r.lock.Lock()
-	defer r.lock.Unlock()
+// followerConfigSequence returns the sequence number of the config of a follower, or nil if it cannot be read.
+func (r *Registrar) followerConfigSequence(channelID string) *uint64 {
+	config, err := r.channelConfig(channelID)
+	if err != nil {
+		logger.Debugf("Failed to read the config of channel %s: %s", channelID, err)
+		return nil
+	}
+	return &config.Sequence
+}
 
-	if status, ok := r.pendingRemoval[channelID]; ok {
-		if status.Status == types.StatusFailed {
-			return types.ChannelInfo{}, types.ErrChannelRemovalFailure
+// joinedFromGenesisOf returns whether a channel was joined with its genesis block, or nil if that is unknown.
+func (r *Registrar) joinedFromGenesisOf(channelID string) *bool {
+	fromGenesis, ok := r.joinedFromGenesis[channelID]
+	if !ok {
+		return nil
+	}
+	return &fromGenesis
+}
+
+// ChannelCapabilities returns the capabilities of the config of a channel. For a follower that is
+// still onboarding, these are the capabilities of the join block.
+func (r *Registrar) ChannelCapabilities(channelID string) (types.ChannelCapabilities, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	config, err := r.channelConfig(channelID)
+	if err != nil {
+		return types.ChannelCapabilities{}, err
+	}
+	return capabilitiesFromConfig(config), nil
+}
+
+// JoinBlock returns the marshaled config block the orderer joined a channel with. It is only kept for channels joined
+// through the channel participation API.
+func (r *Registrar) JoinBlock(channelID string) ([]byte, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if _, ok := r.pendingRemoval[channelID]; ok {
+		return nil, types.ErrChannelPendingRemoval
+	}
+	_, isChain := r.chains[channelID]
+	_, isFollower := r.followers[channelID]
+	if !isChain && !isFollower {
+		return nil, types.ErrChannelNotExist
+	}
+	if r.joinedBlockFileRepo == nil {
+		return nil, types.ErrJoinBlockNotExist
+	}
+
+	blockBytes, err := r.joinedBlockFileRepo.Read(channelID)
+	if os.IsNotExist(err) {
+		return nil, types.ErrJoinBlockNotExist
+	}
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed reading joined block for channel %s", channelID)
+	}
+	return blockBytes, nil
+}
+
+// LedgerBytes returns the approximate size on disk of the block store of a channel, that is, the total size of its
+// block files. The block indexes, which are shared by all the channels, are not included.
+func (r *Registrar) LedgerBytes(channelID string) (uint64, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	_, isChain := r.chains[channelID]
+	_, isFollower := r.followers[channelID]
+	if !isChain && !isFollower {
+		return 0, types.ErrChannelNotExist
+	}
+
+	var size uint64
+	ledgerDir := filepath.Join(r.config.FileLedger.Location, blkstorage.ChainsDir, channelID)
+	err := filepath.Walk(ledgerDir, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += uint64(info.Size())
 		}
-		return types.ChannelInfo{}, types.ErrChannelPendingRemoval
+		return nil
+	})
+	if err != nil {
+		return 0, errors.WithMessagef(err, "failed computing the ledger size of channel %s", channelID)
 	}
+	return size, nil
+}
 
-	if r.systemChannelID != "" {
-		return types.ChannelInfo{}, types.ErrSystemChannelExists
+// FreeDiskSpace returns the disk space available to the ledgers of the channels, that is, on the file system of
+// the ledger location.
+func (r *Registrar) FreeDiskSpace() (uint64, error) {
+	free, err := freeDiskSpace(r.config.FileLedger.Location)
+	if err != nil {
+		return 0, errors.WithMessagef(err, "failed checking the free disk space of %s", r.config.FileLedger.Location)
 	}
+	return free, nil
+}
 
-	if _, ok := r.chains[channelID]; ok {
-		return types.ChannelInfo{}, types.ErrChannelAlreadyExists
+// ConsenterCount returns the number of consenters in the config of a channel. For a follower that is
+// still onboarding, this is the number of consenters in the join block. Only the etcdraft consensus
+// type has a consenter set.
+func (r *Registrar) ConsenterCount(channelID string) (int, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	config, err := r.channelConfig(channelID)
+	if err != nil {
+		return 0, err
+	}
+	return consenterCountFromConfig(config)
+}
+
+// channelConfig returns the current config of a chain, or the config block of a follower.
+// The caller must hold the lock.
+func (r *Registrar) channelConfig(channelID string) (*cb.Config, error) {
+	if cs, ok := r.chains[channelID]; ok {
+		return cs.ConfigProto(), nil
 	}
 
 	if _, ok := r.followers[channelID]; ok {
-		return types.ChannelInfo{}, types.ErrChannelAlreadyExists
+		configBlock, err := r.followerConfigBlock(channelID)
+		if err != nil {
+			return nil, err
+		}
+		configEnv, err := configEnvelopeFromBlock(configBlock)
+		if err != nil {
+			return nil, err
+		}
+		return configEnv.Config, nil
 	}
 
-	if !isAppChannel && len(r.chains) > 0 {
-		return types.ChannelInfo{}, types.ErrAppChannelsAlreadyExists
+	return nil, types.ErrChannelNotExist
+}
+
+// followerConfigBlock returns the join block of a follower, or the last config block in its ledger
+// when the join block was already removed.
+func (r *Registrar) followerConfigBlock(channelID string) (*cb.Block, error) {
+	if blockBytes, err := r.joinBlockFileRepo.Read(channelID); err == nil {
+		return protoutil.UnmarshalBlock(blockBytes)
+	}
+
+	ledger, err := r.ledgerFactory.GetOrCreate(channelID)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get ledger for channel %s", channelID)
+	}
+	if ledger.Height() == 0 {
+		return nil, errors.Errorf("ledger of channel %s is empty", channelID)
+	}
+	lastBlock, err := blockledger.GetBlockByNumber(ledger, ledger.Height()-1)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to retrieve last block of channel %s", channelID)
+	}
+	index, err := protoutil.GetLastConfigIndexFromBlock(lastBlock)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to retrieve last config index of channel %s", channelID)
+	}
+	return blockledger.GetBlockByNumber(ledger, index)
+}
+
+func configEnvelopeFromBlock(block *cb.Block) (*cb.ConfigEnvelope, error) {
+	env, err := protoutil.ExtractEnvelope(block, 0)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to extract envelope from config block")
+	}
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to unmarshal payload of config block")
+	}
+	return configtx.UnmarshalConfigEnvelope(payload.Data)
+}
+
+// capabilitiesFromConfig returns the sorted capability keys of the channel, orderer, and application groups.
+func capabilitiesFromConfig(config *cb.Config) types.ChannelCapabilities {
+	channelGroup := config.GetChannelGroup()
+	return types.ChannelCapabilities{
+		Channel:     capabilityKeys(channelGroup),
+		Orderer:     capabilityKeys(channelGroup.GetGroups()[channelconfig.OrdererGroupKey]),
+		Application: capabilityKeys(channelGroup.GetGroups()[channelconfig.ApplicationGroupKey]),
+	}
+}
+
+// consenterCountFromConfig returns the number of consenters in the etcdraft metadata of the orderer group.
+func consenterCountFromConfig(config *cb.Config) (int, error) {
+	ordererGroup := config.GetChannelGroup().GetGroups()[channelconfig.OrdererGroupKey]
+	value, ok := ordererGroup.GetValues()[channelconfig.ConsensusTypeKey]
+	if !ok {
+		return 0, errors.New("config has no consensus type")
+	}
+	consensusType := &ab.ConsensusType{}
+	if err := proto.Unmarshal(value.Value, consensusType); err != nil {
+		return 0, errors.WithMessage(err, "failed to unmarshal consensus type")
+	}
+	if consensusType.Type != "etcdraft" {
+		return 0, errors.Errorf("consensus type %s has no consenter set", consensusType.Type)
+	}
+	metadata := &etcdraft.ConfigMetadata{}
+	if err := proto.Unmarshal(consensusType.Metadata, metadata); err != nil {
+		return 0, errors.WithMessage(err, "failed to unmarshal etcdraft metadata")
+	}
+	return len(metadata.Consenters), nil
+}
+
+func capabilityKeys(group *cb.ConfigGroup) []string {
+	value, ok := group.GetValues()[channelconfig.CapabilitiesKey]
+	if !ok {
+		return nil
+	}
+	capabilities := &cb.Capabilities{}
+	if err := proto.Unmarshal(value.Value, capabilities); err != nil {
+		logger.Warningf("Failed to unmarshal capabilities: %s", err)
+		return nil
+	}
+	var keys []string
+	for key := range capabilities.Capabilities {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// JoinChannel instructs the orderer to create a channel and join it with the provided config block.
+// The URL field is empty, and is to be completed by the caller.
+func (r *Registrar) JoinChannel(channelID string, configBlock *cb.Block, isAppChannel bool) (info types.ChannelInfo, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if err := r.checkJoin(channelID, isAppChannel); err != nil {
+		return types.ChannelInfo{}, err
 	}
 
 	defer func() {
@@ -769,12 +1066,29 @@ func (r *Registrar) JoinChannel(channelID string, configBlock *cb.Block, isAppCh
 	if err := r.joinBlockFileRepo.Save(channelID, blockBytes); err != nil {
 		return types.ChannelInfo{}, errors.WithMessagef(err, "failed saving joinblock to file repo for channel %s", channelID)
 	}
+	// the joined block is kept for as long as the channel exists, unlike the
+	// joinblock which is removed once the orderer is a member of the channel
+	if err := r.joinedBlockFileRepo.Save(channelID, blockBytes); err != nil {
+		if err2 := r.removeJoinBlock(channelID); err2 != nil {
+			logger.Warningf("Failed to cleanup joinblock for channel %s: %v", channelID, err2)
+		}
+		return types.ChannelInfo{}, errors.WithMessagef(err, "failed saving joined block to file repo for channel %s", channelID)
+	}
 	defer func() {
 		if err != nil {
 			if err2 := r.removeJoinBlock(channelID); err2 != nil {
 				logger.Warningf("Failed to cleanup joinblock for channel %s: %v", channelID, err2)
 			}
+			if err2 := r.removeJoinedBlock(channelID); err2 != nil {
+				logger.Warningf("Failed to cleanup joined block for channel %s: %v", channelID, err2)
+			}
+			return
 		}
+		r.joinedFromGenesis[channelID] = configBlock.Header.Number == 0
+		r.revision++
+		info.JoinedFromGenesis = r.joinedFromGenesisOf(channelID)
+		sequence := ledgerRes.ConfigtxValidator().Sequence()
+		info.ConfigSequence = &sequence
 	}()
 
 	if !isAppChannel {
@@ -808,6 +1122,101 @@ func (r *Registrar) JoinChannel(channelID string, configBlock *cb.Block, isAppCh
 	return info, err
 }
 
+// checkJoin returns the error of joining a channel when the orderer already has the channel, or cannot join it.
+// The caller must hold the lock.
+func (r *Registrar) checkJoin(channelID string, isAppChannel bool) error {
+	if status, ok := r.pendingRemoval[channelID]; ok {
+		if status.Status == types.StatusFailed {
+			return types.ErrChannelRemovalFailure
+		}
+		return types.ErrChannelPendingRemoval
+	}
+
+	if r.systemChannelID != "" {
+		return types.ErrSystemChannelExists
+	}
+
+	if _, ok := r.chains[channelID]; ok {
+		return types.ErrChannelAlreadyExists
+	}
+
+	if _, ok := r.followers[channelID]; ok {
+		return types.ErrChannelAlreadyExists
+	}
+
+	if !isAppChannel && len(r.chains) > 0 {
+		return types.ErrAppChannelsAlreadyExists
+	}
+
+	return nil
+}
+
+// ValidateJoin runs the checks of JoinChannel, without creating the channel or changing anything on disk, and
+// provides the information the channel would have once joined. The URL field is empty, and is to be completed by
+// the caller.
+func (r *Registrar) ValidateJoin(channelID string, configBlock *cb.Block, isAppChannel bool) (types.ChannelInfo, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if err := r.checkJoin(channelID, isAppChannel); err != nil {
+		return types.ChannelInfo{}, err
+	}
+
+	configEnv, err := protoutil.ExtractEnvelope(configBlock, 0)
+	if err != nil {
+		return types.ChannelInfo{}, errors.WithMessagef(err, "failed extracting config envelope from block")
+	}
+	bundle, err := r.newBundle(configEnv)
+	if err != nil {
+		return types.ChannelInfo{}, errors.WithMessagef(err, "failed creating ledger resources")
+	}
+	ordererConfig, _ := bundle.OrdererConfig()
+	clusterConsenter, err := r.clusterConsenter(ordererConfig.ConsensusType())
+	if err != nil {
+		return types.ChannelInfo{}, err
+	}
+
+	fromGenesis := configBlock.Header.Number == 0
+	sequence := bundle.ConfigtxValidator().Sequence()
+	info := types.ChannelInfo{
+		Name:              channelID,
+		URL:               "",
+		JoinedFromGenesis: &fromGenesis,
+		ConfigSequence:    &sequence,
+	}
+
+	if !isAppChannel {
+		// as joinSystemChannel, which appends the genesis block and requires a restart
+		if fromGenesis {
+			info.Height = 1
+		}
+		info.ConsensusRelation, info.Status = types.ConsensusRelationConfigTracker, types.StatusInactive
+		info.RequiresRestart = true
+		return info, nil
+	}
+
+	isMember, err := clusterConsenter.IsChannelMember(configBlock)
+	if err != nil {
+		return types.ChannelInfo{}, errors.WithMessage(err, "failed to determine cluster membership from join-block")
+	}
+
+	if fromGenesis && isMember {
+		// as createAsMember, which appends the genesis block and starts the chain
+		info.Height = 1
+		info.ConsensusRelation, info.Status = types.ConsensusRelationConsenter, types.StatusActive
+		return info, nil
+	}
+
+	// as createFollower, which onboards up to the join block
+	info.ConsensusRelation, info.Status = types.ConsensusRelationFollower, types.StatusOnBoarding
+	if isMember {
+		info.ConsensusRelation = types.ConsensusRelationConsenter
+	}
+	targetHeight := configBlock.Header.Number + 1
+	info.TargetHeight = &targetHeight
+	return info, nil
+}
+
 func (r *Registrar) createAsMember(ledgerRes *ledgerResources, configBlock *cb.Block, channelID string) (*ChainSupport, types.ChannelInfo, error) {
 	if ledgerRes.Height() == 0 {
 		if err := ledgerRes.Append(configBlock); err != nil {
@@ -876,6 +1285,9 @@ func (r *Registrar) createFollower(
 		ConsensusRelation: clusterRelation,
 		Status:            status,
 	}
+	if targetHeight := fChain.TargetHeight(); status == types.StatusOnBoarding && targetHeight > 0 {
+		info.TargetHeight = &targetHeight
+	}
 
 	r.followers[channelID] = fChain
 
@@ -910,9 +1322,10 @@ func (r *Registrar) joinSystemChannel(
 	r.systemChannelID = channelID
 
 	info := types.ChannelInfo{
-		Name:   channelID,
-		URL:    "",
-		Height: ledgerRes.Height(),
+		Name:            channelID,
+		URL:             "",
+		Height:          ledgerRes.Height(),
+		RequiresRestart: true,
 	}
 	info.ConsensusRelation, info.Status = r.systemChannel.StatusReport()
 
@@ -921,6 +1334,244 @@ func (r *Registrar) joinSystemChannel(
 	return info, nil
 }
 
+// ComputeConfigUpdate translates a patch of the orderer batch parameters, or of the consensus state, into a config
+// update of a channel. The config update is not signed: it must be signed by the identities that satisfy the
+// modification policy of the patched values, and then submitted with UpdateChannelConfig.
+func (r *Registrar) ComputeConfigUpdate(channelID string, patch types.ChannelConfigPatch) (*cb.ConfigUpdate, error) {
+	cs, err := r.updatableChain(channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	original := cs.ConfigProto()
+	updated, err := applyConfigPatch(original, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	configUpdate, err := update.Compute(original, updated)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed computing config update")
+	}
+	configUpdate.ChannelId = channelID
+
+	return configUpdate, nil
+}
+
+// UpdateChannelConfig submits a config update, signed by the client, to a channel. The config update may only change
+// the orderer batch parameters, or the consensus state, and it is authorized by its own signatures only: the envelope
+// that carries it is signed by this orderer, as required to submit it, but that signature does not count towards the
+// modification policy of the values it changes.
+func (r *Registrar) UpdateChannelConfig(channelID string, configUpdateEnv *cb.ConfigUpdateEnvelope) error {
+	cs, err := r.updatableChain(channelID)
+	if err != nil {
+		return err
+	}
+
+	configUpdate, err := configtx.UnmarshalConfigUpdate(configUpdateEnv.ConfigUpdate)
+	if err != nil {
+		return errors.WithMessage(err, "invalid config update")
+	}
+	if configUpdate.ChannelId != channelID {
+		return errors.Errorf("config update is for channel %s, not %s", configUpdate.ChannelId, channelID)
+	}
+
+	env, err := protoutil.CreateSignedEnvelope(cb.HeaderType_CONFIG_UPDATE, channelID, r.signer, configUpdateEnv, 0, 0)
+	if err != nil {
+		return errors.WithMessage(err, "failed creating config update envelope")
+	}
+
+	config, configSeq, err := cs.ProcessConfigUpdateMsg(env)
+	if err != nil {
+		return errors.WithMessage(err, "config update rejected")
+	}
+	updated, err := configFromEnvelope(config)
+	if err != nil {
+		return err
+	}
+	if err := checkConfigChange(cs.ConfigProto(), updated); err != nil {
+		return errors.WithMessage(err, "config update rejected")
+	}
+
+	if err := cs.WaitReady(); err != nil {
+		return errors.WithMessage(err, "config update rejected by consenter")
+	}
+	if err := cs.Configure(config, configSeq); err != nil {
+		return errors.WithMessage(err, "config update rejected by consenter")
+	}
+
+	logger.Infof("Submitted config update for channel: %s", channelID)
+
+	return nil
+}
+
+// updatableChain returns the chain of a channel whose config may be updated through this orderer, that is, a channel
+// it is a consenter of.
+func (r *Registrar) updatableChain(channelID string) (*ChainSupport, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if _, ok := r.pendingRemoval[channelID]; ok {
+		return nil, types.ErrChannelPendingRemoval
+	}
+	if _, ok := r.followers[channelID]; ok {
+		return nil, errors.Errorf("cannot update channel %s: this orderer is a follower", channelID)
+	}
+	cs, ok := r.chains[channelID]
+	if !ok {
+		return nil, types.ErrChannelNotExist
+	}
+	return cs, nil
+}
+
+// configFromEnvelope returns the config carried by a config envelope.
+func configFromEnvelope(env *cb.Envelope) (*cb.Config, error) {
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, err
+	}
+	configEnv, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+	return configEnv.Config, nil
+}
+
+// configPatchKeys are the values of the orderer group that a config update submitted with UpdateChannelConfig may change.
+var configPatchKeys = []string{channelconfig.BatchTimeoutKey, channelconfig.BatchSizeKey}
+
+// checkConfigChange returns an error when the updated config differs from the original config in more than the values
+// of the orderer group listed in configPatchKeys, or than the state of the consensus type. A change of the state cannot
+// be combined with other changes, the same way a config patch cannot.
+func checkConfigChange(original, updated *cb.Config) error {
+	strip := func(config *cb.Config) (*cb.ConfigGroup, ab.ConsensusType_State, error) {
+		channelGroup := proto.Clone(config.GetChannelGroup()).(*cb.ConfigGroup)
+		ordererGroup, ok := channelGroup.GetGroups()[channelconfig.OrdererGroupKey]
+		if !ok {
+			return nil, 0, errors.New("config does not contain an orderer group")
+		}
+		var state ab.ConsensusType_State
+		if value, ok := ordererGroup.Values[channelconfig.ConsensusTypeKey]; ok {
+			consensusType := &ab.ConsensusType{}
+			if err := proto.Unmarshal(value.Value, consensusType); err != nil {
+				return nil, 0, errors.Wrap(err, "failed unmarshalling consensus type")
+			}
+			state = consensusType.State
+			consensusType.State = ab.ConsensusType_STATE_NORMAL
+			stripped, err := proto.Marshal(consensusType)
+			if err != nil {
+				return nil, 0, err
+			}
+			value.Value = stripped
+			value.Version = 0
+		}
+		return channelGroup, state, nil
+	}
+
+	originalGroup, originalState, err := strip(original)
+	if err != nil {
+		return err
+	}
+	updatedGroup, updatedState, err := strip(updated)
+	if err != nil {
+		return err
+	}
+	if originalState != updatedState {
+		if !proto.Equal(originalGroup, updatedGroup) {
+			return errors.New("the consensus state cannot be changed together with other values")
+		}
+		return nil
+	}
+	for _, group := range []*cb.ConfigGroup{originalGroup, updatedGroup} {
+		for _, key := range configPatchKeys {
+			delete(group.Groups[channelconfig.OrdererGroupKey].Values, key)
+		}
+	}
+	if !proto.Equal(originalGroup, updatedGroup) {
+		return errors.Errorf("only the orderer values %s, or the consensus state, may be changed", strings.Join(configPatchKeys, ", "))
+	}
+	return nil
+}
+
+// applyConfigPatch returns a copy of the config with the orderer batch parameters, and the consensus state, replaced
+// by the fields set in the patch. Switching a channel to the consensus state it is already in is an error.
+func applyConfigPatch(config *cb.Config, patch types.ChannelConfigPatch) (*cb.Config, error) {
+	updated := proto.Clone(config).(*cb.Config)
+
+	ordererGroup, ok := updated.GetChannelGroup().GetGroups()[channelconfig.OrdererGroupKey]
+	if !ok {
+		return nil, errors.New("config does not contain an orderer group")
+	}
+
+	if patch.BatchTimeout != nil {
+		value, err := proto.Marshal(&ab.BatchTimeout{Timeout: *patch.BatchTimeout})
+		if err != nil {
+			return nil, err
+		}
+		setConfigValue(ordererGroup, channelconfig.BatchTimeoutKey, value)
+	}
+
+	if patch.MaxMessageCount != nil || patch.AbsoluteMaxBytes != nil || patch.PreferredMaxBytes != nil {
+		batchSize := &ab.BatchSize{}
+		if existing, ok := ordererGroup.Values[channelconfig.BatchSizeKey]; ok {
+			if err := proto.Unmarshal(existing.Value, batchSize); err != nil {
+				return nil, errors.Wrap(err, "failed unmarshalling batch size")
+			}
+		}
+		if patch.MaxMessageCount != nil {
+			batchSize.MaxMessageCount = *patch.MaxMessageCount
+		}
+		if patch.AbsoluteMaxBytes != nil {
+			batchSize.AbsoluteMaxBytes = *patch.AbsoluteMaxBytes
+		}
+		if patch.PreferredMaxBytes != nil {
+			batchSize.PreferredMaxBytes = *patch.PreferredMaxBytes
+		}
+		value, err := proto.Marshal(batchSize)
+		if err != nil {
+			return nil, err
+		}
+		setConfigValue(ordererGroup, channelconfig.BatchSizeKey, value)
+	}
+
+	if patch.ConsensusState != nil {
+		consensusType := &ab.ConsensusType{}
+		existing, ok := ordererGroup.Values[channelconfig.ConsensusTypeKey]
+		if !ok {
+			return nil, errors.New("config does not contain a consensus type")
+		}
+		if err := proto.Unmarshal(existing.Value, consensusType); err != nil {
+			return nil, errors.Wrap(err, "failed unmarshalling consensus type")
+		}
+		state := ab.ConsensusType_STATE_NORMAL
+		if *patch.ConsensusState == types.ConsensusStateMaintenance {
+			state = ab.ConsensusType_STATE_MAINTENANCE
+		}
+		if consensusType.State == state {
+			return nil, types.ErrConsensusStateUnchanged
+		}
+		consensusType.State = state
+		value, err := proto.Marshal(consensusType)
+		if err != nil {
+			return nil, err
+		}
+		setConfigValue(ordererGroup, channelconfig.ConsensusTypeKey, value)
+	}
+
+	return updated, nil
+}
+
+func setConfigValue(group *cb.ConfigGroup, key string, value []byte) {
+	if existing, ok := group.Values[key]; ok {
+		existing.Value = value
+		return
+	}
+	if group.Values == nil {
+		group.Values = map[string]*cb.ConfigValue{}
+	}
+	group.Values[key] = &cb.ConfigValue{Value: value, ModPolicy: channelconfig.AdminsPolicyKey}
+}
+
 // RemoveChannel instructs the orderer to remove a channel.
 func (r *Registrar) RemoveChannel(channelID string) error {
 	r.lock.Lock()
@@ -960,6 +1611,11 @@ func (r *Registrar) removeMember(channelID string, cs *ChainSupport) {
 	r.removeLedgerAsync(channelID)
 
 	delete(r.chains, channelID)
+	delete(r.joinedFromGenesis, channelID)
+	r.revision++
+	if err := r.removeJoinedBlock(channelID); err != nil {
+		logger.Warningf("Failed to remove joined block for channel %s: %v", channelID, err)
+	}
 
 	logger.Infof("Removed channel: %s", channelID)
 }
@@ -973,12 +1629,18 @@ func (r *Registrar) removeFollower(channelID string, follower *follower.Chain) e
 	if err := r.removeJoinBlock(channelID); err != nil {
 		return err
 	}
+	// the joined block only records how the channel was joined, it does not keep the follower registered
+	if err := r.removeJoinedBlock(channelID); err != nil {
+		logger.Warningf("Failed to remove joined block for channel %s: %v", channelID, err)
+	}
 
 	relation, status := follower.StatusReport()
 	r.pendingRemoval[channelID] = consensus.StaticStatusReporter{ConsensusRelation: relation, Status: status}
 	r.removeLedgerAsync(channelID)
 
 	delete(r.followers, channelID)
+	delete(r.joinedFromGenesis, channelID)
+	r.revision++
 
 	logger.Infof("Removed channel: %s", channelID)
 
@@ -1019,6 +1681,42 @@ func (r *Registrar) loadJoinBlocks() map[string]*cb.Block {
 	return channelToBlockMap
 }
 
+// loadJoinedFromGenesis reads the joined blocks kept for the channels the orderer hosts, and records whether each was
+// the genesis block of its channel. A joined block that cannot be read leaves this unknown for its channel.
+func (r *Registrar) loadJoinedFromGenesis() {
+	if r.joinedBlockFileRepo == nil {
+		return
+	}
+	fileNames, err := r.joinedBlockFileRepo.List()
+	if err != nil {
+		logger.Warningf("Error listing joined block file repo: %s", err)
+		return
+	}
+
+	for _, fileName := range fileNames {
+		channelID := r.joinedBlockFileRepo.FileToBaseName(fileName)
+		_, isChain := r.chains[channelID]
+		_, isFollower := r.followers[channelID]
+		if !isChain && !isFollower {
+			continue
+		}
+		if _, ok := r.joinedFromGenesis[channelID]; ok {
+			continue
+		}
+		blockBytes, err := r.joinedBlockFileRepo.Read(channelID)
+		if err != nil {
+			logger.Warningf("Error reading joined block file: '%s', error: %s", fileName, err)
+			continue
+		}
+		block, err := protoutil.UnmarshalBlock(blockBytes)
+		if err != nil || block.Header == nil {
+			logger.Warningf("Error unmarshalling joined block file: '%s', error: %v", fileName, err)
+			continue
+		}
+		r.joinedFromGenesis[channelID] = block.Header.Number == 0
+	}
+}
+
 func (r *Registrar) removeJoinBlock(channelID string) error {
 	if err := r.joinBlockFileRepo.Remove(channelID); err != nil {
 		return errors.WithMessagef(err, "failed removing joinblock for channel %s", channelID)
@@ -1027,6 +1725,19 @@ func (r *Registrar) removeJoinBlock(channelID string) error {
 	return nil
 }
 
+// removeJoinedBlock removes the block the orderer joined a channel with. There is none for a channel that was not
+// joined through the channel participation API, which is not an error.
+func (r *Registrar) removeJoinedBlock(channelID string) error {
+	if r.joinedBlockFileRepo == nil {
+		return nil
+	}
+	if err := r.joinedBlockFileRepo.Remove(channelID); err != nil {
+		return errors.WithMessagef(err, "failed removing joined block for channel %s", channelID)
+	}
+
+	return nil
+}
+
 func (r *Registrar) removeSystemChannel() error {
 	systemChannelID := r.systemChannelID
 	consensusType := r.systemChannel.SharedConfig().ConsensusType()
@@ -1041,6 +1752,10 @@ func (r *Registrar) removeSystemChannel() error {
 	// halt the system channel and remove it from the chains map
 	r.systemChannel.Halt()
 	delete(r.chains, systemChannelID)
+	delete(r.joinedFromGenesis, systemChannelID)
+	if err := r.removeJoinedBlock(systemChannelID); err != nil {
+		return err
+	}
 
 	// remove system channel resources
 	err := r.ledgerFactory.Remove(systemChannelID)
@@ -1051,6 +1766,7 @@ func (r *Registrar) removeSystemChannel() error {
 	// remove system channel references
 	r.systemChannel = nil
 	r.systemChannelID = ""
+	r.revision++
 	logger.Infof("removed system channel: %s", systemChannelID)
 
 	failedRemovals := []string{}
@@ -1106,6 +1822,7 @@ func (r *Registrar) removeLedgerAsync(channelID string) {
 			return
 		}
 		delete(r.pendingRemoval, channelID)
+		r.revision++
 	}()
 }
 
@@ -19,17 +19,21 @@ import (
 	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric-protos-go/orderer/etcdraft"
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/cmd/common/signer"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/crypto/tlsgen"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	"github.com/hyperledger/fabric/common/ledger/blockledger/fileledger"
 	"github.com/hyperledger/fabric/common/metrics/disabled"
 	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/config/configtest"
 	"github.com/hyperledger/fabric/internal/configtxgen/encoder"
 	"github.com/hyperledger/fabric/internal/configtxgen/genesisconfig"
+	"github.com/hyperledger/fabric/internal/configtxlator/update"
 	"github.com/hyperledger/fabric/internal/pkg/comm"
 	"github.com/hyperledger/fabric/internal/pkg/identity"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
@@ -264,7 +268,7 @@ func TestNewRegistrar(t *testing.T) {
 		info, err := manager.ChannelInfo("testchannelid")
 		require.NoError(t, err)
 		require.Equal(t,
-			types.ChannelInfo{Name: "testchannelid", URL: "", ConsensusRelation: "other", Status: "active", Height: 1},
+			types.ChannelInfo{Name: "testchannelid", URL: "", ConsensusRelation: "other", Status: "active", Height: 1, ConfigSequence: uint64Ptr(0)},
 			info,
 		)
 
@@ -335,7 +339,9 @@ func TestRegistrar_Initialize(t *testing.T) {
 		lf, _ := newLedgerAndFactory(tmpdir, "my-sys-channel", genesisBlockSysRaft)
 
 		manager := NewRegistrar(config, lf, mockCrypto(), &disabled.Provider{}, cryptoProvider, dialer)
+		require.True(t, manager.Loading())
 		manager.Initialize(consenters)
+		require.False(t, manager.Loading())
 
 		chainSupport := manager.GetChain("Fake")
 		require.Nilf(t, chainSupport, "Should not have found a chain that was not created")
@@ -357,7 +363,7 @@ func TestRegistrar_Initialize(t *testing.T) {
 		info, err := manager.ChannelInfo("my-sys-channel")
 		require.NoError(t, err)
 		require.Equal(t,
-			types.ChannelInfo{Name: "my-sys-channel", URL: "", ConsensusRelation: "consenter", Status: "active", Height: 1},
+			types.ChannelInfo{Name: "my-sys-channel", URL: "", ConsensusRelation: "consenter", Status: "active", Height: 1, ConfigSequence: uint64Ptr(0)},
 			info,
 		)
 	})
@@ -396,7 +402,7 @@ func TestRegistrar_Initialize(t *testing.T) {
 		info, err := manager.ChannelInfo("my-raft-channel")
 		require.NoError(t, err)
 		require.Equal(t,
-			types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "active", Height: 1},
+			types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "active", Height: 1, ConfigSequence: uint64Ptr(0)},
 			info,
 		)
 	})
@@ -432,7 +438,7 @@ func TestRegistrar_Initialize(t *testing.T) {
 		info, err := manager.ChannelInfo("my-raft-channel")
 		require.NoError(t, err)
 		require.Equal(t,
-			types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "follower", Status: "active", Height: 1},
+			types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "follower", Status: "active", Height: 1, ConfigSequence: uint64Ptr(0)},
 			info,
 		)
 
@@ -479,7 +485,7 @@ func TestRegistrar_Initialize(t *testing.T) {
 		info, err := manager.ChannelInfo("my-raft-channel")
 		require.NoError(t, err)
 		require.Equal(t,
-			types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "follower", Status: "onboarding", Height: 1},
+			types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "follower", Status: "onboarding", Height: 1, JoinedFromGenesis: boolPtr(false), ConfigSequence: uint64Ptr(0), TargetHeight: uint64Ptr(11)},
 			info,
 		)
 
@@ -636,14 +642,14 @@ func TestCreateChain(t *testing.T) {
 		info, err := manager.ChannelInfo("testchannelid")
 		require.NoError(t, err)
 		require.Equal(t,
-			types.ChannelInfo{Name: "testchannelid", URL: "", ConsensusRelation: types.ConsensusRelationConsenter, Status: types.StatusActive, Height: 1},
+			types.ChannelInfo{Name: "testchannelid", URL: "", ConsensusRelation: types.ConsensusRelationConsenter, Status: types.StatusActive, Height: 1, ConfigSequence: uint64Ptr(0)},
 			info,
 		)
 
 		info, err = manager.ChannelInfo("mychannel")
 		require.NoError(t, err)
 		require.Equal(t,
-			types.ChannelInfo{Name: "mychannel", URL: "", ConsensusRelation: types.ConsensusRelationConsenter, Status: types.StatusActive, Height: 1},
+			types.ChannelInfo{Name: "mychannel", URL: "", ConsensusRelation: types.ConsensusRelationConsenter, Status: types.StatusActive, Height: 1, ConfigSequence: uint64Ptr(0)},
 			info,
 		)
 
@@ -1106,14 +1112,14 @@ func TestRegistrar_JoinChannel(t *testing.T) {
 			require.Nil(t, registrar.GetChain("my-raft-channel"))
 			info, err := registrar.JoinChannel("my-raft-channel", genesisBlockAppRaft, true)
 			require.NoError(t, err)
-			require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "active", Height: 0x1}, info)
+			require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "active", Height: 0x1, JoinedFromGenesis: boolPtr(true), ConfigSequence: uint64Ptr(0)}, info)
 			// After creating the channel, it exists
 			require.NotNil(t, registrar.GetChain("my-raft-channel"))
 
 			// ChannelInfo() and ChannelList() are working fine
 			info, err = registrar.ChannelInfo("my-raft-channel")
 			require.NoError(t, err)
-			require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "active", Height: 0x1}, info)
+			require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "active", Height: 0x1, JoinedFromGenesis: boolPtr(true), ConfigSequence: uint64Ptr(0)}, info)
 			channelList := registrar.ChannelList()
 			require.Equal(t, 1, len(channelList.Channels))
 			require.Equal(t, "my-raft-channel", channelList.Channels[0].Name)
@@ -1125,6 +1131,254 @@ func TestRegistrar_JoinChannel(t *testing.T) {
 		})
 	})
 
+	t.Run("Validate join", func(t *testing.T) {
+		// a validated join leaves no trace of the channel
+		requireNotJoined := func(t *testing.T, registrar *Registrar) {
+			require.Nil(t, registrar.GetChain("my-raft-channel"))
+			require.Nil(t, registrar.GetFollower("my-raft-channel"))
+			require.Empty(t, ledgerFactory.ChannelIDs())
+			_, err := os.Stat(filepath.Join(tmpdir, "pendingops", "join", "my-raft-channel.join"))
+			require.True(t, os.IsNotExist(err))
+		}
+
+		t.Run("as member without on-boarding", func(t *testing.T) {
+			setup(t)
+			defer cleanup()
+
+			consenter.IsChannelMemberReturns(true, nil)
+			registrar := NewRegistrar(config, ledgerFactory, mockCrypto(), &disabled.Provider{}, cryptoProvider, nil)
+			registrar.Initialize(mockConsenters)
+
+			info, err := registrar.ValidateJoin("my-raft-channel", genesisBlockAppRaft, true)
+			require.NoError(t, err)
+			require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "active", Height: 0x1, JoinedFromGenesis: boolPtr(true), ConfigSequence: uint64Ptr(0)}, info)
+			requireNotJoined(t, registrar)
+
+			// the join then provides the same information
+			joinedInfo, err := registrar.JoinChannel("my-raft-channel", genesisBlockAppRaft, true)
+			require.NoError(t, err)
+			require.Equal(t, joinedInfo, info)
+
+			_, err = registrar.ValidateJoin("my-raft-channel", genesisBlockAppRaft, true)
+			require.Equal(t, types.ErrChannelAlreadyExists, err)
+		})
+
+		t.Run("as follower with on-boarding", func(t *testing.T) {
+			setup(t)
+			defer cleanup()
+
+			genesisBlockAppRaft.Header.Number = 10
+			consenter.IsChannelMemberReturns(false, nil)
+			registrar := NewRegistrar(config, ledgerFactory, mockCrypto(), &disabled.Provider{}, cryptoProvider, dialer)
+			registrar.Initialize(mockConsenters)
+
+			info, err := registrar.ValidateJoin("my-raft-channel", genesisBlockAppRaft, true)
+			require.NoError(t, err)
+			require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "follower", Status: "onboarding", Height: 0x0, JoinedFromGenesis: boolPtr(false), ConfigSequence: uint64Ptr(0), TargetHeight: uint64Ptr(11)}, info)
+			requireNotJoined(t, registrar)
+
+			joinedInfo, err := registrar.JoinChannel("my-raft-channel", genesisBlockAppRaft, true)
+			require.NoError(t, err)
+			require.Equal(t, joinedInfo, info)
+			registrar.GetFollower("my-raft-channel").Halt()
+		})
+
+		t.Run("failure - consenter channel membership error", func(t *testing.T) {
+			setup(t)
+			defer cleanup()
+
+			consenter.IsChannelMemberReturns(false, errors.New("apple"))
+			registrar := NewRegistrar(config, ledgerFactory, mockCrypto(), &disabled.Provider{}, cryptoProvider, nil)
+			registrar.Initialize(mockConsenters)
+
+			_, err := registrar.ValidateJoin("my-raft-channel", genesisBlockAppRaft, true)
+			require.EqualError(t, err, "failed to determine cluster membership from join-block: apple")
+			requireNotJoined(t, registrar)
+		})
+
+		t.Run("failure - bad config block", func(t *testing.T) {
+			setup(t)
+			defer cleanup()
+
+			registrar := NewRegistrar(config, ledgerFactory, mockCrypto(), &disabled.Provider{}, cryptoProvider, nil)
+			registrar.Initialize(mockConsenters)
+
+			_, err := registrar.ValidateJoin("my-raft-channel", &cb.Block{Header: &cb.BlockHeader{}}, true)
+			require.EqualError(t, err, "failed extracting config envelope from block: block data is nil")
+			requireNotJoined(t, registrar)
+		})
+	})
+
+	t.Run("Revision increments after a join", func(t *testing.T) {
+		setup(t)
+		defer cleanup()
+
+		consenter.IsChannelMemberReturns(true, nil)
+		registrar := NewRegistrar(config, ledgerFactory, mockCrypto(), &disabled.Provider{}, cryptoProvider, nil)
+		registrar.Initialize(mockConsenters)
+
+		revision := registrar.ChannelList().Revision
+		require.Equal(t, revision, registrar.ChannelList().Revision)
+
+		_, err := registrar.JoinChannel("my-raft-channel", genesisBlockAppRaft, true)
+		require.NoError(t, err)
+		require.Equal(t, revision+1, registrar.ChannelList().Revision)
+
+		// Reading the channels, or a rejected join, leaves the revision unchanged
+		_, err = registrar.ChannelInfo("my-raft-channel")
+		require.NoError(t, err)
+		_, err = registrar.JoinChannel("my-raft-channel", genesisBlockAppRaft, true)
+		require.EqualError(t, err, "channel already exists")
+		require.Equal(t, revision+1, registrar.ChannelList().Revision)
+	})
+
+	t.Run("Joined from genesis is recovered after restart", func(t *testing.T) {
+		setup(t)
+		defer cleanup()
+
+		consenter.IsChannelMemberReturns(true, nil)
+		registrar := NewRegistrar(config, ledgerFactory, mockCrypto(), &disabled.Provider{}, cryptoProvider, nil)
+		registrar.Initialize(mockConsenters)
+
+		info, err := registrar.JoinChannel("my-raft-channel", genesisBlockAppRaft, true)
+		require.NoError(t, err)
+		require.Equal(t, boolPtr(true), info.JoinedFromGenesis)
+		registrar.GetChain("my-raft-channel").Halt()
+
+		// the join block is no longer around after a restart, but the joined block is
+		_, err = os.Stat(filepath.Join(tmpdir, "pendingops", "join", "my-raft-channel.join"))
+		require.True(t, os.IsNotExist(err))
+		registrar = NewRegistrar(config, ledgerFactory, mockCrypto(), &disabled.Provider{}, cryptoProvider, nil)
+		registrar.Initialize(mockConsenters)
+		defer registrar.GetChain("my-raft-channel").Halt()
+
+		info, err = registrar.ChannelInfo("my-raft-channel")
+		require.NoError(t, err)
+		require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "active", Height: 0x1, JoinedFromGenesis: boolPtr(true), ConfigSequence: uint64Ptr(0)}, info)
+	})
+
+	t.Run("Joined from genesis is unknown after restart without the joined block", func(t *testing.T) {
+		setup(t)
+		defer cleanup()
+
+		consenter.IsChannelMemberReturns(true, nil)
+		registrar := NewRegistrar(config, ledgerFactory, mockCrypto(), &disabled.Provider{}, cryptoProvider, nil)
+		registrar.Initialize(mockConsenters)
+
+		_, err := registrar.JoinChannel("my-raft-channel", genesisBlockAppRaft, true)
+		require.NoError(t, err)
+		registrar.GetChain("my-raft-channel").Halt()
+
+		// e.g. a channel joined before joined blocks were kept
+		require.NoError(t, os.Remove(filepath.Join(tmpdir, "joinblocks", "joined", "my-raft-channel.joined")))
+		registrar = NewRegistrar(config, ledgerFactory, mockCrypto(), &disabled.Provider{}, cryptoProvider, nil)
+		registrar.Initialize(mockConsenters)
+		defer registrar.GetChain("my-raft-channel").Halt()
+
+		info, err := registrar.ChannelInfo("my-raft-channel")
+		require.NoError(t, err)
+		require.Nil(t, info.JoinedFromGenesis)
+	})
+
+	t.Run("Join block is kept while the channel exists", func(t *testing.T) {
+		setup(t)
+		defer cleanup()
+
+		consenter.IsChannelMemberReturns(true, nil)
+		registrar := NewRegistrar(config, ledgerFactory, mockCrypto(), &disabled.Provider{}, cryptoProvider, nil)
+		registrar.Initialize(mockConsenters)
+
+		_, err := registrar.JoinBlock("my-raft-channel")
+		require.Equal(t, types.ErrChannelNotExist, err)
+
+		_, err = registrar.JoinChannel("my-raft-channel", genesisBlockAppRaft, true)
+		require.NoError(t, err)
+		registrar.GetChain("my-raft-channel").Halt()
+
+		// the pending join-block is removed once the orderer is a member, the joined block is not
+		joinBlockPath := filepath.Join(tmpdir, "pendingops", "join", "my-raft-channel.join")
+		_, err = os.Stat(joinBlockPath)
+		require.True(t, os.IsNotExist(err))
+
+		blockBytes, err := registrar.JoinBlock("my-raft-channel")
+		require.NoError(t, err)
+		joinBlock, err := protoutil.UnmarshalBlock(blockBytes)
+		require.NoError(t, err)
+		require.True(t, proto.Equal(genesisBlockAppRaft, joinBlock))
+
+		// and it is still around after a restart
+		registrar = NewRegistrar(config, ledgerFactory, mockCrypto(), &disabled.Provider{}, cryptoProvider, nil)
+		registrar.Initialize(mockConsenters)
+		defer registrar.GetChain("my-raft-channel").Halt()
+
+		restartBlockBytes, err := registrar.JoinBlock("my-raft-channel")
+		require.NoError(t, err)
+		require.Equal(t, blockBytes, restartBlockBytes)
+	})
+
+	t.Run("Join block does not exist for a channel not joined through the API", func(t *testing.T) {
+		setup(t)
+		defer cleanup()
+
+		registrar := NewRegistrar(config, ledgerFactory, mockCrypto(), &disabled.Provider{}, cryptoProvider, nil)
+		registrar.Initialize(mockConsenters)
+
+		ledger, err := ledgerFactory.GetOrCreate("my-raft-channel")
+		require.NoError(t, err)
+		ledger.Append(genesisBlockAppRaft)
+		registrar.CreateChain("my-raft-channel")
+		defer registrar.GetChain("my-raft-channel").Halt()
+
+		_, err = registrar.JoinBlock("my-raft-channel")
+		require.Equal(t, types.ErrJoinBlockNotExist, err)
+	})
+
+	t.Run("Ledger size grows as blocks are committed", func(t *testing.T) {
+		setup(t)
+		defer cleanup()
+
+		consenter.IsChannelMemberReturns(true, nil)
+		registrar := NewRegistrar(config, ledgerFactory, mockCrypto(), &disabled.Provider{}, cryptoProvider, nil)
+		registrar.Initialize(mockConsenters)
+
+		_, err := registrar.LedgerBytes("my-raft-channel")
+		require.Equal(t, types.ErrChannelNotExist, err)
+
+		_, err = registrar.JoinChannel("my-raft-channel", genesisBlockAppRaft, true)
+		require.NoError(t, err)
+		defer registrar.GetChain("my-raft-channel").Halt()
+
+		genesisSize, err := registrar.LedgerBytes("my-raft-channel")
+		require.NoError(t, err)
+		require.NotZero(t, genesisSize)
+
+		block := protoutil.NewBlock(1, protoutil.BlockHeaderHash(genesisBlockAppRaft.Header))
+		block.Data.Data = [][]byte{[]byte("some data")}
+		block.Header.DataHash = protoutil.BlockDataHash(block.Data)
+		require.NoError(t, registrar.GetChain("my-raft-channel").Append(block))
+
+		size, err := registrar.LedgerBytes("my-raft-channel")
+		require.NoError(t, err)
+		require.Greater(t, size, genesisSize)
+	})
+
+	t.Run("Free disk space of the ledger location", func(t *testing.T) {
+		setup(t)
+		defer cleanup()
+
+		registrar := NewRegistrar(config, ledgerFactory, mockCrypto(), &disabled.Provider{}, cryptoProvider, nil)
+		registrar.Initialize(mockConsenters)
+
+		free, err := registrar.FreeDiskSpace()
+		require.NoError(t, err)
+		require.NotZero(t, free)
+
+		registrar.config.FileLedger.Location = filepath.Join(tmpdir, "does-not-exist")
+		_, err = registrar.FreeDiskSpace()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed checking the free disk space of")
+	})
+
 	t.Run("Join app channel as member with on-boarding", func(t *testing.T) {
 		setup(t)
 		defer cleanup()
@@ -1140,14 +1394,14 @@ func TestRegistrar_JoinChannel(t *testing.T) {
 
 		info, err := registrar.JoinChannel("my-raft-channel", genesisBlockAppRaft, true)
 		require.NoError(t, err)
-		require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "onboarding", Height: 0x0}, info)
+		require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "onboarding", Height: 0x0, JoinedFromGenesis: boolPtr(false), ConfigSequence: uint64Ptr(0), TargetHeight: uint64Ptr(11)}, info)
 		// After creating the follower.Chain, it not in the chains map.
 		require.Nil(t, registrar.GetChain("my-raft-channel"))
 
 		// ChannelInfo() and ChannelList() are working fine
 		info, err = registrar.ChannelInfo("my-raft-channel")
 		require.NoError(t, err)
-		require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "onboarding", Height: 0x0}, info)
+		require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "onboarding", Height: 0x0, JoinedFromGenesis: boolPtr(false), ConfigSequence: uint64Ptr(0), TargetHeight: uint64Ptr(11)}, info)
 		channelList := registrar.ChannelList()
 		require.Equal(t, 1, len(channelList.Channels))
 		require.Equal(t, "my-raft-channel", channelList.Channels[0].Name)
@@ -1175,13 +1429,13 @@ func TestRegistrar_JoinChannel(t *testing.T) {
 
 		info, err := registrar.JoinChannel("my-raft-channel", genesisBlockAppRaft, true)
 		require.NoError(t, err)
-		require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "follower", Status: "onboarding", Height: 0x0}, info)
+		require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "follower", Status: "onboarding", Height: 0x0, JoinedFromGenesis: boolPtr(false), ConfigSequence: uint64Ptr(0), TargetHeight: uint64Ptr(11)}, info)
 		// After creating the follower.Chain, it not in the chains map.
 		require.Nil(t, registrar.GetChain("my-raft-channel"))
 		// ChannelInfo() and ChannelList() are working fine
 		info, err = registrar.ChannelInfo("my-raft-channel")
 		require.NoError(t, err)
-		require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "follower", Status: "onboarding", Height: 0x0}, info)
+		require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "follower", Status: "onboarding", Height: 0x0, JoinedFromGenesis: boolPtr(false), ConfigSequence: uint64Ptr(0), TargetHeight: uint64Ptr(11)}, info)
 		channelList := registrar.ChannelList()
 		require.Equal(t, 1, len(channelList.Channels))
 		require.Equal(t, "my-raft-channel", channelList.Channels[0].Name)
@@ -1212,7 +1466,7 @@ func TestRegistrar_JoinChannel(t *testing.T) {
 		genesisBlockAppRaft.Header.Number = 1
 		info, err := registrar.JoinChannel("my-raft-channel", genesisBlockAppRaft, true)
 		require.NoError(t, err)
-		require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "follower", Status: "onboarding", Height: 0x0}, info)
+		require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "follower", Status: "onboarding", Height: 0x0, JoinedFromGenesis: boolPtr(false), ConfigSequence: uint64Ptr(0), TargetHeight: uint64Ptr(2)}, info)
 
 		// After creating the follower.Chain, it not in the chains map, it is in the followers map.
 		require.Nil(t, registrar.GetChain("my-raft-channel"))
@@ -1231,14 +1485,23 @@ func TestRegistrar_JoinChannel(t *testing.T) {
 		newLedger(ledgerFactory, "my-raft-channel", genesisBlockAppRaft)
 
 		// Now Switch => a chain is created and the follower removed
+		relationChanges := make(chan types.ChannelInfo, 1)
+		registrar.ObserveRelationChanges(func(info types.ChannelInfo) { relationChanges <- info })
 		require.NotPanics(t, func() { registrar.SwitchFollowerToChain("my-raft-channel") })
+		// the observer is notified of the new relation
+		select {
+		case info := <-relationChanges:
+			require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "active", Height: 0x1, JoinedFromGenesis: boolPtr(false), ConfigSequence: uint64Ptr(0)}, info)
+		case <-time.After(time.Minute):
+			t.Fatal("relation change was not observed")
+		}
 		// Now the chain is in the chains map, the follower is gone
 		require.NotNil(t, registrar.GetChain("my-raft-channel"))
 		require.Nil(t, registrar.GetFollower("my-raft-channel"))
 		// ChannelInfo() and ChannelList() are still working fine
 		info, err = registrar.ChannelInfo("my-raft-channel")
 		require.NoError(t, err)
-		require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "active", Height: 0x1}, info)
+		require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "active", Height: 0x1, JoinedFromGenesis: boolPtr(false), ConfigSequence: uint64Ptr(0)}, info)
 		channelList := registrar.ChannelList()
 		require.Equal(t, 1, len(channelList.Channels))
 		require.Equal(t, "my-raft-channel", channelList.Channels[0].Name)
@@ -1266,7 +1529,7 @@ func TestRegistrar_JoinChannel(t *testing.T) {
 
 		info, err := registrar.JoinChannel("my-raft-channel", genesisBlockAppRaft, true)
 		require.NoError(t, err)
-		require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "active", Height: 0x1}, info)
+		require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "active", Height: 0x1, JoinedFromGenesis: boolPtr(true), ConfigSequence: uint64Ptr(0)}, info)
 		// After creating the chain, it exists
 		cs := registrar.GetChain("my-raft-channel")
 		require.NotNil(t, cs)
@@ -1274,7 +1537,7 @@ func TestRegistrar_JoinChannel(t *testing.T) {
 		// ChannelInfo() and ChannelList() are working fine
 		info, err = registrar.ChannelInfo("my-raft-channel")
 		require.NoError(t, err)
-		require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "active", Height: 0x1}, info)
+		require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "active", Height: 0x1, JoinedFromGenesis: boolPtr(true), ConfigSequence: uint64Ptr(0)}, info)
 		channelList := registrar.ChannelList()
 		require.Equal(t, 1, len(channelList.Channels))
 		require.Equal(t, "my-raft-channel", channelList.Channels[0].Name)
@@ -1290,7 +1553,16 @@ func TestRegistrar_JoinChannel(t *testing.T) {
 
 		// Now halt and switch, as if the orderer was evicted
 		cs.Halt()
+		relationChanges := make(chan types.ChannelInfo, 1)
+		registrar.ObserveRelationChanges(func(info types.ChannelInfo) { relationChanges <- info })
 		require.NotPanics(t, func() { registrar.SwitchChainToFollower("my-raft-channel") })
+		// the observer is notified of the new relation
+		select {
+		case info := <-relationChanges:
+			require.Equal(t, types.ConsensusRelationFollower, info.ConsensusRelation)
+		case <-time.After(time.Minute):
+			t.Fatal("relation change was not observed")
+		}
 		// Now the follower is in the followers map, the chain is gone
 		fChain := registrar.GetFollower("my-raft-channel")
 		require.NotNil(t, fChain)
@@ -1298,7 +1570,7 @@ func TestRegistrar_JoinChannel(t *testing.T) {
 		// ChannelInfo() and ChannelList() are still working fine
 		info, err = registrar.ChannelInfo("my-raft-channel")
 		require.NoError(t, err)
-		require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "follower", Status: "active", Height: 0x2}, info)
+		require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "follower", Status: "active", Height: 0x2, JoinedFromGenesis: boolPtr(true), ConfigSequence: uint64Ptr(0)}, info)
 		channelList = registrar.ChannelList()
 		require.Equal(t, 1, len(channelList.Channels))
 		require.Equal(t, "my-raft-channel", channelList.Channels[0].Name)
@@ -1321,7 +1593,7 @@ func TestRegistrar_JoinChannel(t *testing.T) {
 
 		info, err := registrar.JoinChannel("sys-raft-channel", genesisBlockSysRaft, false)
 		require.NoError(t, err)
-		require.Equal(t, types.ChannelInfo{Name: "sys-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "inactive", Height: 0x1}, info)
+		require.Equal(t, types.ChannelInfo{Name: "sys-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "inactive", Height: 0x1, JoinedFromGenesis: boolPtr(true), RequiresRestart: true, ConfigSequence: uint64Ptr(0)}, info)
 		// After creating the chain, it exists
 		cs := registrar.GetChain("sys-raft-channel")
 		require.NotNil(t, cs)
@@ -1334,7 +1606,7 @@ func TestRegistrar_JoinChannel(t *testing.T) {
 		// ChannelInfo() and ChannelList() are working fine
 		info, err = registrar.ChannelInfo("sys-raft-channel")
 		require.NoError(t, err)
-		require.Equal(t, types.ChannelInfo{Name: "sys-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "inactive", Height: 0x1}, info)
+		require.Equal(t, types.ChannelInfo{Name: "sys-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "inactive", Height: 0x1, JoinedFromGenesis: boolPtr(true), ConfigSequence: uint64Ptr(0)}, info)
 		channelList := registrar.ChannelList()
 		require.Equal(t, 0, len(channelList.Channels))
 		require.NotNil(t, channelList.SystemChannel)
@@ -1358,7 +1630,7 @@ func TestRegistrar_JoinChannel(t *testing.T) {
 		genesisBlockSysRaft.Header.Number = 7
 		info, err := registrar.JoinChannel("sys-raft-channel", genesisBlockSysRaft, false)
 		require.NoError(t, err)
-		require.Equal(t, types.ChannelInfo{Name: "sys-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "inactive", Height: 0x0}, info)
+		require.Equal(t, types.ChannelInfo{Name: "sys-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "inactive", Height: 0x0, JoinedFromGenesis: boolPtr(false), RequiresRestart: true, ConfigSequence: uint64Ptr(0)}, info)
 		// After creating the chain, it exists
 		cs := registrar.GetChain("sys-raft-channel")
 		require.NotNil(t, cs)
@@ -1371,7 +1643,7 @@ func TestRegistrar_JoinChannel(t *testing.T) {
 		// ChannelInfo() and ChannelList() are working fine
 		info, err = registrar.ChannelInfo("sys-raft-channel")
 		require.NoError(t, err)
-		require.Equal(t, types.ChannelInfo{Name: "sys-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "inactive", Height: 0x0}, info)
+		require.Equal(t, types.ChannelInfo{Name: "sys-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "inactive", Height: 0x0, JoinedFromGenesis: boolPtr(false), ConfigSequence: uint64Ptr(0)}, info)
 		channelList := registrar.ChannelList()
 		require.Equal(t, 0, len(channelList.Channels))
 		require.NotNil(t, channelList.SystemChannel)
@@ -1531,15 +1803,20 @@ func TestRegistrar_RemoveChannel(t *testing.T) {
 			require.NotContains(t, ledgerFactory.ChannelIDs(), "my-raft-channel")
 			info, err := registrar.JoinChannel("my-raft-channel", genesisBlockAppRaft, true)
 			require.NoError(t, err)
-			require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "active", Height: 1}, info)
+			require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "active", Height: 1, JoinedFromGenesis: boolPtr(true), ConfigSequence: uint64Ptr(0)}, info)
 			require.NotNil(t, registrar.GetChain("my-raft-channel"))
 			require.Contains(t, ledgerFactory.ChannelIDs(), "my-raft-channel")
 
+			_, err = os.Stat(filepath.Join(tmpdir, "joinblocks", "joined", "my-raft-channel.joined"))
+			require.NoError(t, err)
+
 			err = registrar.RemoveChannel("my-raft-channel")
 			require.NoError(t, err)
 
 			// After removing the channel, it no longer exists in the registrar or the ledger
 			require.Nil(t, registrar.GetChain("my-raft-channel"))
+			_, err = os.Stat(filepath.Join(tmpdir, "joinblocks", "joined", "my-raft-channel.joined"))
+			require.True(t, os.IsNotExist(err))
 			require.Eventually(t, func() bool { return len(ledgerFactory.ChannelIDs()) == 0 }, time.Minute, time.Second)
 			require.NotContains(t, ledgerFactory.ChannelIDs(), "my-raft-channel")
 		})
@@ -1556,7 +1833,7 @@ func TestRegistrar_RemoveChannel(t *testing.T) {
 			require.NotContains(t, ledgerFactory.ChannelIDs(), "my-follower-raft-channel")
 			info, err := registrar.JoinChannel("my-follower-raft-channel", genesisBlockAppRaftFollower, true)
 			require.NoError(t, err)
-			require.Equal(t, types.ChannelInfo{Name: "my-follower-raft-channel", URL: "", ConsensusRelation: "follower", Status: "onboarding", Height: 0}, info)
+			require.Equal(t, types.ChannelInfo{Name: "my-follower-raft-channel", URL: "", ConsensusRelation: "follower", Status: "onboarding", Height: 0, JoinedFromGenesis: boolPtr(true), ConfigSequence: uint64Ptr(0), TargetHeight: uint64Ptr(1)}, info)
 			require.NotNil(t, registrar.GetFollower("my-follower-raft-channel"))
 			require.Contains(t, ledgerFactory.ChannelIDs(), "my-follower-raft-channel")
 
@@ -1572,6 +1849,29 @@ func TestRegistrar_RemoveChannel(t *testing.T) {
 			require.Equal(t, err, types.ErrChannelNotExist)
 			require.Equal(t, channelInfo, types.ChannelInfo{})
 		})
+
+		t.Run("follower whose joined block cannot be removed", func(t *testing.T) {
+			consenter.IsChannelMemberReturns(false, nil)
+			registrar := NewRegistrar(config, ledgerFactory, mockCrypto(), &disabled.Provider{}, cryptoProvider, dialer)
+			registrar.Initialize(mockConsenters)
+
+			genesisBlockAppRaftFollower := appBootstrapper.GenesisBlockForChannel("my-follower-raft-channel")
+			_, err := registrar.JoinChannel("my-follower-raft-channel", genesisBlockAppRaftFollower, true)
+			require.NoError(t, err)
+			require.NotNil(t, registrar.GetFollower("my-follower-raft-channel"))
+
+			// the joined block repo cannot be synced once its directory is gone
+			require.NoError(t, os.RemoveAll(filepath.Join(tmpdir, "joinblocks", "joined")))
+
+			err = registrar.RemoveChannel("my-follower-raft-channel")
+			require.NoError(t, err)
+
+			// the follower is removed nevertheless
+			require.Nil(t, registrar.GetFollower("my-follower-raft-channel"))
+			require.Eventually(t, func() bool { return len(ledgerFactory.ChannelIDs()) == 0 }, time.Minute, time.Second)
+			_, err = registrar.ChannelInfo("my-follower-raft-channel")
+			require.Equal(t, types.ErrChannelNotExist, err)
+		})
 	})
 
 	t.Run("remove system channel", func(t *testing.T) {
@@ -1686,7 +1986,7 @@ func TestRegistrar_RemoveChannel(t *testing.T) {
 		require.NotContains(t, ledgerFactory.ChannelIDs(), "my-raft-channel")
 		info, err := registrar.JoinChannel("my-raft-channel", genesisBlockAppRaft, true)
 		require.NoError(t, err)
-		require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "active", Height: 1}, info)
+		require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "active", Height: 1, JoinedFromGenesis: boolPtr(true), ConfigSequence: uint64Ptr(0)}, info)
 		require.NotNil(t, registrar.GetChain("my-raft-channel"))
 		require.Contains(t, ledgerFactory.ChannelIDs(), "my-raft-channel")
 
@@ -1711,7 +2011,7 @@ func TestRegistrar_RemoveChannel(t *testing.T) {
 		require.NotContains(t, ledgerFactory.ChannelIDs(), "my-raft-channel")
 		info, err := registrar.JoinChannel("my-raft-channel", genesisBlockAppRaft, true)
 		require.NoError(t, err)
-		require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "active", Height: 1}, info)
+		require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "active", Height: 1, JoinedFromGenesis: boolPtr(true), ConfigSequence: uint64Ptr(0)}, info)
 		require.NotNil(t, registrar.GetChain("my-raft-channel"))
 		require.Contains(t, ledgerFactory.ChannelIDs(), "my-raft-channel")
 
@@ -1736,7 +2036,7 @@ func TestRegistrar_RemoveChannel(t *testing.T) {
 		require.NotContains(t, ledgerFactory.ChannelIDs(), "my-raft-channel")
 		info, err := registrar.JoinChannel("my-raft-channel", genesisBlockAppRaft, true)
 		require.NoError(t, err)
-		require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "active", Height: 1}, info)
+		require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "consenter", Status: "active", Height: 1, JoinedFromGenesis: boolPtr(true), ConfigSequence: uint64Ptr(0)}, info)
 		require.NotNil(t, registrar.GetChain("my-raft-channel"))
 		require.Contains(t, ledgerFactory.ChannelIDs(), "my-raft-channel")
 
@@ -1853,3 +2153,358 @@ func TestRegistrar_ConfigBlockOrPanic(t *testing.T) {
 		assert.Equal(t, genesisBlockSys.Data, cBlock.Data)
 	})
 }
+
+func TestRegistrar_UpdateChannelConfig(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "update-channel-config")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+
+	ledgerFactory := newFactory(tmpdir)
+	defer ledgerFactory.Close()
+
+	config := localconfig.TopLevel{
+		ChannelParticipation: localconfig.ChannelParticipation{Enabled: true},
+		General:              localconfig.General{BootstrapMethod: "none"},
+		FileLedger:           localconfig.FileLedger{Location: tmpdir},
+	}
+	registrar := NewRegistrar(config, ledgerFactory, mockCrypto(), &disabled.Provider{}, cryptoProvider, nil)
+	registrar.Initialize(map[string]consensus.Consenter{})
+
+	timeout, timeout2 := "3s", "4s"
+	t.Run("when channel id does not exist", func(t *testing.T) {
+		_, err := registrar.ComputeConfigUpdate("some-channel", types.ChannelConfigPatch{BatchTimeout: &timeout})
+		require.EqualError(t, err, "channel does not exist")
+		err = registrar.UpdateChannelConfig("some-channel", &cb.ConfigUpdateEnvelope{})
+		require.EqualError(t, err, "channel does not exist")
+	})
+
+	t.Run("when channel is pending removal", func(t *testing.T) {
+		registrar.pendingRemoval["removed-channel"] = consensus.StaticStatusReporter{ConsensusRelation: types.ConsensusRelationConsenter, Status: types.StatusInactive}
+		defer delete(registrar.pendingRemoval, "removed-channel")
+
+		_, err := registrar.ComputeConfigUpdate("removed-channel", types.ChannelConfigPatch{BatchTimeout: &timeout})
+		require.EqualError(t, err, "channel pending removal")
+		err = registrar.UpdateChannelConfig("removed-channel", &cb.ConfigUpdateEnvelope{})
+		require.EqualError(t, err, "channel pending removal")
+	})
+
+	t.Run("with a secure profile", func(t *testing.T) {
+		tmpdir, err := ioutil.TempDir("", "registrar_test-")
+		require.NoError(t, err)
+		defer os.RemoveAll(tmpdir)
+
+		// the orderer group of SampleSingleMSPSolo is modified by the admins of SampleOrg only
+		confSys := genesisconfig.Load(genesisconfig.SampleSingleMSPSoloProfile, configtest.GetDevConfigDir())
+		genesisBlockSys := encoder.New(confSys).GenesisBlock()
+		lf, _ := newLedgerAndFactory(tmpdir, "testchannelid", genesisBlockSys)
+
+		// the sample MSP identity is an admin of SampleOrg, and is also used by the orderer to sign the envelopes that
+		// carry the config updates, so that its signature is known not to authorize them
+		admin, err := signer.NewSigner(signer.Config{
+			MSPID:        "SampleOrg",
+			IdentityPath: filepath.Join(configtest.GetDevMspDir(), "signcerts", "peer.pem"),
+			KeyPath:      filepath.Join(configtest.GetDevMspDir(), "keystore", "key.pem"),
+		})
+		require.NoError(t, err)
+
+		consenter := &mocks.Consenter{}
+		consenter.HandleChainCalls(handleChain)
+		manager := NewRegistrar(localconfig.TopLevel{}, lf, admin, &disabled.Provider{}, cryptoProvider, nil)
+		manager.Initialize(map[string]consensus.Consenter{confSys.Orderer.OrdererType: consenter})
+		defer manager.GetChain("testchannelid").Halt()
+
+		sequence := func() uint64 {
+			info, err := manager.ChannelInfo("testchannelid")
+			require.NoError(t, err)
+			require.NotNil(t, info.ConfigSequence)
+			return *info.ConfigSequence
+		}
+		initial := sequence()
+
+		configUpdate, err := manager.ComputeConfigUpdate("testchannelid", types.ChannelConfigPatch{BatchTimeout: &timeout})
+		require.NoError(t, err)
+		require.Equal(t, "testchannelid", configUpdate.ChannelId)
+
+		t.Run("an unsigned config update is rejected", func(t *testing.T) {
+			err := manager.UpdateChannelConfig("testchannelid", signConfigUpdate(t, configUpdate))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "config update rejected")
+			require.Contains(t, err.Error(), "policy")
+			require.Equal(t, initial, sequence())
+		})
+
+		t.Run("a config update for another channel is rejected", func(t *testing.T) {
+			otherUpdate := proto.Clone(configUpdate).(*cb.ConfigUpdate)
+			otherUpdate.ChannelId = "other-channel"
+			err := manager.UpdateChannelConfig("testchannelid", signConfigUpdate(t, otherUpdate, admin))
+			require.EqualError(t, err, "config update is for channel other-channel, not testchannelid")
+		})
+
+		t.Run("a config update of other values is rejected", func(t *testing.T) {
+			original := manager.GetChain("testchannelid").ConfigProto()
+			updated := proto.Clone(original).(*cb.Config)
+			updated.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.ChannelRestrictionsKey] = &cb.ConfigValue{
+				Value:     protoutil.MarshalOrPanic(&ab.ChannelRestrictions{MaxCount: 10}),
+				ModPolicy: channelconfig.AdminsPolicyKey,
+			}
+			otherUpdate, err := update.Compute(original, updated)
+			require.NoError(t, err)
+			otherUpdate.ChannelId = "testchannelid"
+
+			err = manager.UpdateChannelConfig("testchannelid", signConfigUpdate(t, otherUpdate, admin))
+			require.EqualError(t, err, "config update rejected: only the orderer values BatchTimeout, BatchSize, or the consensus state, may be changed")
+			require.Equal(t, initial, sequence())
+		})
+
+		t.Run("a config update signed by an admin is applied", func(t *testing.T) {
+			err := manager.UpdateChannelConfig("testchannelid", signConfigUpdate(t, configUpdate, admin))
+			require.NoError(t, err)
+
+			require.Eventually(t, func() bool { return sequence() == initial+1 }, 5*time.Second, 10*time.Millisecond)
+		})
+
+		maintenance := types.ConsensusStateMaintenance
+		stateUpdate, err := manager.ComputeConfigUpdate("testchannelid", types.ChannelConfigPatch{ConsensusState: &maintenance})
+		require.NoError(t, err)
+
+		t.Run("a config update of the consensus state together with other values is rejected", func(t *testing.T) {
+			original := manager.GetChain("testchannelid").ConfigProto()
+			updated, err := applyConfigPatch(original, types.ChannelConfigPatch{ConsensusState: &maintenance})
+			require.NoError(t, err)
+			updated, err = applyConfigPatch(updated, types.ChannelConfigPatch{BatchTimeout: &timeout2})
+			require.NoError(t, err)
+			combinedUpdate, err := update.Compute(original, updated)
+			require.NoError(t, err)
+			combinedUpdate.ChannelId = "testchannelid"
+
+			err = manager.UpdateChannelConfig("testchannelid", signConfigUpdate(t, combinedUpdate, admin))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "config update rejected")
+			require.EqualError(t, checkConfigChange(original, updated), "the consensus state cannot be changed together with other values")
+			require.Equal(t, initial+1, sequence())
+		})
+
+		t.Run("an unsigned config update of the consensus state is rejected", func(t *testing.T) {
+			err := manager.UpdateChannelConfig("testchannelid", signConfigUpdate(t, stateUpdate))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "config update rejected")
+			require.Equal(t, initial+1, sequence())
+		})
+
+		t.Run("a config update of the consensus state signed by an admin is applied", func(t *testing.T) {
+			err := manager.UpdateChannelConfig("testchannelid", signConfigUpdate(t, stateUpdate, admin))
+			require.NoError(t, err)
+
+			require.Eventually(t, func() bool { return sequence() == initial+2 }, 5*time.Second, 10*time.Millisecond)
+			ordererConfig, ok := manager.GetChain("testchannelid").OrdererConfig()
+			require.True(t, ok)
+			require.Equal(t, ab.ConsensusType_STATE_MAINTENANCE, ordererConfig.ConsensusState())
+		})
+	})
+}
+
+// signConfigUpdate returns a config update envelope carrying the signatures of the signers over the config update.
+func signConfigUpdate(t *testing.T, configUpdate *cb.ConfigUpdate, signers ...identity.SignerSerializer) *cb.ConfigUpdateEnvelope {
+	configUpdateEnv := &cb.ConfigUpdateEnvelope{ConfigUpdate: protoutil.MarshalOrPanic(configUpdate)}
+	for _, s := range signers {
+		sigHeader, err := protoutil.NewSignatureHeader(s)
+		require.NoError(t, err)
+		configSig := &cb.ConfigSignature{SignatureHeader: protoutil.MarshalOrPanic(sigHeader)}
+		configSig.Signature, err = s.Sign(util.ConcatenateBytes(configSig.SignatureHeader, configUpdateEnv.ConfigUpdate))
+		require.NoError(t, err)
+		configUpdateEnv.Signatures = append(configUpdateEnv.Signatures, configSig)
+	}
+	return configUpdateEnv
+}
+
+func TestApplyConfigPatch(t *testing.T) {
+	conf := genesisconfig.Load(genesisconfig.SampleInsecureSoloProfile, configtest.GetDevConfigDir())
+	channelGroup, err := encoder.NewChannelGroup(conf)
+	require.NoError(t, err)
+	original := &cb.Config{ChannelGroup: channelGroup}
+
+	batchSize := func(config *cb.Config) *ab.BatchSize {
+		bs := &ab.BatchSize{}
+		err := proto.Unmarshal(config.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.BatchSizeKey].Value, bs)
+		require.NoError(t, err)
+		return bs
+	}
+	batchTimeout := func(config *cb.Config) *ab.BatchTimeout {
+		bt := &ab.BatchTimeout{}
+		err := proto.Unmarshal(config.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.BatchTimeoutKey].Value, bt)
+		require.NoError(t, err)
+		return bt
+	}
+
+	t.Run("batch timeout", func(t *testing.T) {
+		timeout := "3s"
+		updated, err := applyConfigPatch(original, types.ChannelConfigPatch{BatchTimeout: &timeout})
+		require.NoError(t, err)
+		require.Equal(t, "3s", batchTimeout(updated).Timeout)
+		require.True(t, proto.Equal(batchSize(original), batchSize(updated)))
+		require.Equal(t, "2s", batchTimeout(original).Timeout, "original config must not be modified")
+	})
+
+	t.Run("batch size", func(t *testing.T) {
+		count := uint32(42)
+		updated, err := applyConfigPatch(original, types.ChannelConfigPatch{MaxMessageCount: &count})
+		require.NoError(t, err)
+		require.Equal(t, uint32(42), batchSize(updated).MaxMessageCount)
+		require.Equal(t, batchSize(original).AbsoluteMaxBytes, batchSize(updated).AbsoluteMaxBytes)
+		require.Equal(t, batchSize(original).PreferredMaxBytes, batchSize(updated).PreferredMaxBytes)
+		require.Equal(t, batchTimeout(original).Timeout, batchTimeout(updated).Timeout)
+	})
+
+	t.Run("consensus state", func(t *testing.T) {
+		consensusType := func(config *cb.Config) *ab.ConsensusType {
+			ct := &ab.ConsensusType{}
+			err := proto.Unmarshal(config.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.ConsensusTypeKey].Value, ct)
+			require.NoError(t, err)
+			return ct
+		}
+		maintenance, normal := types.ConsensusStateMaintenance, types.ConsensusStateNormal
+
+		inMaintenance, err := applyConfigPatch(original, types.ChannelConfigPatch{ConsensusState: &maintenance})
+		require.NoError(t, err)
+		require.Equal(t, ab.ConsensusType_STATE_MAINTENANCE, consensusType(inMaintenance).State)
+		require.Equal(t, consensusType(original).Type, consensusType(inMaintenance).Type)
+		require.Equal(t, ab.ConsensusType_STATE_NORMAL, consensusType(original).State, "original config must not be modified")
+
+		backToNormal, err := applyConfigPatch(inMaintenance, types.ChannelConfigPatch{ConsensusState: &normal})
+		require.NoError(t, err)
+		require.Equal(t, ab.ConsensusType_STATE_NORMAL, consensusType(backToNormal).State)
+
+		_, err = applyConfigPatch(original, types.ChannelConfigPatch{ConsensusState: &normal})
+		require.Equal(t, types.ErrConsensusStateUnchanged, err)
+		_, err = applyConfigPatch(inMaintenance, types.ChannelConfigPatch{ConsensusState: &maintenance})
+		require.Equal(t, types.ErrConsensusStateUnchanged, err)
+	})
+
+	t.Run("no orderer group", func(t *testing.T) {
+		_, err := applyConfigPatch(&cb.Config{ChannelGroup: &cb.ConfigGroup{}}, types.ChannelConfigPatch{})
+		require.EqualError(t, err, "config does not contain an orderer group")
+	})
+}
+
+func TestRegistrar_ChannelCapabilities(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "channel-capabilities")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+
+	ledgerFactory := newFactory(tmpdir)
+	defer ledgerFactory.Close()
+
+	config := localconfig.TopLevel{
+		ChannelParticipation: localconfig.ChannelParticipation{Enabled: true},
+		General:              localconfig.General{BootstrapMethod: "none"},
+		FileLedger:           localconfig.FileLedger{Location: tmpdir},
+	}
+	registrar := NewRegistrar(config, ledgerFactory, mockCrypto(), &disabled.Provider{}, cryptoProvider, nil)
+	registrar.Initialize(map[string]consensus.Consenter{})
+
+	_, err = registrar.ChannelCapabilities("some-channel")
+	require.EqualError(t, err, "channel does not exist")
+}
+
+func TestCapabilitiesFromConfig(t *testing.T) {
+	t.Run("application channel", func(t *testing.T) {
+		conf := genesisconfig.Load(genesisconfig.SampleAppChannelInsecureSoloProfile, configtest.GetDevConfigDir())
+		block := encoder.New(conf).GenesisBlockForChannel("my-channel")
+		configEnv, err := configEnvelopeFromBlock(block)
+		require.NoError(t, err)
+
+		capabilities := capabilitiesFromConfig(configEnv.Config)
+		require.Equal(t, types.ChannelCapabilities{
+			Channel:     []string{"V2_0"},
+			Orderer:     []string{"V2_0"},
+			Application: []string{"V2_0"},
+		}, capabilities)
+	})
+
+	t.Run("system channel", func(t *testing.T) {
+		conf := genesisconfig.Load(genesisconfig.SampleInsecureSoloProfile, configtest.GetDevConfigDir())
+		channelGroup, err := encoder.NewChannelGroup(conf)
+		require.NoError(t, err)
+
+		capabilities := capabilitiesFromConfig(&cb.Config{ChannelGroup: channelGroup})
+		require.Equal(t, []string{"V2_0"}, capabilities.Channel)
+		require.Equal(t, []string{"V2_0"}, capabilities.Orderer)
+		require.Empty(t, capabilities.Application)
+	})
+}
+
+func TestRegistrar_ConsenterCount(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "consenter-count")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+
+	ledgerFactory := newFactory(tmpdir)
+	defer ledgerFactory.Close()
+
+	config := localconfig.TopLevel{
+		ChannelParticipation: localconfig.ChannelParticipation{Enabled: true},
+		General:              localconfig.General{BootstrapMethod: "none"},
+		FileLedger:           localconfig.FileLedger{Location: tmpdir},
+	}
+	registrar := NewRegistrar(config, ledgerFactory, mockCrypto(), &disabled.Provider{}, cryptoProvider, nil)
+	registrar.Initialize(map[string]consensus.Consenter{})
+
+	_, err = registrar.ConsenterCount("some-channel")
+	require.EqualError(t, err, "channel does not exist")
+}
+
+func TestConsenterCountFromConfig(t *testing.T) {
+	configWithConsensusType := func(consensusType *ab.ConsensusType) *cb.Config {
+		return &cb.Config{
+			ChannelGroup: &cb.ConfigGroup{
+				Groups: map[string]*cb.ConfigGroup{
+					channelconfig.OrdererGroupKey: {
+						Values: map[string]*cb.ConfigValue{
+							channelconfig.ConsensusTypeKey: {Value: protoutil.MarshalOrPanic(consensusType)},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("etcdraft", func(t *testing.T) {
+		config := configWithConsensusType(&ab.ConsensusType{
+			Type: "etcdraft",
+			Metadata: protoutil.MarshalOrPanic(&etcdraft.ConfigMetadata{
+				Consenters: []*etcdraft.Consenter{{Host: "orderer1"}, {Host: "orderer2"}, {Host: "orderer3"}},
+			}),
+		})
+		count, err := consenterCountFromConfig(config)
+		require.NoError(t, err)
+		require.Equal(t, 3, count)
+	})
+
+	t.Run("solo", func(t *testing.T) {
+		config := configWithConsensusType(&ab.ConsensusType{Type: "solo"})
+		_, err := consenterCountFromConfig(config)
+		require.EqualError(t, err, "consensus type solo has no consenter set")
+	})
+
+	t.Run("no consensus type", func(t *testing.T) {
+		_, err := consenterCountFromConfig(&cb.Config{ChannelGroup: &cb.ConfigGroup{}})
+		require.EqualError(t, err, "config has no consensus type")
+	})
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func uint64Ptr(u uint64) *uint64 {
+	return &u
+}
@@ -257,10 +257,12 @@ func Main() {
 		tlsCallback,
 	)
 
+	participationHandler := channelparticipation.NewHTTPHandler(conf.ChannelParticipation, manager)
+	manager.ObserveRelationChanges(participationHandler.RelationChanged)
 	adminServer := newAdminServer(conf.Admin)
 	adminServer.RegisterHandler(
 		channelparticipation.URLBaseV1,
-		channelparticipation.NewHTTPHandler(conf.ChannelParticipation, manager),
+		participationHandler,
 		conf.Admin.TLS.Enabled,
 	)
 	if err = adminServer.Start(); err != nil {
@@ -286,6 +288,7 @@ func Main() {
 				clusterGRPCServer.Stop()
 			}
 		},
+		syscall.SIGHUP: func() { reloadChannelParticipation(participationHandler) },
 	}))
 
 	if !reuseGrpcListener && isClusterType {
@@ -477,6 +480,18 @@ func handleSignals(handlers map[os.Signal]func()) {
 	}()
 }
 
+// reloadChannelParticipation reads the configuration file again and applies the settings of the channel participation
+// API that can be changed without a restart. Only MaxRequestBodySize can be changed so far. An unreadable or invalid
+// configuration is logged and leaves the current settings in place.
+func reloadChannelParticipation(handler *channelparticipation.HTTPHandler) {
+	conf, err := localconfig.Reload()
+	if err != nil {
+		logger.Errorf("Failed to reload the configuration, keeping the current one: %s", err)
+		return
+	}
+	handler.SetMaxRequestBodySize(conf.ChannelParticipation.MaxRequestBodySize)
+}
+
 type loadPEMFunc func(string) ([]byte, error)
 
 // configureClusterListener returns a new ServerConfig and a new gRPC server (with its own TLS listener).
@@ -901,6 +916,8 @@ func newAdminServer(admin localconfig.Admin) *fabhttp.Server {
 			ClientCertRequired: admin.TLS.ClientAuthRequired,
 			ClientCACertFiles:  admin.TLS.ClientRootCAs,
 		},
+		UnauthorizedLimit:  admin.UnauthorizedLimit,
+		UnauthorizedWindow: admin.UnauthorizedWindow,
 	})
 }
 
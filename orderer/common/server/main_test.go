@@ -35,6 +35,7 @@ import (
 	"github.com/hyperledger/fabric/internal/pkg/comm"
 	"github.com/hyperledger/fabric/internal/pkg/identity"
 	"github.com/hyperledger/fabric/orderer/common/bootstrap/file"
+	"github.com/hyperledger/fabric/orderer/common/channelparticipation"
 	"github.com/hyperledger/fabric/orderer/common/cluster"
 	"github.com/hyperledger/fabric/orderer/common/filerepo"
 	"github.com/hyperledger/fabric/orderer/common/localconfig"
@@ -1150,6 +1151,58 @@ func TestInitializeEtcdraftConsenter(t *testing.T) {
 	require.NotNil(t, consenters["etcdraft"])
 }
 
+func TestReloadChannelParticipation(t *testing.T) {
+	original, err := ioutil.ReadFile(filepath.Join("testdata", "orderer.yaml"))
+	require.NoError(t, err)
+	require.Contains(t, string(original), "MaxRequestBodySize: 1 MB")
+
+	configDir, err := ioutil.TempDir("", "orderer-config")
+	require.NoError(t, err)
+	defer os.RemoveAll(configDir)
+	configFile := filepath.Join(configDir, "orderer.yaml")
+	require.NoError(t, ioutil.WriteFile(configFile, original, 0o644))
+
+	origEnvValue, set := os.LookupEnv("FABRIC_CFG_PATH")
+	os.Setenv("FABRIC_CFG_PATH", configDir)
+	defer func() {
+		if set {
+			os.Setenv("FABRIC_CFG_PATH", origEnvValue)
+		} else {
+			os.Unsetenv("FABRIC_CFG_PATH")
+		}
+	}()
+
+	conf, err := localconfig.Load()
+	require.NoError(t, err)
+	handler := channelparticipation.NewHTTPHandler(conf.ChannelParticipation, nil)
+	require.Equal(t, uint32(1024*1024), handler.MaxRequestBodySize())
+
+	t.Run("the edited limit is applied", func(t *testing.T) {
+		edited := strings.Replace(string(original), "MaxRequestBodySize: 1 MB", "MaxRequestBodySize: 2 MB", 1)
+		require.NoError(t, ioutil.WriteFile(configFile, []byte(edited), 0o644))
+
+		reloadChannelParticipation(handler)
+		require.Equal(t, uint32(2*1024*1024), handler.MaxRequestBodySize())
+	})
+
+	t.Run("an invalid configuration is ignored", func(t *testing.T) {
+		edited := strings.Replace(string(original), "MaxRequestBodySize: 1 MB", "MaxRequestBodySize: 4 MB", 1)
+		edited = strings.Replace(edited, "UnauthorizedLimit: 0", "UnauthorizedLimit: 5", 1)
+		edited = strings.Replace(edited, "UnauthorizedWindow: 1m", "UnauthorizedWindow: 0s", 1)
+		require.NoError(t, ioutil.WriteFile(configFile, []byte(edited), 0o644))
+
+		require.NotPanics(t, func() { reloadChannelParticipation(handler) })
+		require.Equal(t, uint32(2*1024*1024), handler.MaxRequestBodySize())
+	})
+
+	t.Run("an unreadable configuration is ignored", func(t *testing.T) {
+		require.NoError(t, ioutil.WriteFile(configFile, []byte("General: 42"), 0o644))
+
+		reloadChannelParticipation(handler)
+		require.Equal(t, uint32(2*1024*1024), handler.MaxRequestBodySize())
+	})
+}
+
 func genesisConfig(t *testing.T, genesisFile string) (*localconfig.TopLevel, string) {
 	t.Helper()
 	localMSPDir := configtest.GetDevMspDir()
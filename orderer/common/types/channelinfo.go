@@ -6,10 +6,15 @@ SPDX-License-Identifier: Apache-2.0
 
 package types
 
+import "github.com/pkg/errors"
+
 // ErrorResponse carries the error response an HTTP request.
 // This is marshaled into the body of the HTTP response.
 type ErrorResponse struct {
 	Error string `json:"error"`
+	// Every problem found, the first of which is the error, when a request fails several checks, e.g. the
+	// validation of a join block.
+	Errors []string `json:"errors,omitempty"`
 }
 
 // ChannelList carries the response to an HTTP request to List all the channels.
@@ -20,6 +25,12 @@ type ChannelList struct {
 	SystemChannel *ChannelInfoShort `json:"systemChannel"`
 	// Application channels only, nil or empty if no channels defined.
 	Channels []ChannelInfoShort `json:"channels"`
+	// The total number of channels the orderer hosts, including the system channel,
+	// regardless of the channels left out of the list by filtering.
+	Count int `json:"count"`
+	// The revision of the channels the orderer hosts, incremented on every join, removal, or change of consensus
+	// relation. Clients may compare revisions across requests to detect any change.
+	Revision uint64 `json:"revision"`
 }
 
 // ChannelInfoShort carries a short info of a single channel.
@@ -47,6 +58,26 @@ const (
 	ConsensusRelationOther ConsensusRelation = "other"
 )
 
+// ConsensusRelations returns all the known ConsensusRelation values.
+func ConsensusRelations() []ConsensusRelation {
+	return []ConsensusRelation{
+		ConsensusRelationConsenter,
+		ConsensusRelationFollower,
+		ConsensusRelationConfigTracker,
+		ConsensusRelationOther,
+	}
+}
+
+// ParseConsensusRelation converts a string to a ConsensusRelation, returning an error if it is not a known value.
+func ParseConsensusRelation(s string) (ConsensusRelation, error) {
+	for _, r := range ConsensusRelations() {
+		if string(r) == s {
+			return r, nil
+		}
+	}
+	return "", errors.Errorf("unknown consensus relation: %s", s)
+}
+
 // Status represents the degree by which the orderer had caught up with the rest of the cluster after joining the
 // channel (either as a consenter or a follower).
 type Status string
@@ -62,8 +93,31 @@ const (
 	StatusInactive Status = "inactive"
 	// The last orderer operation against the channel failed.
 	StatusFailed Status = "failed"
+	// The orderer is removing the channel, e.g. releasing its ledger, after an asynchronous remove request.
+	StatusRemoving Status = "removing"
 )
 
+// Statuses returns all the known Status values.
+func Statuses() []Status {
+	return []Status{
+		StatusActive,
+		StatusOnBoarding,
+		StatusInactive,
+		StatusFailed,
+		StatusRemoving,
+	}
+}
+
+// ParseStatus converts a string to a Status, returning an error if it is not a known value.
+func ParseStatus(s string) (Status, error) {
+	for _, st := range Statuses() {
+		if string(st) == s {
+			return st, nil
+		}
+	}
+	return "", errors.Errorf("unknown status: %s", s)
+}
+
 // ChannelInfo carries the response to an HTTP request to List a single channel.
 // This is marshaled into the body of the HTTP response.
 // swagger:model channelInfo
@@ -83,4 +137,142 @@ type ChannelInfo struct {
 	Status Status `json:"status"`
 	// Current block height.
 	Height uint64 `json:"height"`
+	// The endpoint of the orderer that responded, when configured.
+	OrdererEndpoint string `json:"ordererEndpoint,omitempty"`
+	// Whether the orderer joined the channel with its genesis block (true), or onboarded from a later config
+	// block (false). Absent when unknown, e.g. for a channel not joined through the channel participation API.
+	JoinedFromGenesis *bool `json:"joinedFromGenesis,omitempty"`
+	// The sequence number of the channel config, that is, the number of config updates applied to it. For a follower
+	// that is onboarding, it is that of the join block. Absent when unknown, e.g. for a channel being removed.
+	ConfigSequence *uint64 `json:"configSequence,omitempty"`
+	// The height the ledger reaches once onboarding is done, that of the join block, so that the progress of onboarding
+	// can be gauged against Height. Only present while the channel is onboarding.
+	TargetHeight *uint64 `json:"targetHeight,omitempty"`
+	// The subject of the client certificate of the last join or remove of the channel through the API, e.g.
+	// "CN=admin,OU=admin,O=Org1". Absent when unknown, e.g. for a channel not joined through the channel participation API.
+	LastModifiedBy string `json:"lastModifiedBy,omitempty"`
+	// Whether the orderer must be restarted before the channel becomes active, as after joining the system channel.
+	// Only present in the response to a join.
+	RequiresRestart bool `json:"requiresRestart,omitempty"`
+	// The capabilities of the channel config, only present in verbose mode.
+	Capabilities *ChannelCapabilities `json:"capabilities,omitempty"`
+	// The approximate size in bytes of the channel's block files on disk, only present in verbose mode.
+	LedgerBytes *uint64 `json:"ledgerBytes,omitempty"`
+}
+
+// ChannelCapabilities carries the capability keys of the channel config, per config group.
+// swagger:model channelCapabilities
+type ChannelCapabilities struct {
+	// The capabilities of the channel group, e.g. "V2_0".
+	Channel []string `json:"channel"`
+	// The capabilities of the orderer group.
+	Orderer []string `json:"orderer"`
+	// The capabilities of the application group; empty for the system channel.
+	Application []string `json:"application,omitempty"`
+}
+
+// ChannelsSummary carries the response to an HTTP request for an aggregate summary of all the channels.
+// This is marshaled into the body of the HTTP response.
+// swagger:model channelsSummary
+type ChannelsSummary struct {
+	// Whether the system channel exists.
+	SystemChannel bool `json:"systemChannel"`
+	// Whether the orderer has loaded its channels and none of them is onboarding or failed.
+	Ready bool `json:"ready"`
+	// The number of channels, including the system channel.
+	Total int `json:"total"`
+	// The number of channels in each status.
+	Statuses map[Status]int `json:"statuses"`
+}
+
+// ChannelConfigPatch carries a JSON merge patch (RFC 7396) to the orderer batch parameters, or the consensus state,
+// of a channel.
+// Only the fields below may be patched; fields that are absent are left unchanged.
+// swagger:model channelConfigPatch
+type ChannelConfigPatch struct {
+	// The amount of time to wait before creating a batch, e.g. "2s".
+	BatchTimeout *string `json:"batchTimeout,omitempty"`
+	// The maximum number of messages to permit in a batch.
+	MaxMessageCount *uint32 `json:"maxMessageCount,omitempty"`
+	// The absolute maximum number of bytes allowed for the serialized messages in a batch.
+	AbsoluteMaxBytes *uint32 `json:"absoluteMaxBytes,omitempty"`
+	// The preferred maximum number of bytes allowed for the serialized messages in a batch.
+	PreferredMaxBytes *uint32 `json:"preferredMaxBytes,omitempty"`
+	// The consensus state of the channel, "normal" or "maintenance". It may only be switched to the other state,
+	// and not together with other fields.
+	ConsensusState *string `json:"consensusState,omitempty"`
+}
+
+// Consensus states of a channel, as set by a ChannelConfigPatch.
+const (
+	ConsensusStateNormal      = "normal"
+	ConsensusStateMaintenance = "maintenance"
+)
+
+// JoinRequest carries the config block to join a channel with, as an alternative to a multipart form.
+// This is unmarshaled from the body of the HTTP request.
+// swagger:model joinRequest
+type JoinRequest struct {
+	// The protobuf encoded config block, in base64.
+	ConfigBlock string `json:"configBlock"`
+}
+
+// Optional features of the channel participation API, as reported by APICapabilities.
+const (
+	// Listing the channels can be filtered by a name prefix.
+	FeatureFiltering = "filtering"
+	// A join with If-Not-Exists responds with the existing channel.
+	FeatureIdempotentJoin = "idempotent-join"
+	// Listing a single channel can include the capabilities of its config.
+	FeatureVerbose = "verbose"
+	// The batch parameters of a channel can be changed with a config update signed by the client.
+	FeatureConfigPatch = "config-patch"
+	// Listing the channels, or a single channel, can be reduced to some of the fields.
+	FeatureFields = "fields"
+	// A join can be validated without creating the channel.
+	FeatureDryRun = "dry-run"
+	// The number of concurrent join and remove operations is bounded.
+	FeatureJoinLimit = "join-limit"
+	// Removing a channel the orderer is a consenter of requires force.
+	FeatureProtectConsenters = "protect-consenters"
+	// Removing a channel the orderer is the sole consenter of requires force.
+	FeatureProtectSoleConsenter = "protect-sole-consenter"
+	// Channel lifecycle events are POSTed to a webhook.
+	FeatureWebhook = "webhook"
+	// The metrics of the API are served in the Prometheus text format.
+	FeatureMetrics = "metrics"
+	// A join is rejected when the free disk space is below a minimum.
+	FeatureDiskSpaceCheck = "disk-space-check"
+	// The progress of a channel is streamed as Server-Sent Events.
+	FeatureEvents = "events"
+	// A join is rejected when the orderer has the maximum number of channels.
+	FeatureChannelLimit = "channel-limit"
+)
+
+// APICapabilities carries the response to an HTTP OPTIONS request on the channels resource.
+// This is marshaled into the body of the HTTP response.
+// swagger:model apiCapabilities
+type APICapabilities struct {
+	// The media types accepted when joining a channel.
+	JoinContentTypes []string `json:"joinContentTypes"`
+	// The optional features enabled on this orderer, e.g. "filtering".
+	Features []string `json:"features"`
+}
+
+// Types of channel lifecycle events.
+const (
+	ChannelEventJoin   = "join"
+	ChannelEventRemove = "remove"
+	// The consensus relation of the channel changed, e.g. a follower became a consenter.
+	ChannelEventRelation = "relation"
+)
+
+// ChannelEvent carries the notification of a channel lifecycle change.
+// This is marshaled into the body of the HTTP request POSTed to the configured webhook.
+// swagger:model channelEvent
+type ChannelEvent struct {
+	// The type of the event, "join", "remove" or "relation".
+	Type string `json:"type"`
+	// The channel information after the change.
+	Channel ChannelInfo `json:"channel"`
 }
@@ -58,17 +58,19 @@ func TestChannelList(t *testing.T) {
 
 	buff, err := json.Marshal(list)
 	require.NoError(t, err)
-	require.Equal(t, `{"systemChannel":null,"channels":null}`, string(buff))
+	require.Equal(t, `{"systemChannel":null,"channels":null,"count":0,"revision":0}`, string(buff))
 
 	list.SystemChannel = &types.ChannelInfoShort{Name: "s", URL: "/api/channels/s"}
 	list.Channels = []types.ChannelInfoShort{
 		{Name: "a", URL: "/api/channels/a"},
 		{Name: "b", URL: "/api/channels/b"},
 	}
+	list.Count = 3
+	list.Revision = 5
 
 	buff, err = json.Marshal(list)
 	require.NoError(t, err)
-	require.Equal(t, `{"systemChannel":{"name":"s","url":"/api/channels/s"},"channels":[{"name":"a","url":"/api/channels/a"},{"name":"b","url":"/api/channels/b"}]}`, string(buff))
+	require.Equal(t, `{"systemChannel":{"name":"s","url":"/api/channels/s"},"channels":[{"name":"a","url":"/api/channels/a"},{"name":"b","url":"/api/channels/b"}],"count":3,"revision":5}`, string(buff))
 }
 
 func TestChannelInfo(t *testing.T) {
@@ -89,3 +91,25 @@ func TestChannelInfo(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, info.Height, info2.Height)
 }
+
+func TestParseConsensusRelation(t *testing.T) {
+	for _, s := range []string{"consenter", "follower", "config-tracker", "other"} {
+		r, err := types.ParseConsensusRelation(s)
+		require.NoError(t, err)
+		require.Equal(t, s, string(r))
+	}
+
+	_, err := types.ParseConsensusRelation("Consenter")
+	require.EqualError(t, err, "unknown consensus relation: Consenter")
+}
+
+func TestParseStatus(t *testing.T) {
+	for _, s := range []string{"active", "onboarding", "inactive", "failed", "removing"} {
+		st, err := types.ParseStatus(s)
+		require.NoError(t, err)
+		require.Equal(t, s, string(st))
+	}
+
+	_, err := types.ParseStatus("")
+	require.EqualError(t, err, "unknown status: ")
+}
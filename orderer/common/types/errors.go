@@ -27,3 +27,10 @@ var ErrChannelPendingRemoval = errors.New("channel pending removal")
 
 // ErrChannelRemovalFailure is returned when a removal attempt failure has been recorded.
 var ErrChannelRemovalFailure = errors.New("channel removal failure")
+
+// ErrConsensusStateUnchanged is returned when trying to switch a channel to the consensus state it is already in.
+var ErrConsensusStateUnchanged = errors.New("channel is already in the consensus state")
+
+// ErrJoinBlockNotExist is returned when trying to get the join block of a channel that was not joined through the
+// channel participation API.
+var ErrJoinBlockNotExist = errors.New("join block does not exist")
@@ -134,7 +134,7 @@ generateOrCheck \
         docs/wrappers/configtxlator_postscript.md \
         "${commands[@]}"
 
-commands=("osnadmin channel" "osnadmin channel join" "osnadmin channel list" "osnadmin channel remove")
+commands=("osnadmin channel" "osnadmin channel join" "osnadmin channel list" "osnadmin channel remove" "osnadmin channel set-maintenance" "osnadmin channel set-normal" "osnadmin channel diff" "osnadmin channel status")
 generateOrCheck \
         docs/source/commands/osnadminchannel.md \
         docs/wrappers/osnadmin_channel_preamble.md \